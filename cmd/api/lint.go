@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/SergeiKhy/org-structure-api/internal/lint"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// runLint выполняет подкоманду lint: проверяет оргструктуру и печатает находки.
+// Возвращает код выхода: 0 — находок уровня -fail-on нет, 1 — есть, 2 — ошибка.
+func runLint(repo *repository.Repository, defaults lint.Config, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	rules := fs.String("rules", strings.Join(defaults.Rules, ","), "включённые правила через запятую (пусто — все)")
+	maxDepth := fs.Int("max-depth", defaults.MaxDepth, "допустимое число уровней дерева")
+	maxTeamSize := fs.Int("max-team-size", defaults.MaxTeamSize, "допустимое число сотрудников в подразделении")
+	severities := fs.String("severity", "", "уровни серьёзности правил, например team_size=error,empty_department=info")
+	tenant := fs.String("tenant", "", "slug арендатора (по умолчанию — арендатор по умолчанию)")
+	format := fs.String("format", "text", "формат вывода: text или json")
+	failOn := fs.String("fail-on", lint.SeverityError, "уровень находок, при котором код выхода равен 1")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := lint.Config{MaxDepth: *maxDepth, MaxTeamSize: *maxTeamSize}
+	for _, rule := range strings.Split(*rules, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			cfg.Rules = append(cfg.Rules, rule)
+		}
+	}
+	for _, pair := range strings.Split(*severities, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		rule, severity, ok := strings.Cut(pair, "=")
+		if !ok {
+			fmt.Fprintf(stderr, "некорректное значение -severity: %q\n", pair)
+			return 2
+		}
+		if cfg.Severities == nil {
+			cfg.Severities = make(map[string]string)
+		}
+		cfg.Severities[rule] = severity
+	}
+	if !lint.ValidSeverity(*failOn) || (*format != "text" && *format != "json") {
+		fs.Usage()
+		return 2
+	}
+
+	svc := service.NewService(repo)
+	if *tenant != "" {
+		tenantID, err := svc.ResolveTenant(*tenant)
+		if err != nil {
+			fmt.Fprintf(stderr, "арендатор %q не найден\n", *tenant)
+			return 2
+		}
+		svc = service.NewService(repo.WithTenant(tenantID))
+	}
+
+	report, err := svc.LintOrg(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "ошибка проверки: %v\n", err)
+		return 2
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		writeLintText(stdout, report)
+	}
+	if report.HasSeverity(*failOn) {
+		return 1
+	}
+	return 0
+}
+
+// writeLintText печатает находки таблицей и итог по уровням серьёзности
+func writeLintText(w io.Writer, report *lint.Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range report.Findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Severity, f.Rule, strings.Join(f.Path, " / "), f.Message)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "%d error(s), %d warning(s), %d info\n",
+		report.Summary[lint.SeverityError], report.Summary[lint.SeverityWarning], report.Summary[lint.SeverityInfo])
+}
@@ -4,16 +4,24 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
 	"github.com/SergeiKhy/org-structure-api/internal/config"
+	"github.com/SergeiKhy/org-structure-api/internal/graphql"
+	"github.com/SergeiKhy/org-structure-api/internal/grpcapi"
 	"github.com/SergeiKhy/org-structure-api/internal/handler"
+	"github.com/SergeiKhy/org-structure-api/internal/lint"
 	"github.com/SergeiKhy/org-structure-api/internal/logger"
+	"github.com/SergeiKhy/org-structure-api/internal/outbox"
 	"github.com/SergeiKhy/org-structure-api/internal/repository"
 	"github.com/SergeiKhy/org-structure-api/internal/service"
+	"github.com/SergeiKhy/org-structure-api/internal/webhook"
 	"github.com/pressly/goose/v3"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -47,6 +55,12 @@ func main() {
 
 	log.Info("успешное подключение к базе данных")
 
+	// Подкоманда lint проверяет оргструктуру и завершает работу, не запуская сервер
+	lintConfig := lint.Config{Rules: cfg.LintRules, MaxDepth: cfg.LintMaxDepth, MaxTeamSize: cfg.LintMaxTeamSize}
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(repository.NewRepository(db), lintConfig, os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// Запуск миграции Goose
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -66,67 +80,553 @@ func main() {
 
 	// Инициализация слоев
 	repo := repository.NewRepository(db)
-	svc := service.NewService(repo)
-	hndl := handler.NewHandler(svc)
+	svc := service.NewService(repo).WithPrivateWebhooks(cfg.WebhookAllowPrivateNetworks)
+	schema, err := graphql.NewSchema()
+	if err != nil {
+		log.Error("ошибка разбора схемы GraphQL",
+			slog.String("error", err.Error()))
+		return
+	}
+	hndl := handler.NewHandler(svc).WithGraphQL(schema).WithLDIFBaseDN(cfg.LDAPBaseDN).WithLintConfig(lintConfig)
 
 	// Создаём логгер запросов
 	reqLogger := logger.NewRequestLogger()
 
-	// Роутинг с логгированием
-	http.HandleFunc("/departments/", func(w http.ResponseWriter, r *http.Request) {
-		// Запоминаем время начала запроса
-		start := time.Now()
-
-		// Создаём контекст с ID запроса
-		ctx := context.WithValue(r.Context(), "request_id", time.Now().UnixNano())
-		r = r.WithContext(ctx)
-
-		// Обёртка для перехвата статуса ответа
-		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+	// Аутентификация запросов
+	authn := auth.NewAuthenticator(cfg.AuthEnabled, cfg.AuthJWTSecret, cfg.AuthAdminSubjects).
+		WithAPIKeys(svc).
+		WithTenants(svc)
 
+	// Роутинг с логгированием
+	http.HandleFunc("/departments/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
 		path := strings.TrimPrefix(r.URL.Path, "/departments/")
 		parts := strings.Split(path, "/")
 
-		// Если путь пустой или slash, создание подразделения
+		// Если путь пустой или slash, список или создание подразделения
 		if len(parts) == 0 || parts[0] == "" {
+			switch r.Method {
+			case http.MethodGet:
+				hndl.ListDepartments(w, r)
+			case http.MethodPost:
+				hndl.Idempotent(hndl.CreateDepartment)(w, r)
+			default:
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// Поиск подразделения по коду (/departments/by-code/{code})
+		if parts[0] == "by-code" {
+			if r.Method == http.MethodGet {
+				hndl.GetDepartmentByCode(w, r)
+			} else {
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// Смена статуса сотрудника (/departments/{id}/employees/{empID}/status)
+		if len(parts) == 4 && parts[1] == "employees" && parts[3] == "status" {
 			if r.Method == http.MethodPost {
-				hndl.CreateDepartment(rw, r)
+				hndl.ChangeEmployeeStatus(w, r)
+			} else {
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// Бюджеты численности (/departments/{id}/budgets[/{period}])
+		if len(parts) >= 2 && parts[1] == "budgets" {
+			switch {
+			case len(parts) == 2 && r.Method == http.MethodGet:
+				hndl.ListBudgets(w, r)
+			case len(parts) == 3 && r.Method == http.MethodPut:
+				hndl.SetBudget(w, r)
+			case len(parts) == 3 && r.Method == http.MethodDelete:
+				hndl.DeleteBudget(w, r)
+			default:
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// Вакансии (/departments/{id}/requisitions[/{reqID}])
+		if len(parts) >= 2 && parts[1] == "requisitions" {
+			switch {
+			case len(parts) == 2 && r.Method == http.MethodGet:
+				hndl.ListRequisitions(w, r)
+			case len(parts) == 2 && r.Method == http.MethodPost:
+				hndl.Idempotent(hndl.CreateRequisition)(w, r)
+			case len(parts) == 3 && r.Method == http.MethodPatch:
+				hndl.UpdateRequisition(w, r)
+			default:
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// Укомплектованность поддерева (/departments/{id}/staffing)
+		if len(parts) == 2 && parts[1] == "staffing" {
+			if r.Method == http.MethodGet {
+				hndl.GetStaffing(w, r)
+			} else {
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// Сводные показатели поддерева (/departments/{id}/stats)
+		if len(parts) == 2 && parts[1] == "stats" {
+			if r.Method == http.MethodGet {
+				hndl.GetDepartmentStats(w, r)
+			} else {
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// Порядок непосредственных потомков (/departments/{id}/children/order)
+		if len(parts) == 3 && parts[1] == "children" && parts[2] == "order" {
+			if r.Method == http.MethodPut {
+				hndl.ReorderChildren(w, r)
 			} else {
-				hndl.WriteError(rw, http.StatusNotFound, "not found")
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
 			}
-			reqLogger.LogRequest(ctx, r.Method, r.URL.Path, rw.statusCode, time.Since(start).String())
 			return
 		}
 
 		// Проверка на вложенный ресурс employees
+		if len(parts) >= 3 && parts[1] == "employees" && parts[2] != "" {
+			// Работа с конкретным сотрудником (/departments/{id}/employees/{empID})
+			switch r.Method {
+			case http.MethodGet:
+				hndl.GetEmployee(w, r)
+			case http.MethodPatch:
+				hndl.PatchEmployee(w, r)
+			default:
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
 		if len(parts) >= 2 && parts[1] == "employees" {
 			if r.Method == http.MethodPost {
-				hndl.CreateEmployee(rw, r)
+				hndl.Idempotent(hndl.CreateEmployee)(w, r)
 			} else {
-				hndl.WriteError(rw, http.StatusMethodNotAllowed, "method not allowed")
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
 			}
-			reqLogger.LogRequest(ctx, r.Method, r.URL.Path, rw.statusCode, time.Since(start).String())
 			return
 		}
 
 		// Работа с конкретным департаментом (/departments/{id})
 		switch r.Method {
 		case http.MethodGet:
-			hndl.GetDepartment(rw, r)
+			hndl.GetDepartment(w, r)
 		case http.MethodPatch:
-			hndl.UpdateDepartment(rw, r)
+			hndl.UpdateDepartment(w, r)
 		case http.MethodDelete:
-			hndl.DeleteDepartment(rw, r)
+			hndl.DeleteDepartment(w, r)
 		default:
-			hndl.WriteError(rw, http.StatusMethodNotAllowed, "method not allowed")
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
 		}
+	})))
 
-		// Логируем запрос
-		reqLogger.LogRequest(ctx, r.Method, r.URL.Path, rw.statusCode, time.Since(start).String())
-	})
+	// Поиск сотрудников (/employees/)
+	http.HandleFunc("/employees/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/employees/" {
+			hndl.WriteError(w, http.StatusNotFound, "not found")
+		} else if r.Method == http.MethodGet {
+			hndl.ListEmployees(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Каталог должностей (/positions)
+	http.HandleFunc("/positions", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.ListPositions(w, r)
+		case http.MethodPost:
+			hndl.CreatePosition(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/positions/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.GetPosition(w, r)
+		case http.MethodPut:
+			hndl.UpdatePosition(w, r)
+		case http.MethodDelete:
+			hndl.DeletePosition(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Отчёты (/reports/positions)
+	http.HandleFunc("/reports/positions", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hndl.PositionReport(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Схемы дополнительных атрибутов (/admin/attribute-definitions)
+	http.HandleFunc("/admin/attribute-definitions", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.ListAttributeDefinitions(w, r)
+		case http.MethodPost:
+			hndl.CreateAttributeDefinition(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/admin/attribute-definitions/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			hndl.DeleteAttributeDefinition(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Управление ролями (/admin/role-bindings)
+	http.HandleFunc("/admin/role-bindings", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.ListRoleBindings(w, r)
+		case http.MethodPost:
+			hndl.CreateRoleBinding(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/admin/role-bindings/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			hndl.DeleteRoleBinding(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Политики согласования (/admin/approval-policies)
+	http.HandleFunc("/admin/approval-policies", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hndl.ListApprovalPolicies(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/admin/approval-policies/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			hndl.SetApprovalPolicy(w, r)
+		case http.MethodDelete:
+			hndl.DeleteApprovalPolicy(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Управление API-ключами (/admin/api-keys)
+	http.HandleFunc("/admin/api-keys", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.ListAPIKeys(w, r)
+		case http.MethodPost:
+			hndl.CreateAPIKey(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/admin/api-keys/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		// /admin/api-keys/{id}/rotate
+		if strings.HasSuffix(r.URL.Path, "/rotate") {
+			if r.Method == http.MethodPost {
+				hndl.RotateAPIKey(w, r)
+			} else {
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// /admin/api-keys/{id}
+		if r.Method == http.MethodDelete {
+			hndl.RevokeAPIKey(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Управление арендаторами (/admin/tenants)
+	http.HandleFunc("/admin/tenants", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.ListTenants(w, r)
+		case http.MethodPost:
+			hndl.CreateTenant(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Подписки на вебхуки (/webhooks)
+	http.HandleFunc("/webhooks", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.ListWebhookSubscriptions(w, r)
+		case http.MethodPost:
+			hndl.CreateWebhookSubscription(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/webhooks/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		// /webhooks/{id}/deliveries
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/deliveries") {
+			if r.Method == http.MethodGet {
+				hndl.ListWebhookDeliveries(w, r)
+			} else {
+				hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+			}
+			return
+		}
+
+		// /webhooks/{id}
+		switch r.Method {
+		case http.MethodGet:
+			hndl.GetWebhookSubscription(w, r)
+		case http.MethodDelete:
+			hndl.DeleteWebhookSubscription(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Сценарии реорганизации (/scenarios)
+	http.HandleFunc("/scenarios", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.ListScenarios(w, r)
+		case http.MethodPost:
+			hndl.CreateScenario(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/scenarios/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/scenarios/"), "/"), "/")
+		switch {
+		// /scenarios/{id}
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			hndl.GetScenario(w, r)
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			hndl.DeleteScenario(w, r)
+		// /scenarios/{id}/operations[/{index}]
+		case len(parts) == 2 && parts[1] == "operations" && r.Method == http.MethodPost:
+			hndl.AddScenarioOperation(w, r)
+		case len(parts) == 3 && parts[1] == "operations" && r.Method == http.MethodDelete:
+			hndl.RemoveScenarioOperation(w, r)
+		// /scenarios/{id}/tree, /scenarios/{id}/diff, /scenarios/{id}/apply
+		case len(parts) == 2 && parts[1] == "tree" && r.Method == http.MethodGet:
+			hndl.GetScenarioTree(w, r)
+		case len(parts) == 2 && parts[1] == "diff" && r.Method == http.MethodGet:
+			hndl.GetScenarioDiff(w, r)
+		case len(parts) == 2 && parts[1] == "apply" && r.Method == http.MethodPost:
+			hndl.ApplyScenario(w, r)
+		case len(parts) > 3 || (len(parts) > 1 && !slices.Contains([]string{"operations", "tree", "diff", "apply"}, parts[1])):
+			hndl.WriteError(w, http.StatusNotFound, "not found")
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Запланированные изменения (/scheduled-changes)
+	http.HandleFunc("/scheduled-changes", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hndl.ListScheduledChanges(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/scheduled-changes/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			hndl.GetScheduledChange(w, r)
+		case http.MethodDelete:
+			hndl.CancelScheduledChange(w, r)
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Запросы на изменение (/change-requests)
+	http.HandleFunc("/change-requests", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hndl.ListChangeRequests(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	http.HandleFunc("/change-requests/", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/change-requests/"), "/"), "/")
+		switch {
+		// /change-requests/{id}
+		case len(parts) == 1 && r.Method == http.MethodGet:
+			hndl.GetChangeRequest(w, r)
+		// /change-requests/{id}/approve, /change-requests/{id}/reject
+		case len(parts) == 2 && parts[1] == "approve" && r.Method == http.MethodPost:
+			hndl.ApproveChangeRequest(w, r)
+		case len(parts) == 2 && parts[1] == "reject" && r.Method == http.MethodPost:
+			hndl.RejectChangeRequest(w, r)
+		case len(parts) > 2 || (len(parts) == 2 && parts[1] != "approve" && parts[1] != "reject"):
+			hndl.WriteError(w, http.StatusNotFound, "not found")
+		default:
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Пакетные операции (/batch)
+	http.HandleFunc("/batch", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			hndl.ExecuteBatch(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// GraphQL (/graphql)
+	http.HandleFunc("/graphql", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			hndl.GraphQL(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Выгрузка в LDIF (/export/ldif)
+	http.HandleFunc("/export/ldif", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hndl.ExportLDIF(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Проверка оргструктуры (/org/lint)
+	http.HandleFunc("/org/lint", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hndl.LintOrg(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// SCIM 2.0 (/scim/v2/Users, /scim/v2/Groups и документы обнаружения)
+	http.HandleFunc("/scim/v2/", withLogging(reqLogger, hndl.Authenticate(authn, hndl.SCIM)))
+
+	// Лента изменений (Server-Sent Events)
+	http.HandleFunc("/events/stream", withLogging(reqLogger, hndl.Authenticate(authn, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			hndl.StreamEvents(w, r)
+		} else {
+			hndl.WriteError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	})))
+
+	// Публикация событий outbox в настроенные приёмники
+	var sinks []outbox.Sink
+	for _, name := range cfg.OutboxSinks {
+		switch name {
+		case "webhook":
+			sinks = append(sinks, webhook.NewSink(repo))
+		case "stdout":
+			sinks = append(sinks, outbox.NewStdoutSink())
+		case "file":
+			fileSink, err := outbox.NewFileSink(cfg.OutboxFilePath)
+			if err != nil {
+				log.Error("ошибка открытия файла событий",
+					slog.String("path", cfg.OutboxFilePath),
+					slog.String("error", err.Error()))
+				return
+			}
+			defer fileSink.Close()
+			sinks = append(sinks, fileSink)
+		default:
+			log.Error("неизвестный приёмник событий", slog.String("sink", name))
+			return
+		}
+	}
+	relay := outbox.NewRelay(repo, outbox.DefaultConfig(), sinks...)
+	go relay.Run(context.Background())
+
+	// Очистка истёкших ключей идемпотентности
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := svc.PurgeExpiredIdempotencyKeys(); err != nil {
+				log.Error("ошибка очистки ключей идемпотентности",
+					slog.String("error", err.Error()))
+			}
+		}
+	}()
+
+	// Применение наступивших запланированных изменений
+	go func() {
+		ticker := time.NewTicker(time.Duration(cfg.SchedulePollSeconds) * time.Second)
+		defer ticker.Stop()
+		for range ticker.C {
+			applied, failed, err := svc.ApplyDueScheduledChanges(time.Now())
+			if err != nil {
+				log.Error("ошибка применения запланированных изменений",
+					slog.String("error", err.Error()))
+			}
+			if applied > 0 || failed > 0 {
+				log.Info("применены запланированные изменения",
+					slog.Int("applied", applied),
+					slog.Int("failed", failed))
+			}
+		}
+	}()
+
+	// Фоновая доставка вебхуков
+	webhookConfig := webhook.DefaultConfig()
+	webhookConfig.AllowPrivateNetworks = cfg.WebhookAllowPrivateNetworks
+	dispatcher := webhook.NewDispatcher(repo, webhookConfig)
+	go dispatcher.Run(context.Background())
+
+	// gRPC API на отдельном порту
+	grpcServer := grpcapi.NewGRPCServer(svc, authn)
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Error("ошибка открытия порта gRPC",
+			slog.String("port", cfg.GRPCPort),
+			slog.String("error", err.Error()))
+		return
+	}
+	go func() {
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Error("ошибка gRPC сервера",
+				slog.String("error", err.Error()))
+		}
+	}()
 
 	log.Info("сервер запущен",
 		slog.String("port", cfg.ServerPort),
+		slog.String("grpc_port", cfg.GRPCPort),
 		slog.String("environment", getEnv("ENVIRONMENT", "development")))
 
 	if err := http.ListenAndServe(":"+cfg.ServerPort, nil); err != nil {
@@ -135,6 +635,26 @@ func main() {
 	}
 }
 
+// withLogging оборачивает обработчик: присваивает ID запроса и логирует результат
+func withLogging(reqLogger *logger.RequestLogger, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Запоминаем время начала запроса
+		start := time.Now()
+
+		// Создаём контекст с ID запроса
+		ctx := context.WithValue(r.Context(), "request_id", time.Now().UnixNano())
+		r = r.WithContext(ctx)
+
+		// Обёртка для перехвата статуса ответа
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		next(rw, r)
+
+		// Логируем запрос
+		reqLogger.LogRequest(ctx, r.Method, r.URL.Path, rw.statusCode, time.Since(start).String())
+	}
+}
+
 // responseWriter обёртка для перехвата статуса ответа
 type responseWriter struct {
 	http.ResponseWriter
@@ -146,6 +666,11 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Unwrap даёт http.ResponseController доступ к Flush исходного ответа (нужно для SSE)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // getEnv получает переменную окружения или возвращает значение по умолчанию
 func getEnv(key, defaultVal string) string {
 	if val, ok := os.LookupEnv(key); ok {
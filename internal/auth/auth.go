@@ -0,0 +1,279 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+
+	ErrUnknownTenant  = errors.New("unknown tenant")
+	ErrTenantMismatch = errors.New("tenant mismatch")
+)
+
+// TenantHeader заголовок для явного выбора арендатора
+const TenantHeader = "X-Tenant-ID"
+
+// Области действия API-ключей
+const (
+	ScopeReadOnly  = "read-only"
+	ScopeReadWrite = "read-write"
+)
+
+// Principal описывает субъект, от имени которого выполняется запрос
+type Principal struct {
+	Subject string
+	// Superuser обходит проверку ролей (системные вызовы и bootstrap-администраторы)
+	Superuser bool
+	// Scope задан для API-ключей и ограничивает права вместо привязок ролей
+	Scope string
+	// Tenant — slug арендатора из токена или заголовка, TenantID — его идентификатор
+	Tenant   string
+	TenantID int
+}
+
+// System используется для внутренних вызовов и при выключенной аутентификации
+var System = Principal{Subject: "system", Superuser: true}
+
+type contextKey struct{}
+
+// NewContext возвращает контекст с сохранённым субъектом
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext извлекает субъекта из контекста.
+// Если субъект не найден, возвращается анонимный субъект без прав.
+func FromContext(ctx context.Context) Principal {
+	if p, ok := ctx.Value(contextKey{}).(Principal); ok {
+		return p
+	}
+	return Principal{}
+}
+
+// Claims набор поддерживаемых полей JWT
+type Claims struct {
+	Subject   string `json:"sub"`
+	Tenant    string `json:"tenant,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+}
+
+// APIKeyResolver находит субъекта по значению API-ключа
+type APIKeyResolver interface {
+	ResolveAPIKey(key string) (Principal, error)
+}
+
+// TenantResolver находит идентификатор арендатора по slug
+type TenantResolver interface {
+	ResolveTenant(slug string) (int, error)
+}
+
+// Authenticator определяет субъекта по заголовкам запроса
+type Authenticator struct {
+	enabled bool
+	secret  []byte
+	admins  map[string]bool
+	apiKeys APIKeyResolver
+	tenants TenantResolver
+}
+
+// NewAuthenticator создаёт аутентификатор.
+// При enabled == false все запросы выполняются от имени System.
+func NewAuthenticator(enabled bool, secret string, adminSubjects []string) *Authenticator {
+	admins := make(map[string]bool, len(adminSubjects))
+	for _, s := range adminSubjects {
+		if s = strings.TrimSpace(s); s != "" {
+			admins[s] = true
+		}
+	}
+	return &Authenticator{enabled: enabled, secret: []byte(secret), admins: admins}
+}
+
+// WithAPIKeys включает приём заголовка Authorization: ApiKey <key>
+func (a *Authenticator) WithAPIKeys(resolver APIKeyResolver) *Authenticator {
+	a.apiKeys = resolver
+	return a
+}
+
+// WithTenants включает определение арендатора запроса
+func (a *Authenticator) WithTenants(resolver TenantResolver) *Authenticator {
+	a.tenants = resolver
+	return a
+}
+
+// Authenticate определяет субъекта и арендатора HTTP-запроса
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, error) {
+	return a.AuthenticateCredentials(r.Header.Get("Authorization"), r.Header.Get(TenantHeader))
+}
+
+// AuthenticateCredentials определяет субъекта и арендатора по значениям заголовков
+// Authorization и X-Tenant-ID; используется транспортами, отличными от HTTP
+func (a *Authenticator) AuthenticateCredentials(authorization, tenant string) (Principal, error) {
+	p, err := a.principal(authorization)
+	if err != nil {
+		return Principal{}, err
+	}
+	if err := a.resolveTenant(tenant, &p); err != nil {
+		return Principal{}, err
+	}
+	return p, nil
+}
+
+// principal извлекает субъекта из заголовка Authorization:
+// Bearer <jwt> для пользователей или ApiKey <key> для сервисных клиентов
+func (a *Authenticator) principal(header string) (Principal, error) {
+	if !a.enabled {
+		return System, nil
+	}
+
+	scheme, credentials, ok := strings.Cut(header, " ")
+	if !ok {
+		return Principal{}, ErrUnauthorized
+	}
+	credentials = strings.TrimSpace(credentials)
+
+	if strings.EqualFold(scheme, "ApiKey") && a.apiKeys != nil {
+		return a.apiKeys.ResolveAPIKey(credentials)
+	}
+	if !strings.EqualFold(scheme, "Bearer") {
+		return Principal{}, ErrUnauthorized
+	}
+
+	claims, err := ParseToken(credentials, a.secret, time.Now())
+	if err != nil {
+		return Principal{}, err
+	}
+
+	return Principal{Subject: claims.Subject, Superuser: a.admins[claims.Subject], Tenant: claims.Tenant}, nil
+}
+
+// resolveTenant выбирает арендатора: из API-ключа, токена или заголовка X-Tenant-ID.
+// Заголовок не может переопределить арендатора, зафиксированного ключом или токеном.
+func (a *Authenticator) resolveTenant(header string, p *Principal) error {
+	if a.tenants == nil {
+		return nil
+	}
+	header = strings.TrimSpace(header)
+
+	// Арендатор API-ключа известен заранее
+	if p.TenantID != 0 {
+		if header == "" {
+			return nil
+		}
+		id, err := a.tenants.ResolveTenant(header)
+		if err != nil {
+			return err
+		}
+		if id != p.TenantID {
+			return ErrTenantMismatch
+		}
+		return nil
+	}
+
+	if p.Tenant != "" && header != "" && p.Tenant != header {
+		return ErrTenantMismatch
+	}
+	if p.Tenant == "" {
+		p.Tenant = header
+	}
+	if p.Tenant == "" {
+		return nil
+	}
+
+	id, err := a.tenants.ResolveTenant(p.Tenant)
+	if err != nil {
+		return err
+	}
+	p.TenantID = id
+	return nil
+}
+
+// ParseToken проверяет подпись HS256 и срок действия токена
+func ParseToken(token string, secret []byte, now time.Time) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil || header.Alg != "HS256" {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !hmac.Equal(signature, sign(parts[0]+"."+parts[1], secret)) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	if claims.ExpiresAt != 0 && now.Unix() >= claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+// SignToken выпускает токен HS256 (используется в тестах и утилитах)
+func SignToken(claims Claims, secret []byte) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payloadJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(payloadJSON)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sign(signingInput, secret)), nil
+}
+
+func sign(input string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	return mac.Sum(nil)
+}
+
+// apiKeyPrefix отличает ключи этого сервиса от прочих секретов
+const apiKeyPrefix = "osk_"
+
+// GenerateAPIKey создаёт новый API-ключ и его отображаемый префикс
+func GenerateAPIKey() (key string, prefix string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, key[:len(apiKeyPrefix)+8], nil
+}
+
+// HashAPIKey возвращает хеш ключа для хранения в БД.
+// Ключи имеют высокую энтропию, поэтому достаточно SHA-256 без соли.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test-secret")
+
+func TestParseToken_Valid(t *testing.T) {
+	token, err := SignToken(Claims{Subject: "alice", ExpiresAt: time.Now().Add(time.Hour).Unix()}, testSecret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims, err := ParseToken(token, testSecret, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Subject != "alice" {
+		t.Errorf("expected subject 'alice', got %q", claims.Subject)
+	}
+}
+
+func TestParseToken_Invalid(t *testing.T) {
+	valid, _ := SignToken(Claims{Subject: "alice"}, testSecret)
+	expired, _ := SignToken(Claims{Subject: "alice", ExpiresAt: time.Now().Add(-time.Minute).Unix()}, testSecret)
+	noSubject, _ := SignToken(Claims{}, testSecret)
+
+	tests := []struct {
+		name   string
+		token  string
+		secret []byte
+		expect error
+	}{
+		{"garbage", "not-a-token", testSecret, ErrInvalidToken},
+		{"wrong secret", valid, []byte("other"), ErrInvalidToken},
+		{"tampered payload", valid[:len(valid)-2] + "xx", testSecret, ErrInvalidToken},
+		{"expired", expired, testSecret, ErrTokenExpired},
+		{"no subject", noSubject, testSecret, ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseToken(tt.token, tt.secret, time.Now())
+			if err != tt.expect {
+				t.Errorf("expected %v, got %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_Disabled(t *testing.T) {
+	a := NewAuthenticator(false, "", nil)
+	req := httptest.NewRequest("GET", "/departments/1", nil)
+
+	p, err := a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !p.Superuser {
+		t.Error("disabled auth should yield the system principal")
+	}
+}
+
+func TestAuthenticate_Bearer(t *testing.T) {
+	a := NewAuthenticator(true, string(testSecret), []string{"root"})
+
+	tests := []struct {
+		name      string
+		subject   string
+		superuser bool
+	}{
+		{"regular user", "alice", false},
+		{"bootstrap admin", "root", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, _ := SignToken(Claims{Subject: tt.subject}, testSecret)
+			req := httptest.NewRequest("GET", "/departments/1", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			p, err := a.Authenticate(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if p.Subject != tt.subject || p.Superuser != tt.superuser {
+				t.Errorf("unexpected principal %+v", p)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_MissingHeader(t *testing.T) {
+	a := NewAuthenticator(true, string(testSecret), nil)
+	req := httptest.NewRequest("GET", "/departments/1", nil)
+
+	if _, err := a.Authenticate(req); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if p := FromContext(context.Background()); p.Subject != "" || p.Superuser {
+		t.Errorf("expected anonymous principal, got %+v", p)
+	}
+
+	ctx := NewContext(context.Background(), Principal{Subject: "alice"})
+	if p := FromContext(ctx); p.Subject != "alice" {
+		t.Errorf("expected subject 'alice', got %q", p.Subject)
+	}
+}
+
+type stubResolver struct {
+	key string
+}
+
+func (s stubResolver) ResolveAPIKey(key string) (Principal, error) {
+	if key != s.key {
+		return Principal{}, ErrUnauthorized
+	}
+	return Principal{Subject: "apikey:1", Scope: ScopeReadOnly}, nil
+}
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	a := NewAuthenticator(true, string(testSecret), nil).WithAPIKeys(stubResolver{key: "osk_valid"})
+
+	req := httptest.NewRequest("GET", "/departments/1", nil)
+	req.Header.Set("Authorization", "ApiKey osk_valid")
+	p, err := a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Scope != ScopeReadOnly {
+		t.Errorf("expected scope %q, got %q", ScopeReadOnly, p.Scope)
+	}
+
+	req.Header.Set("Authorization", "ApiKey osk_invalid")
+	if _, err := a.Authenticate(req); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key1, prefix1, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	key2, _, _ := GenerateAPIKey()
+
+	if key1 == key2 {
+		t.Error("generated keys should be unique")
+	}
+	if len(prefix1) != 12 || key1[:12] != prefix1 {
+		t.Errorf("unexpected prefix %q for key %q", prefix1, key1)
+	}
+	if HashAPIKey(key1) == HashAPIKey(key2) {
+		t.Error("different keys should have different hashes")
+	}
+	if HashAPIKey(key1) != HashAPIKey(key1) {
+		t.Error("hash should be deterministic")
+	}
+}
+
+type stubTenants map[string]int
+
+func (s stubTenants) ResolveTenant(slug string) (int, error) {
+	if id, ok := s[slug]; ok {
+		return id, nil
+	}
+	return 0, ErrUnknownTenant
+}
+
+func TestAuthenticate_Tenant(t *testing.T) {
+	tenants := stubTenants{"default": 1, "acme": 2, "globex": 3}
+	a := NewAuthenticator(true, string(testSecret), nil).WithTenants(tenants)
+
+	withClaim, _ := SignToken(Claims{Subject: "alice", Tenant: "acme"}, testSecret)
+	withoutClaim, _ := SignToken(Claims{Subject: "alice"}, testSecret)
+
+	tests := []struct {
+		name     string
+		token    string
+		header   string
+		expected int
+		err      error
+	}{
+		{"token claim", withClaim, "", 2, nil},
+		{"header", withoutClaim, "globex", 3, nil},
+		{"claim and matching header", withClaim, "acme", 2, nil},
+		{"claim and other header", withClaim, "globex", 0, ErrTenantMismatch},
+		{"unknown tenant", withoutClaim, "initech", 0, ErrUnknownTenant},
+		{"no tenant", withoutClaim, "", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/departments/1", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+
+			p, err := a.Authenticate(req)
+			if err != tt.err {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if p.TenantID != tt.expected {
+				t.Errorf("expected tenant %d, got %d", tt.expected, p.TenantID)
+			}
+		})
+	}
+}
+
+type stubTenantKey struct{}
+
+func (stubTenantKey) ResolveAPIKey(key string) (Principal, error) {
+	return Principal{Subject: "apikey:1", Scope: ScopeReadWrite, TenantID: 2}, nil
+}
+
+func TestAuthenticate_APIKeyTenant(t *testing.T) {
+	a := NewAuthenticator(true, string(testSecret), nil).
+		WithAPIKeys(stubTenantKey{}).
+		WithTenants(stubTenants{"acme": 2, "globex": 3})
+
+	req := httptest.NewRequest("GET", "/departments/1", nil)
+	req.Header.Set("Authorization", "ApiKey osk_key")
+	req.Header.Set(TenantHeader, "globex")
+
+	if _, err := a.Authenticate(req); err != ErrTenantMismatch {
+		t.Errorf("expected ErrTenantMismatch, got %v", err)
+	}
+
+	req.Header.Set(TenantHeader, "acme")
+	p, err := a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.TenantID != 2 {
+		t.Errorf("expected tenant 2, got %d", p.TenantID)
+	}
+}
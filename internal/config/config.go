@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
@@ -11,17 +13,62 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	ServerPort string
+	GRPCPort   string
+
+	// Аутентификация и авторизация
+	AuthEnabled       bool
+	AuthJWTSecret     string
+	AuthAdminSubjects []string
+
+	// Приёмники событий outbox: webhook, stdout, file
+	OutboxSinks    []string
+	OutboxFilePath string
+
+	// Базовый DN выгрузки LDIF
+	LDAPBaseDN string
+
+	// Проверка оргструктуры: включённые правила (пусто — все) и пороги
+	LintRules       []string
+	LintMaxDepth    int
+	LintMaxTeamSize int
+
+	// Период опроса запланированных изменений, секунды
+	SchedulePollSeconds int
+
+	// Разрешить вебхуки на адреса внутренней сети (только для локальной разработки)
+	WebhookAllowPrivateNetworks bool
 }
 
 func Load() *Config {
-	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     getEnv("DB_PORT", "5432"),
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "postgres"),
-		DBName:     getEnv("DB_NAME", "postgres"),
-		ServerPort: getEnv("SERVER_PORT", "8080"),
+	cfg := &Config{
+		DBHost:            getEnv("DB_HOST", "localhost"),
+		DBPort:            getEnv("DB_PORT", "5432"),
+		DBUser:            getEnv("DB_USER", "postgres"),
+		DBPassword:        getEnv("DB_PASSWORD", "postgres"),
+		DBName:            getEnv("DB_NAME", "postgres"),
+		ServerPort:        getEnv("SERVER_PORT", "8080"),
+		GRPCPort:          getEnv("GRPC_PORT", "9090"),
+		AuthEnabled:       getEnvBool("AUTH_ENABLED", false),
+		AuthJWTSecret:     getEnv("AUTH_JWT_SECRET", ""),
+		AuthAdminSubjects: getEnvList("AUTH_ADMIN_SUBJECTS"),
+		OutboxSinks:       getEnvList("OUTBOX_SINKS"),
+		OutboxFilePath:    getEnv("OUTBOX_FILE_PATH", "events.jsonl"),
+		LDAPBaseDN:        getEnv("LDAP_BASE_DN", "dc=example,dc=com"),
+		LintRules:         getEnvList("LINT_RULES"),
+		LintMaxDepth:      getEnvInt("LINT_MAX_DEPTH", 6),
+		LintMaxTeamSize:   getEnvInt("LINT_MAX_TEAM_SIZE", 15),
+
+		SchedulePollSeconds: getEnvInt("SCHEDULE_POLL_SECONDS", 30),
+
+		WebhookAllowPrivateNetworks: getEnvBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+	}
+	if len(cfg.OutboxSinks) == 0 {
+		cfg.OutboxSinks = []string{"webhook"}
 	}
+	if cfg.SchedulePollSeconds <= 0 {
+		cfg.SchedulePollSeconds = 30
+	}
+	return cfg
 }
 
 func getEnv(key, defaultVal string) string {
@@ -30,3 +77,34 @@ func getEnv(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+// getEnvBool читает булеву переменную, при ошибке разбора возвращает значение по умолчанию
+func getEnvBool(key string, defaultVal bool) bool {
+	if val, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(val); err == nil {
+			return b
+		}
+	}
+	return defaultVal
+}
+
+// getEnvInt читает целочисленную переменную, при ошибке разбора возвращает значение по умолчанию
+func getEnvInt(key string, defaultVal int) int {
+	if val, ok := os.LookupEnv(key); ok {
+		if n, err := strconv.Atoi(val); err == nil {
+			return n
+		}
+	}
+	return defaultVal
+}
+
+// getEnvList читает список значений, разделённых запятыми
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
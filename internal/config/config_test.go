@@ -14,6 +14,13 @@ func TestLoad_DefaultValues(t *testing.T) {
 		os.Unsetenv("DB_PASSWORD")
 		os.Unsetenv("DB_NAME")
 		os.Unsetenv("SERVER_PORT")
+		os.Unsetenv("GRPC_PORT")
+		os.Unsetenv("LDAP_BASE_DN")
+		os.Unsetenv("LINT_RULES")
+		os.Unsetenv("LINT_MAX_DEPTH")
+		os.Unsetenv("LINT_MAX_TEAM_SIZE")
+		os.Unsetenv("SCHEDULE_POLL_SECONDS")
+		os.Unsetenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS")
 	}
 
 	clearEnv()
@@ -39,6 +46,21 @@ func TestLoad_DefaultValues(t *testing.T) {
 	if cfg.ServerPort != "8080" {
 		t.Errorf("expected ServerPort '8080', got %q", cfg.ServerPort)
 	}
+	if cfg.GRPCPort != "9090" {
+		t.Errorf("expected GRPCPort '9090', got %q", cfg.GRPCPort)
+	}
+	if cfg.LDAPBaseDN != "dc=example,dc=com" {
+		t.Errorf("expected LDAPBaseDN 'dc=example,dc=com', got %q", cfg.LDAPBaseDN)
+	}
+	if cfg.LintRules != nil || cfg.LintMaxDepth != 6 || cfg.LintMaxTeamSize != 15 {
+		t.Errorf("unexpected lint defaults: %v, %d, %d", cfg.LintRules, cfg.LintMaxDepth, cfg.LintMaxTeamSize)
+	}
+	if cfg.SchedulePollSeconds != 30 {
+		t.Errorf("expected SchedulePollSeconds 30, got %d", cfg.SchedulePollSeconds)
+	}
+	if cfg.WebhookAllowPrivateNetworks {
+		t.Error("expected private webhook networks to be denied by default")
+	}
 }
 
 func TestLoad_EnvironmentVariables(t *testing.T) {
@@ -230,3 +252,66 @@ func BenchmarkGetEnv(b *testing.B) {
 		getEnv("BENCH_KEY", "default")
 	}
 }
+
+func TestLoad_AuthDefaults(t *testing.T) {
+	os.Unsetenv("AUTH_ENABLED")
+	os.Unsetenv("AUTH_ADMIN_SUBJECTS")
+
+	cfg := Load()
+
+	if cfg.AuthEnabled {
+		t.Error("auth should be disabled by default")
+	}
+	if len(cfg.AuthAdminSubjects) != 0 {
+		t.Errorf("expected no admin subjects, got %v", cfg.AuthAdminSubjects)
+	}
+}
+
+func TestLoad_AuthEnvironmentVariables(t *testing.T) {
+	os.Setenv("AUTH_ENABLED", "true")
+	os.Setenv("AUTH_JWT_SECRET", "secret")
+	os.Setenv("AUTH_ADMIN_SUBJECTS", "alice, bob,,")
+	defer func() {
+		os.Unsetenv("AUTH_ENABLED")
+		os.Unsetenv("AUTH_JWT_SECRET")
+		os.Unsetenv("AUTH_ADMIN_SUBJECTS")
+	}()
+
+	cfg := Load()
+
+	if !cfg.AuthEnabled {
+		t.Error("expected auth to be enabled")
+	}
+	if cfg.AuthJWTSecret != "secret" {
+		t.Errorf("expected AuthJWTSecret 'secret', got %q", cfg.AuthJWTSecret)
+	}
+	if len(cfg.AuthAdminSubjects) != 2 || cfg.AuthAdminSubjects[0] != "alice" || cfg.AuthAdminSubjects[1] != "bob" {
+		t.Errorf("expected [alice bob], got %v", cfg.AuthAdminSubjects)
+	}
+}
+
+func TestGetEnvBool_InvalidValue(t *testing.T) {
+	os.Setenv("BOOL_KEY", "not-a-bool")
+	defer os.Unsetenv("BOOL_KEY")
+
+	if getEnvBool("BOOL_KEY", true) != true {
+		t.Error("invalid value should fall back to default")
+	}
+}
+
+func TestLoad_OutboxSinks(t *testing.T) {
+	os.Unsetenv("OUTBOX_SINKS")
+
+	cfg := Load()
+	if len(cfg.OutboxSinks) != 1 || cfg.OutboxSinks[0] != "webhook" {
+		t.Errorf("expected default sinks [webhook], got %v", cfg.OutboxSinks)
+	}
+
+	os.Setenv("OUTBOX_SINKS", "stdout,file")
+	defer os.Unsetenv("OUTBOX_SINKS")
+
+	cfg = Load()
+	if len(cfg.OutboxSinks) != 2 || cfg.OutboxSinks[0] != "stdout" || cfg.OutboxSinks[1] != "file" {
+		t.Errorf("expected [stdout file], got %v", cfg.OutboxSinks)
+	}
+}
@@ -0,0 +1,201 @@
+//go:build integration
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupTestContainer создаёт контейнер с PostgreSQL для тестов
+func setupTestContainer(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB, context.Context) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	pgContainer, err := tcpostgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:15-alpine"),
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("test"),
+		tcpostgres.WithPassword("test"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		t.Fatalf("ошибка запуска контейнера: %v", err)
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("ошибка получения connection string: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("ошибка подключения к БД: %v", err)
+	}
+
+	err = db.AutoMigrate(&model.Department{}, &model.Employee{}, &model.RoleBinding{}, &model.OutboxEvent{}, &model.AttributeDefinition{}, &model.Position{}, &model.HeadcountBudget{}, &model.Requisition{}, &model.Scenario{}, &model.ScheduledChange{}, &model.ApprovalPolicy{}, &model.ChangeRequest{})
+	if err != nil {
+		t.Fatalf("ошибка миграции: %v", err)
+	}
+
+	return pgContainer, db, ctx
+}
+
+// countQueries считает запросы на чтение, выполненные через db
+func countQueries(t *testing.T, db *gorm.DB) *atomic.Int64 {
+	t.Helper()
+	var n atomic.Int64
+	inc := func(*gorm.DB) { n.Add(1) }
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_query", inc); err != nil {
+		t.Fatalf("ошибка регистрации callback: %v", err)
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:count_row", inc); err != nil {
+		t.Fatalf("ошибка регистрации callback: %v", err)
+	}
+	return &n
+}
+
+// TestExecute_NestedQueryBatched_Integration проверяет, что число запросов не зависит от размера дерева
+func TestExecute_NestedQueryBatched_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := service.NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Root"})
+	for i := 0; i < 3; i++ {
+		child, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: fmt.Sprintf("Child %d", i), ParentID: &root.ID})
+		svc.CreateEmployee(child.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer"})
+		for j := 0; j < 2; j++ {
+			leaf, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: fmt.Sprintf("Leaf %d.%d", i, j), ParentID: &child.ID})
+			svc.CreateEmployee(leaf.ID, model.CreateEmployeeRequest{FullName: "Jane Doe", Position: "Tester"})
+		}
+	}
+
+	schema, err := NewSchema()
+	if err != nil {
+		t.Fatalf("ошибка разбора схемы: %v", err)
+	}
+	queries := countQueries(t, db)
+
+	resp := schema.Execute(ctx, svc, Request{Query: `{
+		departments {
+			name
+			headcount
+			children {
+				name
+				employees { fullName department { name } }
+				children { name headcount ancestors { name } }
+			}
+		}
+	}`})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("ошибки выполнения: %v", resp.Errors)
+	}
+
+	// roots, headcount, children, employees, department, children, headcount, ancestors
+	if n := queries.Load(); n > 8 {
+		t.Errorf("ожидалось не более 8 запросов, выполнено %d", n)
+	}
+
+	var data struct {
+		Departments []struct {
+			Name      string
+			Headcount int
+			Children  []struct {
+				Employees []struct {
+					Department struct{ Name string }
+				}
+				Children []struct {
+					Headcount int
+					Ancestors []struct{ Name string }
+				}
+			}
+		}
+	}
+	json.Unmarshal(resp.Data, &data)
+
+	if len(data.Departments) != 1 || data.Departments[0].Headcount != 9 {
+		t.Fatalf("ожидался 1 корень с численностью 9, получено %+v", data.Departments)
+	}
+	child := data.Departments[0].Children[0]
+	if len(child.Employees) != 1 || child.Employees[0].Department.Name != "Child 0" {
+		t.Errorf("неожиданные сотрудники: %+v", child.Employees)
+	}
+	leaf := child.Children[0]
+	if leaf.Headcount != 1 || len(leaf.Ancestors) != 2 || leaf.Ancestors[0].Name != "Root" {
+		t.Errorf("неожиданный лист: %+v", leaf)
+	}
+}
+
+// TestExecute_Mutations_Integration проверяет мутации и различие отсутствующего поля и null
+func TestExecute_Mutations_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	svc := service.NewService(repository.NewRepository(db))
+	schema, _ := NewSchema()
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Root"})
+	resp := schema.Execute(ctx, svc, Request{
+		Query: `mutation($parent: Int) {
+			createDepartment(input: {name: "Child", parentId: $parent}) { id parentId version }
+		}`,
+		Variables: map[string]interface{}{"parent": root.ID},
+	})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("ошибки выполнения: %v", resp.Errors)
+	}
+	var created struct {
+		CreateDepartment struct {
+			ID       int
+			ParentID *int
+		}
+	}
+	json.Unmarshal(resp.Data, &created)
+
+	// parentId: null переносит в корень, отсутствующее name не меняется
+	resp = schema.Execute(ctx, svc, Request{Query: fmt.Sprintf(`mutation {
+		updateDepartment(id: %d, input: {parentId: null}, version: 1) { name parentId version }
+	}`, created.CreateDepartment.ID)})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("ошибки выполнения: %v", resp.Errors)
+	}
+	var updated struct {
+		UpdateDepartment struct {
+			Name     string
+			ParentID *int
+			Version  int
+		}
+	}
+	json.Unmarshal(resp.Data, &updated)
+	if updated.UpdateDepartment.Name != "Child" || updated.UpdateDepartment.ParentID != nil || updated.UpdateDepartment.Version != 2 {
+		t.Errorf("неожиданный результат: %+v", updated.UpdateDepartment)
+	}
+
+	// Устаревшая версия отклоняется
+	resp = schema.Execute(ctx, svc, Request{Query: fmt.Sprintf(`mutation {
+		deleteDepartment(id: %d, version: 1)
+	}`, created.CreateDepartment.ID)})
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != service.ErrVersionMismatch.Error() {
+		t.Errorf("ожидалась ошибка version mismatch, получено %v", resp.Errors)
+	}
+}
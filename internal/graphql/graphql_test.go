@@ -0,0 +1,174 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// TestNewSchema проверяет, что схема соответствует резолверам
+func TestNewSchema(t *testing.T) {
+	if _, err := NewSchema(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestLoader_Batch проверяет, что обращения к соседям группы не вызывают новых загрузок
+func TestLoader_Batch(t *testing.T) {
+	var mu sync.Mutex
+	var calls [][]int
+	l := newLoader(func(ids []int) (map[int]int, error) {
+		mu.Lock()
+		calls = append(calls, ids)
+		mu.Unlock()
+		result := make(map[int]int)
+		for _, id := range ids {
+			if id != 3 {
+				result[id] = id * 10
+			}
+		}
+		return result, nil
+	})
+
+	batch := []int{1, 2, 3}
+	var wg sync.WaitGroup
+	for _, id := range batch {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			l.load(id, batch)
+		}(id)
+	}
+	wg.Wait()
+
+	if len(calls) != 1 || len(calls[0]) != 3 {
+		t.Fatalf("expected 1 fetch of 3 keys, got %v", calls)
+	}
+
+	// Отсутствующий ключ кэшируется нулевым значением
+	if v, err := l.load(3, nil); err != nil || v != 0 {
+		t.Errorf("expected cached zero value, got %d (%v)", v, err)
+	}
+	if v, _ := l.load(2, nil); v != 20 {
+		t.Errorf("expected 20, got %d", v)
+	}
+
+	// Новый ключ загружается вместе с незакэшированными соседями
+	l.load(4, []int{1, 4, 5})
+	if len(calls) != 2 || !reflect.DeepEqual(calls[1], []int{4, 5}) {
+		t.Errorf("expected fetch of [4 5], got %v", calls)
+	}
+}
+
+// TestStatusLoaders проверяет, что для каждого набора статусов используется свой загрузчик
+func TestStatusLoaders(t *testing.T) {
+	var calls [][]string
+	l := newStatusLoaders(func(ids []int, statuses []string) (map[int]int, error) {
+		calls = append(calls, statuses)
+		return map[int]int{1: len(statuses)}, nil
+	})
+
+	if v, _ := l.get(nil).load(1, nil); v != 0 {
+		t.Errorf("expected 0, got %d", v)
+	}
+	statuses := employeeStatuses(&[]string{"ON_LEAVE", "ACTIVE", "ACTIVE"})
+	if !reflect.DeepEqual(statuses, []string{"active", "on_leave"}) {
+		t.Errorf("unexpected normalized statuses %v", statuses)
+	}
+	if v, _ := l.get(statuses).load(1, nil); v != 2 {
+		t.Errorf("expected 2, got %d", v)
+	}
+	l.get([]string{"active", "on_leave"}).load(1, nil)
+	if len(calls) != 2 {
+		t.Errorf("expected 2 fetches, got %v", calls)
+	}
+}
+
+// TestGroup_NextGroup проверяет, что следующая группа вычисляется один раз
+func TestGroup_NextGroup(t *testing.T) {
+	g := newGroup([]int{1, 2})
+	collected := 0
+	collect := func() []int {
+		collected++
+		return []int{3, 4}
+	}
+
+	first := g.nextGroup("children", collect)
+	second := g.nextGroup("children", collect)
+	if first != second || collected != 1 {
+		t.Errorf("expected shared group and 1 collect call, got %d", collected)
+	}
+	if !reflect.DeepEqual(first.ids, []int{3, 4}) {
+		t.Errorf("expected [3 4], got %v", first.ids)
+	}
+	if g.nextGroup("ancestors", collect) == first {
+		t.Error("expected separate group for another field")
+	}
+}
+
+// TestExecute_Forbidden проверяет, что ошибки доступа возвращаются в errors
+func TestExecute_Forbidden(t *testing.T) {
+	schema, err := NewSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := service.NewService(nil).WithPrincipal(auth.Principal{})
+
+	resp := schema.Execute(context.Background(), svc, Request{Query: `{ department(id: 1) { id name } }`})
+	if len(resp.Errors) != 1 || resp.Errors[0].Message != service.ErrForbidden.Error() {
+		t.Fatalf("expected forbidden error, got %v", resp.Errors)
+	}
+
+	var data map[string]interface{}
+	json.Unmarshal(resp.Data, &data)
+	if v, ok := data["department"]; !ok || v != nil {
+		t.Errorf("expected null department, got %v", data)
+	}
+}
+
+// TestExecute_InvalidQuery проверяет отказ для запросов вне схемы и слишком глубоких запросов
+func TestExecute_InvalidQuery(t *testing.T) {
+	schema, _ := NewSchema()
+	svc := service.NewService(nil).WithPrincipal(auth.Principal{})
+
+	queries := []string{
+		`{ department(id: 1) { salary } }`,
+		`{ department(id: 1) { children { children { children { children { children { children {
+			children { children { children { children { children { children { id } } } } } } } } } } } } }`,
+	}
+	for _, q := range queries {
+		resp := schema.Execute(context.Background(), svc, Request{Query: q})
+		if len(resp.Errors) == 0 {
+			t.Errorf("expected validation error for %q", q)
+		}
+	}
+}
+
+// TestMutationError проверяет расширения ошибки мутации, отложенной до согласования
+func TestMutationError(t *testing.T) {
+	if err := mutationError(service.ErrNotFound); err != service.ErrNotFound {
+		t.Errorf("other errors should pass through, got %v", err)
+	}
+
+	err := mutationError(&service.ApprovalRequiredError{Request: &model.ChangeRequest{ID: 7}})
+	ext, ok := err.(interface{ Extensions() map[string]any })
+	if !ok {
+		t.Fatalf("expected error with extensions, got %T", err)
+	}
+	if got := ext.Extensions(); got["code"] != "APPROVAL_REQUIRED" || got["changeRequestId"] != 7 {
+		t.Errorf("unexpected extensions %v", got)
+	}
+
+	// Запрос, откатанный вместе с операцией, не упоминается
+	err = mutationError(service.ErrApprovalNotDeferrable)
+	if got := err.(interface{ Extensions() map[string]any }).Extensions(); got["changeRequestId"] != nil || !errors.Is(err, service.ErrApprovalRequired) {
+		t.Errorf("unexpected extensions %v", got)
+	}
+}
@@ -0,0 +1,122 @@
+package graphql
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// loader кэширует результаты на время запроса и загружает значения пачками.
+// Пачка — все соседние объекты одного списка: первое обращение к любому из них
+// загружает значения для всех, остальные берутся из кэша.
+type loader[V any] struct {
+	mu    sync.Mutex
+	fetch func(ids []int) (map[int]V, error)
+	cache map[int]V
+}
+
+func newLoader[V any](fetch func(ids []int) (map[int]V, error)) *loader[V] {
+	return &loader[V]{fetch: fetch, cache: make(map[int]V)}
+}
+
+// load возвращает значение для id, при необходимости загружая его вместе с batch
+func (l *loader[V]) load(id int, batch []int) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if v, ok := l.cache[id]; ok {
+		return v, nil
+	}
+
+	keys := []int{id}
+	for _, k := range batch {
+		if _, ok := l.cache[k]; !ok && k != id {
+			keys = append(keys, k)
+		}
+	}
+
+	values, err := l.fetch(keys)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	// Отсутствующие ключи кэшируются нулевым значением: пустой список или 0
+	for _, k := range keys {
+		l.cache[k] = values[k]
+	}
+	return l.cache[id], nil
+}
+
+// statusLoaders загрузчики значений, зависящих от набора статусов сотрудников:
+// для каждого набора, встреченного в запросе, создаётся свой загрузчик
+type statusLoaders[V any] struct {
+	mu      sync.Mutex
+	fetch   func(ids []int, statuses []string) (map[int]V, error)
+	loaders map[string]*loader[V]
+}
+
+func newStatusLoaders[V any](fetch func(ids []int, statuses []string) (map[int]V, error)) *statusLoaders[V] {
+	return &statusLoaders[V]{fetch: fetch, loaders: make(map[string]*loader[V])}
+}
+
+// get возвращает загрузчик для нормализованного набора статусов; пустой набор — значение по умолчанию
+func (s *statusLoaders[V]) get(statuses []string) *loader[V] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := strings.Join(statuses, ",")
+	if l, ok := s.loaders[key]; ok {
+		return l
+	}
+	l := newLoader(func(ids []int) (map[int]V, error) { return s.fetch(ids, statuses) })
+	s.loaders[key] = l
+	return l
+}
+
+// loaders набор загрузчиков одного запроса
+type loaders struct {
+	departments *loader[model.Department]
+	children    *loader[[]model.Department]
+	ancestors   *loader[[]model.Department]
+	employees   *statusLoaders[[]model.Employee]
+	headcounts  *statusLoaders[int]
+}
+
+func newLoaders(svc *service.Service) *loaders {
+	return &loaders{
+		departments: newLoader(svc.LoadDepartments),
+		children:    newLoader(svc.LoadChildren),
+		ancestors:   newLoader(svc.LoadAncestors),
+		employees:   newStatusLoaders(svc.LoadEmployees),
+		headcounts:  newStatusLoaders(svc.LoadHeadcounts),
+	}
+}
+
+// group объекты одного уровня запроса, загружаемые одной пачкой.
+// Связанные объекты всех членов группы (например, все потомки) образуют
+// следующую группу, поэтому каждый уровень вложенности — один запрос на поле.
+type group struct {
+	ids  []int
+	mu   sync.Mutex
+	next map[string]*group
+}
+
+func newGroup(ids []int) *group {
+	return &group{ids: ids}
+}
+
+// nextGroup возвращает группу связанных объектов поля field; collect вызывается один раз
+func (g *group) nextGroup(field string, collect func() []int) *group {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if next, ok := g.next[field]; ok {
+		return next
+	}
+	if g.next == nil {
+		g.next = make(map[string]*group)
+	}
+	next := newGroup(collect())
+	g.next[field] = next
+	return next
+}
@@ -0,0 +1,467 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+type requestKey struct{}
+
+// request состояние одного запроса: сервис субъекта и загрузчики
+type request struct {
+	svc     *service.Service
+	loaders *loaders
+}
+
+func fromContext(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// resolver корневой резолвер запросов и мутаций
+type resolver struct{}
+
+func (r *resolver) Department(ctx context.Context, args struct{ ID int32 }) (*departmentResolver, error) {
+	req := fromContext(ctx)
+	dept, err := req.svc.GetDepartment(int(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	return req.department(*dept), nil
+}
+
+func (r *resolver) DepartmentByCode(ctx context.Context, args struct{ Code string }) (*departmentResolver, error) {
+	req := fromContext(ctx)
+	dept, err := req.svc.GetDepartmentByCode(args.Code)
+	if err != nil {
+		return nil, err
+	}
+	return req.department(*dept), nil
+}
+
+func (r *resolver) Departments(ctx context.Context) ([]*departmentResolver, error) {
+	req := fromContext(ctx)
+	roots, err := req.svc.ListRootDepartments()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(roots))
+	for i, d := range roots {
+		ids[i] = d.ID
+	}
+	return req.departments(roots, newGroup(ids)), nil
+}
+
+func (r *resolver) Employee(ctx context.Context, args struct {
+	DepartmentID int32
+	ID           int32
+}) (*employeeResolver, error) {
+	req := fromContext(ctx)
+	emp, err := req.svc.GetEmployee(int(args.DepartmentID), int(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	return req.employee(*emp), nil
+}
+
+type createDepartmentInput struct {
+	Name        string
+	ParentID    *int32
+	Code        *string
+	CostCenter  *string
+	Description *string
+	Active      *bool
+}
+
+func (r *resolver) CreateDepartment(ctx context.Context, args struct{ Input createDepartmentInput }) (*departmentResolver, error) {
+	req := fromContext(ctx)
+	dept, err := req.svc.CreateDepartment(model.CreateDepartmentRequest{
+		Name:        args.Input.Name,
+		ParentID:    intPtr(args.Input.ParentID),
+		Code:        args.Input.Code,
+		CostCenter:  args.Input.CostCenter,
+		Description: args.Input.Description,
+		Active:      args.Input.Active,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req.department(*dept), nil
+}
+
+type updateDepartmentInput struct {
+	Name        graphqlgo.NullString
+	ParentID    graphqlgo.NullInt
+	Code        graphqlgo.NullString
+	CostCenter  graphqlgo.NullString
+	Description graphqlgo.NullString
+	Active      graphqlgo.NullBool
+}
+
+func (r *resolver) UpdateDepartment(ctx context.Context, args struct {
+	ID      int32
+	Input   updateDepartmentInput
+	Version *int32
+}) (*departmentResolver, error) {
+	req := fromContext(ctx)
+	patch := model.DepartmentPatch{
+		Name:        optionalString(args.Input.Name),
+		ParentID:    optionalInt(args.Input.ParentID),
+		Code:        optionalString(args.Input.Code),
+		CostCenter:  optionalString(args.Input.CostCenter),
+		Description: optionalString(args.Input.Description),
+		Active:      optionalBool(args.Input.Active),
+	}
+	dept, err := req.svc.PatchDepartment(int(args.ID), patch, version(args.Version))
+	if err != nil {
+		return nil, mutationError(err)
+	}
+	return req.department(*dept), nil
+}
+
+func (r *resolver) DeleteDepartment(ctx context.Context, args struct {
+	ID                     int32
+	Mode                   string
+	ReassignToDepartmentID *int32
+	Version                *int32
+}) (bool, error) {
+	req := fromContext(ctx)
+	mode := strings.ToLower(args.Mode)
+	if err := req.svc.DeleteDepartment(int(args.ID), mode, intPtr(args.ReassignToDepartmentID), version(args.Version)); err != nil {
+		return false, mutationError(err)
+	}
+	return true, nil
+}
+
+type createEmployeeInput struct {
+	FullName   string
+	Position   string
+	PositionID *int32
+	HiredAt    *string
+	Status     *string
+}
+
+func (r *resolver) CreateEmployee(ctx context.Context, args struct {
+	DepartmentID int32
+	Input        createEmployeeInput
+}) (*employeeResolver, error) {
+	req := fromContext(ctx)
+	emp, err := req.svc.CreateEmployee(int(args.DepartmentID), model.CreateEmployeeRequest{
+		FullName:   args.Input.FullName,
+		Position:   args.Input.Position,
+		PositionID: intPtr(args.Input.PositionID),
+		HiredAt:    args.Input.HiredAt,
+		Status:     employeeStatus(args.Input.Status),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return req.employee(*emp), nil
+}
+
+type updateEmployeeInput struct {
+	FullName     graphqlgo.NullString
+	Position     graphqlgo.NullString
+	HiredAt      graphqlgo.NullString
+	DepartmentID graphqlgo.NullInt
+}
+
+func (r *resolver) UpdateEmployee(ctx context.Context, args struct {
+	DepartmentID int32
+	ID           int32
+	Input        updateEmployeeInput
+	Version      *int32
+}) (*employeeResolver, error) {
+	req := fromContext(ctx)
+	patch := model.EmployeePatch{
+		FullName:     optionalString(args.Input.FullName),
+		Position:     optionalString(args.Input.Position),
+		HiredAt:      optionalString(args.Input.HiredAt),
+		DepartmentID: optionalInt(args.Input.DepartmentID),
+	}
+	emp, err := req.svc.PatchEmployee(int(args.DepartmentID), int(args.ID), patch, version(args.Version))
+	if err != nil {
+		return nil, err
+	}
+	return req.employee(*emp), nil
+}
+
+type changeEmployeeStatusInput struct {
+	Status        string
+	EffectiveDate *string
+	Reason        *string
+}
+
+func (r *resolver) ChangeEmployeeStatus(ctx context.Context, args struct {
+	DepartmentID int32
+	ID           int32
+	Input        changeEmployeeStatusInput
+	Version      *int32
+}) (*employeeResolver, error) {
+	req := fromContext(ctx)
+	emp, err := req.svc.ChangeEmployeeStatus(int(args.DepartmentID), int(args.ID), model.ChangeEmployeeStatusRequest{
+		Status:        strings.ToLower(args.Input.Status),
+		EffectiveDate: args.Input.EffectiveDate,
+		Reason:        args.Input.Reason,
+	}, version(args.Version))
+	if err != nil {
+		return nil, err
+	}
+	return req.employee(*emp), nil
+}
+
+// department создаёт резолвер подразделения вне списка
+func (req *request) department(dept model.Department) *departmentResolver {
+	return &departmentResolver{req: req, dept: dept, group: newGroup([]int{dept.ID})}
+}
+
+// departments создаёт резолверы списка; g — группа, в которую входят все подразделения списка
+func (req *request) departments(depts []model.Department, g *group) []*departmentResolver {
+	resolvers := make([]*departmentResolver, len(depts))
+	for i, d := range depts {
+		resolvers[i] = &departmentResolver{req: req, dept: d, group: g}
+	}
+	return resolvers
+}
+
+func (req *request) employee(emp model.Employee) *employeeResolver {
+	return &employeeResolver{req: req, emp: emp, group: newGroup([]int{emp.DepartmentID})}
+}
+
+// employees создаёт резолверы списка; g — группа подразделений этих сотрудников
+func (req *request) employees(emps []model.Employee, g *group) []*employeeResolver {
+	resolvers := make([]*employeeResolver, len(emps))
+	for i, e := range emps {
+		resolvers[i] = &employeeResolver{req: req, emp: e, group: g}
+	}
+	return resolvers
+}
+
+// departmentIDs собирает ID подразделений из уже загруженных значений группы без повторов
+func departmentIDs(l *loader[[]model.Department], g *group) []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, id := range g.ids {
+		depts, _ := l.load(id, g.ids)
+		for _, d := range depts {
+			if !seen[d.ID] {
+				seen[d.ID] = true
+				ids = append(ids, d.ID)
+			}
+		}
+	}
+	return ids
+}
+
+type departmentResolver struct {
+	req   *request
+	dept  model.Department
+	group *group
+}
+
+func (d *departmentResolver) ID() int32            { return int32(d.dept.ID) }
+func (d *departmentResolver) Name() string         { return d.dept.Name }
+func (d *departmentResolver) ParentID() *int32     { return int32Ptr(d.dept.ParentID) }
+func (d *departmentResolver) Code() *string        { return d.dept.Code }
+func (d *departmentResolver) CostCenter() *string  { return d.dept.CostCenter }
+func (d *departmentResolver) Description() *string { return d.dept.Description }
+func (d *departmentResolver) Active() bool         { return d.dept.Active }
+func (d *departmentResolver) SortOrder() int32     { return int32(d.dept.SortOrder) }
+func (d *departmentResolver) Version() int32       { return int32(d.dept.Version) }
+func (d *departmentResolver) CreatedAt() string    { return d.dept.CreatedAt.Format(time.RFC3339) }
+func (d *departmentResolver) UpdatedAt() string    { return d.dept.UpdatedAt.Format(time.RFC3339) }
+
+func (d *departmentResolver) Children() ([]*departmentResolver, error) {
+	l := d.req.loaders.children
+	children, err := l.load(d.dept.ID, d.group.ids)
+	if err != nil {
+		return nil, err
+	}
+	next := d.group.nextGroup("children", func() []int { return departmentIDs(l, d.group) })
+	return d.req.departments(children, next), nil
+}
+
+func (d *departmentResolver) Ancestors() ([]*departmentResolver, error) {
+	l := d.req.loaders.ancestors
+	ancestors, err := l.load(d.dept.ID, d.group.ids)
+	if err != nil {
+		return nil, err
+	}
+	next := d.group.nextGroup("ancestors", func() []int { return departmentIDs(l, d.group) })
+	return d.req.departments(ancestors, next), nil
+}
+
+// statusArgs аргумент status полей employees и headcount; по умолчанию — только работающие
+type statusArgs struct {
+	Status *[]string
+}
+
+func (d *departmentResolver) Employees(args statusArgs) ([]*employeeResolver, error) {
+	employees, err := d.req.loaders.employees.get(employeeStatuses(args.Status)).load(d.dept.ID, d.group.ids)
+	if err != nil {
+		return nil, err
+	}
+	return d.req.employees(employees, d.group), nil
+}
+
+func (d *departmentResolver) Headcount(args statusArgs) (int32, error) {
+	count, err := d.req.loaders.headcounts.get(employeeStatuses(args.Status)).load(d.dept.ID, d.group.ids)
+	return int32(count), err
+}
+
+type employeeResolver struct {
+	req   *request
+	emp   model.Employee
+	group *group
+}
+
+func (e *employeeResolver) ID() int32                  { return int32(e.emp.ID) }
+func (e *employeeResolver) DepartmentID() int32        { return int32(e.emp.DepartmentID) }
+func (e *employeeResolver) FullName() string           { return e.emp.FullName }
+func (e *employeeResolver) Position() string           { return e.emp.Position }
+func (e *employeeResolver) PositionID() *int32         { return int32Ptr(e.emp.PositionID) }
+func (e *employeeResolver) Status() string             { return strings.ToUpper(e.emp.Status) }
+func (e *employeeResolver) TerminationReason() *string { return e.emp.TerminationReason }
+func (e *employeeResolver) Version() int32             { return int32(e.emp.Version) }
+func (e *employeeResolver) CreatedAt() string          { return e.emp.CreatedAt.Format(time.RFC3339) }
+func (e *employeeResolver) UpdatedAt() string          { return e.emp.UpdatedAt.Format(time.RFC3339) }
+
+func (e *employeeResolver) HiredAt() *string {
+	if e.emp.HiredAt == nil {
+		return nil
+	}
+	hiredAt := e.emp.HiredAt.Format("2006-01-02")
+	return &hiredAt
+}
+
+func (e *employeeResolver) TerminatedAt() *string {
+	if e.emp.TerminatedAt == nil {
+		return nil
+	}
+	terminatedAt := e.emp.TerminatedAt.Format("2006-01-02")
+	return &terminatedAt
+}
+
+func (e *employeeResolver) Department() (*departmentResolver, error) {
+	dept, err := e.req.loaders.departments.load(e.emp.DepartmentID, e.group.ids)
+	if err != nil {
+		return nil, err
+	}
+	if dept.ID == 0 {
+		return nil, service.ErrNotFound
+	}
+	return e.req.departments([]model.Department{dept}, e.group)[0], nil
+}
+
+// employeeStatus переводит значение перечисления EmployeeStatus в статус сервиса
+// approvalError ошибка мутации, отложенной до согласования. Расширения содержат код
+// APPROVAL_REQUIRED и идентификатор созданного запроса на изменение (как ответ 202 REST API).
+type approvalError struct {
+	err       error
+	requestID int
+}
+
+func (e *approvalError) Error() string { return e.err.Error() }
+func (e *approvalError) Unwrap() error { return e.err }
+
+func (e *approvalError) Extensions() map[string]any {
+	ext := map[string]any{"code": "APPROVAL_REQUIRED"}
+	if e.requestID != 0 {
+		ext["changeRequestId"] = e.requestID
+	}
+	return ext
+}
+
+// mutationError дополняет ошибку согласования расширениями GraphQL
+func mutationError(err error) error {
+	if !errors.Is(err, service.ErrApprovalRequired) {
+		return err
+	}
+	wrapped := &approvalError{err: err}
+	var approvalErr *service.ApprovalRequiredError
+	if errors.As(err, &approvalErr) && approvalErr.Request != nil {
+		wrapped.requestID = approvalErr.Request.ID
+	}
+	return wrapped
+}
+
+func employeeStatus(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	status := strings.ToLower(*v)
+	return &status
+}
+
+// employeeStatuses нормализует список статусов: нижний регистр, по порядку, без повторов
+func employeeStatuses(v *[]string) []string {
+	if v == nil {
+		return nil
+	}
+	statuses := make([]string, 0, len(*v))
+	for _, s := range *v {
+		statuses = append(statuses, strings.ToLower(s))
+	}
+	slices.Sort(statuses)
+	return slices.Compact(statuses)
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
+
+// version превращает необязательный аргумент version в ожидаемую версию; 0 — без проверки
+func version(v *int32) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
+}
+
+func optionalString(v graphqlgo.NullString) model.Optional[string] {
+	if !v.Set {
+		return model.Optional[string]{}
+	}
+	if v.Value == nil {
+		return model.Null[string]()
+	}
+	return model.Some(*v.Value)
+}
+
+func optionalInt(v graphqlgo.NullInt) model.Optional[int] {
+	if !v.Set {
+		return model.Optional[int]{}
+	}
+	if v.Value == nil {
+		return model.Null[int]()
+	}
+	return model.Some(int(*v.Value))
+}
+
+func optionalBool(v graphqlgo.NullBool) model.Optional[bool] {
+	if !v.Set {
+		return model.Optional[bool]{}
+	}
+	if v.Value == nil {
+		return model.Null[bool]()
+	}
+	return model.Some(*v.Value)
+}
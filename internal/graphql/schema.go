@@ -0,0 +1,149 @@
+// Package graphql реализует GraphQL API поверх сервисного слоя.
+// Вложенные поля загружаются пачками через загрузчики запроса, поэтому
+// число запросов к БД зависит от глубины запроса, а не от числа подразделений.
+package graphql
+
+import (
+	"context"
+
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	graphqlgo "github.com/graph-gophers/graphql-go"
+)
+
+// maxDepth ограничивает вложенность запроса
+const maxDepth = 12
+
+const schemaSDL = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	department(id: Int!): Department
+	departmentByCode(code: String!): Department
+	departments: [Department!]!
+	employee(departmentId: Int!, id: Int!): Employee
+}
+
+type Mutation {
+	createDepartment(input: CreateDepartmentInput!): Department!
+	updateDepartment(id: Int!, input: UpdateDepartmentInput!, version: Int): Department!
+	deleteDepartment(id: Int!, mode: DeleteMode = CASCADE, reassignToDepartmentId: Int, version: Int): Boolean!
+	createEmployee(departmentId: Int!, input: CreateEmployeeInput!): Employee!
+	updateEmployee(departmentId: Int!, id: Int!, input: UpdateEmployeeInput!, version: Int): Employee!
+	changeEmployeeStatus(departmentId: Int!, id: Int!, input: ChangeEmployeeStatusInput!, version: Int): Employee!
+}
+
+type Department {
+	id: Int!
+	name: String!
+	parentId: Int
+	code: String
+	costCenter: String
+	description: String
+	active: Boolean!
+	sortOrder: Int!
+	version: Int!
+	createdAt: String!
+	updatedAt: String!
+	children: [Department!]!
+	ancestors: [Department!]!
+	employees(status: [EmployeeStatus!]): [Employee!]!
+	headcount(status: [EmployeeStatus!]): Int!
+}
+
+type Employee {
+	id: Int!
+	departmentId: Int!
+	fullName: String!
+	position: String!
+	positionId: Int
+	hiredAt: String
+	status: EmployeeStatus!
+	terminatedAt: String
+	terminationReason: String
+	version: Int!
+	createdAt: String!
+	updatedAt: String!
+	department: Department!
+}
+
+enum EmployeeStatus {
+	PENDING
+	ACTIVE
+	ON_LEAVE
+	TERMINATED
+}
+
+enum DeleteMode {
+	CASCADE
+	REASSIGN
+}
+
+input CreateDepartmentInput {
+	name: String!
+	parentId: Int
+	code: String
+	costCenter: String
+	description: String
+	active: Boolean
+}
+
+input UpdateDepartmentInput {
+	name: String
+	parentId: Int
+	code: String
+	costCenter: String
+	description: String
+	active: Boolean
+}
+
+input CreateEmployeeInput {
+	fullName: String!
+	position: String!
+	positionId: Int
+	hiredAt: String
+	status: EmployeeStatus
+}
+
+input UpdateEmployeeInput {
+	fullName: String
+	position: String
+	hiredAt: String
+	departmentId: Int
+}
+
+input ChangeEmployeeStatusInput {
+	status: EmployeeStatus!
+	effectiveDate: String
+	reason: String
+}
+`
+
+// Request тело запроса GraphQL over HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Schema разобранная схема; создаётся один раз и используется всеми запросами
+type Schema struct {
+	schema *graphqlgo.Schema
+}
+
+// NewSchema разбирает схему и связывает её с резолверами
+func NewSchema() (*Schema, error) {
+	schema, err := graphqlgo.ParseSchema(schemaSDL, &resolver{}, graphqlgo.MaxDepth(maxDepth))
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Execute выполняет запрос от имени субъекта, которому принадлежит svc
+func (s *Schema) Execute(ctx context.Context, svc *service.Service, req Request) *graphqlgo.Response {
+	ctx = context.WithValue(ctx, requestKey{}, &request{svc: svc, loaders: newLoaders(svc)})
+	return s.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+}
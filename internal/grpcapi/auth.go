@@ -0,0 +1,68 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// healthServicePrefix методы проверки состояния доступны без аутентификации
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// authenticate определяет субъекта по метаданным authorization и x-tenant-id
+func authenticate(ctx context.Context, a *auth.Authenticator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, err := a.AuthenticateCredentials(first(md, "authorization"), first(md, strings.ToLower(auth.TenantHeader)))
+	if err != nil {
+		return nil, authStatus(err)
+	}
+	return auth.NewContext(ctx, p), nil
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// UnaryAuthInterceptor сохраняет субъекта вызова в контексте, как handler.Authenticate для HTTP
+func UnaryAuthInterceptor(a *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, a)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor аналог UnaryAuthInterceptor для потоковых вызовов
+func StreamAuthInterceptor(a *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context(), a)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream подменяет контекст потока контекстом с субъектом
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,107 @@
+package grpcapi
+
+import (
+	"github.com/SergeiKhy/org-structure-api/internal/grpcapi/orgv1"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func departmentToProto(d *model.Department) *orgv1.Department {
+	pb := &orgv1.Department{
+		Id:          int64(d.ID),
+		Name:        d.Name,
+		ParentId:    toOptionalID(d.ParentID),
+		Code:        d.Code,
+		CostCenter:  d.CostCenter,
+		Description: d.Description,
+		Active:      d.Active,
+		Version:     int32(d.Version),
+		CreatedAt:   timestamppb.New(d.CreatedAt),
+		UpdatedAt:   timestamppb.New(d.UpdatedAt),
+	}
+	for i := range d.Employees {
+		pb.Employees = append(pb.Employees, employeeToProto(&d.Employees[i]))
+	}
+	for i := range d.Children {
+		pb.Children = append(pb.Children, departmentToProto(&d.Children[i]))
+	}
+	return pb
+}
+
+func employeeToProto(e *model.Employee) *orgv1.Employee {
+	pb := &orgv1.Employee{
+		Id:                int64(e.ID),
+		DepartmentId:      int64(e.DepartmentID),
+		FullName:          e.FullName,
+		Position:          e.Position,
+		Version:           int32(e.Version),
+		CreatedAt:         timestamppb.New(e.CreatedAt),
+		UpdatedAt:         timestamppb.New(e.UpdatedAt),
+		PositionId:        toOptionalID(e.PositionID),
+		Status:            employeeStatusToProto[e.Status],
+		TerminationReason: e.TerminationReason,
+	}
+	if e.HiredAt != nil {
+		hiredAt := e.HiredAt.Format("2006-01-02")
+		pb.HiredAt = &hiredAt
+	}
+	if e.TerminatedAt != nil {
+		terminatedAt := e.TerminatedAt.Format("2006-01-02")
+		pb.TerminatedAt = &terminatedAt
+	}
+	return pb
+}
+
+var employeeStatusToProto = map[string]orgv1.EmployeeStatus{
+	model.EmployeePending:    orgv1.EmployeeStatus_EMPLOYEE_STATUS_PENDING,
+	model.EmployeeActive:     orgv1.EmployeeStatus_EMPLOYEE_STATUS_ACTIVE,
+	model.EmployeeOnLeave:    orgv1.EmployeeStatus_EMPLOYEE_STATUS_ON_LEAVE,
+	model.EmployeeTerminated: orgv1.EmployeeStatus_EMPLOYEE_STATUS_TERMINATED,
+}
+
+// employeeStatusFromProto переводит статус в значение сервиса; UNSPECIFIED — пустая строка
+func employeeStatusFromProto(s orgv1.EmployeeStatus) (string, error) {
+	if s == orgv1.EmployeeStatus_EMPLOYEE_STATUS_UNSPECIFIED {
+		return "", nil
+	}
+	for value, pb := range employeeStatusToProto {
+		if pb == s {
+			return value, nil
+		}
+	}
+	return "", status.Error(codes.InvalidArgument, "invalid employee status")
+}
+
+// employeeStatusesFromProto переводит список статусов; пустой список — значение по умолчанию сервиса
+func employeeStatusesFromProto(list []orgv1.EmployeeStatus) ([]string, error) {
+	var statuses []string
+	for _, s := range list {
+		value, err := employeeStatusFromProto(s)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" {
+			return nil, status.Error(codes.InvalidArgument, "invalid employee status")
+		}
+		statuses = append(statuses, value)
+	}
+	return statuses, nil
+}
+
+func toOptionalID(id *int) *int64 {
+	if id == nil {
+		return nil
+	}
+	v := int64(*id)
+	return &v
+}
+
+func fromOptionalID(id *int64) *int {
+	if id == nil {
+		return nil
+	}
+	v := int(*id)
+	return &v
+}
@@ -0,0 +1,57 @@
+package grpcapi
+
+import (
+	"errors"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus сопоставляет ошибки сервиса кодам gRPC по тем же категориям, что и REST API:
+// not found — NotFound (404), forbidden — PermissionDenied (403), устаревшая версия — Aborted (412),
+// конфликт со структурой дерева — FailedPrecondition/AlreadyExists (409), остальное — InvalidArgument (400)
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	// Изменение, отложенное до согласования, не выполнено; созданный запрос на изменение
+	// указывается в сообщении, как в ответе 202 REST API
+	var approvalErr *service.ApprovalRequiredError
+	if errors.As(err, &approvalErr) && approvalErr.Request != nil {
+		return status.Errorf(codes.FailedPrecondition, "%s: change request %d", err.Error(), approvalErr.Request.ID)
+	}
+	if errors.Is(err, service.ErrApprovalRequired) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	var code codes.Code
+	switch err {
+	case service.ErrNotFound:
+		code = codes.NotFound
+	case service.ErrForbidden:
+		code = codes.PermissionDenied
+	case service.ErrVersionMismatch:
+		code = codes.Aborted
+	case service.ErrDuplicateName, service.ErrDuplicateUserName, service.ErrDuplicateCode:
+		code = codes.AlreadyExists
+	case service.ErrCycleDetected, service.ErrSelfParent, service.ErrInvalidTransition:
+		code = codes.FailedPrecondition
+	default:
+		code = codes.InvalidArgument
+	}
+	return status.Error(code, err.Error())
+}
+
+// authStatus сопоставляет ошибки аутентификации так же, как handler.Authenticate
+func authStatus(err error) error {
+	if err == auth.ErrTenantMismatch {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Unauthenticated, err.Error())
+}
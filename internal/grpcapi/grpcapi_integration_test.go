@@ -0,0 +1,149 @@
+//go:build integration
+
+package grpcapi
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/grpcapi/orgv1"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupTestContainer создаёт контейнер с PostgreSQL для тестов
+func setupTestContainer(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB, context.Context) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	pgContainer, err := tcpostgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:15-alpine"),
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("test"),
+		tcpostgres.WithPassword("test"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		t.Fatalf("ошибка запуска контейнера: %v", err)
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("ошибка получения connection string: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("ошибка подключения к БД: %v", err)
+	}
+
+	err = db.AutoMigrate(&model.Department{}, &model.Employee{}, &model.RoleBinding{}, &model.OutboxEvent{}, &model.AttributeDefinition{}, &model.Position{}, &model.HeadcountBudget{}, &model.Requisition{}, &model.Scenario{}, &model.ScheduledChange{}, &model.ApprovalPolicy{}, &model.ChangeRequest{})
+	if err != nil {
+		t.Fatalf("ошибка миграции: %v", err)
+	}
+
+	return pgContainer, db, ctx
+}
+
+// TestServer_StreamSubtree_Integration проверяет обход поддерева по уровням и ограничение глубины
+func TestServer_StreamSubtree_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	svc := service.NewService(repository.NewRepository(db))
+	client := orgv1.NewOrgServiceClient(dial(t, NewGRPCServer(svc, auth.NewAuthenticator(false, "", nil))))
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Root"})
+	a, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A", ParentID: &root.ID})
+	b, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "B", ParentID: &root.ID})
+	svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A1", ParentID: &a.ID})
+	svc.CreateEmployee(b.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer"})
+
+	collect := func(req *orgv1.StreamSubtreeRequest) []*orgv1.SubtreeNode {
+		stream, err := client.StreamSubtree(ctx, req)
+		if err != nil {
+			t.Fatalf("ошибка вызова: %v", err)
+		}
+		var nodes []*orgv1.SubtreeNode
+		for {
+			node, err := stream.Recv()
+			if err == io.EOF {
+				return nodes
+			}
+			if err != nil {
+				t.Fatalf("ошибка получения: %v", err)
+			}
+			nodes = append(nodes, node)
+		}
+	}
+
+	nodes := collect(&orgv1.StreamSubtreeRequest{Id: int64(root.ID), IncludeEmployees: true})
+	var names []string
+	for _, n := range nodes {
+		names = append(names, n.Department.Name)
+	}
+	if len(nodes) != 4 || names[0] != "Root" || names[3] != "A1" || nodes[3].Depth != 2 {
+		t.Errorf("ожидался обход Root, A, B, A1, получено %v", names)
+	}
+	if len(nodes[2].Department.Employees) != 1 {
+		t.Errorf("ожидался 1 сотрудник в B, получено %d", len(nodes[2].Department.Employees))
+	}
+
+	if nodes := collect(&orgv1.StreamSubtreeRequest{Id: int64(root.ID), MaxDepth: 1}); len(nodes) != 3 {
+		t.Errorf("ожидалось 3 подразделения до глубины 1, получено %d", len(nodes))
+	}
+
+	stream, _ := client.StreamSubtree(ctx, &orgv1.StreamSubtreeRequest{Id: 9999})
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Errorf("ожидалась ошибка NotFound, получено %v", err)
+	}
+}
+
+// TestServer_UpdateDepartment_Integration проверяет изменение по маске и проверку версии
+func TestServer_UpdateDepartment_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	svc := service.NewService(repository.NewRepository(db))
+	client := orgv1.NewOrgServiceClient(dial(t, NewGRPCServer(svc, auth.NewAuthenticator(false, "", nil))))
+
+	root, err := client.CreateDepartment(ctx, &orgv1.CreateDepartmentRequest{Name: "Root"})
+	if err != nil {
+		t.Fatalf("ошибка создания: %v", err)
+	}
+	child, _ := client.CreateDepartment(ctx, &orgv1.CreateDepartmentRequest{Name: "Child", ParentId: &root.Id})
+
+	updated, err := client.UpdateDepartment(ctx, &orgv1.UpdateDepartmentRequest{
+		Id:              child.Id,
+		Department:      &orgv1.Department{},
+		UpdateMask:      &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}},
+		ExpectedVersion: 1,
+	})
+	if err != nil {
+		t.Fatalf("ошибка обновления: %v", err)
+	}
+	if updated.ParentId != nil || updated.Name != "Child" || updated.Version != 2 {
+		t.Errorf("ожидался корневой Child версии 2, получено %+v", updated)
+	}
+
+	_, err = client.DeleteDepartment(ctx, &orgv1.DeleteDepartmentRequest{Id: child.Id, ExpectedVersion: 1})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("ожидалась ошибка Aborted, получено %v", err)
+	}
+}
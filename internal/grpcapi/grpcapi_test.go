@@ -0,0 +1,155 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/grpcapi/orgv1"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// dial запускает сервер в памяти и возвращает подключённого клиента
+func dial(t *testing.T, srv *grpc.Server) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestToStatus проверяет сопоставление ошибок сервиса кодам gRPC
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{service.ErrNotFound, codes.NotFound},
+		{service.ErrForbidden, codes.PermissionDenied},
+		{service.ErrVersionMismatch, codes.Aborted},
+		{service.ErrDuplicateName, codes.AlreadyExists},
+		{service.ErrCycleDetected, codes.FailedPrecondition},
+		{service.ErrInvalidTransition, codes.FailedPrecondition},
+		{&service.ApprovalRequiredError{Request: &model.ChangeRequest{ID: 1}}, codes.FailedPrecondition},
+		{service.ErrApprovalNotDeferrable, codes.FailedPrecondition},
+		{errors.New("invalid name"), codes.InvalidArgument},
+		{status.Error(codes.Unavailable, "down"), codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		if code := status.Code(toStatus(tt.err)); code != tt.code {
+			t.Errorf("%v: expected %s, got %s", tt.err, tt.code, code)
+		}
+	}
+	if toStatus(nil) != nil {
+		t.Error("expected nil for nil error")
+	}
+	if msg := status.Convert(toStatus(&service.ApprovalRequiredError{Request: &model.ChangeRequest{ID: 12}})).Message(); !strings.Contains(msg, "change request 12") {
+		t.Errorf("expected change request id in message, got %q", msg)
+	}
+}
+
+// TestDepartmentPatch проверяет перевод маски полей в частичное изменение
+func TestDepartmentPatch(t *testing.T) {
+	parentID := int64(7)
+
+	patch, err := departmentPatch(&orgv1.Department{Name: "A", ParentId: &parentID}, &fieldmaskpb.FieldMask{Paths: []string{"name"}})
+	if err != nil || patch != (model.DepartmentPatch{Name: model.Some("A")}) {
+		t.Errorf("expected name only, got %+v (%v)", patch, err)
+	}
+
+	// parent_id в маске без значения — перенос в корень
+	patch, err = departmentPatch(&orgv1.Department{}, &fieldmaskpb.FieldMask{Paths: []string{"parent_id"}})
+	if err != nil || patch != (model.DepartmentPatch{ParentID: model.Null[int]()}) {
+		t.Errorf("expected null parent, got %+v (%v)", patch, err)
+	}
+
+	// Метаданные: code без значения очищается, active берётся из сообщения
+	costCenter := "CC-100"
+	patch, err = departmentPatch(&orgv1.Department{CostCenter: &costCenter}, &fieldmaskpb.FieldMask{Paths: []string{"code", "cost_center", "active"}})
+	expected := model.DepartmentPatch{Code: model.Null[string](), CostCenter: model.Some("CC-100"), Active: model.Some(false)}
+	if err != nil || patch != expected {
+		t.Errorf("expected %+v, got %+v (%v)", expected, patch, err)
+	}
+
+	for _, mask := range []*fieldmaskpb.FieldMask{nil, {Paths: []string{"version"}}} {
+		if _, err := departmentPatch(&orgv1.Department{}, mask); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", mask, err)
+		}
+	}
+}
+
+// TestEmployeePatch проверяет сброс hired_at и перевод в другое подразделение
+func TestEmployeePatch(t *testing.T) {
+	patch, err := employeePatch(&orgv1.Employee{DepartmentId: 3}, &fieldmaskpb.FieldMask{Paths: []string{"hired_at", "department_id"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := model.EmployeePatch{HiredAt: model.Null[string](), DepartmentID: model.Some(3)}
+	if patch != expected {
+		t.Errorf("expected %+v, got %+v", expected, patch)
+	}
+}
+
+// TestEmployeeStatusesFromProto проверяет перевод статусов; UNSPECIFIED в списке — ошибка
+func TestEmployeeStatusesFromProto(t *testing.T) {
+	statuses, err := employeeStatusesFromProto([]orgv1.EmployeeStatus{
+		orgv1.EmployeeStatus_EMPLOYEE_STATUS_ACTIVE, orgv1.EmployeeStatus_EMPLOYEE_STATUS_ON_LEAVE,
+	})
+	if err != nil || !reflect.DeepEqual(statuses, []string{model.EmployeeActive, model.EmployeeOnLeave}) {
+		t.Errorf("unexpected statuses %v (%v)", statuses, err)
+	}
+	if statuses, err := employeeStatusesFromProto(nil); err != nil || statuses != nil {
+		t.Errorf("expected default statuses, got %v (%v)", statuses, err)
+	}
+	for _, s := range []orgv1.EmployeeStatus{orgv1.EmployeeStatus_EMPLOYEE_STATUS_UNSPECIFIED, orgv1.EmployeeStatus(42)} {
+		if _, err := employeeStatusesFromProto([]orgv1.EmployeeStatus{s}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%v: expected InvalidArgument, got %v", s, err)
+		}
+	}
+}
+
+// TestServer_Authentication проверяет, что вызовы требуют аутентификации, а проверка состояния — нет
+func TestServer_Authentication(t *testing.T) {
+	authn := auth.NewAuthenticator(true, "secret", nil)
+	conn := dial(t, NewGRPCServer(service.NewService(nil), authn))
+	ctx := context.Background()
+
+	_, err := orgv1.NewOrgServiceClient(conn).GetDepartment(ctx, &orgv1.GetDepartmentRequest{Id: 1})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated, got %v", err)
+	}
+
+	stream, err := orgv1.NewOrgServiceClient(conn).StreamSubtree(ctx, &orgv1.StreamSubtreeRequest{Id: 1})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("stream: expected Unauthenticated, got %v", err)
+	}
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: orgv1.OrgService_ServiceDesc.ServiceName})
+	if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected SERVING, got %v (%v)", resp, err)
+	}
+}
@@ -0,0 +1,1369 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: org/v1/org.proto
+
+package orgv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EmployeeStatus int32
+
+const (
+	EmployeeStatus_EMPLOYEE_STATUS_UNSPECIFIED EmployeeStatus = 0
+	EmployeeStatus_EMPLOYEE_STATUS_PENDING     EmployeeStatus = 1
+	EmployeeStatus_EMPLOYEE_STATUS_ACTIVE      EmployeeStatus = 2
+	EmployeeStatus_EMPLOYEE_STATUS_ON_LEAVE    EmployeeStatus = 3
+	EmployeeStatus_EMPLOYEE_STATUS_TERMINATED  EmployeeStatus = 4
+)
+
+// Enum value maps for EmployeeStatus.
+var (
+	EmployeeStatus_name = map[int32]string{
+		0: "EMPLOYEE_STATUS_UNSPECIFIED",
+		1: "EMPLOYEE_STATUS_PENDING",
+		2: "EMPLOYEE_STATUS_ACTIVE",
+		3: "EMPLOYEE_STATUS_ON_LEAVE",
+		4: "EMPLOYEE_STATUS_TERMINATED",
+	}
+	EmployeeStatus_value = map[string]int32{
+		"EMPLOYEE_STATUS_UNSPECIFIED": 0,
+		"EMPLOYEE_STATUS_PENDING":     1,
+		"EMPLOYEE_STATUS_ACTIVE":      2,
+		"EMPLOYEE_STATUS_ON_LEAVE":    3,
+		"EMPLOYEE_STATUS_TERMINATED":  4,
+	}
+)
+
+func (x EmployeeStatus) Enum() *EmployeeStatus {
+	p := new(EmployeeStatus)
+	*p = x
+	return p
+}
+
+func (x EmployeeStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EmployeeStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_org_v1_org_proto_enumTypes[0].Descriptor()
+}
+
+func (EmployeeStatus) Type() protoreflect.EnumType {
+	return &file_org_v1_org_proto_enumTypes[0]
+}
+
+func (x EmployeeStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EmployeeStatus.Descriptor instead.
+func (EmployeeStatus) EnumDescriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{0}
+}
+
+type DeleteMode int32
+
+const (
+	DeleteMode_DELETE_MODE_UNSPECIFIED DeleteMode = 0
+	DeleteMode_DELETE_MODE_CASCADE     DeleteMode = 1
+	DeleteMode_DELETE_MODE_REASSIGN    DeleteMode = 2
+)
+
+// Enum value maps for DeleteMode.
+var (
+	DeleteMode_name = map[int32]string{
+		0: "DELETE_MODE_UNSPECIFIED",
+		1: "DELETE_MODE_CASCADE",
+		2: "DELETE_MODE_REASSIGN",
+	}
+	DeleteMode_value = map[string]int32{
+		"DELETE_MODE_UNSPECIFIED": 0,
+		"DELETE_MODE_CASCADE":     1,
+		"DELETE_MODE_REASSIGN":    2,
+	}
+)
+
+func (x DeleteMode) Enum() *DeleteMode {
+	p := new(DeleteMode)
+	*p = x
+	return p
+}
+
+func (x DeleteMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeleteMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_org_v1_org_proto_enumTypes[1].Descriptor()
+}
+
+func (DeleteMode) Type() protoreflect.EnumType {
+	return &file_org_v1_org_proto_enumTypes[1]
+}
+
+func (x DeleteMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeleteMode.Descriptor instead.
+func (DeleteMode) EnumDescriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{1}
+}
+
+type Department struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ParentId      *int64                 `protobuf:"varint,3,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Employees     []*Employee            `protobuf:"bytes,7,rep,name=employees,proto3" json:"employees,omitempty"`
+	Children      []*Department          `protobuf:"bytes,8,rep,name=children,proto3" json:"children,omitempty"`
+	Code          *string                `protobuf:"bytes,9,opt,name=code,proto3,oneof" json:"code,omitempty"`
+	CostCenter    *string                `protobuf:"bytes,10,opt,name=cost_center,json=costCenter,proto3,oneof" json:"cost_center,omitempty"`
+	Description   *string                `protobuf:"bytes,11,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	Active        bool                   `protobuf:"varint,12,opt,name=active,proto3" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Department) Reset() {
+	*x = Department{}
+	mi := &file_org_v1_org_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Department) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Department) ProtoMessage() {}
+
+func (x *Department) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Department.ProtoReflect.Descriptor instead.
+func (*Department) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Department) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Department) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Department) GetParentId() int64 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+func (x *Department) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Department) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Department) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Department) GetEmployees() []*Employee {
+	if x != nil {
+		return x.Employees
+	}
+	return nil
+}
+
+func (x *Department) GetChildren() []*Department {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *Department) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
+	}
+	return ""
+}
+
+func (x *Department) GetCostCenter() string {
+	if x != nil && x.CostCenter != nil {
+		return *x.CostCenter
+	}
+	return ""
+}
+
+func (x *Department) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *Department) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+type Employee struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	DepartmentId int64                  `protobuf:"varint,2,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	FullName     string                 `protobuf:"bytes,3,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Position     string                 `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
+	// Дата приёма в формате YYYY-MM-DD
+	HiredAt   *string                `protobuf:"bytes,5,opt,name=hired_at,json=hiredAt,proto3,oneof" json:"hired_at,omitempty"`
+	Version   int32                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status    EmployeeStatus         `protobuf:"varint,9,opt,name=status,proto3,enum=org.v1.EmployeeStatus" json:"status,omitempty"`
+	// Дата увольнения в формате YYYY-MM-DD
+	TerminatedAt      *string `protobuf:"bytes,10,opt,name=terminated_at,json=terminatedAt,proto3,oneof" json:"terminated_at,omitempty"`
+	TerminationReason *string `protobuf:"bytes,11,opt,name=termination_reason,json=terminationReason,proto3,oneof" json:"termination_reason,omitempty"`
+	// Должность из каталога; position — её название
+	PositionId    *int64 `protobuf:"varint,12,opt,name=position_id,json=positionId,proto3,oneof" json:"position_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Employee) Reset() {
+	*x = Employee{}
+	mi := &file_org_v1_org_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Employee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Employee) ProtoMessage() {}
+
+func (x *Employee) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Employee.ProtoReflect.Descriptor instead.
+func (*Employee) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Employee) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Employee) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *Employee) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *Employee) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Employee) GetHiredAt() string {
+	if x != nil && x.HiredAt != nil {
+		return *x.HiredAt
+	}
+	return ""
+}
+
+func (x *Employee) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Employee) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Employee) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Employee) GetStatus() EmployeeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return EmployeeStatus_EMPLOYEE_STATUS_UNSPECIFIED
+}
+
+func (x *Employee) GetTerminatedAt() string {
+	if x != nil && x.TerminatedAt != nil {
+		return *x.TerminatedAt
+	}
+	return ""
+}
+
+func (x *Employee) GetTerminationReason() string {
+	if x != nil && x.TerminationReason != nil {
+		return *x.TerminationReason
+	}
+	return ""
+}
+
+func (x *Employee) GetPositionId() int64 {
+	if x != nil && x.PositionId != nil {
+		return *x.PositionId
+	}
+	return 0
+}
+
+type CreateDepartmentRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ParentId    *int64                 `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	Code        *string                `protobuf:"bytes,3,opt,name=code,proto3,oneof" json:"code,omitempty"`
+	CostCenter  *string                `protobuf:"bytes,4,opt,name=cost_center,json=costCenter,proto3,oneof" json:"cost_center,omitempty"`
+	Description *string                `protobuf:"bytes,5,opt,name=description,proto3,oneof" json:"description,omitempty"`
+	// По умолчанию подразделение активно
+	Active        *bool `protobuf:"varint,6,opt,name=active,proto3,oneof" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDepartmentRequest) Reset() {
+	*x = CreateDepartmentRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDepartmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDepartmentRequest) ProtoMessage() {}
+
+func (x *CreateDepartmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDepartmentRequest.ProtoReflect.Descriptor instead.
+func (*CreateDepartmentRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateDepartmentRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateDepartmentRequest) GetParentId() int64 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+func (x *CreateDepartmentRequest) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
+	}
+	return ""
+}
+
+func (x *CreateDepartmentRequest) GetCostCenter() string {
+	if x != nil && x.CostCenter != nil {
+		return *x.CostCenter
+	}
+	return ""
+}
+
+func (x *CreateDepartmentRequest) GetDescription() string {
+	if x != nil && x.Description != nil {
+		return *x.Description
+	}
+	return ""
+}
+
+func (x *CreateDepartmentRequest) GetActive() bool {
+	if x != nil && x.Active != nil {
+		return *x.Active
+	}
+	return false
+}
+
+type GetDepartmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Глубина дерева 1..5, по умолчанию 1
+	Depth int32 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	// По умолчанию сотрудники включаются
+	IncludeEmployees *bool `protobuf:"varint,3,opt,name=include_employees,json=includeEmployees,proto3,oneof" json:"include_employees,omitempty"`
+	// Статусы включаемых сотрудников; по умолчанию только ACTIVE
+	EmployeeStatuses []EmployeeStatus `protobuf:"varint,4,rep,packed,name=employee_statuses,json=employeeStatuses,proto3,enum=org.v1.EmployeeStatus" json:"employee_statuses,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetDepartmentRequest) Reset() {
+	*x = GetDepartmentRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDepartmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDepartmentRequest) ProtoMessage() {}
+
+func (x *GetDepartmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDepartmentRequest.ProtoReflect.Descriptor instead.
+func (*GetDepartmentRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetDepartmentRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetDepartmentRequest) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *GetDepartmentRequest) GetIncludeEmployees() bool {
+	if x != nil && x.IncludeEmployees != nil {
+		return *x.IncludeEmployees
+	}
+	return false
+}
+
+func (x *GetDepartmentRequest) GetEmployeeStatuses() []EmployeeStatus {
+	if x != nil {
+		return x.EmployeeStatuses
+	}
+	return nil
+}
+
+type UpdateDepartmentRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Department *Department            `protobuf:"bytes,2,opt,name=department,proto3" json:"department,omitempty"`
+	// Изменяемые поля: name, parent_id, code, cost_center, description, active.
+	// parent_id в маске без значения — перенос в корень, code, cost_center и description — очистка.
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Ожидаемая версия (аналог If-Match); 0 — без проверки
+	ExpectedVersion int32 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateDepartmentRequest) Reset() {
+	*x = UpdateDepartmentRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDepartmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDepartmentRequest) ProtoMessage() {}
+
+func (x *UpdateDepartmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDepartmentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDepartmentRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateDepartmentRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateDepartmentRequest) GetDepartment() *Department {
+	if x != nil {
+		return x.Department
+	}
+	return nil
+}
+
+func (x *UpdateDepartmentRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateDepartmentRequest) GetExpectedVersion() int32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteDepartmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// По умолчанию CASCADE
+	Mode                   DeleteMode `protobuf:"varint,2,opt,name=mode,proto3,enum=org.v1.DeleteMode" json:"mode,omitempty"`
+	ReassignToDepartmentId *int64     `protobuf:"varint,3,opt,name=reassign_to_department_id,json=reassignToDepartmentId,proto3,oneof" json:"reassign_to_department_id,omitempty"`
+	ExpectedVersion        int32      `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *DeleteDepartmentRequest) Reset() {
+	*x = DeleteDepartmentRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDepartmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDepartmentRequest) ProtoMessage() {}
+
+func (x *DeleteDepartmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDepartmentRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDepartmentRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteDepartmentRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteDepartmentRequest) GetMode() DeleteMode {
+	if x != nil {
+		return x.Mode
+	}
+	return DeleteMode_DELETE_MODE_UNSPECIFIED
+}
+
+func (x *DeleteDepartmentRequest) GetReassignToDepartmentId() int64 {
+	if x != nil && x.ReassignToDepartmentId != nil {
+		return *x.ReassignToDepartmentId
+	}
+	return 0
+}
+
+func (x *DeleteDepartmentRequest) GetExpectedVersion() int32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type StreamSubtreeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Максимальная глубина относительно корня; 0 — всё поддерево
+	MaxDepth         int32 `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
+	IncludeEmployees bool  `protobuf:"varint,3,opt,name=include_employees,json=includeEmployees,proto3" json:"include_employees,omitempty"`
+	// Статусы включаемых сотрудников; по умолчанию только ACTIVE
+	EmployeeStatuses []EmployeeStatus `protobuf:"varint,4,rep,packed,name=employee_statuses,json=employeeStatuses,proto3,enum=org.v1.EmployeeStatus" json:"employee_statuses,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *StreamSubtreeRequest) Reset() {
+	*x = StreamSubtreeRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSubtreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSubtreeRequest) ProtoMessage() {}
+
+func (x *StreamSubtreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSubtreeRequest.ProtoReflect.Descriptor instead.
+func (*StreamSubtreeRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StreamSubtreeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StreamSubtreeRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *StreamSubtreeRequest) GetIncludeEmployees() bool {
+	if x != nil {
+		return x.IncludeEmployees
+	}
+	return false
+}
+
+func (x *StreamSubtreeRequest) GetEmployeeStatuses() []EmployeeStatus {
+	if x != nil {
+		return x.EmployeeStatuses
+	}
+	return nil
+}
+
+type SubtreeNode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Подразделение без потомков
+	Department *Department `protobuf:"bytes,1,opt,name=department,proto3" json:"department,omitempty"`
+	// Глубина относительно корня обхода (корень — 0)
+	Depth         int32 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubtreeNode) Reset() {
+	*x = SubtreeNode{}
+	mi := &file_org_v1_org_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubtreeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubtreeNode) ProtoMessage() {}
+
+func (x *SubtreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubtreeNode.ProtoReflect.Descriptor instead.
+func (*SubtreeNode) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SubtreeNode) GetDepartment() *Department {
+	if x != nil {
+		return x.Department
+	}
+	return nil
+}
+
+func (x *SubtreeNode) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+type CreateEmployeeRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	DepartmentId int64                  `protobuf:"varint,1,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	FullName     string                 `protobuf:"bytes,2,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Position     string                 `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	HiredAt      *string                `protobuf:"bytes,4,opt,name=hired_at,json=hiredAt,proto3,oneof" json:"hired_at,omitempty"`
+	// PENDING или ACTIVE; по умолчанию ACTIVE
+	Status EmployeeStatus `protobuf:"varint,5,opt,name=status,proto3,enum=org.v1.EmployeeStatus" json:"status,omitempty"`
+	// Должность из каталога; position можно не указывать
+	PositionId    *int64 `protobuf:"varint,6,opt,name=position_id,json=positionId,proto3,oneof" json:"position_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEmployeeRequest) Reset() {
+	*x = CreateEmployeeRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEmployeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEmployeeRequest) ProtoMessage() {}
+
+func (x *CreateEmployeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEmployeeRequest.ProtoReflect.Descriptor instead.
+func (*CreateEmployeeRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateEmployeeRequest) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *CreateEmployeeRequest) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetHiredAt() string {
+	if x != nil && x.HiredAt != nil {
+		return *x.HiredAt
+	}
+	return ""
+}
+
+func (x *CreateEmployeeRequest) GetStatus() EmployeeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return EmployeeStatus_EMPLOYEE_STATUS_UNSPECIFIED
+}
+
+func (x *CreateEmployeeRequest) GetPositionId() int64 {
+	if x != nil && x.PositionId != nil {
+		return *x.PositionId
+	}
+	return 0
+}
+
+type GetEmployeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DepartmentId  int64                  `protobuf:"varint,1,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Id            int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEmployeeRequest) Reset() {
+	*x = GetEmployeeRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEmployeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEmployeeRequest) ProtoMessage() {}
+
+func (x *GetEmployeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEmployeeRequest.ProtoReflect.Descriptor instead.
+func (*GetEmployeeRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetEmployeeRequest) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *GetEmployeeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type UpdateEmployeeRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	DepartmentId int64                  `protobuf:"varint,1,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Id           int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Employee     *Employee              `protobuf:"bytes,3,opt,name=employee,proto3" json:"employee,omitempty"`
+	// Изменяемые поля: full_name, position, hired_at, department_id
+	UpdateMask      *fieldmaskpb.FieldMask `protobuf:"bytes,4,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	ExpectedVersion int32                  `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateEmployeeRequest) Reset() {
+	*x = UpdateEmployeeRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateEmployeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateEmployeeRequest) ProtoMessage() {}
+
+func (x *UpdateEmployeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateEmployeeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateEmployeeRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateEmployeeRequest) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *UpdateEmployeeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateEmployeeRequest) GetEmployee() *Employee {
+	if x != nil {
+		return x.Employee
+	}
+	return nil
+}
+
+func (x *UpdateEmployeeRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateEmployeeRequest) GetExpectedVersion() int32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ChangeEmployeeStatusRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	DepartmentId int64                  `protobuf:"varint,1,opt,name=department_id,json=departmentId,proto3" json:"department_id,omitempty"`
+	Id           int64                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Status       EmployeeStatus         `protobuf:"varint,3,opt,name=status,proto3,enum=org.v1.EmployeeStatus" json:"status,omitempty"`
+	// Только для TERMINATED: дата увольнения YYYY-MM-DD (по умолчанию сегодня) и причина
+	EffectiveDate   *string `protobuf:"bytes,4,opt,name=effective_date,json=effectiveDate,proto3,oneof" json:"effective_date,omitempty"`
+	Reason          *string `protobuf:"bytes,5,opt,name=reason,proto3,oneof" json:"reason,omitempty"`
+	ExpectedVersion int32   `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeEmployeeStatusRequest) Reset() {
+	*x = ChangeEmployeeStatusRequest{}
+	mi := &file_org_v1_org_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEmployeeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEmployeeStatusRequest) ProtoMessage() {}
+
+func (x *ChangeEmployeeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_org_v1_org_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEmployeeStatusRequest.ProtoReflect.Descriptor instead.
+func (*ChangeEmployeeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_org_v1_org_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ChangeEmployeeStatusRequest) GetDepartmentId() int64 {
+	if x != nil {
+		return x.DepartmentId
+	}
+	return 0
+}
+
+func (x *ChangeEmployeeStatusRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChangeEmployeeStatusRequest) GetStatus() EmployeeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return EmployeeStatus_EMPLOYEE_STATUS_UNSPECIFIED
+}
+
+func (x *ChangeEmployeeStatusRequest) GetEffectiveDate() string {
+	if x != nil && x.EffectiveDate != nil {
+		return *x.EffectiveDate
+	}
+	return ""
+}
+
+func (x *ChangeEmployeeStatusRequest) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *ChangeEmployeeStatusRequest) GetExpectedVersion() int32 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+var File_org_v1_org_proto protoreflect.FileDescriptor
+
+const file_org_v1_org_proto_rawDesc = "" +
+	"\n" +
+	"\x10org/v1/org.proto\x12\x06org.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf7\x03\n" +
+	"\n" +
+	"Department\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\tparent_id\x18\x03 \x01(\x03H\x00R\bparentId\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12.\n" +
+	"\temployees\x18\a \x03(\v2\x10.org.v1.EmployeeR\temployees\x12.\n" +
+	"\bchildren\x18\b \x03(\v2\x12.org.v1.DepartmentR\bchildren\x12\x17\n" +
+	"\x04code\x18\t \x01(\tH\x01R\x04code\x88\x01\x01\x12$\n" +
+	"\vcost_center\x18\n" +
+	" \x01(\tH\x02R\n" +
+	"costCenter\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\v \x01(\tH\x03R\vdescription\x88\x01\x01\x12\x16\n" +
+	"\x06active\x18\f \x01(\bR\x06activeB\f\n" +
+	"\n" +
+	"_parent_idB\a\n" +
+	"\x05_codeB\x0e\n" +
+	"\f_cost_centerB\x0e\n" +
+	"\f_description\"\xa2\x04\n" +
+	"\bEmployee\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12#\n" +
+	"\rdepartment_id\x18\x02 \x01(\x03R\fdepartmentId\x12\x1b\n" +
+	"\tfull_name\x18\x03 \x01(\tR\bfullName\x12\x1a\n" +
+	"\bposition\x18\x04 \x01(\tR\bposition\x12\x1e\n" +
+	"\bhired_at\x18\x05 \x01(\tH\x00R\ahiredAt\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\x06 \x01(\x05R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12.\n" +
+	"\x06status\x18\t \x01(\x0e2\x16.org.v1.EmployeeStatusR\x06status\x12(\n" +
+	"\rterminated_at\x18\n" +
+	" \x01(\tH\x01R\fterminatedAt\x88\x01\x01\x122\n" +
+	"\x12termination_reason\x18\v \x01(\tH\x02R\x11terminationReason\x88\x01\x01\x12$\n" +
+	"\vposition_id\x18\f \x01(\x03H\x03R\n" +
+	"positionId\x88\x01\x01B\v\n" +
+	"\t_hired_atB\x10\n" +
+	"\x0e_terminated_atB\x15\n" +
+	"\x13_termination_reasonB\x0e\n" +
+	"\f_position_id\"\x94\x02\n" +
+	"\x17CreateDepartmentRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\tparent_id\x18\x02 \x01(\x03H\x00R\bparentId\x88\x01\x01\x12\x17\n" +
+	"\x04code\x18\x03 \x01(\tH\x01R\x04code\x88\x01\x01\x12$\n" +
+	"\vcost_center\x18\x04 \x01(\tH\x02R\n" +
+	"costCenter\x88\x01\x01\x12%\n" +
+	"\vdescription\x18\x05 \x01(\tH\x03R\vdescription\x88\x01\x01\x12\x1b\n" +
+	"\x06active\x18\x06 \x01(\bH\x04R\x06active\x88\x01\x01B\f\n" +
+	"\n" +
+	"_parent_idB\a\n" +
+	"\x05_codeB\x0e\n" +
+	"\f_cost_centerB\x0e\n" +
+	"\f_descriptionB\t\n" +
+	"\a_active\"\xc9\x01\n" +
+	"\x14GetDepartmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\x120\n" +
+	"\x11include_employees\x18\x03 \x01(\bH\x00R\x10includeEmployees\x88\x01\x01\x12C\n" +
+	"\x11employee_statuses\x18\x04 \x03(\x0e2\x16.org.v1.EmployeeStatusR\x10employeeStatusesB\x14\n" +
+	"\x12_include_employees\"\xc5\x01\n" +
+	"\x17UpdateDepartmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x122\n" +
+	"\n" +
+	"department\x18\x02 \x01(\v2\x12.org.v1.DepartmentR\n" +
+	"department\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x05R\x0fexpectedVersion\"\xda\x01\n" +
+	"\x17DeleteDepartmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12&\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x12.org.v1.DeleteModeR\x04mode\x12>\n" +
+	"\x19reassign_to_department_id\x18\x03 \x01(\x03H\x00R\x16reassignToDepartmentId\x88\x01\x01\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x05R\x0fexpectedVersionB\x1c\n" +
+	"\x1a_reassign_to_department_id\"\xb5\x01\n" +
+	"\x14StreamSubtreeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\x12+\n" +
+	"\x11include_employees\x18\x03 \x01(\bR\x10includeEmployees\x12C\n" +
+	"\x11employee_statuses\x18\x04 \x03(\x0e2\x16.org.v1.EmployeeStatusR\x10employeeStatuses\"W\n" +
+	"\vSubtreeNode\x122\n" +
+	"\n" +
+	"department\x18\x01 \x01(\v2\x12.org.v1.DepartmentR\n" +
+	"department\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\"\x88\x02\n" +
+	"\x15CreateEmployeeRequest\x12#\n" +
+	"\rdepartment_id\x18\x01 \x01(\x03R\fdepartmentId\x12\x1b\n" +
+	"\tfull_name\x18\x02 \x01(\tR\bfullName\x12\x1a\n" +
+	"\bposition\x18\x03 \x01(\tR\bposition\x12\x1e\n" +
+	"\bhired_at\x18\x04 \x01(\tH\x00R\ahiredAt\x88\x01\x01\x12.\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x16.org.v1.EmployeeStatusR\x06status\x12$\n" +
+	"\vposition_id\x18\x06 \x01(\x03H\x01R\n" +
+	"positionId\x88\x01\x01B\v\n" +
+	"\t_hired_atB\x0e\n" +
+	"\f_position_id\"I\n" +
+	"\x12GetEmployeeRequest\x12#\n" +
+	"\rdepartment_id\x18\x01 \x01(\x03R\fdepartmentId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"\xe2\x01\n" +
+	"\x15UpdateEmployeeRequest\x12#\n" +
+	"\rdepartment_id\x18\x01 \x01(\x03R\fdepartmentId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12,\n" +
+	"\bemployee\x18\x03 \x01(\v2\x10.org.v1.EmployeeR\bemployee\x12;\n" +
+	"\vupdate_mask\x18\x04 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12)\n" +
+	"\x10expected_version\x18\x05 \x01(\x05R\x0fexpectedVersion\"\x94\x02\n" +
+	"\x1bChangeEmployeeStatusRequest\x12#\n" +
+	"\rdepartment_id\x18\x01 \x01(\x03R\fdepartmentId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\x12.\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x16.org.v1.EmployeeStatusR\x06status\x12*\n" +
+	"\x0eeffective_date\x18\x04 \x01(\tH\x00R\reffectiveDate\x88\x01\x01\x12\x1b\n" +
+	"\x06reason\x18\x05 \x01(\tH\x01R\x06reason\x88\x01\x01\x12)\n" +
+	"\x10expected_version\x18\x06 \x01(\x05R\x0fexpectedVersionB\x11\n" +
+	"\x0f_effective_dateB\t\n" +
+	"\a_reason*\xa8\x01\n" +
+	"\x0eEmployeeStatus\x12\x1f\n" +
+	"\x1bEMPLOYEE_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17EMPLOYEE_STATUS_PENDING\x10\x01\x12\x1a\n" +
+	"\x16EMPLOYEE_STATUS_ACTIVE\x10\x02\x12\x1c\n" +
+	"\x18EMPLOYEE_STATUS_ON_LEAVE\x10\x03\x12\x1e\n" +
+	"\x1aEMPLOYEE_STATUS_TERMINATED\x10\x04*\\\n" +
+	"\n" +
+	"DeleteMode\x12\x1b\n" +
+	"\x17DELETE_MODE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DELETE_MODE_CASCADE\x10\x01\x12\x18\n" +
+	"\x14DELETE_MODE_REASSIGN\x10\x022\x86\x05\n" +
+	"\n" +
+	"OrgService\x12G\n" +
+	"\x10CreateDepartment\x12\x1f.org.v1.CreateDepartmentRequest\x1a\x12.org.v1.Department\x12A\n" +
+	"\rGetDepartment\x12\x1c.org.v1.GetDepartmentRequest\x1a\x12.org.v1.Department\x12G\n" +
+	"\x10UpdateDepartment\x12\x1f.org.v1.UpdateDepartmentRequest\x1a\x12.org.v1.Department\x12K\n" +
+	"\x10DeleteDepartment\x12\x1f.org.v1.DeleteDepartmentRequest\x1a\x16.google.protobuf.Empty\x12D\n" +
+	"\rStreamSubtree\x12\x1c.org.v1.StreamSubtreeRequest\x1a\x13.org.v1.SubtreeNode0\x01\x12A\n" +
+	"\x0eCreateEmployee\x12\x1d.org.v1.CreateEmployeeRequest\x1a\x10.org.v1.Employee\x12;\n" +
+	"\vGetEmployee\x12\x1a.org.v1.GetEmployeeRequest\x1a\x10.org.v1.Employee\x12A\n" +
+	"\x0eUpdateEmployee\x12\x1d.org.v1.UpdateEmployeeRequest\x1a\x10.org.v1.Employee\x12M\n" +
+	"\x14ChangeEmployeeStatus\x12#.org.v1.ChangeEmployeeStatusRequest\x1a\x10.org.v1.EmployeeBEZCgithub.com/SergeiKhy/org-structure-api/internal/grpcapi/orgv1;orgv1b\x06proto3"
+
+var (
+	file_org_v1_org_proto_rawDescOnce sync.Once
+	file_org_v1_org_proto_rawDescData []byte
+)
+
+func file_org_v1_org_proto_rawDescGZIP() []byte {
+	file_org_v1_org_proto_rawDescOnce.Do(func() {
+		file_org_v1_org_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_org_v1_org_proto_rawDesc), len(file_org_v1_org_proto_rawDesc)))
+	})
+	return file_org_v1_org_proto_rawDescData
+}
+
+var file_org_v1_org_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_org_v1_org_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_org_v1_org_proto_goTypes = []any{
+	(EmployeeStatus)(0),                 // 0: org.v1.EmployeeStatus
+	(DeleteMode)(0),                     // 1: org.v1.DeleteMode
+	(*Department)(nil),                  // 2: org.v1.Department
+	(*Employee)(nil),                    // 3: org.v1.Employee
+	(*CreateDepartmentRequest)(nil),     // 4: org.v1.CreateDepartmentRequest
+	(*GetDepartmentRequest)(nil),        // 5: org.v1.GetDepartmentRequest
+	(*UpdateDepartmentRequest)(nil),     // 6: org.v1.UpdateDepartmentRequest
+	(*DeleteDepartmentRequest)(nil),     // 7: org.v1.DeleteDepartmentRequest
+	(*StreamSubtreeRequest)(nil),        // 8: org.v1.StreamSubtreeRequest
+	(*SubtreeNode)(nil),                 // 9: org.v1.SubtreeNode
+	(*CreateEmployeeRequest)(nil),       // 10: org.v1.CreateEmployeeRequest
+	(*GetEmployeeRequest)(nil),          // 11: org.v1.GetEmployeeRequest
+	(*UpdateEmployeeRequest)(nil),       // 12: org.v1.UpdateEmployeeRequest
+	(*ChangeEmployeeStatusRequest)(nil), // 13: org.v1.ChangeEmployeeStatusRequest
+	(*timestamppb.Timestamp)(nil),       // 14: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),       // 15: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),               // 16: google.protobuf.Empty
+}
+var file_org_v1_org_proto_depIdxs = []int32{
+	14, // 0: org.v1.Department.created_at:type_name -> google.protobuf.Timestamp
+	14, // 1: org.v1.Department.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 2: org.v1.Department.employees:type_name -> org.v1.Employee
+	2,  // 3: org.v1.Department.children:type_name -> org.v1.Department
+	14, // 4: org.v1.Employee.created_at:type_name -> google.protobuf.Timestamp
+	14, // 5: org.v1.Employee.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: org.v1.Employee.status:type_name -> org.v1.EmployeeStatus
+	0,  // 7: org.v1.GetDepartmentRequest.employee_statuses:type_name -> org.v1.EmployeeStatus
+	2,  // 8: org.v1.UpdateDepartmentRequest.department:type_name -> org.v1.Department
+	15, // 9: org.v1.UpdateDepartmentRequest.update_mask:type_name -> google.protobuf.FieldMask
+	1,  // 10: org.v1.DeleteDepartmentRequest.mode:type_name -> org.v1.DeleteMode
+	0,  // 11: org.v1.StreamSubtreeRequest.employee_statuses:type_name -> org.v1.EmployeeStatus
+	2,  // 12: org.v1.SubtreeNode.department:type_name -> org.v1.Department
+	0,  // 13: org.v1.CreateEmployeeRequest.status:type_name -> org.v1.EmployeeStatus
+	3,  // 14: org.v1.UpdateEmployeeRequest.employee:type_name -> org.v1.Employee
+	15, // 15: org.v1.UpdateEmployeeRequest.update_mask:type_name -> google.protobuf.FieldMask
+	0,  // 16: org.v1.ChangeEmployeeStatusRequest.status:type_name -> org.v1.EmployeeStatus
+	4,  // 17: org.v1.OrgService.CreateDepartment:input_type -> org.v1.CreateDepartmentRequest
+	5,  // 18: org.v1.OrgService.GetDepartment:input_type -> org.v1.GetDepartmentRequest
+	6,  // 19: org.v1.OrgService.UpdateDepartment:input_type -> org.v1.UpdateDepartmentRequest
+	7,  // 20: org.v1.OrgService.DeleteDepartment:input_type -> org.v1.DeleteDepartmentRequest
+	8,  // 21: org.v1.OrgService.StreamSubtree:input_type -> org.v1.StreamSubtreeRequest
+	10, // 22: org.v1.OrgService.CreateEmployee:input_type -> org.v1.CreateEmployeeRequest
+	11, // 23: org.v1.OrgService.GetEmployee:input_type -> org.v1.GetEmployeeRequest
+	12, // 24: org.v1.OrgService.UpdateEmployee:input_type -> org.v1.UpdateEmployeeRequest
+	13, // 25: org.v1.OrgService.ChangeEmployeeStatus:input_type -> org.v1.ChangeEmployeeStatusRequest
+	2,  // 26: org.v1.OrgService.CreateDepartment:output_type -> org.v1.Department
+	2,  // 27: org.v1.OrgService.GetDepartment:output_type -> org.v1.Department
+	2,  // 28: org.v1.OrgService.UpdateDepartment:output_type -> org.v1.Department
+	16, // 29: org.v1.OrgService.DeleteDepartment:output_type -> google.protobuf.Empty
+	9,  // 30: org.v1.OrgService.StreamSubtree:output_type -> org.v1.SubtreeNode
+	3,  // 31: org.v1.OrgService.CreateEmployee:output_type -> org.v1.Employee
+	3,  // 32: org.v1.OrgService.GetEmployee:output_type -> org.v1.Employee
+	3,  // 33: org.v1.OrgService.UpdateEmployee:output_type -> org.v1.Employee
+	3,  // 34: org.v1.OrgService.ChangeEmployeeStatus:output_type -> org.v1.Employee
+	26, // [26:35] is the sub-list for method output_type
+	17, // [17:26] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_org_v1_org_proto_init() }
+func file_org_v1_org_proto_init() {
+	if File_org_v1_org_proto != nil {
+		return
+	}
+	file_org_v1_org_proto_msgTypes[0].OneofWrappers = []any{}
+	file_org_v1_org_proto_msgTypes[1].OneofWrappers = []any{}
+	file_org_v1_org_proto_msgTypes[2].OneofWrappers = []any{}
+	file_org_v1_org_proto_msgTypes[3].OneofWrappers = []any{}
+	file_org_v1_org_proto_msgTypes[5].OneofWrappers = []any{}
+	file_org_v1_org_proto_msgTypes[8].OneofWrappers = []any{}
+	file_org_v1_org_proto_msgTypes[11].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_org_v1_org_proto_rawDesc), len(file_org_v1_org_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_org_v1_org_proto_goTypes,
+		DependencyIndexes: file_org_v1_org_proto_depIdxs,
+		EnumInfos:         file_org_v1_org_proto_enumTypes,
+		MessageInfos:      file_org_v1_org_proto_msgTypes,
+	}.Build()
+	File_org_v1_org_proto = out.File
+	file_org_v1_org_proto_goTypes = nil
+	file_org_v1_org_proto_depIdxs = nil
+}
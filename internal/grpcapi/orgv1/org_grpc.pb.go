@@ -0,0 +1,442 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: org/v1/org.proto
+
+package orgv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OrgService_CreateDepartment_FullMethodName     = "/org.v1.OrgService/CreateDepartment"
+	OrgService_GetDepartment_FullMethodName        = "/org.v1.OrgService/GetDepartment"
+	OrgService_UpdateDepartment_FullMethodName     = "/org.v1.OrgService/UpdateDepartment"
+	OrgService_DeleteDepartment_FullMethodName     = "/org.v1.OrgService/DeleteDepartment"
+	OrgService_StreamSubtree_FullMethodName        = "/org.v1.OrgService/StreamSubtree"
+	OrgService_CreateEmployee_FullMethodName       = "/org.v1.OrgService/CreateEmployee"
+	OrgService_GetEmployee_FullMethodName          = "/org.v1.OrgService/GetEmployee"
+	OrgService_UpdateEmployee_FullMethodName       = "/org.v1.OrgService/UpdateEmployee"
+	OrgService_ChangeEmployeeStatus_FullMethodName = "/org.v1.OrgService/ChangeEmployeeStatus"
+)
+
+// OrgServiceClient is the client API for OrgService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// OrgService повторяет REST API подразделений и сотрудников.
+// Аутентификация — метаданные authorization ("Bearer <jwt>" или "ApiKey <key>") и x-tenant-id.
+type OrgServiceClient interface {
+	CreateDepartment(ctx context.Context, in *CreateDepartmentRequest, opts ...grpc.CallOption) (*Department, error)
+	// GetDepartment возвращает подразделение с потомками до глубины depth (как GET /departments/{id})
+	GetDepartment(ctx context.Context, in *GetDepartmentRequest, opts ...grpc.CallOption) (*Department, error)
+	UpdateDepartment(ctx context.Context, in *UpdateDepartmentRequest, opts ...grpc.CallOption) (*Department, error)
+	DeleteDepartment(ctx context.Context, in *DeleteDepartmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// StreamSubtree обходит поддерево в ширину и передаёт подразделения по мере загрузки уровней
+	StreamSubtree(ctx context.Context, in *StreamSubtreeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubtreeNode], error)
+	CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	UpdateEmployee(ctx context.Context, in *UpdateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error)
+	// ChangeEmployeeStatus переводит сотрудника в новый статус (как POST .../employees/{id}/status)
+	ChangeEmployeeStatus(ctx context.Context, in *ChangeEmployeeStatusRequest, opts ...grpc.CallOption) (*Employee, error)
+}
+
+type orgServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrgServiceClient(cc grpc.ClientConnInterface) OrgServiceClient {
+	return &orgServiceClient{cc}
+}
+
+func (c *orgServiceClient) CreateDepartment(ctx context.Context, in *CreateDepartmentRequest, opts ...grpc.CallOption) (*Department, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Department)
+	err := c.cc.Invoke(ctx, OrgService_CreateDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orgServiceClient) GetDepartment(ctx context.Context, in *GetDepartmentRequest, opts ...grpc.CallOption) (*Department, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Department)
+	err := c.cc.Invoke(ctx, OrgService_GetDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orgServiceClient) UpdateDepartment(ctx context.Context, in *UpdateDepartmentRequest, opts ...grpc.CallOption) (*Department, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Department)
+	err := c.cc.Invoke(ctx, OrgService_UpdateDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orgServiceClient) DeleteDepartment(ctx context.Context, in *DeleteDepartmentRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, OrgService_DeleteDepartment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orgServiceClient) StreamSubtree(ctx context.Context, in *StreamSubtreeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubtreeNode], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrgService_ServiceDesc.Streams[0], OrgService_StreamSubtree_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamSubtreeRequest, SubtreeNode]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrgService_StreamSubtreeClient = grpc.ServerStreamingClient[SubtreeNode]
+
+func (c *orgServiceClient) CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, OrgService_CreateEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orgServiceClient) GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, OrgService_GetEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orgServiceClient) UpdateEmployee(ctx context.Context, in *UpdateEmployeeRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, OrgService_UpdateEmployee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orgServiceClient) ChangeEmployeeStatus(ctx context.Context, in *ChangeEmployeeStatusRequest, opts ...grpc.CallOption) (*Employee, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Employee)
+	err := c.cc.Invoke(ctx, OrgService_ChangeEmployeeStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrgServiceServer is the server API for OrgService service.
+// All implementations must embed UnimplementedOrgServiceServer
+// for forward compatibility.
+//
+// OrgService повторяет REST API подразделений и сотрудников.
+// Аутентификация — метаданные authorization ("Bearer <jwt>" или "ApiKey <key>") и x-tenant-id.
+type OrgServiceServer interface {
+	CreateDepartment(context.Context, *CreateDepartmentRequest) (*Department, error)
+	// GetDepartment возвращает подразделение с потомками до глубины depth (как GET /departments/{id})
+	GetDepartment(context.Context, *GetDepartmentRequest) (*Department, error)
+	UpdateDepartment(context.Context, *UpdateDepartmentRequest) (*Department, error)
+	DeleteDepartment(context.Context, *DeleteDepartmentRequest) (*emptypb.Empty, error)
+	// StreamSubtree обходит поддерево в ширину и передаёт подразделения по мере загрузки уровней
+	StreamSubtree(*StreamSubtreeRequest, grpc.ServerStreamingServer[SubtreeNode]) error
+	CreateEmployee(context.Context, *CreateEmployeeRequest) (*Employee, error)
+	GetEmployee(context.Context, *GetEmployeeRequest) (*Employee, error)
+	UpdateEmployee(context.Context, *UpdateEmployeeRequest) (*Employee, error)
+	// ChangeEmployeeStatus переводит сотрудника в новый статус (как POST .../employees/{id}/status)
+	ChangeEmployeeStatus(context.Context, *ChangeEmployeeStatusRequest) (*Employee, error)
+	mustEmbedUnimplementedOrgServiceServer()
+}
+
+// UnimplementedOrgServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrgServiceServer struct{}
+
+func (UnimplementedOrgServiceServer) CreateDepartment(context.Context, *CreateDepartmentRequest) (*Department, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDepartment not implemented")
+}
+func (UnimplementedOrgServiceServer) GetDepartment(context.Context, *GetDepartmentRequest) (*Department, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDepartment not implemented")
+}
+func (UnimplementedOrgServiceServer) UpdateDepartment(context.Context, *UpdateDepartmentRequest) (*Department, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDepartment not implemented")
+}
+func (UnimplementedOrgServiceServer) DeleteDepartment(context.Context, *DeleteDepartmentRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDepartment not implemented")
+}
+func (UnimplementedOrgServiceServer) StreamSubtree(*StreamSubtreeRequest, grpc.ServerStreamingServer[SubtreeNode]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSubtree not implemented")
+}
+func (UnimplementedOrgServiceServer) CreateEmployee(context.Context, *CreateEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmployee not implemented")
+}
+func (UnimplementedOrgServiceServer) GetEmployee(context.Context, *GetEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmployee not implemented")
+}
+func (UnimplementedOrgServiceServer) UpdateEmployee(context.Context, *UpdateEmployeeRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateEmployee not implemented")
+}
+func (UnimplementedOrgServiceServer) ChangeEmployeeStatus(context.Context, *ChangeEmployeeStatusRequest) (*Employee, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeEmployeeStatus not implemented")
+}
+func (UnimplementedOrgServiceServer) mustEmbedUnimplementedOrgServiceServer() {}
+func (UnimplementedOrgServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeOrgServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrgServiceServer will
+// result in compilation errors.
+type UnsafeOrgServiceServer interface {
+	mustEmbedUnimplementedOrgServiceServer()
+}
+
+func RegisterOrgServiceServer(s grpc.ServiceRegistrar, srv OrgServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOrgServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrgService_ServiceDesc, srv)
+}
+
+func _OrgService_CreateDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDepartmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).CreateDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_CreateDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).CreateDepartment(ctx, req.(*CreateDepartmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrgService_GetDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDepartmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).GetDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_GetDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).GetDepartment(ctx, req.(*GetDepartmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrgService_UpdateDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDepartmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).UpdateDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_UpdateDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).UpdateDepartment(ctx, req.(*UpdateDepartmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrgService_DeleteDepartment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDepartmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).DeleteDepartment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_DeleteDepartment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).DeleteDepartment(ctx, req.(*DeleteDepartmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrgService_StreamSubtree_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamSubtreeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrgServiceServer).StreamSubtree(m, &grpc.GenericServerStream[StreamSubtreeRequest, SubtreeNode]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrgService_StreamSubtreeServer = grpc.ServerStreamingServer[SubtreeNode]
+
+func _OrgService_CreateEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).CreateEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_CreateEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).CreateEmployee(ctx, req.(*CreateEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrgService_GetEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).GetEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_GetEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).GetEmployee(ctx, req.(*GetEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrgService_UpdateEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).UpdateEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_UpdateEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).UpdateEmployee(ctx, req.(*UpdateEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrgService_ChangeEmployeeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeEmployeeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrgServiceServer).ChangeEmployeeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrgService_ChangeEmployeeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrgServiceServer).ChangeEmployeeStatus(ctx, req.(*ChangeEmployeeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrgService_ServiceDesc is the grpc.ServiceDesc for OrgService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrgService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "org.v1.OrgService",
+	HandlerType: (*OrgServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDepartment",
+			Handler:    _OrgService_CreateDepartment_Handler,
+		},
+		{
+			MethodName: "GetDepartment",
+			Handler:    _OrgService_GetDepartment_Handler,
+		},
+		{
+			MethodName: "UpdateDepartment",
+			Handler:    _OrgService_UpdateDepartment_Handler,
+		},
+		{
+			MethodName: "DeleteDepartment",
+			Handler:    _OrgService_DeleteDepartment_Handler,
+		},
+		{
+			MethodName: "CreateEmployee",
+			Handler:    _OrgService_CreateEmployee_Handler,
+		},
+		{
+			MethodName: "GetEmployee",
+			Handler:    _OrgService_GetEmployee_Handler,
+		},
+		{
+			MethodName: "UpdateEmployee",
+			Handler:    _OrgService_UpdateEmployee_Handler,
+		},
+		{
+			MethodName: "ChangeEmployeeStatus",
+			Handler:    _OrgService_ChangeEmployeeStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSubtree",
+			Handler:       _OrgService_StreamSubtree_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "org/v1/org.proto",
+}
@@ -0,0 +1,298 @@
+// Package grpcapi реализует gRPC API (org.v1.OrgService) поверх сервисного слоя.
+// Схема — proto/org/v1/org.proto, сгенерированный код — пакет orgv1.
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/grpcapi/orgv1"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// Генерация orgv1 из proto/org/v1/org.proto (protoc, protoc-gen-go, protoc-gen-go-grpc)
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/SergeiKhy/org-structure-api --go-grpc_out=../.. --go-grpc_opt=module=github.com/SergeiKhy/org-structure-api org/v1/org.proto
+
+// NewGRPCServer создаёт gRPC-сервер с OrgService и проверкой состояния grpc.health.v1
+func NewGRPCServer(svc *service.Service, a *auth.Authenticator) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryAuthInterceptor(a)),
+		grpc.StreamInterceptor(StreamAuthInterceptor(a)),
+	)
+	orgv1.RegisterOrgServiceServer(srv, NewServer(svc))
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthSrv.SetServingStatus(orgv1.OrgService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	return srv
+}
+
+// Server реализация orgv1.OrgServiceServer
+type Server struct {
+	orgv1.UnimplementedOrgServiceServer
+	service *service.Service
+}
+
+func NewServer(svc *service.Service) *Server {
+	return &Server{service: svc}
+}
+
+// serviceFor возвращает сервис, действующий от имени субъекта вызова
+func (s *Server) serviceFor(ctx context.Context) *service.Service {
+	return s.service.WithPrincipal(auth.FromContext(ctx))
+}
+
+func (s *Server) CreateDepartment(ctx context.Context, req *orgv1.CreateDepartmentRequest) (*orgv1.Department, error) {
+	dept, err := s.serviceFor(ctx).CreateDepartment(model.CreateDepartmentRequest{
+		Name:        req.GetName(),
+		ParentID:    fromOptionalID(req.ParentId),
+		Code:        req.Code,
+		CostCenter:  req.CostCenter,
+		Description: req.Description,
+		Active:      req.Active,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return departmentToProto(dept), nil
+}
+
+func (s *Server) GetDepartment(ctx context.Context, req *orgv1.GetDepartmentRequest) (*orgv1.Department, error) {
+	includeEmployees := req.IncludeEmployees == nil || *req.IncludeEmployees
+	statuses, err := employeeStatusesFromProto(req.GetEmployeeStatuses())
+	if err != nil {
+		return nil, err
+	}
+	dept, err := s.serviceFor(ctx).GetDepartmentTreeByStatus(int(req.GetId()), int(req.GetDepth()), includeEmployees, statuses)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return departmentToProto(dept), nil
+}
+
+func (s *Server) UpdateDepartment(ctx context.Context, req *orgv1.UpdateDepartmentRequest) (*orgv1.Department, error) {
+	patch, err := departmentPatch(req.GetDepartment(), req.GetUpdateMask())
+	if err != nil {
+		return nil, err
+	}
+	dept, err := s.serviceFor(ctx).PatchDepartment(int(req.GetId()), patch, int(req.GetExpectedVersion()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return departmentToProto(dept), nil
+}
+
+func (s *Server) DeleteDepartment(ctx context.Context, req *orgv1.DeleteDepartmentRequest) (*emptypb.Empty, error) {
+	mode := "cascade"
+	switch req.GetMode() {
+	case orgv1.DeleteMode_DELETE_MODE_UNSPECIFIED, orgv1.DeleteMode_DELETE_MODE_CASCADE:
+	case orgv1.DeleteMode_DELETE_MODE_REASSIGN:
+		mode = "reassign"
+		if req.ReassignToDepartmentId == nil {
+			return nil, status.Error(codes.InvalidArgument, "reassign_to_department_id required")
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "invalid mode")
+	}
+
+	err := s.serviceFor(ctx).DeleteDepartment(int(req.GetId()), mode, fromOptionalID(req.ReassignToDepartmentId), int(req.GetExpectedVersion()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// StreamSubtree обходит поддерево по уровням: на каждый уровень — один запрос потомков
+// и при include_employees один запрос сотрудников, независимо от числа подразделений
+func (s *Server) StreamSubtree(req *orgv1.StreamSubtreeRequest, stream grpc.ServerStreamingServer[orgv1.SubtreeNode]) error {
+	ctx := stream.Context()
+	svc := s.serviceFor(ctx)
+
+	statuses, err := employeeStatusesFromProto(req.GetEmployeeStatuses())
+	if err != nil {
+		return err
+	}
+	root, err := svc.GetDepartment(int(req.GetId()))
+	if err != nil {
+		return toStatus(err)
+	}
+
+	level := []model.Department{*root}
+	for depth := 0; len(level) > 0; depth++ {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+
+		ids := make([]int, len(level))
+		for i, d := range level {
+			ids[i] = d.ID
+		}
+
+		var employees map[int][]model.Employee
+		if req.GetIncludeEmployees() {
+			if employees, err = svc.LoadEmployees(ids, statuses); err != nil {
+				return toStatus(err)
+			}
+		}
+		for i := range level {
+			level[i].Employees = employees[level[i].ID]
+			node := &orgv1.SubtreeNode{Department: departmentToProto(&level[i]), Depth: int32(depth)}
+			if err := stream.Send(node); err != nil {
+				return err
+			}
+		}
+
+		if req.GetMaxDepth() > 0 && depth >= int(req.GetMaxDepth()) {
+			break
+		}
+		children, err := svc.LoadChildren(ids)
+		if err != nil {
+			return toStatus(err)
+		}
+		var next []model.Department
+		for _, id := range ids {
+			next = append(next, children[id]...)
+		}
+		level = next
+	}
+	return nil
+}
+
+func (s *Server) CreateEmployee(ctx context.Context, req *orgv1.CreateEmployeeRequest) (*orgv1.Employee, error) {
+	empStatus, err := employeeStatusFromProto(req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+	createReq := model.CreateEmployeeRequest{
+		FullName:   req.GetFullName(),
+		Position:   req.GetPosition(),
+		PositionID: fromOptionalID(req.PositionId),
+		HiredAt:    req.HiredAt,
+	}
+	if empStatus != "" {
+		createReq.Status = &empStatus
+	}
+	emp, err := s.serviceFor(ctx).CreateEmployee(int(req.GetDepartmentId()), createReq)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return employeeToProto(emp), nil
+}
+
+func (s *Server) GetEmployee(ctx context.Context, req *orgv1.GetEmployeeRequest) (*orgv1.Employee, error) {
+	emp, err := s.serviceFor(ctx).GetEmployee(int(req.GetDepartmentId()), int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return employeeToProto(emp), nil
+}
+
+func (s *Server) UpdateEmployee(ctx context.Context, req *orgv1.UpdateEmployeeRequest) (*orgv1.Employee, error) {
+	patch, err := employeePatch(req.GetEmployee(), req.GetUpdateMask())
+	if err != nil {
+		return nil, err
+	}
+	emp, err := s.serviceFor(ctx).PatchEmployee(int(req.GetDepartmentId()), int(req.GetId()), patch, int(req.GetExpectedVersion()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return employeeToProto(emp), nil
+}
+
+func (s *Server) ChangeEmployeeStatus(ctx context.Context, req *orgv1.ChangeEmployeeStatusRequest) (*orgv1.Employee, error) {
+	empStatus, err := employeeStatusFromProto(req.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+	if empStatus == "" {
+		return nil, status.Error(codes.InvalidArgument, "status required")
+	}
+	emp, err := s.serviceFor(ctx).ChangeEmployeeStatus(int(req.GetDepartmentId()), int(req.GetId()), model.ChangeEmployeeStatusRequest{
+		Status:        empStatus,
+		EffectiveDate: req.EffectiveDate,
+		Reason:        req.Reason,
+	}, int(req.GetExpectedVersion()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return employeeToProto(emp), nil
+}
+
+// departmentPatch переводит маску полей в частичное изменение.
+// Поле в маске без значения в сообщении сбрасывается (для parent_id — перенос в корень).
+func departmentPatch(dept *orgv1.Department, mask *fieldmaskpb.FieldMask) (model.DepartmentPatch, error) {
+	var patch model.DepartmentPatch
+	if len(mask.GetPaths()) == 0 {
+		return patch, status.Error(codes.InvalidArgument, "update_mask required")
+	}
+	for _, path := range mask.GetPaths() {
+		switch path {
+		case "name":
+			patch.Name = model.Some(dept.GetName())
+		case "parent_id":
+			patch.ParentID = optionalID(dept.GetParentId(), dept != nil && dept.ParentId != nil)
+		case "code":
+			patch.Code = optionalString(dept.GetCode(), dept != nil && dept.Code != nil)
+		case "cost_center":
+			patch.CostCenter = optionalString(dept.GetCostCenter(), dept != nil && dept.CostCenter != nil)
+		case "description":
+			patch.Description = optionalString(dept.GetDescription(), dept != nil && dept.Description != nil)
+		case "active":
+			patch.Active = model.Some(dept.GetActive())
+		default:
+			return patch, status.Error(codes.InvalidArgument, fmt.Sprintf("unsupported update_mask path %q", path))
+		}
+	}
+	return patch, nil
+}
+
+func employeePatch(emp *orgv1.Employee, mask *fieldmaskpb.FieldMask) (model.EmployeePatch, error) {
+	var patch model.EmployeePatch
+	if len(mask.GetPaths()) == 0 {
+		return patch, status.Error(codes.InvalidArgument, "update_mask required")
+	}
+	for _, path := range mask.GetPaths() {
+		switch path {
+		case "full_name":
+			patch.FullName = model.Some(emp.GetFullName())
+		case "position":
+			patch.Position = model.Some(emp.GetPosition())
+		case "hired_at":
+			if emp != nil && emp.HiredAt != nil {
+				patch.HiredAt = model.Some(*emp.HiredAt)
+			} else {
+				patch.HiredAt = model.Null[string]()
+			}
+		case "department_id":
+			patch.DepartmentID = model.Some(int(emp.GetDepartmentId()))
+		default:
+			return patch, status.Error(codes.InvalidArgument, fmt.Sprintf("unsupported update_mask path %q", path))
+		}
+	}
+	return patch, nil
+}
+
+func optionalID(id int64, set bool) model.Optional[int] {
+	if !set {
+		return model.Null[int]()
+	}
+	return model.Some(int(id))
+}
+
+func optionalString(v string, set bool) model.Optional[string] {
+	if !set {
+		return model.Null[string]()
+	}
+	return model.Some(v)
+}
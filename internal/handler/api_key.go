@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.serviceFor(r).ListAPIKeys()
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, keys)
+}
+
+func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	key, err := h.serviceFor(r).CreateAPIKey(req)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, key)
+}
+
+func (h *Handler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	// Путь: /admin/api-keys/{id}/rotate
+	id, err := apiKeyIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	key, err := h.serviceFor(r).RotateAPIKey(id)
+	if err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if err == service.ErrAPIKeyRevoked {
+			h.WriteError(w, http.StatusConflict, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, key)
+}
+
+func (h *Handler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	// Путь: /admin/api-keys/{id}
+	id, err := apiKeyIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.serviceFor(r).RevokeAPIKey(id); err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiKeyIDFromPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/admin/api-keys/")
+	return strconv.Atoi(strings.Split(idStr, "/")[0])
+}
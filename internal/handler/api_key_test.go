@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCreateAPIKey_InvalidJSON проверяет обработку невалидного тела запроса
+func TestCreateAPIKey_InvalidJSON(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", bytes.NewReader([]byte("invalid")))
+	w := httptest.NewRecorder()
+
+	h.CreateAPIKey(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestAPIKeyIDFromPath проверяет разбор ID ключа из пути
+func TestAPIKeyIDFromPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		expected  int
+		expectErr bool
+	}{
+		{"/admin/api-keys/5", 5, false},
+		{"/admin/api-keys/7/rotate", 7, false},
+		{"/admin/api-keys/abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			id, err := apiKeyIDFromPath(tt.path)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil || id != tt.expected {
+				t.Errorf("expected %d, got %d (%v)", tt.expected, id, err)
+			}
+		})
+	}
+}
+
+// TestRotateAPIKey_InvalidID проверяет ответ на невалидный ID
+func TestRotateAPIKey_InvalidID(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys/x/rotate", nil)
+	w := httptest.NewRecorder()
+
+	h.RotateAPIKey(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
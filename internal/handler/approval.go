@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+func (h *Handler) ListApprovalPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.serviceFor(r).ListApprovalPolicies()
+	if err != nil {
+		h.writeApprovalError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, policies)
+}
+
+// SetApprovalPolicy создаёт или заменяет политику (PUT /admin/approval-policies/{action})
+func (h *Handler) SetApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	action := approvalActionFromPath(r.URL.Path)
+	if action == "" {
+		h.WriteError(w, http.StatusBadRequest, "action required")
+		return
+	}
+
+	var req model.SetApprovalPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	policy, err := h.serviceFor(r).SetApprovalPolicy(action, req)
+	if err != nil {
+		h.writeApprovalError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, policy)
+}
+
+func (h *Handler) DeleteApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	action := approvalActionFromPath(r.URL.Path)
+	if action == "" {
+		h.WriteError(w, http.StatusBadRequest, "action required")
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteApprovalPolicy(action); err != nil {
+		h.writeApprovalError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListChangeRequests список запросов на изменение (GET /change-requests?status=pending)
+func (h *Handler) ListChangeRequests(w http.ResponseWriter, r *http.Request) {
+	requests, err := h.serviceFor(r).ListChangeRequests(r.URL.Query().Get("status"))
+	if err != nil {
+		h.writeApprovalError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, requests)
+}
+
+func (h *Handler) GetChangeRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := changeRequestIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	cr, err := h.serviceFor(r).GetChangeRequest(id)
+	if err != nil {
+		h.writeApprovalError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, cr)
+}
+
+// ApproveChangeRequest одобряет и выполняет изменение (POST /change-requests/{id}/approve)
+func (h *Handler) ApproveChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideChangeRequest(w, r, true)
+}
+
+// RejectChangeRequest отклоняет изменение (POST /change-requests/{id}/reject)
+func (h *Handler) RejectChangeRequest(w http.ResponseWriter, r *http.Request) {
+	h.decideChangeRequest(w, r, false)
+}
+
+// decideChangeRequest разбирает запрос решения; тело {"comment": "..."} необязательно
+func (h *Handler) decideChangeRequest(w http.ResponseWriter, r *http.Request, approve bool) {
+	id, err := changeRequestIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req model.DecideChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	svc := h.serviceFor(r)
+	decide := svc.RejectChangeRequest
+	if approve {
+		decide = svc.ApproveChangeRequest
+	}
+	cr, err := decide(id, req.Comment)
+	if err != nil {
+		h.writeApprovalError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, cr)
+}
+
+// writeApprovalRequired отвечает 202 Accepted с запросом на изменение, если изменение
+// отложено до согласования; false — ошибка другого вида
+func (h *Handler) writeApprovalRequired(w http.ResponseWriter, err error) bool {
+	var approvalErr *service.ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		return false
+	}
+	h.writeJSON(w, http.StatusAccepted, approvalErr.Request)
+	return true
+}
+
+func approvalActionFromPath(path string) string {
+	return strings.Trim(strings.TrimPrefix(path, "/admin/approval-policies/"), "/")
+}
+
+func changeRequestIDFromPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/change-requests/")
+	return strconv.Atoi(strings.Split(idStr, "/")[0])
+}
+
+// writeApprovalError сопоставляет ошибки согласования со статусами HTTP. Ошибки выполнения
+// одобренного изменения (версия подразделения устарела, цикл, совпадение имён) — 409.
+func (h *Handler) writeApprovalError(w http.ResponseWriter, err error) {
+	if err == service.ErrNotFound {
+		h.WriteError(w, http.StatusNotFound, err.Error())
+	} else if err == service.ErrForbidden || err == service.ErrSelfApproval {
+		h.WriteError(w, http.StatusForbidden, err.Error())
+	} else if err == service.ErrChangeRequestDecided || err == service.ErrVersionMismatch || err == service.ErrCycleDetected ||
+		err == service.ErrSelfParent || err == service.ErrDuplicateName {
+		h.WriteError(w, http.StatusConflict, err.Error())
+	} else {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
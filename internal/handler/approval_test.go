@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// TestApproval_InvalidRequest проверяет отказ для некорректных запросов до обращения к БД
+func TestApproval_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *http.Request
+	}{
+		{"set policy without action", h.SetApprovalPolicy, httptest.NewRequest(http.MethodPut, "/admin/approval-policies/", strings.NewReader("{}"))},
+		{"set policy invalid json", h.SetApprovalPolicy, httptest.NewRequest(http.MethodPut, "/admin/approval-policies/delete_department", strings.NewReader("{"))},
+		{"delete policy without action", h.DeleteApprovalPolicy, httptest.NewRequest(http.MethodDelete, "/admin/approval-policies/", nil)},
+		{"get invalid id", h.GetChangeRequest, httptest.NewRequest(http.MethodGet, "/change-requests/x", nil)},
+		{"approve invalid id", h.ApproveChangeRequest, httptest.NewRequest(http.MethodPost, "/change-requests/x/approve", nil)},
+		{"reject invalid json", h.RejectChangeRequest, httptest.NewRequest(http.MethodPost, "/change-requests/1/reject", strings.NewReader("{"))},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, tt.req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tt.name, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestWriteApprovalRequired проверяет ответ на изменение, отложенное до согласования
+func TestWriteApprovalRequired(t *testing.T) {
+	h := &Handler{}
+
+	w := httptest.NewRecorder()
+	h.writePatchError(w, &service.ApprovalRequiredError{Request: &model.ChangeRequest{ID: 3, Status: model.ChangeRequestPending}})
+	if w.Code != http.StatusAccepted || !strings.Contains(w.Body.String(), `"id":3`) {
+		t.Errorf("expected 202 with change request, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if h.writeApprovalRequired(w, errors.New("invalid name")) || w.Code != http.StatusOK {
+		t.Error("unexpected response for other errors")
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// attributeParamPrefix префикс параметров запроса, фильтрующих по дополнительным атрибутам
+const attributeParamPrefix = "attr."
+
+// parseAttributeFilter собирает фильтр из параметров attr.<name>=<value>;
+// значения приводятся к типам схем в сервисе
+func parseAttributeFilter(q url.Values) model.Attributes {
+	var attrs model.Attributes
+	for key, values := range q {
+		name, ok := strings.CutPrefix(key, attributeParamPrefix)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if attrs == nil {
+			attrs = make(model.Attributes)
+		}
+		attrs[name] = values[0]
+	}
+	return attrs
+}
+
+func (h *Handler) ListAttributeDefinitions(w http.ResponseWriter, r *http.Request) {
+	defs, err := h.serviceFor(r).ListAttributeDefinitions(r.URL.Query().Get("entity"))
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, defs)
+}
+
+func (h *Handler) CreateAttributeDefinition(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateAttributeDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	def, err := h.serviceFor(r).CreateAttributeDefinition(req)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if err == service.ErrDuplicateAttribute {
+			h.WriteError(w, http.StatusConflict, err.Error())
+		} else {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, def)
+}
+
+func (h *Handler) DeleteAttributeDefinition(w http.ResponseWriter, r *http.Request) {
+	// Путь: /admin/attribute-definitions/{id}
+	idStr := strings.TrimPrefix(r.URL.Path, "/admin/attribute-definitions/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteAttributeDefinition(id); err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestAttributeDefinitions_InvalidRequest проверяет отказ для некорректных запросов до обращения к БД
+func TestAttributeDefinitions_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+
+	w := httptest.NewRecorder()
+	h.CreateAttributeDefinition(w, httptest.NewRequest(http.MethodPost, "/admin/attribute-definitions", strings.NewReader("{")))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid json: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.DeleteAttributeDefinition(w, httptest.NewRequest(http.MethodDelete, "/admin/attribute-definitions/abc", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid id: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// Authenticate определяет субъекта запроса и сохраняет его в контексте
+func (h *Handler) Authenticate(a *auth.Authenticator, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(r)
+		if err != nil {
+			if err == auth.ErrTenantMismatch {
+				h.WriteError(w, http.StatusForbidden, err.Error())
+				return
+			}
+			w.Header().Set("WWW-Authenticate", "Bearer, ApiKey")
+			h.WriteError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		next(w, r.WithContext(auth.NewContext(r.Context(), p)))
+	}
+}
+
+// serviceFor возвращает сервис, действующий от имени субъекта запроса
+func (h *Handler) serviceFor(r *http.Request) *service.Service {
+	return h.service.WithPrincipal(auth.FromContext(r.Context()))
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// ExecuteBatch выполняет пакет операций атомарно.
+// При ошибке возвращается статус упавшей операции и результаты всех операций.
+func (h *Handler) ExecuteBatch(w http.ResponseWriter, r *http.Request) {
+	var req model.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if len(req.Operations) == 0 {
+		h.WriteError(w, http.StatusBadRequest, "operations required")
+		return
+	}
+
+	resp, err := h.serviceFor(r).ExecuteBatch(req)
+	if err != nil {
+		if resp == nil {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.writeJSON(w, batchErrorStatus(err), resp)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// batchErrorStatus статус ответа для ошибки упавшей операции. Операция, требующая согласования,
+// не выполняется в пакете (запрос на изменение откатывается вместе с ним) — 409.
+func batchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, service.ErrCycleDetected), errors.Is(err, service.ErrSelfParent),
+		errors.Is(err, service.ErrDuplicateName), errors.Is(err, service.ErrDuplicateCode),
+		errors.Is(err, service.ErrApprovalRequired):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// TestExecuteBatch_InvalidRequest проверяет отказ для некорректного тела пакета
+func TestExecuteBatch_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+
+	for _, body := range []string{"[", `{"operations": []}`, `{"operations": [{"op": "create_department", "parent_id": true}]}`} {
+		req := httptest.NewRequest(http.MethodPost, "/batch", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+
+		h.ExecuteBatch(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", body, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestBatchErrorStatus проверяет соответствие ошибок операций статусам ответа
+func TestBatchErrorStatus(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{service.ErrNotFound, http.StatusNotFound},
+		{service.ErrForbidden, http.StatusForbidden},
+		{service.ErrVersionMismatch, http.StatusPreconditionFailed},
+		{service.ErrCycleDetected, http.StatusConflict},
+		{service.ErrDuplicateName, http.StatusConflict},
+		{service.ErrDuplicateCode, http.StatusConflict},
+		{&service.ApprovalRequiredError{}, http.StatusConflict},
+		{fmt.Errorf("operation 2: %w", service.ErrNotFound), http.StatusNotFound},
+		{errors.New("invalid name"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		if status := batchErrorStatus(tt.err); status != tt.expected {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.expected, status)
+		}
+	}
+}
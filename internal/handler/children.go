@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// ReorderChildren задаёт порядок непосредственных потомков подразделения
+// (PUT /departments/{id}/children/order, тело {"order": [3, 1, 2]}) и возвращает их в новом порядке
+func (h *Handler) ReorderChildren(w http.ResponseWriter, r *http.Request) {
+	deptID, rest, ok := parseDepartmentSubpath(r.URL.Path, "children")
+	if !ok || rest != "order" {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	var req model.ReorderChildrenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if req.Order == nil {
+		h.WriteError(w, http.StatusBadRequest, "order required")
+		return
+	}
+
+	children, err := h.serviceFor(r).ReorderChildren(deptID, req.Order)
+	if err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if err == service.ErrOrderMismatch {
+			h.WriteError(w, http.StatusConflict, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, children)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReorderChildren_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	cases := []struct {
+		path string
+		body string
+	}{
+		{"/departments/abc/children/order", `{"order":[1]}`},
+		{"/departments/1/children", `{"order":[1]}`},
+		{"/departments/1/children/other", `{"order":[1]}`},
+		{"/departments/1/children/order", `{"order":`},
+		{"/departments/1/children/order", `{}`},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		h.ReorderChildren(w, httptest.NewRequest(http.MethodPut, tc.path, strings.NewReader(tc.body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected status %d, got %d", tc.path, tc.body, http.StatusBadRequest, w.Code)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// parseDepartmentFilter разбирает параметры списка подразделений:
+// active, cost_center, parent_id (число или null — верхний уровень), q, attr.<name>, limit, offset
+func parseDepartmentFilter(r *http.Request) (model.DepartmentFilter, bool) {
+	q := r.URL.Query()
+	filter := model.DepartmentFilter{
+		CostCenter: q.Get("cost_center"),
+		Query:      q.Get("q"),
+		Attributes: parseAttributeFilter(q),
+	}
+	if v := q.Get("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, false
+		}
+		filter.Active = &active
+	}
+	if v := q.Get("parent_id"); v == "null" {
+		filter.Root = true
+	} else if v != "" {
+		parentID, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, false
+		}
+		filter.ParentID = &parentID
+	}
+	return filter, parsePage(q, &filter.Limit, &filter.Offset)
+}
+
+// parseEmployeeFilter разбирает параметры поиска сотрудников: department_id, q, attr.<name>, limit, offset
+func parseEmployeeFilter(r *http.Request) (model.EmployeeFilter, bool) {
+	q := r.URL.Query()
+	filter := model.EmployeeFilter{
+		Query:      q.Get("q"),
+		Attributes: parseAttributeFilter(q),
+	}
+	if v := q.Get("department_id"); v != "" {
+		deptID, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, false
+		}
+		filter.DepartmentID = &deptID
+	}
+	statuses, ok := parseEmployeeStatuses(q, "status")
+	if !ok {
+		return filter, false
+	}
+	filter.Statuses = statuses
+	return filter, parsePage(q, &filter.Limit, &filter.Offset)
+}
+
+// parsePage разбирает неотрицательные limit и offset
+func parsePage(q url.Values, limit, offset *int) bool {
+	for name, dst := range map[string]*int{"limit": limit, "offset": offset} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return false
+			}
+			*dst = n
+		}
+	}
+	return true
+}
+
+// ListDepartments возвращает подразделения по фильтру (GET /departments/)
+func (h *Handler) ListDepartments(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseDepartmentFilter(r)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	depts, err := h.serviceFor(r).ListDepartments(filter)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if errors.Is(err, service.ErrInvalidAttributes) {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, depts)
+}
+
+// ListEmployees ищет сотрудников по фильтру (GET /employees/)
+func (h *Handler) ListEmployees(w http.ResponseWriter, r *http.Request) {
+	filter, ok := parseEmployeeFilter(r)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid query parameters")
+		return
+	}
+
+	employees, err := h.serviceFor(r).ListEmployees(filter)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if errors.Is(err, service.ErrInvalidAttributes) {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, employees)
+}
+
+// GetDepartmentByCode возвращает подразделение по коду (GET /departments/by-code/{code})
+// с теми же параметрами depth, include_employees и employee_status, что и GetDepartment
+func (h *Handler) GetDepartmentByCode(w http.ResponseWriter, r *http.Request) {
+	code := strings.TrimPrefix(r.URL.Path, "/departments/by-code/")
+	if code == "" || strings.Contains(code, "/") {
+		h.WriteError(w, http.StatusBadRequest, "invalid code")
+		return
+	}
+
+	statuses, ok := parseEmployeeStatuses(r.URL.Query(), "employee_status")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid employee_status")
+		return
+	}
+
+	svc := h.serviceFor(r)
+	dept, err := svc.GetDepartmentByCode(code)
+	if err == nil {
+		dept, err = svc.GetDepartmentTreeByStatus(dept.ID, queryDepth(r), r.URL.Query().Get("include_employees") != "false", statuses)
+	}
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusNotFound, "not found")
+		}
+		return
+	}
+
+	w.Header().Set("Accept-Patch", acceptPatch)
+	h.writeJSONWithETag(w, r, http.StatusOK, dept.Version, dept)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// TestParseDepartmentFilter проверяет разбор параметров списка подразделений
+func TestParseDepartmentFilter(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/departments/?active=false&cost_center=CC-1&parent_id=7&q=sales&limit=20&offset=40", nil)
+	filter, ok := parseDepartmentFilter(r)
+	if !ok {
+		t.Fatal("expected valid filter")
+	}
+	if filter.Active == nil || *filter.Active {
+		t.Errorf("expected active=false, got %v", filter.Active)
+	}
+	if filter.ParentID == nil || *filter.ParentID != 7 || filter.Root {
+		t.Errorf("expected parent_id=7, got %v (root %v)", filter.ParentID, filter.Root)
+	}
+	if filter.CostCenter != "CC-1" || filter.Query != "sales" || filter.Limit != 20 || filter.Offset != 40 {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+
+	filter, ok = parseDepartmentFilter(httptest.NewRequest(http.MethodGet, "/departments/?parent_id=null", nil))
+	if !ok || !filter.Root || filter.ParentID != nil || filter.Active != nil {
+		t.Errorf("expected root filter, got %+v", filter)
+	}
+}
+
+// TestListDepartments_InvalidQuery проверяет отказ для некорректных параметров до обращения к БД
+func TestListDepartments_InvalidQuery(t *testing.T) {
+	h := &Handler{}
+	for _, query := range []string{"active=maybe", "parent_id=abc", "limit=-1", "offset=x"} {
+		w := httptest.NewRecorder()
+		h.ListDepartments(w, httptest.NewRequest(http.MethodGet, "/departments/?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestGetDepartmentByCode_InvalidPath проверяет отказ для пустого или составного кода
+func TestGetDepartmentByCode_InvalidPath(t *testing.T) {
+	h := &Handler{}
+	for _, path := range []string{"/departments/by-code/", "/departments/by-code/SALES/extra"} {
+		w := httptest.NewRecorder()
+		h.GetDepartmentByCode(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestParseEmployeeFilter проверяет разбор параметров поиска сотрудников и фильтров по атрибутам
+func TestParseEmployeeFilter(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/employees/?department_id=3&q=ann&attr.office=Berlin&attr.floor=3&limit=5", nil)
+	filter, ok := parseEmployeeFilter(r)
+	if !ok {
+		t.Fatal("expected valid filter")
+	}
+	if filter.DepartmentID == nil || *filter.DepartmentID != 3 || filter.Query != "ann" || filter.Limit != 5 {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+	if len(filter.Attributes) != 2 || filter.Attributes["office"] != "Berlin" || filter.Attributes["floor"] != "3" {
+		t.Errorf("unexpected attribute filter: %v", filter.Attributes)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/employees/?status=on_leave,terminated", nil)
+	if filter, ok = parseEmployeeFilter(r); !ok || len(filter.Statuses) != 2 || filter.Statuses[1] != model.EmployeeTerminated {
+		t.Errorf("unexpected status filter: %v", filter.Statuses)
+	}
+
+	for _, query := range []string{"department_id=x", "offset=-1", "status=fired"} {
+		if _, ok := parseEmployeeFilter(httptest.NewRequest(http.MethodGet, "/employees/?"+query, nil)); ok {
+			t.Errorf("%s: expected invalid filter", query)
+		}
+	}
+}
+
+// TestParseEmployeeStatuses проверяет разбор параметра со статусами сотрудников
+func TestParseEmployeeStatuses(t *testing.T) {
+	q := url.Values{}
+	if statuses, ok := parseEmployeeStatuses(q, "employee_status"); !ok || statuses != nil {
+		t.Errorf("expected default statuses, got %v", statuses)
+	}
+	q.Set("employee_status", "all")
+	if statuses, ok := parseEmployeeStatuses(q, "employee_status"); !ok || len(statuses) != len(model.EmployeeStatuses) {
+		t.Errorf("expected all statuses, got %v", statuses)
+	}
+	q.Set("employee_status", "active, on_leave")
+	if statuses, ok := parseEmployeeStatuses(q, "employee_status"); !ok || len(statuses) != 2 || statuses[1] != model.EmployeeOnLeave {
+		t.Errorf("unexpected statuses %v", statuses)
+	}
+	for _, v := range []string{"active,", "ACTIVE", "fired"} {
+		q.Set("employee_status", v)
+		if _, ok := parseEmployeeStatuses(q, "employee_status"); ok {
+			t.Errorf("%q: expected invalid statuses", v)
+		}
+	}
+
+	h := &Handler{}
+	w := httptest.NewRecorder()
+	h.GetDepartment(w, httptest.NewRequest(http.MethodGet, "/departments/1?employee_status=fired", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestChangeEmployeeStatus_InvalidRequest проверяет отказ для некорректных запросов до обращения к БД
+func TestChangeEmployeeStatus_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	for _, tt := range []struct{ path, body string }{
+		{"/departments/1/employees/x/status", `{"status":"active"}`},
+		{"/departments/1/employees/2", `{"status":"active"}`},
+		{"/departments/1/employees/2/status", "{"},
+	} {
+		w := httptest.NewRecorder()
+		h.ChangeEmployeeStatus(w, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected status %d, got %d", tt.path, tt.body, http.StatusBadRequest, w.Code)
+		}
+	}
+}
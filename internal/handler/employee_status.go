@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// ChangeEmployeeStatus переводит сотрудника в новый статус
+// (POST /departments/{id}/employees/{empID}/status)
+func (h *Handler) ChangeEmployeeStatus(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/status")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	deptID, empID, ok := parseEmployeePath(path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	var req model.ChangeEmployeeStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writePreconditionError(w, err)
+		return
+	}
+
+	emp, err := h.serviceFor(r).ChangeEmployeeStatus(deptID, empID, req, version)
+	if err != nil {
+		h.writePatchError(w, err)
+		return
+	}
+	h.writeJSONWithETag(w, r, http.StatusOK, emp.Version, emp)
+}
+
+// parseEmployeeStatuses разбирает список статусов через запятую из параметра name.
+// all — все статусы, отсутствие параметра — значение по умолчанию (nil).
+func parseEmployeeStatuses(q url.Values, name string) ([]string, bool) {
+	v := q.Get(name)
+	if v == "" {
+		return nil, true
+	}
+	if v == "all" {
+		return model.EmployeeStatuses, true
+	}
+	var statuses []string
+	for _, s := range strings.Split(v, ",") {
+		s = strings.TrimSpace(s)
+		if !model.ValidEmployeeStatus(s) {
+			return nil, false
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, true
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	errIfMatchRequired = errors.New("If-Match header required")
+	errInvalidIfMatch  = errors.New("invalid If-Match header")
+)
+
+// entityTag формирует ETag вида "v<version>-<hash>".
+// Версия корневой записи используется для If-Match, хеш тела — для If-None-Match,
+// поэтому изменение любого узла дерева в ответе меняет ETag.
+func entityTag(version int, body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"v%d-%s"`, version, hex.EncodeToString(sum[:8]))
+}
+
+// writeJSONWithETag отвечает JSON с заголовком ETag.
+// Для GET с совпадающим If-None-Match возвращает 304 без тела.
+func (h *Handler) writeJSONWithETag(w http.ResponseWriter, r *http.Request, status int, version int, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	tag := entityTag(version, body)
+	w.Header().Set("ETag", tag)
+
+	if r.Method == http.MethodGet && noneMatch(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
+
+// noneMatch сообщает, совпадает ли If-None-Match с текущим ETag (слабое сравнение)
+func noneMatch(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion извлекает ожидаемую версию из обязательного заголовка If-Match.
+// "*" означает любую версию и возвращается как 0.
+func ifMatchVersion(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, errIfMatchRequired
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	// Слабые ETag не подходят для If-Match (RFC 9110, строгое сравнение)
+	if strings.HasPrefix(header, "W/") || strings.Contains(header, ",") {
+		return 0, errInvalidIfMatch
+	}
+	tag := strings.Trim(header, `"`)
+	if !strings.HasPrefix(tag, "v") {
+		return 0, errInvalidIfMatch
+	}
+	tag, _, _ = strings.Cut(tag[1:], "-")
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// writePreconditionError отвечает на ошибку разбора If-Match
+func (h *Handler) writePreconditionError(w http.ResponseWriter, err error) {
+	if err == errIfMatchRequired {
+		h.WriteError(w, http.StatusPreconditionRequired, err.Error())
+	} else {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestIfMatchVersion проверяет разбор заголовка If-Match
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected int
+		err      error
+	}{
+		{"missing", "", 0, errIfMatchRequired},
+		{"any", "*", 0, nil},
+		{"full tag", `"v3-0011223344556677"`, 3, nil},
+		{"version only", `"v7"`, 7, nil},
+		{"weak tag", `W/"v3-0011223344556677"`, 0, errInvalidIfMatch},
+		{"list", `"v1", "v2"`, 0, errInvalidIfMatch},
+		{"garbage", `"abc"`, 0, errInvalidIfMatch},
+		{"zero version", `"v0"`, 0, errInvalidIfMatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/departments/1", nil)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+
+			version, err := ifMatchVersion(req)
+			if err != tt.err || version != tt.expected {
+				t.Errorf("expected (%d, %v), got (%d, %v)", tt.expected, tt.err, version, err)
+			}
+		})
+	}
+}
+
+// TestWriteJSONWithETag_NotModified проверяет ответ 304 при совпадении If-None-Match
+func TestWriteJSONWithETag_NotModified(t *testing.T) {
+	h := &Handler{}
+	data := map[string]int{"id": 1}
+
+	w := httptest.NewRecorder()
+	h.writeJSONWithETag(w, httptest.NewRequest(http.MethodGet, "/departments/1", nil), http.StatusOK, 2, data)
+	tag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || tag == "" {
+		t.Fatalf("expected 200 with ETag, got %d %q", w.Code, tag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1", nil)
+	req.Header.Set("If-None-Match", `"other", `+tag)
+	w = httptest.NewRecorder()
+	h.writeJSONWithETag(w, req, http.StatusOK, 2, data)
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("expected 304 without body, got %d", w.Code)
+	}
+
+	// Изменение данных меняет ETag даже при той же версии корня
+	w = httptest.NewRecorder()
+	h.writeJSONWithETag(w, req, http.StatusOK, 2, map[string]int{"id": 2})
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 for changed body, got %d", w.Code)
+	}
+}
+
+// TestDepartmentMutations_RequireIfMatch проверяет, что PATCH и DELETE требуют If-Match
+func TestDepartmentMutations_RequireIfMatch(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPatch, "/departments/1", bytes.NewReader([]byte(`{"name":"A"}`)))
+	w := httptest.NewRecorder()
+	h.UpdateDepartment(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("PATCH: expected status %d, got %d", http.StatusPreconditionRequired, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/departments/1", nil)
+	w = httptest.NewRecorder()
+	h.DeleteDepartment(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("DELETE: expected status %d, got %d", http.StatusPreconditionRequired, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/departments/1", nil)
+	req.Header.Set("If-Match", `W/"v1"`)
+	w = httptest.NewRecorder()
+	h.DeleteDepartment(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("DELETE weak tag: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// Интервалы опроса ленты и отправки комментария-пинга (не даёт прокси закрыть соединение)
+var (
+	streamPollInterval = time.Second
+	streamPingInterval = 15 * time.Second
+)
+
+// streamRetryMillis задержка переподключения, рекомендуемая клиенту
+const streamRetryMillis = 3000
+
+// StreamEvents отдаёт ленту изменений в формате Server-Sent Events.
+// Параметры: department_id — корень поддерева, event_types — типы через запятую.
+// Возобновление — по заголовку Last-Event-ID или параметру last_event_id.
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	var rootID *int
+	if val := r.URL.Query().Get("department_id"); val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid department_id")
+			return
+		}
+		rootID = &id
+	}
+
+	var eventTypes []string
+	for _, t := range strings.Split(r.URL.Query().Get("event_types"), ",") {
+		if t = strings.TrimSpace(t); t == "" {
+			continue
+		}
+		if t == model.EventAll || !model.ValidEventType(t) {
+			h.WriteError(w, http.StatusBadRequest, "invalid event type: "+t)
+			return
+		}
+		eventTypes = append(eventTypes, t)
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	after := -1
+	if lastID != "" {
+		val, err := strconv.Atoi(lastID)
+		if err != nil || val < 0 {
+			h.WriteError(w, http.StatusBadRequest, "invalid last event id")
+			return
+		}
+		after = val
+	}
+
+	svc := h.serviceFor(r)
+
+	// Новый клиент получает только события, опубликованные после подключения
+	if after < 0 {
+		seq, err := svc.LatestEventSeq(rootID)
+		if err != nil {
+			h.writeStreamError(w, err)
+			return
+		}
+		after = seq
+	}
+
+	// Первая выборка до отправки заголовков, чтобы ошибки вернулись обычным ответом
+	events, err := svc.ListEvents(after, rootID, eventTypes, 0)
+	if err != nil {
+		h.writeStreamError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+
+	poll := time.NewTicker(streamPollInterval)
+	defer poll.Stop()
+	lastWrite := time.Now()
+
+	for {
+		for i := range events {
+			if err := writeSSE(w, &events[i]); err != nil {
+				return
+			}
+			after = *events[i].Seq
+		}
+		if len(events) > 0 {
+			lastWrite = time.Now()
+		} else if time.Since(lastWrite) >= streamPingInterval {
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			lastWrite = time.Now()
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-poll.C:
+		}
+
+		// Права перепроверяются на каждой выборке: отзыв роли закрывает поток
+		events, err = svc.ListEvents(after, rootID, eventTypes, 0)
+		if err != nil {
+			return
+		}
+	}
+}
+
+// writeSSE записывает событие; id — порядковый номер публикации
+func writeSSE(w http.ResponseWriter, event *model.OutboxEvent) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", *event.Seq, event.EventType, event.Payload)
+	return err
+}
+
+func (h *Handler) writeStreamError(w http.ResponseWriter, err error) {
+	if err == service.ErrForbidden {
+		h.WriteError(w, http.StatusForbidden, err.Error())
+	} else {
+		h.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
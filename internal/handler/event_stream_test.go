@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// TestStreamEvents_InvalidParams проверяет разбор параметров ленты событий
+func TestStreamEvents_InvalidParams(t *testing.T) {
+	h := &Handler{}
+
+	tests := []struct {
+		name   string
+		path   string
+		lastID string
+	}{
+		{"invalid department id", "/events/stream?department_id=abc", ""},
+		{"invalid event type", "/events/stream?event_types=department.created,unknown", ""},
+		{"wildcard event type", "/events/stream?event_types=*", ""},
+		{"invalid header", "/events/stream", "abc"},
+		{"negative query", "/events/stream?last_event_id=-5", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.lastID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastID)
+			}
+			w := httptest.NewRecorder()
+
+			h.StreamEvents(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
+
+// TestWriteSSE проверяет формат события
+func TestWriteSSE(t *testing.T) {
+	seq := 42
+	w := httptest.NewRecorder()
+
+	err := writeSSE(w, &model.OutboxEvent{
+		Seq:       &seq,
+		EventType: model.EventDepartmentMoved,
+		Payload:   model.JSON(`{"id":"abc"}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "id: 42\nevent: department.moved\ndata: {\"id\":\"abc\"}\n\n"
+	if w.Body.String() != expected {
+		t.Errorf("expected %q, got %q", expected, w.Body.String())
+	}
+	if strings.Count(w.Body.String(), "\n\n") != 1 {
+		t.Error("event must be terminated by a single blank line")
+	}
+}
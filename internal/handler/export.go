@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/SergeiKhy/org-structure-api/internal/ldif"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// WithLDIFBaseDN задаёт базовый DN выгрузки LDIF по умолчанию
+func (h *Handler) WithLDIFBaseDN(dn string) *Handler {
+	h.ldifBaseDN = dn
+	return h
+}
+
+// ExportLDIF выгружает оргструктуру в формате LDIF (GET /export/ldif).
+// Параметр base_dn заменяет базовый DN из конфигурации.
+func (h *Handler) ExportLDIF(w http.ResponseWriter, r *http.Request) {
+	baseDN := h.ldifBaseDN
+	if v := r.URL.Query().Get("base_dn"); v != "" {
+		baseDN = v
+	}
+	if err := ldif.ValidateDN(baseDN); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid base_dn")
+		return
+	}
+
+	depts, employees, err := h.serviceFor(r).ExportDirectory()
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// Выгрузка формируется целиком до отправки, чтобы ошибка не оборвала ответ со статусом 200
+	var buf bytes.Buffer
+	if err := ldif.Write(&buf, baseDN, depts, employees); err != nil {
+		h.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "text/x-ldif; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="org.ldif"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestExportLDIF_InvalidBaseDN проверяет отказ для некорректного базового DN до обращения к БД
+func TestExportLDIF_InvalidBaseDN(t *testing.T) {
+	for _, tt := range []struct {
+		configured string
+		query      string
+	}{
+		{"dc=example,dc=com", "example.com"},
+		{"", ""},
+		{"dc=example,dc=com", "dc=example,"},
+	} {
+		h := (&Handler{}).WithLDIFBaseDN(tt.configured)
+		target := "/export/ldif"
+		if tt.query != "" {
+			target += "?base_dn=" + url.QueryEscape(tt.query)
+		}
+		w := httptest.NewRecorder()
+		h.ExportLDIF(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q/%q: expected status %d, got %d", tt.configured, tt.query, http.StatusBadRequest, w.Code)
+		}
+	}
+}
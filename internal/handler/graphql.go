@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SergeiKhy/org-structure-api/internal/graphql"
+)
+
+// maxGraphQLBodySize ограничивает размер тела запроса GraphQL
+const maxGraphQLBodySize = 1 << 20
+
+// WithGraphQL подключает схему GraphQL
+func (h *Handler) WithGraphQL(schema *graphql.Schema) *Handler {
+	h.graphql = schema
+	return h
+}
+
+// GraphQL выполняет запрос GraphQL от имени субъекта запроса.
+// Ошибки полей возвращаются в массиве errors со статусом 200.
+func (h *Handler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	if h.graphql == nil {
+		h.WriteError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	var req graphql.Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBodySize)).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if req.Query == "" {
+		h.WriteError(w, http.StatusBadRequest, "query required")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.graphql.Execute(r.Context(), h.serviceFor(r), req))
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/graphql"
+)
+
+// TestGraphQL_InvalidRequest проверяет отказ для неверного тела запроса
+func TestGraphQL_InvalidRequest(t *testing.T) {
+	schema, err := graphql.NewSchema()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h := (&Handler{}).WithGraphQL(schema)
+
+	for _, body := range []string{`{"query":`, `{"query":""}`} {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+		h.GraphQL(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", body, http.StatusBadRequest, w.Code)
+		}
+	}
+}
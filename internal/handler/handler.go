@@ -2,20 +2,26 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
+	"github.com/SergeiKhy/org-structure-api/internal/graphql"
+	"github.com/SergeiKhy/org-structure-api/internal/lint"
 	"github.com/SergeiKhy/org-structure-api/internal/model"
 	"github.com/SergeiKhy/org-structure-api/internal/service"
 )
 
 type Handler struct {
-	service *service.Service
+	service    *service.Service
+	graphql    *graphql.Schema
+	ldifBaseDN string
+	lintConfig lint.Config
 }
 
 func NewHandler(s *service.Service) *Handler {
-	return &Handler{service: s}
+	return &Handler{service: s, lintConfig: lint.DefaultConfig()}
 }
 
 func (h *Handler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -37,13 +43,21 @@ func (h *Handler) CreateDepartment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dept, err := h.service.CreateDepartment(req)
+	dept, err := h.serviceFor(r).CreateDepartment(req)
 	if err != nil {
-		h.WriteError(w, http.StatusBadGateway, err.Error())
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if err == service.ErrDuplicateCode {
+			h.WriteError(w, http.StatusConflict, err.Error())
+		} else if errors.Is(err, service.ErrInvalidAttributes) {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		} else {
+			h.WriteError(w, http.StatusBadGateway, err.Error())
+		}
 		return
 	}
 
-	h.writeJSON(w, http.StatusCreated, dept)
+	h.writeJSONWithETag(w, r, http.StatusCreated, dept.Version, dept)
 }
 
 func (h *Handler) UpdateDepartment(w http.ResponseWriter, r *http.Request) {
@@ -60,25 +74,43 @@ func (h *Handler) UpdateDepartment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Перенос и переименование с будущей датой effective_at откладываются
+	effectiveAt, ok := h.scheduled(w, r)
+	if !ok {
+		return
+	}
+	if effectiveAt != nil {
+		h.scheduleDepartmentChange(w, r, id, *effectiveAt)
+		return
+	}
+
+	// Формат тела определяется Content-Type; application/json — прежний формат запроса
+	switch mt := patchMediaType(r); mt {
+	case "", "application/json":
+	default:
+		h.patchDepartment(w, r, id, mt)
+		return
+	}
+
 	var req model.UpdateDepartmentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.WriteError(w, http.StatusBadRequest, "invalid json")
 		return
 	}
 
-	dept, err := h.service.UpdateDepartment(id, req)
+	version, err := ifMatchVersion(r)
 	if err != nil {
-		if err == service.ErrNotFound {
-			h.WriteError(w, http.StatusNotFound, err.Error())
-		} else if err == service.ErrCycleDetected || err == service.ErrSelfParent {
-			h.WriteError(w, http.StatusConflict, err.Error())
-		} else {
-			h.WriteError(w, http.StatusBadRequest, err.Error())
-		}
+		h.writePreconditionError(w, err)
+		return
+	}
+
+	dept, err := h.serviceFor(r).UpdateDepartment(id, req, version)
+	if err != nil {
+		h.writePatchError(w, err)
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, dept)
+	h.writeJSONWithETag(w, r, http.StatusOK, dept.Version, dept)
 }
 
 func (h *Handler) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
@@ -111,9 +143,22 @@ func (h *Handler) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
 		reassignToID = &idVal
 	}
 
-	if err := h.service.DeleteDepartment(id, mode, reassignToID); err != nil {
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writePreconditionError(w, err)
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteDepartment(id, mode, reassignToID, version); err != nil {
+		if h.writeApprovalRequired(w, err) {
+			return
+		}
 		if err == service.ErrNotFound {
 			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if err == service.ErrVersionMismatch {
+			h.WriteError(w, http.StatusPreconditionFailed, err.Error())
 		} else {
 			h.WriteError(w, http.StatusInternalServerError, err.Error())
 		}
@@ -131,25 +176,40 @@ func (h *Handler) GetDepartment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	depth := 1
-	if d := r.URL.Query().Get("depth"); d != "" {
-		if val, err := strconv.Atoi(d); err == nil {
-			depth = val
-		}
-	}
-
 	includeEmployees := true
 	if ie := r.URL.Query().Get("include_employees"); ie == "false" {
 		includeEmployees = false
 	}
 
-	dept, err := h.service.GetDepartmentTree(id, depth, includeEmployees)
+	statuses, ok := parseEmployeeStatuses(r.URL.Query(), "employee_status")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid employee_status")
+		return
+	}
+
+	dept, err := h.serviceFor(r).GetDepartmentTreeByStatus(id, queryDepth(r), includeEmployees, statuses)
 	if err != nil {
-		h.WriteError(w, http.StatusNotFound, "not found")
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusNotFound, "not found")
+		}
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, dept)
+	w.Header().Set("Accept-Patch", acceptPatch)
+	h.writeJSONWithETag(w, r, http.StatusOK, dept.Version, dept)
+}
+
+// queryDepth глубина дерева из параметра depth; по умолчанию 1
+func queryDepth(r *http.Request) int {
+	depth := 1
+	if d := r.URL.Query().Get("depth"); d != "" {
+		if val, err := strconv.Atoi(d); err == nil {
+			depth = val
+		}
+	}
+	return depth
 }
 
 func (h *Handler) CreateEmployee(w http.ResponseWriter, r *http.Request) {
@@ -173,15 +233,19 @@ func (h *Handler) CreateEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	emp, err := h.service.CreateEmployee(deptID, req)
+	emp, err := h.serviceFor(r).CreateEmployee(deptID, req)
 	if err != nil {
 		if err == service.ErrNotFound {
 			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if err == service.ErrDuplicateUserName {
+			h.WriteError(w, http.StatusConflict, err.Error())
 		} else {
 			h.WriteError(w, http.StatusBadRequest, err.Error())
 		}
 		return
 	}
 
-	h.writeJSON(w, http.StatusCreated, emp)
+	h.writeJSONWithETag(w, r, http.StatusCreated, emp.Version, emp)
 }
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/logger"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// IdempotencyKeyHeader заголовок, по которому повтор запроса возвращает исходный результат
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	maxIdempotencyKeyLength = 255
+	// Тело запроса целиком читается в память для вычисления отпечатка
+	maxIdempotentBodySize = 1 << 20
+)
+
+// Idempotent обрабатывает заголовок Idempotency-Key.
+// Повтор с тем же ключом и телом возвращает сохранённый ответ,
+// тот же ключ с другим запросом — 422, пока исходный запрос выполняется — 409.
+// Ответы 5xx не сохраняются: такой запрос можно повторить с тем же ключом.
+func (h *Handler) Idempotent(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(IdempotencyKeyHeader))
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			h.WriteError(w, http.StatusBadRequest, "invalid idempotency key")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			h.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		svc := h.serviceFor(r)
+		record, err := svc.ReserveIdempotencyKey(key, requestFingerprint(r, body))
+		if err != nil {
+			if err == service.ErrIdempotencyKeyReused {
+				h.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+			} else if err == service.ErrIdempotencyKeyInProgress {
+				h.WriteError(w, http.StatusConflict, err.Error())
+			} else {
+				h.WriteError(w, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		if record.Completed() {
+			if record.ResponseETag != "" {
+				w.Header().Set("ETag", record.ResponseETag)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(record.ResponseStatus)
+			w.Write(record.ResponseBody)
+			return
+		}
+
+		rec := &capturingWriter{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r)
+
+		if rec.status >= http.StatusInternalServerError {
+			err = svc.ReleaseIdempotencyKey(record)
+		} else {
+			err = svc.CompleteIdempotencyKey(record, rec.status, rec.body.Bytes(), rec.Header().Get("ETag"))
+		}
+		if err != nil {
+			logger.Error("ошибка сохранения результата идемпотентного запроса",
+				slog.String("key", key),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// requestFingerprint отпечаток запроса: метод, путь с параметрами и тело
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// capturingWriter передаёт ответ клиенту и сохраняет его копию
+type capturingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (cw *capturingWriter) WriteHeader(code int) {
+	cw.status = code
+	cw.ResponseWriter.WriteHeader(code)
+}
+
+func (cw *capturingWriter) Write(b []byte) (int, error) {
+	cw.body.Write(b)
+	return cw.ResponseWriter.Write(b)
+}
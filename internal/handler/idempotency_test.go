@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestIdempotent_WithoutKey проверяет, что запрос без ключа обрабатывается как обычно
+func TestIdempotent_WithoutKey(t *testing.T) {
+	h := &Handler{}
+	called := false
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/", bytes.NewReader([]byte(`{}`)))
+	w := httptest.NewRecorder()
+
+	h.Idempotent(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusCreated)
+	})(w, req)
+
+	if !called || w.Code != http.StatusCreated {
+		t.Errorf("expected handler to be called, got status %d", w.Code)
+	}
+}
+
+// TestIdempotent_InvalidKey проверяет отказ для слишком длинного ключа
+func TestIdempotent_InvalidKey(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPost, "/departments/", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set(IdempotencyKeyHeader, strings.Repeat("k", maxIdempotencyKeyLength+1))
+	w := httptest.NewRecorder()
+
+	h.Idempotent(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	})(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestRequestFingerprint проверяет, что отпечаток зависит от пути и тела
+func TestRequestFingerprint(t *testing.T) {
+	base := requestFingerprint(httptest.NewRequest(http.MethodPost, "/departments/1/employees/", nil), []byte(`{"a":1}`))
+
+	if base != requestFingerprint(httptest.NewRequest(http.MethodPost, "/departments/1/employees/", nil), []byte(`{"a":1}`)) {
+		t.Error("same request should produce the same fingerprint")
+	}
+	if base == requestFingerprint(httptest.NewRequest(http.MethodPost, "/departments/2/employees/", nil), []byte(`{"a":1}`)) {
+		t.Error("different path should change the fingerprint")
+	}
+	if base == requestFingerprint(httptest.NewRequest(http.MethodPost, "/departments/1/employees/", nil), []byte(`{"a":2}`)) {
+		t.Error("different body should change the fingerprint")
+	}
+}
+
+// TestCapturingWriter проверяет, что ответ уходит клиенту и сохраняется
+func TestCapturingWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	cw := &capturingWriter{ResponseWriter: w, status: http.StatusOK}
+
+	cw.WriteHeader(http.StatusCreated)
+	cw.Write([]byte(`{"id":1}`))
+
+	if cw.status != http.StatusCreated || cw.body.String() != `{"id":1}` {
+		t.Errorf("unexpected capture: %d %q", cw.status, cw.body.String())
+	}
+	if w.Code != http.StatusCreated || w.Body.String() != `{"id":1}` {
+		t.Errorf("response should be passed through, got %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/lint"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// WithLintConfig задаёт правила проверки оргструктуры по умолчанию
+func (h *Handler) WithLintConfig(cfg lint.Config) *Handler {
+	h.lintConfig = cfg
+	return h
+}
+
+// LintOrg проверяет оргструктуру (GET /org/lint).
+// Параметры rules, max_depth, max_team_size и severity.<rule> заменяют настройки из конфигурации.
+func (h *Handler) LintOrg(w http.ResponseWriter, r *http.Request) {
+	cfg, err := lintConfigFromQuery(h.lintConfig, r.URL.Query())
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.serviceFor(r).LintOrg(cfg)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// lintConfigFromQuery применяет параметры запроса к конфигурации base и проверяет результат
+func lintConfigFromQuery(base lint.Config, q url.Values) (lint.Config, error) {
+	cfg := base
+	if v := q.Get("rules"); v != "" {
+		cfg.Rules = nil
+		for _, rule := range strings.Split(v, ",") {
+			cfg.Rules = append(cfg.Rules, strings.TrimSpace(rule))
+		}
+	}
+	for name, target := range map[string]*int{"max_depth": &cfg.MaxDepth, "max_team_size": &cfg.MaxTeamSize} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return cfg, errors.New("invalid " + name)
+			}
+			*target = n
+		}
+	}
+	cfg.Severities = maps.Clone(base.Severities)
+	for key := range q {
+		rule, ok := strings.CutPrefix(key, "severity.")
+		if !ok {
+			continue
+		}
+		if cfg.Severities == nil {
+			cfg.Severities = make(map[string]string)
+		}
+		cfg.Severities[rule] = q.Get(key)
+	}
+	return cfg, cfg.Validate()
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/lint"
+)
+
+func TestLintConfigFromQuery(t *testing.T) {
+	base := lint.DefaultConfig()
+	base.Severities = map[string]string{lint.RuleMaxDepth: lint.SeverityWarning}
+
+	q := url.Values{
+		"rules":              {"team_size, max_depth"},
+		"max_depth":          {"4"},
+		"max_team_size":      {"10"},
+		"severity.team_size": {"error"},
+	}
+	cfg, err := lintConfigFromQuery(base, q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := lint.Config{
+		Rules:       []string{lint.RuleTeamSize, lint.RuleMaxDepth},
+		MaxDepth:    4,
+		MaxTeamSize: 10,
+		Severities:  map[string]string{lint.RuleMaxDepth: lint.SeverityWarning, lint.RuleTeamSize: lint.SeverityError},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("expected %+v, got %+v", want, cfg)
+	}
+	if len(base.Severities) != 1 {
+		t.Errorf("base config modified: %+v", base.Severities)
+	}
+
+	if cfg, err := lintConfigFromQuery(base, url.Values{}); err != nil || !reflect.DeepEqual(cfg, base) {
+		t.Errorf("expected base config, got %+v (%v)", cfg, err)
+	}
+}
+
+func TestLintOrg_InvalidRequest(t *testing.T) {
+	h := &Handler{lintConfig: lint.DefaultConfig()}
+	for _, query := range []string{"rules=deep", "max_depth=x", "max_team_size=0", "severity.team_size=fatal", "severity.deep=error"} {
+		w := httptest.NewRecorder()
+		h.LintOrg(w, httptest.NewRequest(http.MethodGet, "/org/lint?"+query, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", query, http.StatusBadRequest, w.Code)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/jsonpatch"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+const (
+	mediaTypeMergePatch = "application/merge-patch+json"
+	mediaTypeJSONPatch  = "application/json-patch+json"
+
+	// acceptPatch перечисляет поддерживаемые форматы PATCH (RFC 5789)
+	acceptPatch = mediaTypeMergePatch + ", " + mediaTypeJSONPatch
+)
+
+// patchMediaType возвращает тип тела PATCH-запроса без параметров
+func patchMediaType(r *http.Request) string {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ct
+	}
+	return mt
+}
+
+// decodeMergePatch строго разбирает тело merge patch: неизвестные поля — ошибка
+func decodeMergePatch(r *http.Request, patch any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	return dec.Decode(patch)
+}
+
+func (h *Handler) writeUnsupportedPatch(w http.ResponseWriter) {
+	w.Header().Set("Accept-Patch", acceptPatch)
+	h.WriteError(w, http.StatusUnsupportedMediaType, "unsupported patch media type")
+}
+
+// writePatchError сопоставляет ошибки частичного изменения со статусами HTTP
+// Перенос, отложенный до согласования, отвечает 202 Accepted с запросом на изменение.
+func (h *Handler) writePatchError(w http.ResponseWriter, err error) {
+	if h.writeApprovalRequired(w, err) {
+		return
+	}
+	if err == service.ErrNotFound {
+		h.WriteError(w, http.StatusNotFound, err.Error())
+	} else if err == service.ErrForbidden {
+		h.WriteError(w, http.StatusForbidden, err.Error())
+	} else if err == service.ErrVersionMismatch {
+		h.WriteError(w, http.StatusPreconditionFailed, err.Error())
+	} else if err == service.ErrCycleDetected || err == service.ErrSelfParent || err == service.ErrDuplicateUserName || err == service.ErrDuplicateCode || err == service.ErrInvalidTransition || errors.Is(err, jsonpatch.ErrTestFailed) {
+		h.WriteError(w, http.StatusConflict, err.Error())
+	} else if errors.Is(err, jsonpatch.ErrPathNotFound) {
+		h.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+	} else {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+// patchDepartment обрабатывает PATCH /departments/{id} с телом merge patch или JSON Patch
+func (h *Handler) patchDepartment(w http.ResponseWriter, r *http.Request, id int, mediaType string) {
+	var dept *model.Department
+	switch mediaType {
+	case mediaTypeMergePatch:
+		var patch model.DepartmentPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid merge patch")
+			return
+		}
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.writePreconditionError(w, err)
+			return
+		}
+		if dept, err = h.serviceFor(r).PatchDepartment(id, patch, version); err != nil {
+			h.writePatchError(w, err)
+			return
+		}
+	case mediaTypeJSONPatch:
+		ops, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.writePreconditionError(w, err)
+			return
+		}
+		if dept, err = h.serviceFor(r).ApplyDepartmentJSONPatch(id, ops, version); err != nil {
+			h.writePatchError(w, err)
+			return
+		}
+	default:
+		h.writeUnsupportedPatch(w)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusOK, dept.Version, dept)
+}
+
+// parseEmployeePath извлекает ID подразделения и сотрудника из /departments/{id}/employees/{empID}
+func parseEmployeePath(path string) (int, int, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "departments" || parts[2] != "employees" {
+		return 0, 0, false
+	}
+	deptID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	empID, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return 0, 0, false
+	}
+	return deptID, empID, true
+}
+
+// GetEmployee возвращает сотрудника подразделения (GET /departments/{id}/employees/{empID})
+func (h *Handler) GetEmployee(w http.ResponseWriter, r *http.Request) {
+	deptID, empID, ok := parseEmployeePath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	emp, err := h.serviceFor(r).GetEmployee(deptID, empID)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusNotFound, "not found")
+		}
+		return
+	}
+
+	w.Header().Set("Accept-Patch", acceptPatch)
+	h.writeJSONWithETag(w, r, http.StatusOK, emp.Version, emp)
+}
+
+// PatchEmployee частично изменяет сотрудника (PATCH /departments/{id}/employees/{empID}).
+// Тело application/json трактуется как merge patch.
+func (h *Handler) PatchEmployee(w http.ResponseWriter, r *http.Request) {
+	deptID, empID, ok := parseEmployeePath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	// Перевод с будущей датой effective_at откладывается
+	effectiveAt, ok := h.scheduled(w, r)
+	if !ok {
+		return
+	}
+	if effectiveAt != nil {
+		h.scheduleEmployeeTransfer(w, r, deptID, empID, *effectiveAt)
+		return
+	}
+
+	var emp *model.Employee
+	switch patchMediaType(r) {
+	case "", "application/json", mediaTypeMergePatch:
+		var patch model.EmployeePatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid merge patch")
+			return
+		}
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.writePreconditionError(w, err)
+			return
+		}
+		if emp, err = h.serviceFor(r).PatchEmployee(deptID, empID, patch, version); err != nil {
+			h.writePatchError(w, err)
+			return
+		}
+	case mediaTypeJSONPatch:
+		ops, err := io.ReadAll(r.Body)
+		if err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid body")
+			return
+		}
+		version, err := ifMatchVersion(r)
+		if err != nil {
+			h.writePreconditionError(w, err)
+			return
+		}
+		if emp, err = h.serviceFor(r).ApplyEmployeeJSONPatch(deptID, empID, ops, version); err != nil {
+			h.writePatchError(w, err)
+			return
+		}
+	default:
+		h.writeUnsupportedPatch(w)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusOK, emp.Version, emp)
+}
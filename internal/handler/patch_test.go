@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestPatchMediaType проверяет выделение типа тела PATCH-запроса
+func TestPatchMediaType(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", ""},
+		{"application/json", "application/json"},
+		{"application/merge-patch+json; charset=utf-8", mediaTypeMergePatch},
+		{"Application/JSON-Patch+JSON", mediaTypeJSONPatch},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPatch, "/departments/1", nil)
+		if tt.header != "" {
+			req.Header.Set("Content-Type", tt.header)
+		}
+		if got := patchMediaType(req); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.header, tt.expected, got)
+		}
+	}
+}
+
+// TestUpdateDepartment_UnsupportedMediaType проверяет ответ 415 с заголовком Accept-Patch
+func TestUpdateDepartment_UnsupportedMediaType(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPatch, "/departments/1", bytes.NewReader([]byte(`name=A`)))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	h.UpdateDepartment(w, req)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status %d, got %d", http.StatusUnsupportedMediaType, w.Code)
+	}
+	if w.Header().Get("Accept-Patch") != acceptPatch {
+		t.Errorf("expected Accept-Patch %q, got %q", acceptPatch, w.Header().Get("Accept-Patch"))
+	}
+}
+
+// TestUpdateDepartment_InvalidMergePatch проверяет отказ для неизвестных полей и неверного JSON
+func TestUpdateDepartment_InvalidMergePatch(t *testing.T) {
+	h := &Handler{}
+
+	for _, body := range []string{`{"title":"A"}`, `{"name":`, `{"parent_id":"x"}`} {
+		req := httptest.NewRequest(http.MethodPatch, "/departments/1", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", mediaTypeMergePatch)
+		req.Header.Set("If-Match", "*")
+		w := httptest.NewRecorder()
+		h.UpdateDepartment(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", body, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// TestPatchEmployee_RequiresIfMatch проверяет, что изменение сотрудника требует If-Match
+func TestPatchEmployee_RequiresIfMatch(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPatch, "/departments/1/employees/2", bytes.NewReader([]byte(`{"position":"Lead"}`)))
+	w := httptest.NewRecorder()
+	h.PatchEmployee(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected status %d, got %d", http.StatusPreconditionRequired, w.Code)
+	}
+}
+
+// TestParseEmployeePath проверяет разбор пути сотрудника
+func TestParseEmployeePath(t *testing.T) {
+	tests := []struct {
+		path   string
+		deptID int
+		empID  int
+		ok     bool
+	}{
+		{"/departments/1/employees/2", 1, 2, true},
+		{"/departments/1/employees/2/", 1, 2, true},
+		{"/departments/1/employees/", 0, 0, false},
+		{"/departments/x/employees/2", 0, 0, false},
+		{"/departments/1/employees/2/extra", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		deptID, empID, ok := parseEmployeePath(tt.path)
+		if deptID != tt.deptID || empID != tt.empID || ok != tt.ok {
+			t.Errorf("%s: expected (%d, %d, %v), got (%d, %d, %v)", tt.path, tt.deptID, tt.empID, tt.ok, deptID, empID, ok)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+func (h *Handler) ListPositions(w http.ResponseWriter, r *http.Request) {
+	positions, err := h.serviceFor(r).ListPositions(r.URL.Query().Get("job_family"))
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, positions)
+}
+
+func (h *Handler) CreatePosition(w http.ResponseWriter, r *http.Request) {
+	var req model.CreatePositionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	pos, err := h.serviceFor(r).CreatePosition(req)
+	if err != nil {
+		h.writePositionError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, pos)
+}
+
+func (h *Handler) GetPosition(w http.ResponseWriter, r *http.Request) {
+	id, ok := positionID(r)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	pos, err := h.serviceFor(r).GetPosition(id)
+	if err != nil {
+		h.writePositionError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, pos)
+}
+
+func (h *Handler) UpdatePosition(w http.ResponseWriter, r *http.Request) {
+	id, ok := positionID(r)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req model.UpdatePositionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	pos, err := h.serviceFor(r).UpdatePosition(id, req)
+	if err != nil {
+		h.writePositionError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, pos)
+}
+
+func (h *Handler) DeletePosition(w http.ResponseWriter, r *http.Request) {
+	id, ok := positionID(r)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.serviceFor(r).DeletePosition(id); err != nil {
+		h.writePositionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PositionReport численность по семействам должностей и грейдам
+// (GET /reports/positions?department_id=1&employee_status=active,on_leave)
+func (h *Handler) PositionReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	var deptID *int
+	if v := q.Get("department_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid department_id")
+			return
+		}
+		deptID = &id
+	}
+	statuses, ok := parseEmployeeStatuses(q, "employee_status")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid employee_status")
+		return
+	}
+
+	report, err := h.serviceFor(r).PositionReport(deptID, statuses)
+	if err != nil {
+		h.writePositionError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// positionID извлекает ID из пути /positions/{id}
+func positionID(r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/positions/"))
+	return id, err == nil
+}
+
+func (h *Handler) writePositionError(w http.ResponseWriter, err error) {
+	if err == service.ErrNotFound {
+		h.WriteError(w, http.StatusNotFound, err.Error())
+	} else if err == service.ErrForbidden {
+		h.WriteError(w, http.StatusForbidden, err.Error())
+	} else if err == service.ErrDuplicatePosition || err == service.ErrPositionInUse {
+		h.WriteError(w, http.StatusConflict, err.Error())
+	} else {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestPositions_InvalidRequest проверяет отказ для некорректных запросов до обращения к БД
+func TestPositions_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *http.Request
+	}{
+		{"create invalid json", h.CreatePosition, httptest.NewRequest(http.MethodPost, "/positions", strings.NewReader("{"))},
+		{"get invalid id", h.GetPosition, httptest.NewRequest(http.MethodGet, "/positions/abc", nil)},
+		{"update invalid id", h.UpdatePosition, httptest.NewRequest(http.MethodPut, "/positions/abc", strings.NewReader("{}"))},
+		{"update invalid json", h.UpdatePosition, httptest.NewRequest(http.MethodPut, "/positions/1", strings.NewReader("{"))},
+		{"delete invalid id", h.DeletePosition, httptest.NewRequest(http.MethodDelete, "/positions/", nil)},
+		{"report invalid department", h.PositionReport, httptest.NewRequest(http.MethodGet, "/reports/positions?department_id=x", nil)},
+		{"report invalid status", h.PositionReport, httptest.NewRequest(http.MethodGet, "/reports/positions?employee_status=fired", nil)},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, tt.req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tt.name, http.StatusBadRequest, w.Code)
+		}
+	}
+}
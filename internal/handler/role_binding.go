@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+func (h *Handler) ListRoleBindings(w http.ResponseWriter, r *http.Request) {
+	var departmentID *int
+	if val := r.URL.Query().Get("department_id"); val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid department_id")
+			return
+		}
+		departmentID = &id
+	}
+
+	bindings, err := h.serviceFor(r).ListRoleBindings(r.URL.Query().Get("subject"), departmentID)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, bindings)
+}
+
+func (h *Handler) CreateRoleBinding(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateRoleBindingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	binding, err := h.serviceFor(r).CreateRoleBinding(req)
+	if err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else if err == service.ErrDuplicateRoleBinding {
+			h.WriteError(w, http.StatusConflict, err.Error())
+		} else {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, binding)
+}
+
+func (h *Handler) DeleteRoleBinding(w http.ResponseWriter, r *http.Request) {
+	// Путь: /admin/role-bindings/{id}
+	idStr := strings.TrimPrefix(r.URL.Path, "/admin/role-bindings/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteRoleBinding(id); err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
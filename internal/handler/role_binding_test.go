@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+)
+
+// TestCreateRoleBinding_InvalidJSON проверяет обработку невалидного тела запроса
+func TestCreateRoleBinding_InvalidJSON(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/role-bindings", bytes.NewReader([]byte("{")))
+	w := httptest.NewRecorder()
+
+	h.CreateRoleBinding(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestListRoleBindings_InvalidDepartmentID проверяет разбор фильтра department_id
+func TestListRoleBindings_InvalidDepartmentID(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/role-bindings?department_id=abc", nil)
+	w := httptest.NewRecorder()
+
+	h.ListRoleBindings(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestDeleteRoleBinding_InvalidID проверяет разбор ID из пути
+func TestDeleteRoleBinding_InvalidID(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/role-bindings/abc", nil)
+	w := httptest.NewRecorder()
+
+	h.DeleteRoleBinding(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestAuthenticate_Unauthorized проверяет отказ без учётных данных
+func TestAuthenticate_Unauthorized(t *testing.T) {
+	h := &Handler{}
+	a := auth.NewAuthenticator(true, "secret", nil)
+
+	called := false
+	next := h.Authenticate(a, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1", nil)
+	w := httptest.NewRecorder()
+	next(w, req)
+
+	if called {
+		t.Error("next handler should not be called")
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+// TestAuthenticate_StoresPrincipal проверяет сохранение субъекта в контексте
+func TestAuthenticate_StoresPrincipal(t *testing.T) {
+	h := &Handler{}
+	a := auth.NewAuthenticator(true, "secret", nil)
+	token, _ := auth.SignToken(auth.Claims{Subject: "alice"}, []byte("secret"))
+
+	var subject string
+	next := h.Authenticate(a, func(w http.ResponseWriter, r *http.Request) {
+		subject = auth.FromContext(r.Context()).Subject
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	next(httptest.NewRecorder(), req)
+
+	if subject != "alice" {
+		t.Errorf("expected subject 'alice', got %q", subject)
+	}
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+func (h *Handler) ListScenarios(w http.ResponseWriter, r *http.Request) {
+	scenarios, err := h.serviceFor(r).ListScenarios()
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, scenarios)
+}
+
+func (h *Handler) CreateScenario(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateScenarioRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	sc, err := h.serviceFor(r).CreateScenario(req)
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusCreated, sc.Version, sc)
+}
+
+func (h *Handler) GetScenario(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := parseScenarioPath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	sc, err := h.serviceFor(r).GetScenario(id)
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusOK, sc.Version, sc)
+}
+
+func (h *Handler) DeleteScenario(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := parseScenarioPath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteScenario(id); err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddScenarioOperation добавляет операцию в черновик (POST /scenarios/{id}/operations), If-Match — версия сценария
+func (h *Handler) AddScenarioOperation(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := parseScenarioPath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var op model.ScenarioOperation
+	if err := json.NewDecoder(r.Body).Decode(&op); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writePreconditionError(w, err)
+		return
+	}
+
+	sc, err := h.serviceFor(r).AddScenarioOperation(id, op, version)
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusOK, sc.Version, sc)
+}
+
+// RemoveScenarioOperation удаляет операцию черновика (DELETE /scenarios/{id}/operations/{index})
+func (h *Handler) RemoveScenarioOperation(w http.ResponseWriter, r *http.Request) {
+	id, rest, ok := parseScenarioPath(r.URL.Path)
+	indexStr, found := strings.CutPrefix(rest, "operations/")
+	if !ok || !found {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid index")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writePreconditionError(w, err)
+		return
+	}
+
+	sc, err := h.serviceFor(r).RemoveScenarioOperation(id, index, version)
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusOK, sc.Version, sc)
+}
+
+// GetScenarioTree прогнозируемая оргструктура (GET /scenarios/{id}/tree?include_employees=true)
+func (h *Handler) GetScenarioTree(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := parseScenarioPath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	tree, err := h.serviceFor(r).GetScenarioTree(id, r.URL.Query().Get("include_employees") == "true")
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tree)
+}
+
+// GetScenarioDiff изменения сценария относительно рабочих данных (GET /scenarios/{id}/diff)
+func (h *Handler) GetScenarioDiff(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := parseScenarioPath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	diff, err := h.serviceFor(r).GetScenarioDiff(id)
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, diff)
+}
+
+// ApplyScenario применяет сценарий в одной транзакции (POST /scenarios/{id}/apply).
+// При конфликтах возвращается 409 Conflict со списком conflicts.
+func (h *Handler) ApplyScenario(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := parseScenarioPath(r.URL.Path)
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writePreconditionError(w, err)
+		return
+	}
+
+	sc, conflicts, err := h.serviceFor(r).ApplyScenario(id, version)
+	if err == service.ErrScenarioConflict {
+		h.writeJSON(w, http.StatusConflict, map[string]any{"error": err.Error(), "conflicts": conflicts})
+		return
+	}
+	if err != nil {
+		h.writeScenarioError(w, err)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusOK, sc.Version, sc)
+}
+
+// parseScenarioPath извлекает ID сценария и остаток пути из /scenarios/{id}[/{rest}]
+func parseScenarioPath(path string) (int, string, bool) {
+	idStr, rest, _ := strings.Cut(strings.Trim(strings.TrimPrefix(path, "/scenarios/"), "/"), "/")
+	id, err := strconv.Atoi(idStr)
+	return id, rest, err == nil
+}
+
+func (h *Handler) writeScenarioError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrNotFound) {
+		h.WriteError(w, http.StatusNotFound, err.Error())
+	} else if errors.Is(err, service.ErrForbidden) {
+		h.WriteError(w, http.StatusForbidden, err.Error())
+	} else if err == service.ErrVersionMismatch {
+		h.WriteError(w, http.StatusPreconditionFailed, err.Error())
+	} else if err == service.ErrScenarioApplied || errors.Is(err, service.ErrCycleDetected) || errors.Is(err, service.ErrSelfParent) ||
+		errors.Is(err, service.ErrDuplicateName) || errors.Is(err, service.ErrApprovalRequired) {
+		h.WriteError(w, http.StatusConflict, err.Error())
+	} else {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
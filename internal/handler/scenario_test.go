@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestScenario_InvalidRequest проверяет отказ для некорректных запросов до обращения к БД
+func TestScenario_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *http.Request
+		status  int
+	}{
+		{"create invalid json", h.CreateScenario, httptest.NewRequest(http.MethodPost, "/scenarios", strings.NewReader("{")), http.StatusBadRequest},
+		{"get invalid id", h.GetScenario, httptest.NewRequest(http.MethodGet, "/scenarios/abc", nil), http.StatusBadRequest},
+		{"add operation invalid json", h.AddScenarioOperation, httptest.NewRequest(http.MethodPost, "/scenarios/1/operations", strings.NewReader("{")), http.StatusBadRequest},
+		{"add operation without If-Match", h.AddScenarioOperation, httptest.NewRequest(http.MethodPost, "/scenarios/1/operations", strings.NewReader("{}")), http.StatusPreconditionRequired},
+		{"remove operation invalid index", h.RemoveScenarioOperation, httptest.NewRequest(http.MethodDelete, "/scenarios/1/operations/x", nil), http.StatusBadRequest},
+		{"tree invalid id", h.GetScenarioTree, httptest.NewRequest(http.MethodGet, "/scenarios/x/tree", nil), http.StatusBadRequest},
+		{"apply without If-Match", h.ApplyScenario, httptest.NewRequest(http.MethodPost, "/scenarios/1/apply", nil), http.StatusPreconditionRequired},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, tt.req)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, w.Code)
+		}
+	}
+}
+
+func TestParseScenarioPath(t *testing.T) {
+	tests := []struct {
+		path string
+		id   int
+		rest string
+		ok   bool
+	}{
+		{"/scenarios/3", 3, "", true},
+		{"/scenarios/3/tree", 3, "tree", true},
+		{"/scenarios/3/operations/0/", 3, "operations/0", true},
+		{"/scenarios/", 0, "", false},
+		{"/scenarios/x/apply", 0, "apply", false},
+	}
+	for _, tt := range tests {
+		id, rest, ok := parseScenarioPath(tt.path)
+		if id != tt.id || rest != tt.rest || ok != tt.ok {
+			t.Errorf("%s: got (%d, %q, %v)", tt.path, id, rest, ok)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+var errInvalidEffectiveAt = errors.New("invalid effective_at")
+
+// queryEffectiveAt читает параметр effective_at: RFC 3339 или дата YYYY-MM-DD (полночь UTC).
+// nil — параметр не задан.
+func queryEffectiveAt(r *http.Request) (*time.Time, error) {
+	val := r.URL.Query().Get("effective_at")
+	if val == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		return &t, nil
+	}
+	if t, err := time.Parse("2006-01-02", val); err == nil {
+		return &t, nil
+	}
+	return nil, errInvalidEffectiveAt
+}
+
+// scheduled возвращает дату вступления в силу, если изменение нужно отложить.
+// Наступившая дата означает немедленное применение. При ошибке ответ уже записан.
+func (h *Handler) scheduled(w http.ResponseWriter, r *http.Request) (*time.Time, bool) {
+	at, err := queryEffectiveAt(r)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if at != nil && !at.After(time.Now()) {
+		at = nil
+	}
+	return at, true
+}
+
+// scheduleDepartmentChange планирует перенос или переименование подразделения (PATCH /departments/{id}?effective_at=...).
+// If-Match не требуется: изменение применяется к версии, актуальной на дату вступления в силу.
+func (h *Handler) scheduleDepartmentChange(w http.ResponseWriter, r *http.Request, id int, effectiveAt time.Time) {
+	var changes []model.ScheduledChange
+	var err error
+	switch patchMediaType(r) {
+	case "", "application/json":
+		var req model.UpdateDepartmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		changes, err = h.serviceFor(r).ScheduleDepartmentUpdate(id, req, effectiveAt)
+	case mediaTypeMergePatch:
+		var patch model.DepartmentPatch
+		if err := decodeMergePatch(r, &patch); err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid merge patch")
+			return
+		}
+		changes, err = h.serviceFor(r).ScheduleDepartmentPatch(id, patch, effectiveAt)
+	default:
+		h.WriteError(w, http.StatusBadRequest, "effective_at is not supported for this media type")
+		return
+	}
+	if err != nil {
+		h.writeScheduleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, changes)
+}
+
+// scheduleEmployeeTransfer планирует перевод сотрудника (PATCH /departments/{id}/employees/{empID}?effective_at=...)
+func (h *Handler) scheduleEmployeeTransfer(w http.ResponseWriter, r *http.Request, deptID, empID int, effectiveAt time.Time) {
+	switch patchMediaType(r) {
+	case "", "application/json", mediaTypeMergePatch:
+	default:
+		h.WriteError(w, http.StatusBadRequest, "effective_at is not supported for this media type")
+		return
+	}
+	var patch model.EmployeePatch
+	if err := decodeMergePatch(r, &patch); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid merge patch")
+		return
+	}
+
+	changes, err := h.serviceFor(r).ScheduleEmployeeTransfer(deptID, empID, patch, effectiveAt)
+	if err != nil {
+		h.writeScheduleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, changes)
+}
+
+// ListScheduledChanges список запланированных изменений (GET /scheduled-changes?status=pending&department_id=5).
+// По умолчанию возвращаются ожидающие изменения, status=all — все.
+func (h *Handler) ListScheduledChanges(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = model.ScheduledPending
+	case "all":
+		status = ""
+	}
+
+	var deptID *int
+	if val := r.URL.Query().Get("department_id"); val != "" {
+		id, err := strconv.Atoi(val)
+		if err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid department_id")
+			return
+		}
+		deptID = &id
+	}
+
+	changes, err := h.serviceFor(r).ListScheduledChanges(status, deptID)
+	if err != nil {
+		h.writeScheduleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, changes)
+}
+
+func (h *Handler) GetScheduledChange(w http.ResponseWriter, r *http.Request) {
+	id, err := scheduledChangeIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	change, err := h.serviceFor(r).GetScheduledChange(id)
+	if err != nil {
+		h.writeScheduleError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, change)
+}
+
+// CancelScheduledChange отменяет ожидающее изменение (DELETE /scheduled-changes/{id}); запись остаётся со статусом cancelled
+func (h *Handler) CancelScheduledChange(w http.ResponseWriter, r *http.Request) {
+	id, err := scheduledChangeIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if _, err := h.serviceFor(r).CancelScheduledChange(id); err != nil {
+		h.writeScheduleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func scheduledChangeIDFromPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/scheduled-changes/")
+	return strconv.Atoi(strings.Split(idStr, "/")[0])
+}
+
+func (h *Handler) writeScheduleError(w http.ResponseWriter, err error) {
+	if err == service.ErrNotFound {
+		h.WriteError(w, http.StatusNotFound, err.Error())
+	} else if err == service.ErrForbidden {
+		h.WriteError(w, http.StatusForbidden, err.Error())
+	} else if err == service.ErrChangeNotPending || err == service.ErrSelfParent {
+		h.WriteError(w, http.StatusConflict, err.Error())
+	} else if err == service.ErrNotSchedulable || err == service.ErrScheduleApproval {
+		h.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+	} else {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
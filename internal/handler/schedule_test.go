@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryEffectiveAt(t *testing.T) {
+	tests := []struct {
+		query string
+		want  *time.Time
+		ok    bool
+	}{
+		{"", nil, true},
+		{"effective_at=2026-11-01", ptrTime(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)), true},
+		{"effective_at=2026-11-01T09:30:00%2B03:00", ptrTime(time.Date(2026, 11, 1, 6, 30, 0, 0, time.UTC)), true},
+		{"effective_at=01.11.2026", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := queryEffectiveAt(httptest.NewRequest(http.MethodPatch, "/departments/1?"+tt.query, nil))
+		if (err == nil) != tt.ok || (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+			t.Errorf("%q: got %v, %v", tt.query, got, err)
+		}
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+// TestSchedule_InvalidRequest проверяет отказ для некорректных запросов до обращения к БД
+func TestSchedule_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	future := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	jsonPatch := httptest.NewRequest(http.MethodPatch, "/departments/1?effective_at="+future, strings.NewReader("[]"))
+	jsonPatch.Header.Set("Content-Type", mediaTypeJSONPatch)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *http.Request
+	}{
+		{"department invalid effective_at", h.UpdateDepartment, httptest.NewRequest(http.MethodPatch, "/departments/1?effective_at=soon", strings.NewReader("{}"))},
+		{"department invalid json", h.UpdateDepartment, httptest.NewRequest(http.MethodPatch, "/departments/1?effective_at="+future, strings.NewReader("{"))},
+		{"department json patch", h.UpdateDepartment, jsonPatch},
+		{"employee invalid effective_at", h.PatchEmployee, httptest.NewRequest(http.MethodPatch, "/departments/1/employees/2?effective_at=soon", strings.NewReader("{}"))},
+		{"employee unknown field", h.PatchEmployee, httptest.NewRequest(http.MethodPatch, "/departments/1/employees/2?effective_at="+future, strings.NewReader(`{"salary": 1}`))},
+		{"list invalid department_id", h.ListScheduledChanges, httptest.NewRequest(http.MethodGet, "/scheduled-changes?department_id=x", nil)},
+		{"get invalid id", h.GetScheduledChange, httptest.NewRequest(http.MethodGet, "/scheduled-changes/x", nil)},
+		{"cancel invalid id", h.CancelScheduledChange, httptest.NewRequest(http.MethodDelete, "/scheduled-changes/x", nil)},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, tt.req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tt.name, http.StatusBadRequest, w.Code)
+		}
+	}
+}
@@ -0,0 +1,513 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/scim"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+const (
+	// scimPrefix корень SCIM API
+	scimPrefix = "/scim/v2"
+
+	// Размер страницы списка по умолчанию и наибольший
+	scimDefaultCount = 100
+	scimMaxCount     = 200
+)
+
+// SCIM обрабатывает запросы /scim/v2/...: Users, Groups и документы обнаружения
+func (h *Handler) SCIM(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, scimPrefix), "/")
+	resource, id, hasID := strings.Cut(path, "/")
+
+	switch resource {
+	case "Users":
+		if !hasID {
+			h.scimCollection(w, r, h.listSCIMUsers, h.createSCIMUser)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			h.getSCIMUser(w, r, id)
+		case http.MethodPut:
+			h.replaceSCIMUser(w, r, id)
+		case http.MethodPatch:
+			h.patchSCIMUser(w, r, id)
+		case http.MethodDelete:
+			h.writeSCIMError(w, http.StatusNotImplemented, "", "deleting users is not supported")
+		default:
+			h.writeSCIMError(w, http.StatusMethodNotAllowed, "", "method not allowed")
+		}
+	case "Groups":
+		if !hasID {
+			h.scimCollection(w, r, h.listSCIMGroups, h.createSCIMGroup)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			h.getSCIMGroup(w, r, id)
+		case http.MethodPut:
+			h.replaceSCIMGroup(w, r, id)
+		case http.MethodPatch:
+			h.patchSCIMGroup(w, r, id)
+		case http.MethodDelete:
+			h.writeSCIMError(w, http.StatusNotImplemented, "", "deleting groups is not supported")
+		default:
+			h.writeSCIMError(w, http.StatusMethodNotAllowed, "", "method not allowed")
+		}
+	case "ServiceProviderConfig":
+		if !h.scimDiscovery(w, r) {
+			return
+		}
+		h.writeSCIM(w, http.StatusOK, scim.NewServiceProviderConfig(scimMaxCount, scimBaseURL(r)))
+	case "ResourceTypes":
+		if !h.scimDiscovery(w, r) {
+			return
+		}
+		types := scim.NewResourceTypes(scimBaseURL(r))
+		if !hasID {
+			h.writeSCIM(w, http.StatusOK, scim.NewListResponse(types, len(types), int64(len(types)), 1))
+			return
+		}
+		for _, t := range types {
+			if t.ID == id {
+				h.writeSCIM(w, http.StatusOK, t)
+				return
+			}
+		}
+		h.writeSCIMError(w, http.StatusNotFound, "", "resource type not found")
+	case "Schemas":
+		if !h.scimDiscovery(w, r) {
+			return
+		}
+		schemas := scim.NewSchemas(scimBaseURL(r))
+		if !hasID {
+			h.writeSCIM(w, http.StatusOK, scim.NewListResponse(schemas, len(schemas), int64(len(schemas)), 1))
+			return
+		}
+		for _, s := range schemas {
+			if s.ID == id {
+				h.writeSCIM(w, http.StatusOK, s)
+				return
+			}
+		}
+		h.writeSCIMError(w, http.StatusNotFound, "", "schema not found")
+	default:
+		h.writeSCIMError(w, http.StatusNotFound, "", "not found")
+	}
+}
+
+func (h *Handler) scimCollection(w http.ResponseWriter, r *http.Request, list, create http.HandlerFunc) {
+	switch r.Method {
+	case http.MethodGet:
+		list(w, r)
+	case http.MethodPost:
+		create(w, r)
+	default:
+		h.writeSCIMError(w, http.StatusMethodNotAllowed, "", "method not allowed")
+	}
+}
+
+// scimDiscovery проверяет метод запроса к документу обнаружения
+func (h *Handler) scimDiscovery(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet {
+		h.writeSCIMError(w, http.StatusMethodNotAllowed, "", "method not allowed")
+		return false
+	}
+	return true
+}
+
+func (h *Handler) writeSCIM(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", scim.MediaType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) writeSCIMError(w http.ResponseWriter, status int, scimType, detail string) {
+	h.writeSCIM(w, status, scim.NewError(status, scimType, detail))
+}
+
+// writeSCIMServiceError сопоставляет ошибки сервиса и разбора запроса с ответами SCIM
+func (h *Handler) writeSCIMServiceError(w http.ResponseWriter, err error) {
+	var reqErr *scim.RequestError
+	switch {
+	case errors.As(err, &reqErr):
+		h.writeSCIMError(w, http.StatusBadRequest, reqErr.Type, reqErr.Detail)
+	case errors.Is(err, scim.ErrInvalidFilter):
+		h.writeSCIMError(w, http.StatusBadRequest, scim.ErrorInvalidFilter, err.Error())
+	case err == service.ErrNotFound:
+		h.writeSCIMError(w, http.StatusNotFound, "", "resource not found")
+	case err == service.ErrForbidden:
+		h.writeSCIMError(w, http.StatusForbidden, "", err.Error())
+	case err == service.ErrVersionMismatch:
+		h.writeSCIMError(w, http.StatusPreconditionFailed, "", err.Error())
+	case err == service.ErrDuplicateUserName || err == service.ErrDuplicateName:
+		h.writeSCIMError(w, http.StatusConflict, scim.ErrorUniqueness, err.Error())
+	case err == service.ErrEmployeeMembership:
+		h.writeSCIMError(w, http.StatusBadRequest, scim.ErrorMutability, err.Error())
+	case errors.Is(err, service.ErrApprovalRequired):
+		// Перенос под политикой согласования не выполняется через SCIM, запрос на изменение не создаётся
+		h.writeSCIMError(w, http.StatusConflict, "", err.Error())
+	default:
+		h.writeSCIMError(w, http.StatusBadRequest, scim.ErrorInvalidValue, err.Error())
+	}
+}
+
+// scimBaseURL адрес корня SCIM API для ссылок в ответах
+func scimBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + scimPrefix
+}
+
+// scimPage разбирает startIndex (с 1) и count
+func scimPage(r *http.Request) (startIndex, count int, err error) {
+	startIndex, count = 1, scimDefaultCount
+	if v := r.URL.Query().Get("startIndex"); v != "" {
+		if startIndex, err = strconv.Atoi(v); err != nil {
+			return 0, 0, errors.New("invalid startIndex")
+		}
+		// Значения меньше 1 трактуются как 1 (RFC 7644, раздел 3.4.2.4)
+		startIndex = max(startIndex, 1)
+	}
+	if v := r.URL.Query().Get("count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil {
+			return 0, 0, errors.New("invalid count")
+		}
+		count = min(max(count, 0), scimMaxCount)
+	}
+	return startIndex, count, nil
+}
+
+// scimID разбирает идентификатор ресурса; нечисловой идентификатор не соответствует ни одному ресурсу
+func (h *Handler) scimID(w http.ResponseWriter, id string) (int, bool) {
+	n, ok := scim.ID(id).Int()
+	if !ok {
+		h.writeSCIMError(w, http.StatusNotFound, "", "resource not found")
+	}
+	return n, ok
+}
+
+// decodeSCIM разбирает тело запроса
+func (h *Handler) decodeSCIM(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		h.writeSCIMError(w, http.StatusBadRequest, scim.ErrorInvalidSyntax, "invalid json")
+		return false
+	}
+	return true
+}
+
+// scimUsers представляет сотрудников как пользователей; подразделения и их предки
+// загружаются одним набором запросов на всю страницу
+func (h *Handler) scimUsers(r *http.Request, employees []model.Employee) ([]scim.User, error) {
+	svc := h.serviceFor(r)
+	deptIDs := make([]int, 0, len(employees))
+	for _, e := range employees {
+		deptIDs = append(deptIDs, e.DepartmentID)
+	}
+	depts, err := svc.LoadDepartments(deptIDs)
+	if err != nil {
+		return nil, err
+	}
+	ancestors, err := svc.LoadAncestors(deptIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := scimBaseURL(r)
+	users := make([]scim.User, 0, len(employees))
+	for i := range employees {
+		var dept *model.Department
+		if d, ok := depts[employees[i].DepartmentID]; ok {
+			dept = &d
+		}
+		users = append(users, scim.NewUser(&employees[i], dept, ancestors[employees[i].DepartmentID], baseURL))
+	}
+	return users, nil
+}
+
+// scimGroups представляет подразделения как группы; withMembers добавляет участников
+func (h *Handler) scimGroups(r *http.Request, depts []model.Department, withMembers bool) ([]scim.Group, error) {
+	var employees map[int][]model.Employee
+	var children map[int][]model.Department
+	if withMembers && len(depts) > 0 {
+		svc := h.serviceFor(r)
+		ids := make([]int, 0, len(depts))
+		for _, d := range depts {
+			ids = append(ids, d.ID)
+		}
+		var err error
+		if employees, err = svc.LoadEmployees(ids, model.EmployeeStatuses); err != nil {
+			return nil, err
+		}
+		if children, err = svc.LoadChildren(ids); err != nil {
+			return nil, err
+		}
+	}
+
+	baseURL := scimBaseURL(r)
+	groups := make([]scim.Group, 0, len(depts))
+	for i := range depts {
+		groups = append(groups, scim.NewGroup(&depts[i], employees[depts[i].ID], children[depts[i].ID], withMembers, baseURL))
+	}
+	return groups, nil
+}
+
+// withMembers проверяет, что клиент не исключил members (excludedAttributes=members)
+func withMembers(r *http.Request) bool {
+	for _, attr := range strings.Split(r.URL.Query().Get("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attr), "members") {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *Handler) writeSCIMUser(w http.ResponseWriter, r *http.Request, status int, emp *model.Employee) {
+	users, err := h.scimUsers(r, []model.Employee{*emp})
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	if status == http.StatusCreated {
+		w.Header().Set("Location", users[0].Meta.Location)
+	}
+	h.writeSCIM(w, status, users[0])
+}
+
+func (h *Handler) writeSCIMGroup(w http.ResponseWriter, r *http.Request, status int, dept *model.Department) {
+	groups, err := h.scimGroups(r, []model.Department{*dept}, withMembers(r))
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	if status == http.StatusCreated {
+		w.Header().Set("Location", groups[0].Meta.Location)
+	}
+	h.writeSCIM(w, status, groups[0])
+}
+
+// listSCIMUsers GET /scim/v2/Users?filter=...&startIndex=...&count=...
+func (h *Handler) listSCIMUsers(w http.ResponseWriter, r *http.Request) {
+	startIndex, count, err := scimPage(r)
+	if err != nil {
+		h.writeSCIMError(w, http.StatusBadRequest, scim.ErrorInvalidValue, err.Error())
+		return
+	}
+	where, args, err := scim.Where(r.URL.Query().Get("filter"), scim.UserAttributes)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+
+	employees, total, err := h.serviceFor(r).SearchEmployees(where, args, startIndex-1, count)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	users, err := h.scimUsers(r, employees)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIM(w, http.StatusOK, scim.NewListResponse(users, len(users), total, startIndex))
+}
+
+// createSCIMUser POST /scim/v2/Users; подразделение задаётся атрибутом departmentId расширения Employee
+func (h *Handler) createSCIMUser(w http.ResponseWriter, r *http.Request) {
+	var user scim.User
+	if !h.decodeSCIM(w, r, &user) {
+		return
+	}
+	deptID, req, err := user.EmployeeRequest()
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	emp, err := h.serviceFor(r).CreateEmployee(deptID, req)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIMUser(w, r, http.StatusCreated, emp)
+}
+
+func (h *Handler) getSCIMUser(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, ok := h.scimID(w, rawID)
+	if !ok {
+		return
+	}
+	emp, err := h.serviceFor(r).FindEmployee(id)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIMUser(w, r, http.StatusOK, emp)
+}
+
+// replaceSCIMUser PUT /scim/v2/Users/{id}: заменяет все изменяемые атрибуты
+func (h *Handler) replaceSCIMUser(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, ok := h.scimID(w, rawID)
+	if !ok {
+		return
+	}
+	var user scim.User
+	if !h.decodeSCIM(w, r, &user) {
+		return
+	}
+	patch, err := user.ReplacePatch()
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.updateSCIMUser(w, r, id, patch)
+}
+
+// patchSCIMUser PATCH /scim/v2/Users/{id}
+func (h *Handler) patchSCIMUser(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, ok := h.scimID(w, rawID)
+	if !ok {
+		return
+	}
+	var req scim.PatchRequest
+	if !h.decodeSCIM(w, r, &req) {
+		return
+	}
+	patch, err := scim.UserPatch(req)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.updateSCIMUser(w, r, id, patch)
+}
+
+func (h *Handler) updateSCIMUser(w http.ResponseWriter, r *http.Request, id int, patch model.EmployeePatch) {
+	svc := h.serviceFor(r)
+	current, err := svc.FindEmployee(id)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	emp, err := svc.PatchEmployee(current.DepartmentID, id, patch, 0)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIMUser(w, r, http.StatusOK, emp)
+}
+
+// listSCIMGroups GET /scim/v2/Groups?filter=...&startIndex=...&count=...
+func (h *Handler) listSCIMGroups(w http.ResponseWriter, r *http.Request) {
+	startIndex, count, err := scimPage(r)
+	if err != nil {
+		h.writeSCIMError(w, http.StatusBadRequest, scim.ErrorInvalidValue, err.Error())
+		return
+	}
+	where, args, err := scim.Where(r.URL.Query().Get("filter"), scim.GroupAttributes)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+
+	depts, total, err := h.serviceFor(r).SearchDepartments(where, args, startIndex-1, count)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	groups, err := h.scimGroups(r, depts, withMembers(r))
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIM(w, http.StatusOK, scim.NewListResponse(groups, len(groups), total, startIndex))
+}
+
+// createSCIMGroup POST /scim/v2/Groups: создаёт подразделение верхнего уровня с участниками
+func (h *Handler) createSCIMGroup(w http.ResponseWriter, r *http.Request) {
+	var group scim.Group
+	if !h.decodeSCIM(w, r, &group) {
+		return
+	}
+	changes, err := group.MembershipChanges()
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	dept, err := h.serviceFor(r).CreateDepartmentWithMembers(model.CreateDepartmentRequest{Name: group.DisplayName}, changes)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIMGroup(w, r, http.StatusCreated, dept)
+}
+
+func (h *Handler) getSCIMGroup(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, ok := h.scimID(w, rawID)
+	if !ok {
+		return
+	}
+	dept, err := h.serviceFor(r).GetDepartment(id)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIMGroup(w, r, http.StatusOK, dept)
+}
+
+// replaceSCIMGroup PUT /scim/v2/Groups/{id}: меняет displayName и добавляет перечисленных участников.
+// Сотрудников нельзя оставить без подразделения, поэтому отсутствующие в members не удаляются.
+func (h *Handler) replaceSCIMGroup(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, ok := h.scimID(w, rawID)
+	if !ok {
+		return
+	}
+	var group scim.Group
+	if !h.decodeSCIM(w, r, &group) {
+		return
+	}
+	changes, err := group.MembershipChanges()
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	dept, err := h.serviceFor(r).UpdateDepartmentMembers(id, model.DepartmentPatch{Name: model.Some(group.DisplayName)}, changes, 0)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIMGroup(w, r, http.StatusOK, dept)
+}
+
+// patchSCIMGroup PATCH /scim/v2/Groups/{id}: все операции применяются атомарно
+func (h *Handler) patchSCIMGroup(w http.ResponseWriter, r *http.Request, rawID string) {
+	id, ok := h.scimID(w, rawID)
+	if !ok {
+		return
+	}
+	var req scim.PatchRequest
+	if !h.decodeSCIM(w, r, &req) {
+		return
+	}
+	patch, changes, err := scim.GroupPatch(req)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	dept, err := h.serviceFor(r).UpdateDepartmentMembers(id, patch, changes, 0)
+	if err != nil {
+		h.writeSCIMServiceError(w, err)
+		return
+	}
+	h.writeSCIMGroup(w, r, http.StatusOK, dept)
+}
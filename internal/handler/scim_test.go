@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/scim"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// TestSCIM_Discovery проверяет документы обнаружения и тип содержимого
+func TestSCIM_Discovery(t *testing.T) {
+	h := &Handler{}
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/scim/v2/ServiceProviderConfig", http.StatusOK},
+		{"/scim/v2/Schemas", http.StatusOK},
+		{"/scim/v2/Schemas/" + scim.SchemaUser, http.StatusOK},
+		{"/scim/v2/Schemas/urn:unknown", http.StatusNotFound},
+		{"/scim/v2/ResourceTypes", http.StatusOK},
+		{"/scim/v2/ResourceTypes/Group", http.StatusOK},
+		{"/scim/v2/Bulk", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		h.SCIM(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, w.Code)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != scim.MediaType {
+			t.Errorf("%s: expected Content-Type %s, got %s", tt.path, scim.MediaType, ct)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/scim/v2/ResourceTypes", nil)
+	req.Host = "org.example.com"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+	h.SCIM(w, req)
+	var list struct {
+		TotalResults int                 `json:"totalResults"`
+		Resources    []scim.ResourceType `json:"Resources"`
+	}
+	json.NewDecoder(w.Body).Decode(&list)
+	if list.TotalResults != 2 || list.Resources[0].Meta.Location != "https://org.example.com/scim/v2/ResourceTypes/User" {
+		t.Errorf("unexpected resource types: %+v", list)
+	}
+}
+
+// TestSCIM_RequestErrors проверяет ошибки в формате SCIM до обращения к сервису
+func TestSCIM_RequestErrors(t *testing.T) {
+	h := &Handler{}
+
+	tests := []struct {
+		method   string
+		path     string
+		body     string
+		status   int
+		scimType string
+	}{
+		{http.MethodGet, `/scim/v2/Users?filter=userName+like+"a"`, "", http.StatusBadRequest, scim.ErrorInvalidFilter},
+		{http.MethodGet, `/scim/v2/Groups?filter=title+eq+"a"`, "", http.StatusBadRequest, scim.ErrorInvalidFilter},
+		{http.MethodGet, "/scim/v2/Users?count=x", "", http.StatusBadRequest, scim.ErrorInvalidValue},
+		{http.MethodGet, "/scim/v2/Users/abc", "", http.StatusNotFound, ""},
+		{http.MethodPatch, "/scim/v2/Users/1", `{"Operations":`, http.StatusBadRequest, scim.ErrorInvalidSyntax},
+		{http.MethodPatch, "/scim/v2/Users/1", `{"Operations":[{"op":"replace","path":"active","value":false}]}`, http.StatusBadRequest, scim.ErrorMutability},
+		{http.MethodPatch, "/scim/v2/Groups/1", `{"Operations":[{"op":"replace","path":"members","value":[]}]}`, http.StatusBadRequest, scim.ErrorMutability},
+		{http.MethodPost, "/scim/v2/Users", `{"userName":"a","displayName":"A","title":"T"}`, http.StatusBadRequest, scim.ErrorInvalidValue},
+		{http.MethodDelete, "/scim/v2/Users/1", "", http.StatusNotImplemented, ""},
+		{http.MethodDelete, "/scim/v2/Groups", "", http.StatusMethodNotAllowed, ""},
+		{http.MethodPost, "/scim/v2/Schemas", "", http.StatusMethodNotAllowed, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, bytes.NewReader([]byte(tt.body)))
+		w := httptest.NewRecorder()
+		h.SCIM(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, w.Code)
+			continue
+		}
+		var resp scim.Error
+		json.NewDecoder(w.Body).Decode(&resp)
+		if len(resp.Schemas) != 1 || resp.Schemas[0] != scim.SchemaError || resp.ScimType != tt.scimType {
+			t.Errorf("%s %s: unexpected error body %+v", tt.method, tt.path, resp)
+		}
+	}
+}
+
+// TestWriteSCIMServiceError_ApprovalRequired проверяет, что перенос под политикой согласования
+// отклоняется с 409, а не как невалидное значение
+func TestWriteSCIMServiceError_ApprovalRequired(t *testing.T) {
+	h := &Handler{}
+
+	for _, err := range []error{
+		service.ErrApprovalNotDeferrable,
+		&service.ApprovalRequiredError{Request: &model.ChangeRequest{ID: 1}},
+	} {
+		w := httptest.NewRecorder()
+		h.writeSCIMServiceError(w, err)
+		if w.Code != http.StatusConflict {
+			t.Errorf("%v: expected status %d, got %d", err, http.StatusConflict, w.Code)
+		}
+		var resp scim.Error
+		json.NewDecoder(w.Body).Decode(&resp)
+		if resp.Detail != err.Error() {
+			t.Errorf("%v: unexpected detail %q", err, resp.Detail)
+		}
+	}
+}
+
+// TestSCIMPage проверяет разбор startIndex и count
+func TestSCIMPage(t *testing.T) {
+	tests := []struct {
+		query      string
+		startIndex int
+		count      int
+	}{
+		{"", 1, scimDefaultCount},
+		{"?startIndex=0&count=-5", 1, 0},
+		{"?startIndex=11&count=10", 11, 10},
+		{"?count=100000", 1, scimMaxCount},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/scim/v2/Users"+tt.query, nil)
+		startIndex, count, err := scimPage(req)
+		if err != nil || startIndex != tt.startIndex || count != tt.count {
+			t.Errorf("%q: expected (%d, %d), got (%d, %d, %v)", tt.query, tt.startIndex, tt.count, startIndex, count, err)
+		}
+	}
+}
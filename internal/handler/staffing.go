@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// ListBudgets возвращает бюджеты численности подразделения (GET /departments/{id}/budgets)
+func (h *Handler) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	deptID, _, ok := parseDepartmentSubpath(r.URL.Path, "budgets")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	budgets, err := h.serviceFor(r).ListBudgets(deptID)
+	if err != nil {
+		h.writeStaffingError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, budgets)
+}
+
+// SetBudget утверждает численность на период (PUT /departments/{id}/budgets/{period})
+func (h *Handler) SetBudget(w http.ResponseWriter, r *http.Request) {
+	deptID, period, ok := parseDepartmentSubpath(r.URL.Path, "budgets")
+	if !ok || period == "" {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	var req model.SetBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	budget, err := h.serviceFor(r).SetBudget(deptID, period, req)
+	if err != nil {
+		h.writeStaffingError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, budget)
+}
+
+// DeleteBudget удаляет бюджет на период (DELETE /departments/{id}/budgets/{period})
+func (h *Handler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	deptID, period, ok := parseDepartmentSubpath(r.URL.Path, "budgets")
+	if !ok || period == "" {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteBudget(deptID, period); err != nil {
+		h.writeStaffingError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListRequisitions возвращает вакансии подразделения (GET /departments/{id}/requisitions?status=open)
+func (h *Handler) ListRequisitions(w http.ResponseWriter, r *http.Request) {
+	deptID, _, ok := parseDepartmentSubpath(r.URL.Path, "requisitions")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	reqs, err := h.serviceFor(r).ListRequisitions(deptID, r.URL.Query().Get("status"))
+	if err != nil {
+		h.writeStaffingError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, reqs)
+}
+
+// CreateRequisition открывает вакансию (POST /departments/{id}/requisitions)
+func (h *Handler) CreateRequisition(w http.ResponseWriter, r *http.Request) {
+	deptID, _, ok := parseDepartmentSubpath(r.URL.Path, "requisitions")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+
+	var req model.CreateRequisitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	reqn, err := h.serviceFor(r).CreateRequisition(deptID, req)
+	if err != nil {
+		h.writeStaffingError(w, err)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusCreated, reqn.Version, reqn)
+}
+
+// UpdateRequisition изменяет вакансию (PATCH /departments/{id}/requisitions/{reqID}) с учётом If-Match
+func (h *Handler) UpdateRequisition(w http.ResponseWriter, r *http.Request) {
+	deptID, rest, ok := parseDepartmentSubpath(r.URL.Path, "requisitions")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	id, err := strconv.Atoi(rest)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req model.UpdateRequisitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		h.writePreconditionError(w, err)
+		return
+	}
+
+	reqn, err := h.serviceFor(r).UpdateRequisition(deptID, id, req, version)
+	if err != nil {
+		h.writeStaffingError(w, err)
+		return
+	}
+
+	h.writeJSONWithETag(w, r, http.StatusOK, reqn.Version, reqn)
+}
+
+// GetStaffing сравнивает бюджет, фактическую численность и открытые вакансии поддерева
+// (GET /departments/{id}/staffing?period=2026-Q3&employee_status=active,on_leave)
+func (h *Handler) GetStaffing(w http.ResponseWriter, r *http.Request) {
+	deptID, rest, ok := parseDepartmentSubpath(r.URL.Path, "staffing")
+	if !ok || rest != "" {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	q := r.URL.Query()
+	if q.Get("period") == "" {
+		h.WriteError(w, http.StatusBadRequest, "period is required")
+		return
+	}
+	statuses, ok := parseEmployeeStatuses(q, "employee_status")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid employee_status")
+		return
+	}
+
+	staffing, err := h.serviceFor(r).GetStaffing(deptID, q.Get("period"), statuses)
+	if err != nil {
+		h.writeStaffingError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, staffing)
+}
+
+// parseDepartmentSubpath извлекает ID подразделения и остаток пути из /departments/{id}/{resource}[/{rest}]
+func parseDepartmentSubpath(path, resource string) (int, string, bool) {
+	parts := strings.SplitN(strings.Trim(path, "/"), "/", 4)
+	if len(parts) < 3 || parts[0] != "departments" || parts[2] != resource {
+		return 0, "", false
+	}
+	deptID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, "", false
+	}
+	if len(parts) == 4 {
+		return deptID, parts[3], true
+	}
+	return deptID, "", true
+}
+
+func (h *Handler) writeStaffingError(w http.ResponseWriter, err error) {
+	if err == service.ErrNotFound {
+		h.WriteError(w, http.StatusNotFound, err.Error())
+	} else if err == service.ErrForbidden {
+		h.WriteError(w, http.StatusForbidden, err.Error())
+	} else if err == service.ErrVersionMismatch {
+		h.WriteError(w, http.StatusPreconditionFailed, err.Error())
+	} else if err == service.ErrInvalidTransition {
+		h.WriteError(w, http.StatusConflict, err.Error())
+	} else {
+		h.WriteError(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestStaffing_InvalidRequest проверяет отказ для некорректных запросов до обращения к БД
+func TestStaffing_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *http.Request
+	}{
+		{"list budgets invalid id", h.ListBudgets, httptest.NewRequest(http.MethodGet, "/departments/abc/budgets", nil)},
+		{"set budget without period", h.SetBudget, httptest.NewRequest(http.MethodPut, "/departments/1/budgets", strings.NewReader("{}"))},
+		{"set budget invalid json", h.SetBudget, httptest.NewRequest(http.MethodPut, "/departments/1/budgets/2026", strings.NewReader("{"))},
+		{"delete budget without period", h.DeleteBudget, httptest.NewRequest(http.MethodDelete, "/departments/1/budgets/", nil)},
+		{"create requisition invalid json", h.CreateRequisition, httptest.NewRequest(http.MethodPost, "/departments/1/requisitions", strings.NewReader("{"))},
+		{"update requisition invalid id", h.UpdateRequisition, httptest.NewRequest(http.MethodPatch, "/departments/1/requisitions/x", strings.NewReader("{}"))},
+		{"staffing without period", h.GetStaffing, httptest.NewRequest(http.MethodGet, "/departments/1/staffing", nil)},
+		{"staffing invalid status", h.GetStaffing, httptest.NewRequest(http.MethodGet, "/departments/1/staffing?period=2026&employee_status=fired", nil)},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		tt.handler(w, tt.req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", tt.name, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestParseDepartmentSubpath(t *testing.T) {
+	tests := []struct {
+		path     string
+		resource string
+		deptID   int
+		rest     string
+		ok       bool
+	}{
+		{"/departments/5/budgets", "budgets", 5, "", true},
+		{"/departments/5/budgets/2026-Q3", "budgets", 5, "2026-Q3", true},
+		{"/departments/5/requisitions/7/", "requisitions", 5, "7", true},
+		{"/departments/x/budgets", "budgets", 0, "", false},
+		{"/departments/5/staffing", "budgets", 0, "", false},
+	}
+	for _, tt := range tests {
+		deptID, rest, ok := parseDepartmentSubpath(tt.path, tt.resource)
+		if deptID != tt.deptID || rest != tt.rest || ok != tt.ok {
+			t.Errorf("%s: got (%d, %q, %v)", tt.path, deptID, rest, ok)
+		}
+	}
+}
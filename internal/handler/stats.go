@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+// GetDepartmentStats сводные показатели поддерева подразделения
+// (GET /departments/{id}/stats?employee_status=active,on_leave)
+func (h *Handler) GetDepartmentStats(w http.ResponseWriter, r *http.Request) {
+	deptID, rest, ok := parseDepartmentSubpath(r.URL.Path, "stats")
+	if !ok || rest != "" {
+		h.WriteError(w, http.StatusBadRequest, "invalid path")
+		return
+	}
+	statuses, ok := parseEmployeeStatuses(r.URL.Query(), "employee_status")
+	if !ok {
+		h.WriteError(w, http.StatusBadRequest, "invalid employee_status")
+		return
+	}
+
+	stats, err := h.serviceFor(r).GetDepartmentStats(deptID, statuses)
+	if err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetDepartmentStats_InvalidRequest(t *testing.T) {
+	h := &Handler{}
+	for _, path := range []string{"/departments/abc/stats", "/departments/1/stats/x", "/departments/1/stats?employee_status=fired"} {
+		w := httptest.NewRecorder()
+		h.GetDepartmentStats(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", path, http.StatusBadRequest, w.Code)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+)
+
+// TestCreateTenant_InvalidJSON проверяет обработку невалидного тела запроса
+func TestCreateTenant_InvalidJSON(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/tenants", bytes.NewReader([]byte("{")))
+	w := httptest.NewRecorder()
+
+	h.CreateTenant(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+type mismatchTenants struct{}
+
+func (mismatchTenants) ResolveTenant(slug string) (int, error) {
+	return 0, auth.ErrUnknownTenant
+}
+
+// TestAuthenticate_UnknownTenant проверяет отказ для неизвестного арендатора
+func TestAuthenticate_UnknownTenant(t *testing.T) {
+	h := &Handler{}
+	a := auth.NewAuthenticator(false, "", nil).WithTenants(mismatchTenants{})
+
+	next := h.Authenticate(a, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("next handler should not be called")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/departments/1", nil)
+	req.Header.Set(auth.TenantHeader, "initech")
+	w := httptest.NewRecorder()
+	next(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+)
+
+func (h *Handler) ListWebhookSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.serviceFor(r).ListWebhookSubscriptions()
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, subs)
+}
+
+func (h *Handler) CreateWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	var req model.CreateWebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	sub, err := h.serviceFor(r).CreateWebhookSubscription(req)
+	if err != nil {
+		if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, sub)
+}
+
+func (h *Handler) GetWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	// Путь: /webhooks/{id}
+	id, err := webhookIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	sub, err := h.serviceFor(r).GetWebhookSubscription(id)
+	if err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, sub)
+}
+
+func (h *Handler) DeleteWebhookSubscription(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := h.serviceFor(r).DeleteWebhookSubscription(id); err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	// Путь: /webhooks/{id}/deliveries
+	id, err := webhookIDFromPath(r.URL.Path)
+	if err != nil {
+		h.WriteError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	limit := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil {
+			h.WriteError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+	}
+
+	deliveries, err := h.serviceFor(r).ListWebhookDeliveries(id, r.URL.Query().Get("status"), limit)
+	if err != nil {
+		if err == service.ErrNotFound {
+			h.WriteError(w, http.StatusNotFound, err.Error())
+		} else if err == service.ErrForbidden {
+			h.WriteError(w, http.StatusForbidden, err.Error())
+		} else {
+			h.WriteError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, deliveries)
+}
+
+func webhookIDFromPath(path string) (int, error) {
+	idStr := strings.TrimPrefix(path, "/webhooks/")
+	return strconv.Atoi(strings.Split(idStr, "/")[0])
+}
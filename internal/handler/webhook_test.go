@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCreateWebhookSubscription_InvalidJSON проверяет обработку невалидного тела запроса
+func TestCreateWebhookSubscription_InvalidJSON(t *testing.T) {
+	h := &Handler{}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader([]byte("[")))
+	w := httptest.NewRecorder()
+
+	h.CreateWebhookSubscription(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+// TestListWebhookDeliveries_InvalidParams проверяет разбор пути и параметров журнала доставок
+func TestListWebhookDeliveries_InvalidParams(t *testing.T) {
+	h := &Handler{}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"invalid id", "/webhooks/abc/deliveries"},
+		{"invalid limit", "/webhooks/1/deliveries?limit=many"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			h.ListWebhookDeliveries(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// Package ldif формирует выгрузку оргструктуры в формате LDIF (RFC 2849) для загрузки в каталог LDAP:
+// подразделения — записи organizationalUnit, сотрудники — inetOrgPerson.
+package ldif
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// ErrInvalidDN некорректный DN
+var ErrInvalidDN = errors.New("invalid dn")
+
+// maxLineLength длина строки LDIF, после которой она переносится
+const maxLineLength = 76
+
+// attributeType тип атрибута RDN: имя или OID
+var attributeType = regexp.MustCompile(`^(?:[A-Za-z][A-Za-z0-9-]*|[0-9]+(?:\.[0-9]+)*)$`)
+
+// ValidateDN проверяет, что dn — последовательность RDN вида attr=value через запятую (RFC 4514)
+func ValidateDN(dn string) error {
+	if strings.TrimSpace(dn) == "" {
+		return ErrInvalidDN
+	}
+	for _, rdn := range splitDN(dn) {
+		for _, ava := range splitUnescaped(rdn, '+') {
+			attr, value, ok := strings.Cut(ava, "=")
+			if !ok || !attributeType.MatchString(strings.TrimSpace(attr)) || strings.TrimSpace(value) == "" {
+				return ErrInvalidDN
+			}
+		}
+	}
+	return nil
+}
+
+func splitDN(dn string) []string {
+	return splitUnescaped(dn, ',')
+}
+
+// splitUnescaped разбивает s по разделителю sep, не экранированному обратной косой чертой
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// EscapeValue экранирует значение атрибута RDN (RFC 4514, раздел 2.4). Символы не из ASCII,
+// в том числе кириллица, допустимы в DN как есть; в файл такой DN записывается в base64.
+func EscapeValue(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '+' || r == ',' || r == ';' || r == '<' || r == '>' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == 0:
+			b.WriteString(`\00`)
+		case (r == ' ' || r == '#') && i == 0:
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == ' ' && i == len(s)-1:
+			b.WriteString(`\ `)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// DepartmentDN DN подразделения под parentDN
+func DepartmentDN(name, parentDN string) string {
+	return "ou=" + EscapeValue(name) + "," + parentDN
+}
+
+// EmployeeUID идентификатор сотрудника в каталоге: логин или employee-<id>
+func EmployeeUID(emp *model.Employee) string {
+	if emp.UserName != nil {
+		return *emp.UserName
+	}
+	return "employee-" + strconv.Itoa(emp.ID)
+}
+
+// EmployeeDN DN сотрудника в подразделении deptDN
+func EmployeeDN(emp *model.Employee, deptDN string) string {
+	return "uid=" + EscapeValue(EmployeeUID(emp)) + "," + deptDN
+}
+
+// Write выводит подразделения, вложенные по parent_id под baseDN, и сотрудников под DN
+// их подразделений. Родительская запись всегда предшествует дочерним, поэтому файл
+// загружается через ldapadd без дополнительной сортировки. Запись baseDN должна существовать в каталоге.
+func Write(w io.Writer, baseDN string, depts []model.Department, employees []model.Employee) error {
+	children := make(map[int][]model.Department)
+	known := make(map[int]bool, len(depts))
+	for _, d := range depts {
+		known[d.ID] = true
+	}
+	for _, d := range depts {
+		// Подразделение с родителем вне выгрузки размещается непосредственно под baseDN
+		parent := 0
+		if d.ParentID != nil && known[*d.ParentID] {
+			parent = *d.ParentID
+		}
+		children[parent] = append(children[parent], d)
+	}
+	members := make(map[int][]model.Employee)
+	for _, e := range employees {
+		members[e.DepartmentID] = append(members[e.DepartmentID], e)
+	}
+	for _, list := range children {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].SortOrder != list[j].SortOrder {
+				return list[i].SortOrder < list[j].SortOrder
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+	for _, list := range members {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+
+	enc := &encoder{w: bufio.NewWriter(w)}
+	enc.line("version: 1")
+
+	var walk func(parent int, parentDN string)
+	walk = func(parent int, parentDN string) {
+		for _, d := range children[parent] {
+			dn := DepartmentDN(d.Name, parentDN)
+			enc.line("")
+			enc.attr("dn", dn)
+			enc.attr("objectClass", "top")
+			enc.attr("objectClass", "organizationalUnit")
+			enc.attr("ou", d.Name)
+
+			for _, e := range members[d.ID] {
+				enc.line("")
+				enc.attr("dn", EmployeeDN(&e, dn))
+				enc.attr("objectClass", "top")
+				enc.attr("objectClass", "person")
+				enc.attr("objectClass", "organizationalPerson")
+				enc.attr("objectClass", "inetOrgPerson")
+				enc.attr("uid", EmployeeUID(&e))
+				enc.attr("cn", e.FullName)
+				// Порядок частей ФИО не фиксирован, поэтому фамилией считается полное имя
+				enc.attr("sn", e.FullName)
+				enc.attr("displayName", e.FullName)
+				enc.attr("title", e.Position)
+				enc.attr("employeeNumber", strconv.Itoa(e.ID))
+				enc.attr("departmentNumber", strconv.Itoa(d.ID))
+			}
+			walk(d.ID, dn)
+		}
+	}
+	walk(0, baseDN)
+
+	if enc.err != nil {
+		return enc.err
+	}
+	return enc.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (e *encoder) line(s string) {
+	if e.err == nil {
+		_, e.err = e.w.WriteString(s + "\n")
+	}
+}
+
+// attr выводит атрибут; значения вне SAFE-STRING (RFC 2849) кодируются в base64,
+// длинные строки переносятся
+func (e *encoder) attr(name, value string) {
+	s := name + ": " + value
+	if !safeString(value) {
+		s = name + ":: " + base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	for len(s) > maxLineLength {
+		// После кодирования строка состоит только из ASCII и делится по любому байту
+		e.line(s[:maxLineLength])
+		s = " " + s[maxLineLength:]
+	}
+	e.line(s)
+}
+
+// safeString проверяет, что значение можно записать без base64: только ASCII без NUL, CR и LF,
+// не начинается с пробела, двоеточия или "<" и не заканчивается пробелом
+func safeString(s string) bool {
+	if s == "" {
+		return true
+	}
+	if s[0] == ' ' || s[0] == ':' || s[0] == '<' || s[len(s)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == 0 || c == '\n' || c == '\r' || c >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package ldif
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+func TestEscapeValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"Engineering", "Engineering"},
+		{"Sales, EMEA", `Sales\, EMEA`},
+		{"R+D", `R\+D`},
+		{`"Quoted" <x>; y\z`, `\"Quoted\" \<x\>\; y\\z`},
+		{"#1 team", `\#1 team`},
+		{" padded ", `\ padded\ `},
+		{"Отдел продаж, Москва", `Отдел продаж\, Москва`},
+		{"a\x00b", `a\00b`},
+	}
+	for _, tt := range tests {
+		if got := EscapeValue(tt.value); got != tt.expected {
+			t.Errorf("%q: expected %q, got %q", tt.value, tt.expected, got)
+		}
+	}
+}
+
+func TestValidateDN(t *testing.T) {
+	valid := []string{"dc=example,dc=com", "ou=People,dc=example,dc=com", `o=Acme\, Inc.,c=US`, "cn=a+uid=b,dc=x", "2.5.4.3=x"}
+	for _, dn := range valid {
+		if err := ValidateDN(dn); err != nil {
+			t.Errorf("%q: unexpected error %v", dn, err)
+		}
+	}
+	invalid := []string{"", "   ", "example.com", "dc=example,", "=x", "dc=", "1dc=x"}
+	for _, dn := range invalid {
+		if err := ValidateDN(dn); err != ErrInvalidDN {
+			t.Errorf("%q: expected ErrInvalidDN, got %v", dn, err)
+		}
+	}
+}
+
+// TestWrite_Golden сравнивает выгрузку с эталоном: вложенность по parent_id, экранирование RDN,
+// base64 для кириллицы и перенос длинных строк
+func TestWrite_Golden(t *testing.T) {
+	company, engineering := 1, 2
+	userName := "ivan.petrov"
+	depts := []model.Department{
+		{ID: 3, Name: "Отдел продаж, Москва", ParentID: &company},
+		{ID: 2, Name: "Engineering", ParentID: &company},
+		{ID: 1, Name: "Acme"},
+		{ID: 4, Name: "Platform", ParentID: &engineering},
+	}
+	employees := []model.Employee{
+		{ID: 11, DepartmentID: 3, FullName: "Анна Смирнова", Position: "Менеджер по работе с ключевыми клиентами"},
+		{ID: 10, DepartmentID: 2, FullName: "John Doe", Position: "CTO", UserName: &userName},
+		{ID: 12, DepartmentID: 4, FullName: "Jane Roe", Position: "Developer"},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "dc=example,dc=com", depts, employees); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile(filepath.Join("testdata", "org.ldif"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(expected) {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if len(line) > maxLineLength {
+			t.Errorf("line longer than %d: %q", maxLineLength, line)
+		}
+	}
+}
+
+// TestWrite_Base64DN проверяет, что DN с кириллицей после склейки перенесённых строк декодируется в экранированный DN
+func TestWrite_Base64DN(t *testing.T) {
+	depts := []model.Department{{ID: 1, Name: "Отдел продаж, Москва"}}
+	var buf bytes.Buffer
+	if err := Write(&buf, "dc=example,dc=com", depts, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	unfolded := strings.ReplaceAll(buf.String(), "\n ", "")
+	for _, line := range strings.Split(unfolded, "\n") {
+		if encoded, ok := strings.CutPrefix(line, "dn:: "); ok {
+			dn, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(dn) != `ou=Отдел продаж\, Москва,dc=example,dc=com` {
+				t.Errorf("unexpected dn %q", dn)
+			}
+			return
+		}
+	}
+	t.Errorf("base64 dn not found in:\n%s", buf.String())
+}
@@ -0,0 +1,198 @@
+// Package lint проверяет оргструктуру на типичные проблемы: пустые подразделения,
+// цепочки из единственных потомков, превышение допустимой глубины и размера команды.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Правила проверки
+const (
+	RuleEmptyDepartment  = "empty_department"
+	RuleSingleChildChain = "single_child_chain"
+	RuleMaxDepth         = "max_depth"
+	RuleTeamSize         = "team_size"
+)
+
+// Rules перечисляет все правила
+var Rules = []string{RuleEmptyDepartment, RuleSingleChildChain, RuleMaxDepth, RuleTeamSize}
+
+// Уровни серьёзности находок
+const (
+	SeverityInfo    = "info"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+var severityRank = map[string]int{SeverityInfo: 0, SeverityWarning: 1, SeverityError: 2}
+
+// defaultSeverities уровни серьёзности правил по умолчанию
+var defaultSeverities = map[string]string{
+	RuleEmptyDepartment:  SeverityWarning,
+	RuleSingleChildChain: SeverityInfo,
+	RuleMaxDepth:         SeverityError,
+	RuleTeamSize:         SeverityWarning,
+}
+
+// ValidRule проверяет, что правило известно
+func ValidRule(rule string) bool {
+	return slices.Contains(Rules, rule)
+}
+
+// ValidSeverity проверяет, что уровень серьёзности известен
+func ValidSeverity(severity string) bool {
+	_, ok := severityRank[severity]
+	return ok
+}
+
+// Config набор включённых правил и их пороги
+type Config struct {
+	// Rules — включённые правила; пустой список — все
+	Rules []string
+	// MaxDepth — допустимое число уровней дерева, корень — уровень 1
+	MaxDepth int
+	// MaxTeamSize — допустимое число сотрудников непосредственно в подразделении
+	MaxTeamSize int
+	// Severities переопределяет уровни серьёзности правил
+	Severities map[string]string
+}
+
+// DefaultConfig возвращает конфигурацию по умолчанию: все правила, 6 уровней, команды до 15 человек
+func DefaultConfig() Config {
+	return Config{MaxDepth: 6, MaxTeamSize: 15}
+}
+
+// Validate проверяет имена правил, уровни серьёзности и пороги
+func (c Config) Validate() error {
+	for _, rule := range c.Rules {
+		if !ValidRule(rule) {
+			return fmt.Errorf("unknown rule %q", rule)
+		}
+	}
+	for rule, severity := range c.Severities {
+		if !ValidRule(rule) {
+			return fmt.Errorf("unknown rule %q", rule)
+		}
+		if !ValidSeverity(severity) {
+			return fmt.Errorf("invalid severity %q", severity)
+		}
+	}
+	if c.MaxDepth < 1 {
+		return errors.New("max_depth must be positive")
+	}
+	if c.MaxTeamSize < 1 {
+		return errors.New("max_team_size must be positive")
+	}
+	return nil
+}
+
+func (c Config) enabled(rule string) bool {
+	return len(c.Rules) == 0 || slices.Contains(c.Rules, rule)
+}
+
+func (c Config) severity(rule string) string {
+	if severity, ok := c.Severities[rule]; ok {
+		return severity
+	}
+	return defaultSeverities[rule]
+}
+
+// Finding нарушение правила подразделением; Path — названия от корня до подразделения
+type Finding struct {
+	Rule         string   `json:"rule"`
+	Severity     string   `json:"severity"`
+	DepartmentID int      `json:"department_id"`
+	Path         []string `json:"path"`
+	Message      string   `json:"message"`
+}
+
+// Report результат проверки; Summary — число находок по уровням серьёзности
+type Report struct {
+	Findings []Finding      `json:"findings"`
+	Summary  map[string]int `json:"summary"`
+}
+
+// HasSeverity сообщает, есть ли находки с уровнем не ниже severity
+func (r *Report) HasSeverity(severity string) bool {
+	for _, f := range r.Findings {
+		if severityRank[f.Severity] >= severityRank[severity] {
+			return true
+		}
+	}
+	return false
+}
+
+// Run проверяет дерево подразделений; headcounts — число сотрудников непосредственно в подразделении.
+// Дерево обходится в глубину, потомки — по названию, так что находки упорядочены по пути.
+func Run(depts []model.Department, headcounts map[int]int, cfg Config) *Report {
+	byID := make(map[int]*model.Department, len(depts))
+	for i := range depts {
+		byID[depts[i].ID] = &depts[i]
+	}
+	// Подразделения с отсутствующим родителем считаются корнями
+	var roots []int
+	children := make(map[int][]int, len(depts))
+	for _, d := range depts {
+		if d.ParentID != nil && byID[*d.ParentID] != nil {
+			children[*d.ParentID] = append(children[*d.ParentID], d.ID)
+		} else {
+			roots = append(roots, d.ID)
+		}
+	}
+	sortByName := func(ids []int) {
+		sort.Slice(ids, func(i, j int) bool {
+			a, b := byID[ids[i]], byID[ids[j]]
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+			return a.ID < b.ID
+		})
+	}
+
+	report := &Report{
+		Findings: []Finding{},
+		Summary:  map[string]int{SeverityInfo: 0, SeverityWarning: 0, SeverityError: 0},
+	}
+	add := func(rule string, id int, path []string, message string) {
+		severity := cfg.severity(rule)
+		report.Findings = append(report.Findings, Finding{
+			Rule: rule, Severity: severity, DepartmentID: id, Path: path, Message: message,
+		})
+		report.Summary[severity]++
+	}
+
+	var visit func(id int, parentPath []string)
+	visit = func(id int, parentPath []string) {
+		path := append(slices.Clip(parentPath), byID[id].Name)
+		kids := children[id]
+		headcount := headcounts[id]
+
+		if cfg.enabled(RuleEmptyDepartment) && len(kids) == 0 && headcount == 0 {
+			add(RuleEmptyDepartment, id, path, "department has no employees and no subdepartments")
+		}
+		if cfg.enabled(RuleSingleChildChain) && len(kids) == 1 && headcount == 0 {
+			add(RuleSingleChildChain, id, path, fmt.Sprintf("department has no employees and a single subdepartment %q", byID[kids[0]].Name))
+		}
+		if cfg.enabled(RuleMaxDepth) && len(path) > cfg.MaxDepth {
+			add(RuleMaxDepth, id, path, fmt.Sprintf("department is at level %d, maximum is %d", len(path), cfg.MaxDepth))
+		}
+		if cfg.enabled(RuleTeamSize) && headcount > cfg.MaxTeamSize {
+			add(RuleTeamSize, id, path, fmt.Sprintf("department has %d employees, maximum is %d", headcount, cfg.MaxTeamSize))
+		}
+
+		sortByName(kids)
+		for _, child := range kids {
+			visit(child, path)
+		}
+	}
+	sortByName(roots)
+	for _, id := range roots {
+		visit(id, nil)
+	}
+	return report
+}
@@ -0,0 +1,99 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+func ptr(n int) *int { return &n }
+
+// testTree: Company -> Engineering -> Platform -> Core, Company -> Sales, Company -> Legal
+func testTree() ([]model.Department, map[int]int) {
+	depts := []model.Department{
+		{ID: 1, Name: "Company"},
+		{ID: 2, Name: "Engineering", ParentID: ptr(1)},
+		{ID: 3, Name: "Platform", ParentID: ptr(2)},
+		{ID: 4, Name: "Core", ParentID: ptr(3)},
+		{ID: 5, Name: "Sales", ParentID: ptr(1)},
+		{ID: 6, Name: "Legal", ParentID: ptr(1)},
+	}
+	headcounts := map[int]int{1: 2, 4: 20, 5: 3}
+	return depts, headcounts
+}
+
+func TestRun(t *testing.T) {
+	depts, headcounts := testTree()
+	report := Run(depts, headcounts, Config{MaxDepth: 3, MaxTeamSize: 15})
+
+	type row struct {
+		rule     string
+		severity string
+		id       int
+	}
+	var got []row
+	for _, f := range report.Findings {
+		got = append(got, row{f.Rule, f.Severity, f.DepartmentID})
+	}
+	want := []row{
+		{RuleSingleChildChain, SeverityInfo, 2},
+		{RuleSingleChildChain, SeverityInfo, 3},
+		{RuleMaxDepth, SeverityError, 4},
+		{RuleTeamSize, SeverityWarning, 4},
+		{RuleEmptyDepartment, SeverityWarning, 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected findings:\n got %v\nwant %v", got, want)
+	}
+	if path := report.Findings[2].Path; !reflect.DeepEqual(path, []string{"Company", "Engineering", "Platform", "Core"}) {
+		t.Errorf("unexpected path %v", path)
+	}
+	if report.Summary[SeverityError] != 1 || report.Summary[SeverityWarning] != 2 || report.Summary[SeverityInfo] != 2 {
+		t.Errorf("unexpected summary %v", report.Summary)
+	}
+	if !report.HasSeverity(SeverityWarning) || !report.HasSeverity(SeverityError) {
+		t.Error("expected findings of warning and error severity")
+	}
+}
+
+func TestRun_Config(t *testing.T) {
+	depts, headcounts := testTree()
+	cfg := Config{
+		Rules:       []string{RuleTeamSize, RuleEmptyDepartment},
+		MaxDepth:    1,
+		MaxTeamSize: 2,
+		Severities:  map[string]string{RuleTeamSize: SeverityError},
+	}
+	report := Run(depts, headcounts, cfg)
+	if len(report.Findings) != 3 || report.Summary[SeverityError] != 2 || report.Summary[SeverityWarning] != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	for _, f := range report.Findings {
+		if f.Rule != RuleTeamSize && f.Rule != RuleEmptyDepartment {
+			t.Errorf("disabled rule reported: %+v", f)
+		}
+	}
+
+	if report := Run(nil, nil, DefaultConfig()); len(report.Findings) != 0 || report.HasSeverity(SeverityInfo) {
+		t.Errorf("expected empty report, got %+v", report)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	invalid := []Config{
+		{Rules: []string{"deep"}, MaxDepth: 6, MaxTeamSize: 15},
+		{Severities: map[string]string{RuleTeamSize: "fatal"}, MaxDepth: 6, MaxTeamSize: 15},
+		{Severities: map[string]string{"deep": SeverityError}, MaxDepth: 6, MaxTeamSize: 15},
+		{MaxDepth: 0, MaxTeamSize: 15},
+		{MaxDepth: 6, MaxTeamSize: 0},
+	}
+	for _, cfg := range invalid {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%+v: expected error", cfg)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+)
+
+// APIKey учётные данные для сервисных клиентов.
+// Сам ключ не хранится — только его хеш.
+type APIKey struct {
+	ID         int        `json:"id" gorm:"primaryKey"`
+	TenantID   int        `json:"-" gorm:"not null;default:1;index"`
+	Name       string     `json:"name" gorm:"size:200;not null"`
+	Prefix     string     `json:"prefix" gorm:"size:20;not null"`
+	KeyHash    string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Scope      string     `json:"scope" gorm:"size:20;not null"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedBy  string     `json:"created_by" gorm:"size:200"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// Active сообщает, можно ли использовать ключ в момент now
+func (k *APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// APIKeyWithSecret ответ на создание и ротацию: ключ показывается только один раз
+type APIKeyWithSecret struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string  `json:"name"`
+	Scope     string  `json:"scope"`
+	ExpiresAt *string `json:"expires_at"`
+}
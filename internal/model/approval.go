@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// Действия, для которых настраивается согласование
+const (
+	ApprovalDeleteDepartment = "delete_department"
+	ApprovalMoveDepartment   = "move_department"
+)
+
+// Статусы запроса на изменение
+const (
+	ChangeRequestPending  = "pending"
+	ChangeRequestApproved = "approved"
+	ChangeRequestRejected = "rejected"
+)
+
+// ValidApprovalAction проверяет, что для действия можно настроить согласование
+func ValidApprovalAction(action string) bool {
+	return action == ApprovalDeleteDepartment || action == ApprovalMoveDepartment
+}
+
+// ApprovalPolicy требует согласования действия над подразделением, в поддереве которого
+// больше MinEmployees сотрудников в статусе active. Согласовать может субъект с ролью
+// не ниже ApproverRole на подразделение, кроме автора изменения.
+type ApprovalPolicy struct {
+	ID           int       `json:"id" gorm:"primaryKey"`
+	TenantID     int       `json:"-" gorm:"not null;default:1;uniqueIndex:idx_approval_policies_action"`
+	Action       string    `json:"action" gorm:"size:50;not null;uniqueIndex:idx_approval_policies_action"`
+	MinEmployees int       `json:"min_employees" gorm:"not null"`
+	ApproverRole string    `json:"approver_role" gorm:"size:20;not null"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type SetApprovalPolicyRequest struct {
+	MinEmployees int    `json:"min_employees"`
+	ApproverRole string `json:"approver_role"`
+}
+
+// ChangeRequest изменение, ожидающее согласования. Параметры вызова сохраняются как есть
+// и передаются методу сервиса после одобрения; DepartmentVersion — версия подразделения
+// на момент запроса, изменение подразделения после этого делает запрос невыполнимым.
+type ChangeRequest struct {
+	ID                int              `json:"id" gorm:"primaryKey"`
+	TenantID          int              `json:"-" gorm:"not null;default:1;index"`
+	Action            string           `json:"action" gorm:"size:50;not null"`
+	DepartmentID      int              `json:"department_id" gorm:"not null"`
+	DepartmentName    string           `json:"department_name" gorm:"size:200;not null"`
+	DepartmentVersion int              `json:"department_version" gorm:"not null"`
+	Employees         int              `json:"employees" gorm:"not null"`
+	PolicyID          *int             `json:"policy_id,omitempty"`
+	ApproverRole      string           `json:"approver_role" gorm:"size:20;not null"`
+	Mode              string           `json:"mode,omitempty" gorm:"size:20"`
+	ReassignToID      *int             `json:"reassign_to_department_id,omitempty"`
+	Patch             *DepartmentPatch `json:"patch,omitempty" gorm:"serializer:json;type:jsonb"`
+	Status            string           `json:"status" gorm:"size:20;not null;default:pending"`
+	RequestedBy       string           `json:"requested_by" gorm:"size:200"`
+	DecidedBy         *string          `json:"decided_by,omitempty" gorm:"size:200"`
+	Comment           *string          `json:"comment,omitempty" gorm:"size:2000"`
+	CreatedAt         time.Time        `json:"created_at"`
+	DecidedAt         *time.Time       `json:"decided_at,omitempty"`
+}
+
+type DecideChangeRequest struct {
+	Comment string `json:"comment"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Сущности, для которых задаются дополнительные атрибуты
+const (
+	AttributeEntityEmployee   = "employee"
+	AttributeEntityDepartment = "department"
+)
+
+// Типы значений дополнительных атрибутов
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeDate    = "date"
+	AttributeTypeEnum    = "enum"
+)
+
+// ValidAttributeEntity проверяет, что сущность поддерживает дополнительные атрибуты
+func ValidAttributeEntity(entity string) bool {
+	return entity == AttributeEntityEmployee || entity == AttributeEntityDepartment
+}
+
+// ValidAttributeType проверяет, что тип атрибута известен
+func ValidAttributeType(t string) bool {
+	switch t {
+	case AttributeTypeString, AttributeTypeNumber, AttributeTypeBoolean, AttributeTypeDate, AttributeTypeEnum:
+		return true
+	}
+	return false
+}
+
+// AttributeDefinition схема дополнительного атрибута сотрудников или подразделений организации
+type AttributeDefinition struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	TenantID    int       `json:"-" gorm:"not null;default:1;index"`
+	Entity      string    `json:"entity" gorm:"size:20;not null"`
+	Name        string    `json:"name" gorm:"size:64;not null"`
+	Type        string    `json:"type" gorm:"size:20;not null"`
+	Required    bool      `json:"required" gorm:"not null"`
+	EnumValues  []string  `json:"enum_values,omitempty" gorm:"serializer:json;type:jsonb"`
+	Description string    `json:"description,omitempty" gorm:"size:2000"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateAttributeDefinitionRequest struct {
+	Entity      string   `json:"entity"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	EnumValues  []string `json:"enum_values"`
+	Description string   `json:"description"`
+}
+
+// Attributes значения дополнительных атрибутов по имени (колонка jsonb).
+// В частичном изменении null удаляет атрибут.
+type Attributes map[string]any
+
+func (a Attributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (a *Attributes) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return errors.New("unsupported type for Attributes")
+	}
+	*a = nil
+	return json.Unmarshal(data, a)
+}
+
+// GormDataType тип колонки при AutoMigrate
+func (Attributes) GormDataType() string {
+	return "jsonb"
+}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// Операции пакетного запроса
+const (
+	BatchCreateDepartment = "create_department"
+	BatchUpdateDepartment = "update_department"
+	BatchDeleteDepartment = "delete_department"
+	BatchCreateEmployee   = "create_employee"
+)
+
+// Статусы операций в ответе
+const (
+	BatchStatusOK         = "ok"
+	BatchStatusFailed     = "failed"
+	BatchStatusRolledBack = "rolled_back"
+	BatchStatusSkipped    = "skipped"
+)
+
+// BatchRef ссылка на подразделение: число — существующий ID,
+// строка — ref подразделения, созданного ранее в том же пакете
+type BatchRef struct {
+	ID  int
+	Ref string
+}
+
+func (r *BatchRef) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(data, []byte(`"`)) {
+		if err := json.Unmarshal(data, &r.Ref); err != nil {
+			return err
+		}
+		if r.Ref == "" {
+			return errors.New("empty ref")
+		}
+		return nil
+	}
+	return json.Unmarshal(data, &r.ID)
+}
+
+func (r BatchRef) MarshalJSON() ([]byte, error) {
+	if r.Ref != "" {
+		return json.Marshal(r.Ref)
+	}
+	return json.Marshal(r.ID)
+}
+
+// BatchOperation одна операция пакета; набор полей зависит от Op
+type BatchOperation struct {
+	Op string `json:"op"`
+	// Ref имя созданного подразделения для ссылок из последующих операций
+	Ref string `json:"ref,omitempty"`
+	// ID целевое подразделение update_department и delete_department
+	ID *BatchRef `json:"id,omitempty"`
+	// Version ожидаемая версия подразделения (аналог If-Match), 0 — без проверки
+	Version int `json:"version,omitempty"`
+
+	Name     string    `json:"name,omitempty"`
+	ParentID *BatchRef `json:"parent_id,omitempty"`
+
+	Mode                   string    `json:"mode,omitempty"`
+	ReassignToDepartmentID *BatchRef `json:"reassign_to_department_id,omitempty"`
+
+	DepartmentID *BatchRef `json:"department_id,omitempty"`
+	FullName     string    `json:"full_name,omitempty"`
+	Position     string    `json:"position,omitempty"`
+	HiredAt      *string   `json:"hired_at,omitempty"`
+}
+
+type BatchRequest struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult результат операции
+type BatchResult struct {
+	Index      int         `json:"index"`
+	Op         string      `json:"op"`
+	Ref        string      `json:"ref,omitempty"`
+	Status     string      `json:"status"`
+	Error      string      `json:"error,omitempty"`
+	Department *Department `json:"department,omitempty"`
+	Employee   *Employee   `json:"employee,omitempty"`
+}
+
+// BatchResponse результаты всех операций; при ошибке пакет откатывается целиком
+type BatchResponse struct {
+	Committed bool          `json:"committed"`
+	Results   []BatchResult `json:"results"`
+}
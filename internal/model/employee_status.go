@@ -0,0 +1,51 @@
+package model
+
+import "slices"
+
+// Статусы занятости сотрудника
+const (
+	// EmployeePending оффер принят, сотрудник ещё не вышел на работу
+	EmployeePending    = "pending"
+	EmployeeActive     = "active"
+	EmployeeOnLeave    = "on_leave"
+	EmployeeTerminated = "terminated"
+)
+
+// EmployeeStatuses все статусы занятости
+var EmployeeStatuses = []string{EmployeePending, EmployeeActive, EmployeeOnLeave, EmployeeTerminated}
+
+// employeeTransitions допустимые переходы между статусами; из terminated переходов нет
+var employeeTransitions = map[string][]string{
+	EmployeePending: {EmployeeActive, EmployeeTerminated},
+	EmployeeActive:  {EmployeeOnLeave, EmployeeTerminated},
+	EmployeeOnLeave: {EmployeeActive, EmployeeTerminated},
+}
+
+// ValidEmployeeStatus проверяет, что статус известен
+func ValidEmployeeStatus(status string) bool {
+	return slices.Contains(EmployeeStatuses, status)
+}
+
+// CanTransition сообщает, допустим ли переход сотрудника из статуса from в статус to
+func CanTransition(from, to string) bool {
+	return slices.Contains(employeeTransitions[from], to)
+}
+
+// Employed сообщает, состоит ли сотрудник в штате: работает или в отпуске
+func (e *Employee) Employed() bool {
+	return e.Status == EmployeeActive || e.Status == EmployeeOnLeave
+}
+
+// ChangeEmployeeStatusRequest смена статуса сотрудника.
+// Для terminated effective_date — дата увольнения (по умолчанию сегодня), reason — причина.
+type ChangeEmployeeStatusRequest struct {
+	Status        string  `json:"status"`
+	EffectiveDate *string `json:"effective_date"`
+	Reason        *string `json:"reason"`
+}
+
+// EmployeeStatusChangedData данные события employee.status_changed
+type EmployeeStatusChangedData struct {
+	Employee  *Employee `json:"employee"`
+	OldStatus string    `json:"old_status"`
+}
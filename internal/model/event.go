@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+)
+
+// Типы событий изменения оргструктуры
+const (
+	EventDepartmentCreated = "department.created"
+	EventDepartmentUpdated = "department.updated"
+	EventDepartmentMoved   = "department.moved"
+	EventDepartmentDeleted = "department.deleted"
+	EventChildrenReordered = "department.children_reordered"
+	EventEmployeeCreated   = "employee.created"
+	EventEmployeeUpdated   = "employee.updated"
+	EventEmployeeStatus    = "employee.status_changed"
+
+	// EventAll подписка на все события
+	EventAll = "*"
+)
+
+// EventTypes перечень всех публикуемых событий
+var EventTypes = []string{
+	EventDepartmentCreated,
+	EventDepartmentUpdated,
+	EventDepartmentMoved,
+	EventDepartmentDeleted,
+	EventChildrenReordered,
+	EventEmployeeCreated,
+	EventEmployeeUpdated,
+	EventEmployeeStatus,
+}
+
+// ValidEventType проверяет, что тип события известен
+func ValidEventType(eventType string) bool {
+	if eventType == EventAll {
+		return true
+	}
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event конверт события, отправляемый подписчикам
+type Event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// DepartmentMovedData данные события department.moved
+type DepartmentMovedData struct {
+	Department  *Department `json:"department"`
+	OldParentID *int        `json:"old_parent_id"`
+	NewParentID *int        `json:"new_parent_id"`
+}
+
+// DepartmentDeletedData данные события department.deleted
+type DepartmentDeletedData struct {
+	ID                     int    `json:"id"`
+	Mode                   string `json:"mode"`
+	ReassignToDepartmentID *int   `json:"reassign_to_department_id,omitempty"`
+}
+
+// ChildrenReorderedData данные события department.children_reordered
+type ChildrenReorderedData struct {
+	ParentID int   `json:"parent_id"`
+	Order    []int `json:"order"`
+}
+
+// EmployeeUpdatedData данные события employee.updated
+type EmployeeUpdatedData struct {
+	Employee        *Employee `json:"employee"`
+	OldDepartmentID int       `json:"old_department_id"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// IdempotencyKey сохранённый результат запроса с заголовком Idempotency-Key.
+// ResponseStatus == 0 означает, что исходный запрос ещё выполняется.
+type IdempotencyKey struct {
+	ID             int       `json:"id" gorm:"primaryKey"`
+	TenantID       int       `json:"-" gorm:"not null;default:1;uniqueIndex:idx_idempotency_keys_key"`
+	Subject        string    `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_key"`
+	Key            string    `json:"key" gorm:"size:255;not null;uniqueIndex:idx_idempotency_keys_key"`
+	Fingerprint    string    `json:"-" gorm:"size:64;not null"`
+	ResponseStatus int       `json:"response_status" gorm:"not null;default:0"`
+	ResponseBody   []byte    `json:"-" gorm:"type:bytea"`
+	ResponseETag   string    `json:"-" gorm:"column:response_etag;size:100"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// Completed сообщает, сохранён ли ответ исходного запроса
+func (k *IdempotencyKey) Completed() bool {
+	return k.ResponseStatus != 0
+}
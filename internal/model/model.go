@@ -5,36 +5,107 @@ import (
 )
 
 type Department struct {
-	ID        int          `json:"id" gorm:"primaryKey"`
-	Name      string       `json:"name" gorm:"size:200;not null"`
-	ParentID  *int         `json:"parent_id" gorm:"index"`
-	CreatedAt time.Time    `json:"created_at"`
-	Employees []Employee   `json:"employees,omitempty" gorm:"foreignKey:DepartmentID"`
-	Children  []Department `json:"children,omitempty" gorm:"foreignKey:ParentID"`
+	ID          int          `json:"id" gorm:"primaryKey"`
+	TenantID    int          `json:"-" gorm:"not null;default:1;index"`
+	Name        string       `json:"name" gorm:"size:200;not null"`
+	ParentID    *int         `json:"parent_id" gorm:"index"`
+	Code        *string      `json:"code,omitempty" gorm:"size:32"`
+	CostCenter  *string      `json:"cost_center,omitempty" gorm:"size:50"`
+	Description *string      `json:"description,omitempty" gorm:"size:2000"`
+	Active      bool         `json:"active" gorm:"not null"`
+	Attributes  Attributes   `json:"attributes,omitempty" gorm:"not null"`
+	SortOrder   int          `json:"sort_order" gorm:"not null;default:0"`
+	Version     int          `json:"version" gorm:"not null;default:1"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	Employees   []Employee   `json:"employees,omitempty" gorm:"foreignKey:DepartmentID"`
+	Children    []Department `json:"children,omitempty" gorm:"foreignKey:ParentID"`
 }
 
 type Employee struct {
-	ID           int        `json:"id" gorm:"primaryKey"`
-	DepartmentID int        `json:"department_id" gorm:"not null;index"`
-	FullName     string     `json:"full_name" gorm:"size:200;not null"`
-	Position     string     `json:"position" gorm:"size:200;not null"`
-	HiredAt      *time.Time `json:"hired_at,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
+	ID                int        `json:"id" gorm:"primaryKey"`
+	TenantID          int        `json:"-" gorm:"not null;default:1;index"`
+	DepartmentID      int        `json:"department_id" gorm:"not null;index"`
+	FullName          string     `json:"full_name" gorm:"size:200;not null"`
+	Position          string     `json:"position" gorm:"size:200;not null"`
+	PositionID        *int       `json:"position_id,omitempty" gorm:"index"`
+	HiredAt           *time.Time `json:"hired_at,omitempty"`
+	Status            string     `json:"status" gorm:"size:20;not null;default:active"`
+	TerminatedAt      *time.Time `json:"terminated_at,omitempty" gorm:"type:date"`
+	TerminationReason *string    `json:"termination_reason,omitempty" gorm:"size:500"`
+	UserName          *string    `json:"user_name,omitempty" gorm:"size:200"`
+	ExternalID        *string    `json:"external_id,omitempty" gorm:"size:200"`
+	Attributes        Attributes `json:"attributes,omitempty" gorm:"not null"`
+	Version           int        `json:"version" gorm:"not null;default:1"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
 }
 
 // DTO для запросов
 type CreateDepartmentRequest struct {
-	Name     string `json:"name"`
-	ParentID *int   `json:"parent_id"`
+	Name        string  `json:"name"`
+	ParentID    *int    `json:"parent_id"`
+	Code        *string `json:"code"`
+	CostCenter  *string `json:"cost_center"`
+	Description *string `json:"description"`
+	// По умолчанию подразделение активно
+	Active     *bool      `json:"active"`
+	Attributes Attributes `json:"attributes"`
 }
 
 type CreateEmployeeRequest struct {
-	FullName string  `json:"full_name"`
-	Position string  `json:"position"`
-	HiredAt  *string `json:"hired_at"`
+	FullName   string     `json:"full_name"`
+	Position   string     `json:"position"`
+	PositionID *int       `json:"position_id"`
+	HiredAt    *string    `json:"hired_at"`
+	UserName   *string    `json:"user_name"`
+	ExternalID *string    `json:"external_id"`
+	Attributes Attributes `json:"attributes"`
+	// Status — pending или active (по умолчанию)
+	Status *string `json:"status"`
 }
 
 type UpdateDepartmentRequest struct {
-	Name     string `json:"name"`
-	ParentID *int   `json:"parent_id"`
+	Name        string  `json:"name"`
+	ParentID    *int    `json:"parent_id"`
+	Code        *string `json:"code"`
+	CostCenter  *string `json:"cost_center"`
+	Description *string `json:"description"`
+	Active      *bool   `json:"active"`
+	// Attributes объединяется с текущими значениями; null удаляет атрибут
+	Attributes Attributes `json:"attributes"`
+}
+
+// ReorderChildrenRequest новый порядок непосредственных потомков: полный список их ID
+type ReorderChildrenRequest struct {
+	Order []int `json:"order"`
+}
+
+// DepartmentFilter условия списка подразделений; пустые поля не ограничивают выборку
+type DepartmentFilter struct {
+	Active     *bool
+	CostCenter string
+	// ParentID ограничивает выборку непосредственными потомками подразделения, Root — подразделениями верхнего уровня
+	ParentID *int
+	Root     bool
+	// Query — подстрока имени без учёта регистра
+	Query string
+	// Attributes — точные значения дополнительных атрибутов
+	Attributes Attributes
+	Limit      int
+	Offset     int
+}
+
+// EmployeeFilter условия поиска сотрудников; пустые поля не ограничивают выборку
+type EmployeeFilter struct {
+	// DepartmentID ограничивает выборку сотрудниками подразделения (без поддерева)
+	DepartmentID *int
+	// Query — подстрока ФИО без учёта регистра
+	Query string
+	// Statuses — статусы занятости; пустой список — любой статус
+	Statuses []string
+	// Attributes — точные значения дополнительных атрибутов
+	Attributes Attributes
+	Limit      int
+	Offset     int
 }
@@ -1,8 +1,12 @@
 package model
 
 import (
+	"encoding/json"
+	"net/netip"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 // Department Tests
@@ -373,3 +377,334 @@ func BenchmarkEmployeeCreation(b *testing.B) {
 		}
 	}
 }
+
+// RoleBinding Tests
+
+func TestRoleSatisfies(t *testing.T) {
+	tests := []struct {
+		granted  string
+		required string
+		expected bool
+	}{
+		{RoleAdmin, RoleEditor, true},
+		{RoleEditor, RoleEditor, true},
+		{RoleEditor, RoleAdmin, false},
+		{RoleViewer, RoleEditor, false},
+		{RoleViewer, RoleViewer, true},
+		{"owner", RoleViewer, false},
+	}
+
+	for _, tt := range tests {
+		if got := RoleSatisfies(tt.granted, tt.required); got != tt.expected {
+			t.Errorf("RoleSatisfies(%q, %q) = %v, expected %v", tt.granted, tt.required, got, tt.expected)
+		}
+	}
+}
+
+func TestValidRole(t *testing.T) {
+	for _, role := range []string{RoleViewer, RoleEditor, RoleAdmin} {
+		if !ValidRole(role) {
+			t.Errorf("expected %q to be valid", role)
+		}
+	}
+	if ValidRole("superuser") {
+		t.Error("unknown role should be invalid")
+	}
+}
+
+// APIKey Tests
+
+func TestAPIKey_Active(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		key      APIKey
+		expected bool
+	}{
+		{"no expiry", APIKey{}, true},
+		{"not expired", APIKey{ExpiresAt: &future}, true},
+		{"expired", APIKey{ExpiresAt: &past}, false},
+		{"revoked", APIKey{RevokedAt: &past}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.Active(now); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// Webhook Tests
+
+func TestWebhookSubscription_Matches(t *testing.T) {
+	sub := WebhookSubscription{EventTypes: []string{EventDepartmentMoved, EventEmployeeCreated}}
+	if !sub.Matches(EventDepartmentMoved) {
+		t.Error("expected subscription to match department.moved")
+	}
+	if sub.Matches(EventDepartmentDeleted) {
+		t.Error("subscription should not match department.deleted")
+	}
+
+	all := WebhookSubscription{EventTypes: []string{EventAll}}
+	if !all.Matches(EventDepartmentDeleted) {
+		t.Error("wildcard subscription should match any event")
+	}
+}
+
+func TestValidEventType(t *testing.T) {
+	for _, eventType := range append(EventTypes, EventAll) {
+		if !ValidEventType(eventType) {
+			t.Errorf("expected %q to be valid", eventType)
+		}
+	}
+	if ValidEventType("department.renamed") {
+		t.Error("unknown event type should be invalid")
+	}
+}
+
+func TestJSON_ScanValue(t *testing.T) {
+	var j JSON
+	if err := j.Scan([]byte(`{"a":1}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(j) != `{"a":1}` {
+		t.Errorf("unexpected value %s", j)
+	}
+
+	v, err := j.Value()
+	if err != nil || v != `{"a":1}` {
+		t.Errorf("unexpected driver value %v (%v)", v, err)
+	}
+
+	var empty JSON
+	if data, _ := empty.MarshalJSON(); string(data) != "null" {
+		t.Errorf("empty JSON should marshal to null, got %s", data)
+	}
+}
+
+// TestBatchRef_JSON проверяет разбор ссылок пакета: число — ID, строка — ref
+func TestBatchRef_JSON(t *testing.T) {
+	var op BatchOperation
+	err := json.Unmarshal([]byte(`{"op":"create_employee","department_id":"eng","parent_id":7}`), &op)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if op.DepartmentID == nil || op.DepartmentID.Ref != "eng" {
+		t.Errorf("expected ref 'eng', got %+v", op.DepartmentID)
+	}
+	if op.ParentID == nil || op.ParentID.ID != 7 || op.ParentID.Ref != "" {
+		t.Errorf("expected id 7, got %+v", op.ParentID)
+	}
+
+	if err := json.Unmarshal([]byte(`{"department_id":""}`), &op); err == nil {
+		t.Error("expected error for empty ref")
+	}
+
+	data, _ := json.Marshal(op.ParentID)
+	if string(data) != "7" {
+		t.Errorf("expected 7, got %s", data)
+	}
+}
+
+// TestDepartmentPatch_JSON проверяет различие отсутствующего поля, null и значения
+func TestDepartmentPatch_JSON(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected DepartmentPatch
+	}{
+		{`{}`, DepartmentPatch{}},
+		{`{"parent_id":null}`, DepartmentPatch{ParentID: Null[int]()}},
+		{`{"name":"A","parent_id":5}`, DepartmentPatch{Name: Some("A"), ParentID: Some(5)}},
+		{`{"code":null,"active":false}`, DepartmentPatch{Code: Null[string](), Active: Some(false)}},
+	}
+
+	for _, tt := range tests {
+		var patch DepartmentPatch
+		if err := json.Unmarshal([]byte(tt.body), &patch); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.body, err)
+		}
+		if patch != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.body, tt.expected, patch)
+		}
+	}
+
+	var patch DepartmentPatch
+	if err := json.Unmarshal([]byte(`{"parent_id":"x"}`), &patch); err == nil {
+		t.Error("expected error for invalid parent_id")
+	}
+}
+
+// TestAttributes_ScanValue проверяет хранение атрибутов в jsonb: отсутствие значений — пустой объект
+func TestAttributes_ScanValue(t *testing.T) {
+	var empty Attributes
+	if v, err := empty.Value(); err != nil || v != "{}" {
+		t.Errorf("expected {}, got %v (%v)", v, err)
+	}
+
+	var attrs Attributes
+	if err := attrs.Scan([]byte(`{"office":"Berlin","floor":3}`)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attrs["office"] != "Berlin" || attrs["floor"] != float64(3) {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+}
+
+// TestDepartmentPatch_Attributes проверяет различие отсутствующих атрибутов, null и объекта
+func TestDepartmentPatch_Attributes(t *testing.T) {
+	var patch DepartmentPatch
+	if err := json.Unmarshal([]byte(`{"name":"A"}`), &patch); err != nil || patch.Attributes.Set {
+		t.Errorf("expected attributes unset, got %+v (%v)", patch.Attributes, err)
+	}
+
+	patch = DepartmentPatch{}
+	if err := json.Unmarshal([]byte(`{"attributes":null}`), &patch); err != nil || !patch.Attributes.Null {
+		t.Errorf("expected null attributes, got %+v (%v)", patch.Attributes, err)
+	}
+
+	patch = DepartmentPatch{}
+	if err := json.Unmarshal([]byte(`{"attributes":{"office":"Berlin","slack":null}}`), &patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attrs := *patch.Attributes.Value
+	if value, ok := attrs["slack"]; attrs["office"] != "Berlin" || !ok || value != nil {
+		t.Errorf("expected office set and slack removed, got %v", attrs)
+	}
+}
+
+// TestCanTransition проверяет допустимые переходы между статусами сотрудника
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{EmployeePending, EmployeeActive, true},
+		{EmployeePending, EmployeeTerminated, true},
+		{EmployeePending, EmployeeOnLeave, false},
+		{EmployeeActive, EmployeeOnLeave, true},
+		{EmployeeOnLeave, EmployeeActive, true},
+		{EmployeeActive, EmployeeTerminated, true},
+		{EmployeeOnLeave, EmployeeTerminated, true},
+		{EmployeeActive, EmployeePending, false},
+		{EmployeeActive, EmployeeActive, false},
+		{EmployeeTerminated, EmployeeActive, false},
+		{"", EmployeeActive, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.allowed {
+			t.Errorf("%s -> %s: expected %v, got %v", tt.from, tt.to, tt.allowed, got)
+		}
+	}
+	if (&Employee{Status: EmployeeOnLeave}).Employed() != true || (&Employee{Status: EmployeePending}).Employed() {
+		t.Error("unexpected Employed result")
+	}
+}
+
+// TestPositionKey проверяет нормализацию названий должностей для поиска дубликатов
+func TestPositionKey(t *testing.T) {
+	tests := []struct{ title, key string }{
+		{"Senior Developer", "senior developer"},
+		{"Sr. developer", "senior developer"},
+		{"  SENIOR   developer ", "senior developer"},
+		{"Jr Front-end Developer", "junior front end developer"},
+		{"Engineering Mgr", "engineering manager"},
+		{"Старший разработчик", "старший разработчик"},
+		{"Srbija Lead", "srbija lead"},
+		{"...", ""},
+	}
+	for _, tt := range tests {
+		if key := PositionKey(tt.title); key != tt.key {
+			t.Errorf("%q: expected %q, got %q", tt.title, tt.key, key)
+		}
+	}
+}
+
+// TestValidPeriod проверяет форматы периода бюджета
+func TestValidPeriod(t *testing.T) {
+	for _, period := range []string{"2026", "2026-Q1", "2026-Q4", "2026-01", "2026-12"} {
+		if !ValidPeriod(period) {
+			t.Errorf("%q: expected valid period", period)
+		}
+	}
+	for _, period := range []string{"", "26", "2026-Q5", "2026-Q0", "2026-13", "2026-00", "2026-1", "2026/01", "2026-q1"} {
+		if ValidPeriod(period) {
+			t.Errorf("%q: expected invalid period", period)
+		}
+	}
+}
+
+// TestScheduledChange_Operation проверяет операцию, выполняемую запланированным изменением
+func TestScheduledChange_Operation(t *testing.T) {
+	empID, parentID := 7, 3
+	transfer := (&ScheduledChange{Op: ScenarioTransferEmployee, DepartmentID: 5, EmployeeID: &empID}).Operation()
+	if transfer != (ScenarioOperation{Op: ScenarioTransferEmployee, DepartmentID: 5, EmployeeID: 7}) {
+		t.Errorf("unexpected transfer operation: %+v", transfer)
+	}
+	move := (&ScheduledChange{Op: ScenarioMoveDepartment, DepartmentID: 5, ParentID: &parentID}).Operation()
+	if move.DepartmentID != 5 || move.ParentID != &parentID || move.EmployeeID != 0 {
+		t.Errorf("unexpected move operation: %+v", move)
+	}
+}
+
+// TestDepartmentPatch_RoundTrip проверяет, что сохранённое изменение восстанавливается без потери отсутствующих полей
+func TestDepartmentPatch_RoundTrip(t *testing.T) {
+	patch := DepartmentPatch{Name: Some("Platform"), ParentID: Null[int]()}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"name":"Platform","parent_id":null}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+	var restored DepartmentPatch
+	if err := json.Unmarshal(data, &restored); err != nil || restored != patch {
+		t.Errorf("expected %+v, got %+v (%v)", patch, restored, err)
+	}
+}
+
+// TestTruncateRunes проверяет, что обрезка не разрывает многобайтовые символы
+func TestTruncateRunes(t *testing.T) {
+	if got := TruncateRunes("короткая", 100); got != "короткая" {
+		t.Errorf("short string should be unchanged, got %q", got)
+	}
+	long := strings.Repeat("ошибка ", 500)
+	for _, n := range []int{1, 2, 3, 1999, 2000} {
+		got := TruncateRunes(long, n)
+		if len(got) > n || !utf8.ValidString(got) || !strings.HasPrefix(long, got) {
+			t.Errorf("n=%d: got %d bytes, valid=%v", n, len(got), utf8.ValidString(got))
+		}
+		if len(got) < n-utf8.UTFMax {
+			t.Errorf("n=%d: truncated too much, got %d bytes", n, len(got))
+		}
+	}
+}
+
+// TestPublicAddress проверяет запрет адресов внутренней сети для вебхуков
+func TestPublicAddress(t *testing.T) {
+	tests := map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"0.0.0.0":          false,
+		"::":               false,
+		"::ffff:127.0.0.1": false,
+		"224.0.0.1":        false,
+	}
+	for s, want := range tests {
+		if got := PublicAddress(netip.MustParseAddr(s)); got != want {
+			t.Errorf("%s: expected %v, got %v", s, want, got)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+)
+
+// OutboxEvent событие, записанное в той же транзакции, что и изменение данных.
+// EventID служит ключом идемпотентности для приёмников.
+type OutboxEvent struct {
+	ID           int    `json:"id" gorm:"primaryKey"`
+	TenantID     int    `json:"-" gorm:"not null;default:1;index"`
+	EventID      string `json:"event_id" gorm:"size:64;not null;uniqueIndex"`
+	EventType    string `json:"event_type" gorm:"size:100;not null"`
+	DepartmentID *int   `json:"department_id"`
+	// DepartmentPath подразделение и его предки на момент события (для фильтра по поддереву)
+	DepartmentPath []int `json:"department_path" gorm:"serializer:json;type:jsonb;not null;default:'[]'"`
+	// Seq порядковый номер публикации, назначается relay
+	Seq *int `json:"seq,omitempty" gorm:"uniqueIndex"`
+	// TxID транзакция, записавшая событие; определяет порядок публикации
+	TxID      int64  `json:"-" gorm:"column:txid;not null;default:(pg_current_xact_id()::text::bigint)"`
+	Payload   JSON   `json:"payload" gorm:"not null"`
+	Attempts  int    `json:"attempts" gorm:"not null;default:0"`
+	LastError string `json:"last_error,omitempty" gorm:"size:2000"`
+	// NextAttemptAt время следующей попытки после сбоя приёмника
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	// ParkedAt время, когда событие исчерпало попытки и было отложено
+	ParkedAt    *time.Time `json:"parked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+// Optional поле частичного обновления (RFC 7396): отсутствует (Set == false),
+// явный null (Null == true) или значение
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// Some возвращает заданное значение
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Set: true, Value: v}
+}
+
+// Null возвращает явный null
+func Null[T any]() Optional[T] {
+	return Optional[T]{Set: true, Null: true}
+}
+
+// UnmarshalJSON вызывается только для присутствующего поля, в том числе для null
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// IsZero сообщает, что поле отсутствует; с тегом omitzero такое поле не попадает в JSON
+func (o Optional[T]) IsZero() bool {
+	return !o.Set
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Set || o.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// DepartmentPatch изменяемые поля подразделения; null в parent_id — перенос в корень,
+// null в code, cost_center и description очищает значение.
+// attributes объединяется с текущими значениями, null в attributes удаляет все атрибуты.
+// При сериализации отсутствующие поля опускаются, поэтому изменение можно сохранить и применить позже.
+type DepartmentPatch struct {
+	Name        Optional[string]      `json:"name,omitzero"`
+	ParentID    Optional[int]         `json:"parent_id,omitzero"`
+	Code        Optional[string]      `json:"code,omitzero"`
+	CostCenter  Optional[string]      `json:"cost_center,omitzero"`
+	Description Optional[string]      `json:"description,omitzero"`
+	Active      Optional[bool]        `json:"active,omitzero"`
+	Attributes  Optional[*Attributes] `json:"attributes,omitzero"`
+}
+
+// DepartmentDocument изменяемое представление подразделения, к которому применяется JSON Patch
+type DepartmentDocument struct {
+	Name        string     `json:"name"`
+	ParentID    *int       `json:"parent_id"`
+	Code        *string    `json:"code"`
+	CostCenter  *string    `json:"cost_center"`
+	Description *string    `json:"description"`
+	Active      bool       `json:"active"`
+	Attributes  Attributes `json:"attributes"`
+}
+
+// EmployeePatch изменяемые поля сотрудника; department_id — перевод в другое подразделение,
+// null в hired_at, user_name и external_id очищает значение, attributes — как в DepartmentPatch
+type EmployeePatch struct {
+	FullName     Optional[string]      `json:"full_name"`
+	Position     Optional[string]      `json:"position"`
+	PositionID   Optional[int]         `json:"position_id"`
+	HiredAt      Optional[string]      `json:"hired_at"`
+	DepartmentID Optional[int]         `json:"department_id"`
+	UserName     Optional[string]      `json:"user_name"`
+	ExternalID   Optional[string]      `json:"external_id"`
+	Attributes   Optional[*Attributes] `json:"attributes"`
+}
+
+// EmployeeDocument изменяемое представление сотрудника, к которому применяется JSON Patch
+type EmployeeDocument struct {
+	FullName     string     `json:"full_name"`
+	Position     string     `json:"position"`
+	PositionID   *int       `json:"position_id"`
+	HiredAt      *string    `json:"hired_at"`
+	DepartmentID int        `json:"department_id"`
+	UserName     *string    `json:"user_name"`
+	ExternalID   *string    `json:"external_id"`
+	Attributes   Attributes `json:"attributes"`
+}
+
+// Виды участников подразделения
+const (
+	MemberEmployee   = "employee"
+	MemberDepartment = "department"
+)
+
+// MembershipChange изменение состава подразделения: добавление сотрудника переводит его
+// в подразделение, добавление подразделения делает его дочерним; удаление дочернего
+// подразделения переносит его в корень. Пустой Kind при удалении — участник любого вида с этим ID.
+type MembershipChange struct {
+	Remove bool
+	Kind   string
+	ID     int
+}
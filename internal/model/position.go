@@ -0,0 +1,89 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Position должность из каталога. Сотрудники ссылаются на неё через position_id,
+// а поле Employee.Position хранит её название.
+type Position struct {
+	ID       int    `json:"id" gorm:"primaryKey"`
+	TenantID int    `json:"-" gorm:"not null;default:1;index"`
+	Title    string `json:"title" gorm:"size:200;not null"`
+	// TitleKey — нормализованное название (см. PositionKey), уникально в пределах арендатора
+	TitleKey  string    `json:"-" gorm:"type:text;not null"`
+	JobFamily *string   `json:"job_family,omitempty" gorm:"size:100"`
+	Grade     *int      `json:"grade,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Границы уровня грейда
+const (
+	MinGrade = 1
+	MaxGrade = 20
+)
+
+type CreatePositionRequest struct {
+	Title     string  `json:"title"`
+	JobFamily *string `json:"job_family"`
+	Grade     *int    `json:"grade"`
+}
+
+// UpdatePositionRequest изменение должности; отсутствующее поле не меняется,
+// пустая строка job_family и grade = 0 сбрасывают значение
+type UpdatePositionRequest struct {
+	Title     *string `json:"title"`
+	JobFamily *string `json:"job_family"`
+	Grade     *int    `json:"grade"`
+}
+
+// positionAbbreviations сокращения, которые раскрываются при нормализации названия
+var positionAbbreviations = map[string]string{
+	"sr":  "senior",
+	"jr":  "junior",
+	"mgr": "manager",
+}
+
+// PositionKey нормализует название должности для поиска дубликатов: нижний регистр,
+// все символы кроме букв и цифр — разделители, известные сокращения раскрыты.
+// "Sr. developer" и "Senior  Developer" дают один ключ "senior developer".
+// Миграция каталога повторяет это правило в SQL.
+func PositionKey(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, w := range words {
+		if full, ok := positionAbbreviations[w]; ok {
+			words[i] = full
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// PositionReport численность работающих сотрудников по семействам должностей и грейдам.
+// Сотрудники без должности из каталога учитываются с пустыми job_family и grade.
+type PositionReport struct {
+	Total               int                       `json:"total"`
+	ByJobFamily         []JobFamilyHeadcount      `json:"by_job_family"`
+	ByGrade             []GradeHeadcount          `json:"by_grade"`
+	ByJobFamilyAndGrade []JobFamilyGradeHeadcount `json:"by_job_family_and_grade"`
+}
+
+type JobFamilyHeadcount struct {
+	JobFamily *string `json:"job_family"`
+	Headcount int     `json:"headcount"`
+}
+
+type GradeHeadcount struct {
+	Grade     *int `json:"grade"`
+	Headcount int  `json:"headcount"`
+}
+
+type JobFamilyGradeHeadcount struct {
+	JobFamily *string `json:"job_family"`
+	Grade     *int    `json:"grade"`
+	Headcount int     `json:"headcount"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+)
+
+// Роли доступа в порядке возрастания прав
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+// ValidRole проверяет, что роль известна
+func ValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// RoleSatisfies сообщает, покрывает ли роль granted требуемую роль required
+func RoleSatisfies(granted, required string) bool {
+	g, ok := roleRanks[granted]
+	if !ok {
+		return false
+	}
+	return g >= roleRanks[required]
+}
+
+// RoleBinding выдаёт роль субъекту на поддерево подразделения.
+// DepartmentID == nil означает всю организацию.
+type RoleBinding struct {
+	ID           int       `json:"id" gorm:"primaryKey"`
+	TenantID     int       `json:"-" gorm:"not null;default:1;index"`
+	Subject      string    `json:"subject" gorm:"size:200;not null;index"`
+	Role         string    `json:"role" gorm:"size:20;not null"`
+	DepartmentID *int      `json:"department_id" gorm:"index"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type CreateRoleBindingRequest struct {
+	Subject      string `json:"subject"`
+	Role         string `json:"role"`
+	DepartmentID *int   `json:"department_id"`
+}
@@ -0,0 +1,100 @@
+package model
+
+import "time"
+
+// Статусы сценария реорганизации
+const (
+	ScenarioDraft   = "draft"
+	ScenarioApplied = "applied"
+)
+
+// Операции сценария
+const (
+	ScenarioMoveDepartment   = "move_department"
+	ScenarioRenameDepartment = "rename_department"
+	ScenarioMergeDepartments = "merge_departments"
+	ScenarioTransferEmployee = "transfer_employee"
+)
+
+// Scenario черновик реорганизации: последовательность операций, которые не затрагивают
+// рабочие данные до применения. DepartmentVersions и EmployeeVersions — версии затронутых
+// записей на момент добавления операций; их изменение в рабочих данных считается конфликтом.
+type Scenario struct {
+	ID                 int                 `json:"id" gorm:"primaryKey"`
+	TenantID           int                 `json:"-" gorm:"not null;default:1;index"`
+	Name               string              `json:"name" gorm:"size:200;not null"`
+	Description        *string             `json:"description,omitempty" gorm:"size:2000"`
+	Status             string              `json:"status" gorm:"size:20;not null;default:draft"`
+	Operations         []ScenarioOperation `json:"operations" gorm:"serializer:json;type:jsonb;not null"`
+	DepartmentVersions map[int]int         `json:"department_versions" gorm:"serializer:json;type:jsonb;not null"`
+	EmployeeVersions   map[int]int         `json:"employee_versions" gorm:"serializer:json;type:jsonb;not null"`
+	CreatedBy          string              `json:"created_by" gorm:"size:200"`
+	Version            int                 `json:"version" gorm:"not null;default:1"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	AppliedAt          *time.Time          `json:"applied_at,omitempty"`
+}
+
+// ScenarioOperation одна операция сценария; набор полей зависит от Op:
+//   - move_department: department_id, parent_id (отсутствует — в корень)
+//   - rename_department: department_id, name
+//   - merge_departments: department_id сливается в target_id — сотрудники и дочерние
+//     подразделения переходят в target_id, само подразделение удаляется
+//   - transfer_employee: employee_id, department_id
+type ScenarioOperation struct {
+	Op           string `json:"op"`
+	DepartmentID int    `json:"department_id,omitempty"`
+	ParentID     *int   `json:"parent_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	TargetID     int    `json:"target_id,omitempty"`
+	EmployeeID   int    `json:"employee_id,omitempty"`
+}
+
+type CreateScenarioRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+}
+
+// ScenarioConflict расхождение сценария с рабочими данными. Для изменённой или удалённой записи
+// заданы Entity, ID и версии (CurrentVersion = nil — запись удалена); для операции, которую
+// больше нельзя применить, — Operation и Message.
+type ScenarioConflict struct {
+	Entity         string `json:"entity,omitempty"`
+	ID             int    `json:"id,omitempty"`
+	BaseVersion    int    `json:"base_version,omitempty"`
+	CurrentVersion *int   `json:"current_version,omitempty"`
+	Operation      *int   `json:"operation,omitempty"`
+	Message        string `json:"message"`
+}
+
+// Виды изменений в сравнении сценария с рабочими данными
+const (
+	ChangeDepartmentMoved     = "department_moved"
+	ChangeDepartmentRenamed   = "department_renamed"
+	ChangeDepartmentMerged    = "department_merged"
+	ChangeEmployeeTransferred = "employee_transferred"
+)
+
+// ScenarioChange изменение одной записи относительно рабочих данных. From и To — прежнее и новое
+// значение: ID родителя (null — корень) для department_moved, название для department_renamed,
+// ID подразделения для department_merged (To — куда слито) и employee_transferred.
+type ScenarioChange struct {
+	Type         string `json:"type"`
+	DepartmentID int    `json:"department_id,omitempty"`
+	EmployeeID   int    `json:"employee_id,omitempty"`
+	Name         string `json:"name"`
+	From         any    `json:"from"`
+	To           any    `json:"to"`
+}
+
+// ScenarioDiff изменения сценария относительно рабочих данных и найденные конфликты
+type ScenarioDiff struct {
+	Changes   []ScenarioChange   `json:"changes"`
+	Conflicts []ScenarioConflict `json:"conflicts"`
+}
+
+// ScenarioTree прогнозируемая оргструктура после применения сценария
+type ScenarioTree struct {
+	Departments []Department       `json:"departments"`
+	Conflicts   []ScenarioConflict `json:"conflicts"`
+}
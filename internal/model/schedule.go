@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// Статусы запланированного изменения
+const (
+	ScheduledPending   = "pending"
+	ScheduledApplied   = "applied"
+	ScheduledCancelled = "cancelled"
+	ScheduledFailed    = "failed"
+)
+
+// ScheduledChange изменение с датой вступления в силу. Поддерживаются те же операции,
+// что и в сценариях: move_department (ParentID = nil — в корень), rename_department
+// и transfer_employee (DepartmentID — подразделение назначения).
+type ScheduledChange struct {
+	ID           int        `json:"id" gorm:"primaryKey"`
+	TenantID     int        `json:"-" gorm:"not null;default:1;index"`
+	Op           string     `json:"op" gorm:"size:50;not null"`
+	DepartmentID int        `json:"department_id" gorm:"not null"`
+	ParentID     *int       `json:"parent_id,omitempty"`
+	Name         string     `json:"name,omitempty" gorm:"size:200"`
+	EmployeeID   *int       `json:"employee_id,omitempty"`
+	EffectiveAt  time.Time  `json:"effective_at" gorm:"not null"`
+	Status       string     `json:"status" gorm:"size:20;not null;default:pending"`
+	Error        *string    `json:"error,omitempty" gorm:"size:2000"`
+	CreatedBy    string     `json:"created_by" gorm:"size:200"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ProcessedAt  *time.Time `json:"processed_at,omitempty"`
+}
+
+// Operation операция, которую выполняет изменение в дату вступления в силу
+func (c *ScheduledChange) Operation() ScenarioOperation {
+	op := ScenarioOperation{Op: c.Op, DepartmentID: c.DepartmentID, ParentID: c.ParentID, Name: c.Name}
+	if c.EmployeeID != nil {
+		op.EmployeeID = *c.EmployeeID
+	}
+	return op
+}
+
+// ValidScheduledStatus проверяет статус запланированного изменения
+func ValidScheduledStatus(status string) bool {
+	switch status {
+	case ScheduledPending, ScheduledApplied, ScheduledCancelled, ScheduledFailed:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"regexp"
+	"time"
+)
+
+// periodFormat формат периода бюджета: год (2026), квартал (2026-Q3) или месяц (2026-07)
+var periodFormat = regexp.MustCompile(`^[0-9]{4}(-Q[1-4]|-(0[1-9]|1[0-2]))?$`)
+
+// ValidPeriod проверяет формат периода бюджета
+func ValidPeriod(period string) bool {
+	return periodFormat.MatchString(period)
+}
+
+// HeadcountBudget утверждённая численность подразделения на период
+type HeadcountBudget struct {
+	ID                int       `json:"-" gorm:"primaryKey"`
+	TenantID          int       `json:"-" gorm:"not null;default:1;uniqueIndex:idx_headcount_budgets_period"`
+	DepartmentID      int       `json:"department_id" gorm:"not null;uniqueIndex:idx_headcount_budgets_period"`
+	Period            string    `json:"period" gorm:"size:7;not null;uniqueIndex:idx_headcount_budgets_period"`
+	ApprovedHeadcount int       `json:"approved_headcount" gorm:"not null"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type SetBudgetRequest struct {
+	ApprovedHeadcount *int `json:"approved_headcount"`
+}
+
+// Статусы вакансии
+const (
+	RequisitionOpen      = "open"
+	RequisitionFilled    = "filled"
+	RequisitionCancelled = "cancelled"
+)
+
+// ValidRequisitionStatus проверяет, что статус вакансии известен
+func ValidRequisitionStatus(status string) bool {
+	return status == RequisitionOpen || status == RequisitionFilled || status == RequisitionCancelled
+}
+
+// Requisition вакансия подразделения на должность из каталога
+type Requisition struct {
+	ID              int       `json:"id" gorm:"primaryKey"`
+	TenantID        int       `json:"-" gorm:"not null;default:1;index"`
+	DepartmentID    int       `json:"department_id" gorm:"not null;index"`
+	PositionID      int       `json:"position_id" gorm:"not null"`
+	TargetStartDate time.Time `json:"target_start_date" gorm:"type:date;not null"`
+	Status          string    `json:"status" gorm:"size:20;not null;default:open"`
+	Note            *string   `json:"note,omitempty" gorm:"size:2000"`
+	Version         int       `json:"version" gorm:"not null;default:1"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type CreateRequisitionRequest struct {
+	PositionID      int     `json:"position_id"`
+	TargetStartDate string  `json:"target_start_date"`
+	Note            *string `json:"note"`
+}
+
+// UpdateRequisitionRequest изменение вакансии; отсутствующее поле не меняется.
+// Должность и дата меняются только у открытой вакансии.
+type UpdateRequisitionRequest struct {
+	PositionID      *int    `json:"position_id"`
+	TargetStartDate *string `json:"target_start_date"`
+	Status          *string `json:"status"`
+	Note            *string `json:"note"`
+}
+
+// Staffing бюджет, фактическая численность и открытые вакансии поддерева подразделения
+type Staffing struct {
+	DepartmentID int    `json:"department_id"`
+	Period       string `json:"period"`
+	Budget       int    `json:"budget"`
+	Actual       int    `json:"actual"`
+	Open         int    `json:"open"`
+	// Available — бюджет за вычетом сотрудников и открытых вакансий; отрицательное значение — превышение
+	Available   int                  `json:"available"`
+	Departments []DepartmentStaffing `json:"departments"`
+}
+
+// DepartmentStaffing показатели одного подразделения поддерева без учёта потомков;
+// Budget — nil, если бюджет на период не утверждён
+type DepartmentStaffing struct {
+	DepartmentID int    `json:"department_id"`
+	Name         string `json:"name"`
+	Budget       *int   `json:"budget"`
+	Actual       int    `json:"actual"`
+	Open         int    `json:"open"`
+}
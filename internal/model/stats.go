@@ -0,0 +1,41 @@
+package model
+
+// Интервалы стажа в распределении DepartmentStats.Tenure
+const (
+	TenureUnderOneYear     = "lt_1y"
+	TenureOneToThreeYears  = "1_3y"
+	TenureThreeToFiveYears = "3_5y"
+	TenureFiveYearsPlus    = "5y_plus"
+	TenureUnknown          = "unknown"
+	// TenureNotStarted дата приёма ещё не наступила
+	TenureNotStarted = "not_started"
+)
+
+// DepartmentStats сводные показатели поддерева подразделения
+type DepartmentStats struct {
+	DepartmentID int `json:"department_id"`
+	// DirectHeadcount — сотрудники самого подразделения, TotalHeadcount — всего поддерева
+	DirectHeadcount       int `json:"direct_headcount"`
+	TotalHeadcount        int `json:"total_headcount"`
+	DescendantDepartments int `json:"descendant_departments"`
+	// MaxDepth — глубина самого глубокого потомка относительно подразделения; 0 — потомков нет
+	MaxDepth int `json:"max_depth"`
+	// AverageTeamSize — среднее число сотрудников на подразделение поддерева
+	AverageTeamSize float64        `json:"average_team_size"`
+	Hires           HireCounts     `json:"hires"`
+	Tenure          []TenureBucket `json:"tenure"`
+}
+
+// HireCounts число сотрудников, принятых за последние 30, 90 и 365 дней
+type HireCounts struct {
+	Last30Days  int `json:"last_30_days"`
+	Last90Days  int `json:"last_90_days"`
+	Last365Days int `json:"last_365_days"`
+}
+
+// TenureBucket число сотрудников со стажем в интервале; без даты приёма — unknown,
+// с датой приёма в будущем — not_started
+type TenureBucket struct {
+	Bucket    string `json:"bucket"`
+	Headcount int    `json:"headcount"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// Арендатор по умолчанию создаётся миграцией, ему принадлежат данные,
+// существовавшие до включения мультиарендности
+const (
+	DefaultTenantID   = 1
+	DefaultTenantSlug = "default"
+)
+
+// Tenant отдельная организация (юридическое лицо) внутри одного развёртывания
+type Tenant struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	Slug      string    `json:"slug" gorm:"size:63;not null;uniqueIndex"`
+	Name      string    `json:"name" gorm:"size:200;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateTenantRequest struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+	// AdminSubject получает роль admin на всю организацию нового арендатора
+	AdminSubject string `json:"admin_subject"`
+}
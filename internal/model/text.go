@@ -0,0 +1,15 @@
+package model
+
+import "unicode/utf8"
+
+// TruncateRunes обрезает строку не более чем до n байт, не разрывая многобайтовые
+// символы UTF-8: Postgres отклоняет строки с некорректной кодировкой.
+func TruncateRunes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"net/netip"
+	"time"
+)
+
+// Статусы доставки вебхука
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// WebhookSubscription подписка внешней системы на события
+type WebhookSubscription struct {
+	ID         int       `json:"id" gorm:"primaryKey"`
+	TenantID   int       `json:"-" gorm:"not null;default:1;index"`
+	URL        string    `json:"url" gorm:"size:2000;not null"`
+	EventTypes []string  `json:"event_types" gorm:"serializer:json;type:jsonb;not null"`
+	Secret     string    `json:"-" gorm:"size:200;not null"`
+	Active     bool      `json:"active" gorm:"not null;default:true"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Matches сообщает, подписана ли подписка на событие
+func (s *WebhookSubscription) Matches(eventType string) bool {
+	for _, t := range s.EventTypes {
+		if t == EventAll || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicAddress сообщает, можно ли отправлять вебхук на адрес: loopback, link-local,
+// частные (RFC 1918, fc00::/7), multicast и неуказанные адреса запрещены,
+// чтобы подписка не открывала доступ к внутренней сети
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsPrivate() &&
+		!addr.IsUnspecified()
+}
+
+// WebhookSubscriptionWithSecret ответ на создание: секрет показывается только один раз
+type WebhookSubscriptionWithSecret struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery попытка доставки события подписчику (журнал доставок)
+type WebhookDelivery struct {
+	ID             int                  `json:"id" gorm:"primaryKey"`
+	TenantID       int                  `json:"-" gorm:"not null;default:1;index"`
+	SubscriptionID int                  `json:"subscription_id" gorm:"not null;index;uniqueIndex:idx_webhook_deliveries_event"`
+	Subscription   *WebhookSubscription `json:"-" gorm:"foreignKey:SubscriptionID"`
+	EventID        string               `json:"event_id" gorm:"size:64;not null;uniqueIndex:idx_webhook_deliveries_event"`
+	EventType      string               `json:"event_type" gorm:"size:100;not null"`
+	Payload        JSON                 `json:"payload" gorm:"not null"`
+	Status         string               `json:"status" gorm:"size:20;not null;index"`
+	Attempts       int                  `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  time.Time            `json:"next_attempt_at" gorm:"index"`
+	LastError      string               `json:"last_error,omitempty" gorm:"size:2000"`
+	ResponseStatus int                  `json:"response_status,omitempty"`
+	DeliveredAt    *time.Time           `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+}
+
+type CreateWebhookSubscriptionRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	// Secret опционален: если не задан, будет сгенерирован
+	Secret string `json:"secret"`
+}
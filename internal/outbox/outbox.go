@@ -0,0 +1,231 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/logger"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Sink приёмник событий outbox.
+// Доставка выполняется не менее одного раза: после сбоя событие придёт повторно,
+// поэтому приёмник должен учитывать EventID как ключ идемпотентности.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event *model.OutboxEvent) error
+}
+
+// Config параметры relay
+type Config struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+// DefaultConfig значения по умолчанию: 10 попыток с интервалами от секунды до 5 минут
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+// Backoff задержка перед следующей попыткой после attempt неудачных попыток
+func (c Config) Backoff(attempt int) time.Duration {
+	d := c.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= c.MaxBackoff {
+			return c.MaxBackoff
+		}
+	}
+	return d
+}
+
+// retry определяет судьбу события после attempts неудачных попыток:
+// время следующей попытки либо, когда попытки исчерпаны, время откладывания
+func (c Config) retry(attempts int, now time.Time) (nextAttemptAt, parkedAt *time.Time) {
+	if attempts >= c.MaxAttempts {
+		return nil, &now
+	}
+	next := now.Add(c.Backoff(attempts))
+	return &next, nil
+}
+
+// Relay публикует события outbox в приёмники в порядке фиксации транзакций
+type Relay struct {
+	repo  *repository.Repository
+	sinks []Sink
+	cfg   Config
+}
+
+func NewRelay(repo *repository.Repository, cfg Config, sinks ...Sink) *Relay {
+	return &Relay{repo: repo, sinks: sinks, cfg: cfg}
+}
+
+// Run публикует события до отмены контекста
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil {
+			logger.Error("ошибка публикации событий outbox", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending выполняет один проход и возвращает число опубликованных событий.
+// Проход идёт под advisory-блокировкой, поэтому несколько экземпляров не нарушают порядок.
+// При ошибке приёмника проход останавливается: последующие события ждут, пока
+// не будет опубликовано предыдущее, а повтор откладывается с экспоненциальной задержкой.
+// Событие, исчерпавшее MaxAttempts попыток, откладывается (parked) и больше не держит очередь.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	err := r.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := r.repo.WithTx(tx)
+
+		locked, err := txRepo.TryLockOutbox()
+		if err != nil || !locked {
+			return err
+		}
+
+		events, err := txRepo.GetUnpublishedOutboxEvents(r.cfg.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range events {
+			event := &events[i]
+			// Событие ждёт повторной попытки, а вместе с ним и все следующие
+			if event.NextAttemptAt != nil && event.NextAttemptAt.After(now) {
+				return nil
+			}
+			if err := r.publish(ctx, event); err != nil {
+				attempts := event.Attempts + 1
+				nextAttemptAt, parkedAt := r.cfg.retry(attempts, now)
+				if err := txRepo.RecordOutboxFailure(event.ID, attempts, model.TruncateRunes(err.Error(), 2000), nextAttemptAt, parkedAt); err != nil {
+					return err
+				}
+				if parkedAt == nil {
+					logger.Warn("событие outbox не опубликовано",
+						slog.String("event_id", event.EventID),
+						slog.Int("attempts", attempts),
+						slog.String("error", err.Error()))
+					return nil
+				}
+				logger.Error("событие outbox отложено после исчерпания попыток",
+					slog.String("event_id", event.EventID),
+					slog.Int("attempts", attempts),
+					slog.String("error", err.Error()))
+				continue
+			}
+			if err := txRepo.MarkOutboxEventPublished(event.ID, time.Now()); err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	return published, err
+}
+
+// publish передаёт событие всем приёмникам по очереди
+func (r *Relay) publish(ctx context.Context, event *model.OutboxEvent) error {
+	for _, sink := range r.sinks {
+		if err := sink.Publish(ctx, event); err != nil {
+			return fmt.Errorf("%s: %w", sink.Name(), err)
+		}
+	}
+	return nil
+}
+
+// record строка, которую пишут потоковые приёмники (stdout, file)
+type record struct {
+	EventID   string     `json:"event_id"`
+	Tenant    int        `json:"tenant_id"`
+	EventType string     `json:"event_type"`
+	Payload   model.JSON `json:"payload"`
+}
+
+// WriterSink пишет события построчно в формате JSON Lines
+type WriterSink struct {
+	name string
+	mu   sync.Mutex
+	w    io.Writer
+}
+
+// NewStdoutSink приёмник в стандартный вывод
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink("stdout", os.Stdout)
+}
+
+func NewWriterSink(name string, w io.Writer) *WriterSink {
+	return &WriterSink{name: name, w: w}
+}
+
+func (s *WriterSink) Name() string {
+	return s.name
+}
+
+func (s *WriterSink) Publish(_ context.Context, event *model.OutboxEvent) error {
+	line, err := json.Marshal(record{
+		EventID:   event.EventID,
+		Tenant:    event.TenantID,
+		EventType: event.EventType,
+		Payload:   event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// FileSink дописывает события в файл и сбрасывает их на диск перед подтверждением
+type FileSink struct {
+	*WriterSink
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{WriterSink: NewWriterSink("file", f), file: f}, nil
+}
+
+func (s *FileSink) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	if err := s.WriterSink.Publish(ctx, event); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
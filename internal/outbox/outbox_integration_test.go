@@ -0,0 +1,139 @@
+//go:build integration
+
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/logger"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupTestContainer создаёт контейнер с PostgreSQL для тестов
+func setupTestContainer(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB, context.Context) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	pgContainer, err := tcpostgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:15-alpine"),
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("test"),
+		tcpostgres.WithPassword("test"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		t.Fatalf("ошибка запуска контейнера: %v", err)
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("ошибка получения connection string: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("ошибка подключения к БД: %v", err)
+	}
+
+	if err := db.AutoMigrate(&model.OutboxEvent{}); err != nil {
+		t.Fatalf("ошибка миграции: %v", err)
+	}
+
+	return pgContainer, db, ctx
+}
+
+// TestRelay_CommitOrder_Integration проверяет, что событие незавершённой транзакции
+// не обгоняют события, записанные после него и уже зафиксированные
+func TestRelay_CommitOrder_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+	logger.Init("error")
+
+	repo := repository.NewRepository(db)
+	sink := &recordingSink{name: "recording"}
+	relay := NewRelay(repo, DefaultConfig(), sink)
+
+	// Транзакция записала событие, но ещё не зафиксирована
+	tx := db.Begin()
+	if err := repo.WithTx(tx).CreateOutboxEvent(testEvent("early")); err != nil {
+		t.Fatalf("ошибка записи события: %v", err)
+	}
+	if err := repo.CreateOutboxEvent(testEvent("late")); err != nil {
+		t.Fatalf("ошибка записи события: %v", err)
+	}
+
+	n, err := relay.RelayPending(ctx)
+	if err != nil || n != 0 {
+		t.Fatalf("пока транзакция открыта, публиковать нечего: опубликовано %d (%v)", n, err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		t.Fatalf("ошибка фиксации: %v", err)
+	}
+	if n, err := relay.RelayPending(ctx); err != nil || n != 2 {
+		t.Fatalf("ожидалась публикация 2 событий, получено %d (%v)", n, err)
+	}
+	if !slices.Equal(sink.got, []string{"early", "late"}) {
+		t.Errorf("ожидался порядок [early late], получено %v", sink.got)
+	}
+}
+
+// TestRelay_BackoffAndPark_Integration проверяет задержку повторов и откладывание события,
+// которое исчерпало попытки и больше не держит очередь
+func TestRelay_BackoffAndPark_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+	logger.Init("error")
+
+	repo := repository.NewRepository(db)
+	sink := &recordingSink{name: "recording", err: errors.New("unavailable"), failOn: "broken"}
+	cfg := DefaultConfig()
+	cfg.MaxAttempts = 2
+	cfg.BaseBackoff = time.Hour
+	relay := NewRelay(repo, cfg, sink)
+
+	repo.CreateOutboxEvent(testEvent("broken"))
+	repo.CreateOutboxEvent(testEvent("next"))
+
+	if n, err := relay.RelayPending(ctx); err != nil || n != 0 {
+		t.Fatalf("после сбоя следующие события должны ждать: опубликовано %d (%v)", n, err)
+	}
+	var broken model.OutboxEvent
+	db.Where("event_id = ?", "broken").First(&broken)
+	if broken.Attempts != 1 || broken.NextAttemptAt == nil || broken.NextAttemptAt.Before(time.Now().Add(50*time.Minute)) {
+		t.Fatalf("ожидалась отложенная повторная попытка, получено %+v", broken)
+	}
+
+	// До срока повтора приёмник не вызывается
+	relay.RelayPending(ctx)
+	if sink.failures != 1 {
+		t.Fatalf("повтор до истечения задержки: %d вызовов", sink.failures)
+	}
+
+	db.Model(&model.OutboxEvent{}).Where("id = ?", broken.ID).Update("next_attempt_at", time.Now().Add(-time.Second))
+	if n, err := relay.RelayPending(ctx); err != nil || n != 1 {
+		t.Fatalf("после откладывания очередь должна продолжиться: опубликовано %d (%v)", n, err)
+	}
+	if !slices.Equal(sink.got, []string{"next"}) {
+		t.Errorf("ожидалась публикация [next], получено %v", sink.got)
+	}
+
+	db.Where("event_id = ?", "broken").First(&broken)
+	if broken.ParkedAt == nil || broken.PublishedAt != nil || broken.Attempts != 2 || broken.LastError == "" {
+		t.Errorf("событие должно быть отложено, получено %+v", broken)
+	}
+}
@@ -0,0 +1,136 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+type recordingSink struct {
+	name string
+	err  error
+	// failOn событие, на котором приёмник возвращает err; пусто — любое
+	failOn   string
+	failures int
+	got      []string
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Publish(_ context.Context, event *model.OutboxEvent) error {
+	if s.err != nil && (s.failOn == "" || s.failOn == event.EventID) {
+		s.failures++
+		return s.err
+	}
+	s.got = append(s.got, event.EventID)
+	return nil
+}
+
+func testEvent(id string) *model.OutboxEvent {
+	return &model.OutboxEvent{
+		EventID:   id,
+		TenantID:  1,
+		EventType: model.EventDepartmentCreated,
+		Payload:   model.JSON(`{"id":"` + id + `"}`),
+	}
+}
+
+func TestRelay_PublishAllSinks(t *testing.T) {
+	a := &recordingSink{name: "a"}
+	b := &recordingSink{name: "b"}
+	r := NewRelay(nil, DefaultConfig(), a, b)
+
+	if err := r.publish(context.Background(), testEvent("e1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(a.got) != 1 || len(b.got) != 1 {
+		t.Errorf("expected event in both sinks, got %v and %v", a.got, b.got)
+	}
+}
+
+func TestRelay_PublishStopsOnSinkError(t *testing.T) {
+	failing := &recordingSink{name: "failing", err: errors.New("boom")}
+	after := &recordingSink{name: "after"}
+	r := NewRelay(nil, DefaultConfig(), failing, after)
+
+	err := r.publish(context.Background(), testEvent("e1"))
+	if err == nil || !strings.Contains(err.Error(), "failing") {
+		t.Fatalf("expected error naming the sink, got %v", err)
+	}
+	if len(after.got) != 0 {
+		t.Error("sinks after a failure should not receive the event")
+	}
+}
+
+// TestConfig_Retry проверяет экспоненциальную задержку и откладывание после исчерпания попыток
+func TestConfig_Retry(t *testing.T) {
+	cfg := Config{MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: 3 * time.Second}
+	now := time.Now()
+
+	for attempts, delay := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second} {
+		next, parked := cfg.retry(attempts, now)
+		if parked != nil || next == nil || !next.Equal(now.Add(delay)) {
+			t.Errorf("attempts=%d: expected retry after %v, got next=%v parked=%v", attempts, delay, next, parked)
+		}
+	}
+	if got := cfg.Backoff(5); got != cfg.MaxBackoff {
+		t.Errorf("backoff should be capped at %v, got %v", cfg.MaxBackoff, got)
+	}
+
+	next, parked := cfg.retry(3, now)
+	if next != nil || parked == nil || !parked.Equal(now) {
+		t.Errorf("expected event to be parked after max attempts, got next=%v parked=%v", next, parked)
+	}
+}
+
+func TestWriterSink_JSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink("buffer", &buf)
+
+	sink.Publish(context.Background(), testEvent("e1"))
+	sink.Publish(context.Background(), testEvent("e2"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+
+	var rec record
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("invalid json line: %v", err)
+	}
+	if rec.EventID != "e2" || rec.EventType != model.EventDepartmentCreated || string(rec.Payload) != `{"id":"e2"}` {
+		t.Errorf("unexpected record: %+v", rec)
+	}
+}
+
+func TestFileSink_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	for _, id := range []string{"e1", "e2"} {
+		sink, err := NewFileSink(path)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := sink.Publish(context.Background(), testEvent(id)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sink.Close()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 2 {
+		t.Errorf("expected 2 lines after reopening, got %d", n)
+	}
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// API Key Methods
+func (r *Repository) CreateAPIKey(key *model.APIKey) error {
+	key.TenantID = r.tenantID
+	return r.db.Create(key).Error
+}
+
+func (r *Repository) GetAPIKeyByID(id int) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.tenant().First(&key, id).Error
+	return &key, err
+}
+
+// GetAPIKeyByHash ищет ключ среди всех арендаторов: именно ключ определяет арендатора запроса
+func (r *Repository) GetAPIKeyByHash(hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.Where("key_hash = ?", hash).First(&key).Error
+	return &key, err
+}
+
+func (r *Repository) ListAPIKeys() ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := r.tenant().Order("id ASC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *Repository) UpdateAPIKey(key *model.APIKey) error {
+	key.TenantID = r.tenantID
+	return r.tenant().Save(key).Error
+}
+
+// TouchAPIKey обновляет время последнего использования без загрузки всей записи
+func (r *Repository) TouchAPIKey(id int, usedAt time.Time) error {
+	return r.db.Model(&model.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+// Approval Policy Methods
+
+// UpsertApprovalPolicy создаёт или заменяет политику согласования действия
+func (r *Repository) UpsertApprovalPolicy(policy *model.ApprovalPolicy) error {
+	policy.TenantID = r.tenantID
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "action"}},
+		DoUpdates: clause.AssignmentColumns([]string{"min_employees", "approver_role", "updated_at"}),
+	}).Create(policy).Error
+}
+
+func (r *Repository) ListApprovalPolicies() ([]model.ApprovalPolicy, error) {
+	var policies []model.ApprovalPolicy
+	err := r.tenant().Order("action ASC").Find(&policies).Error
+	return policies, err
+}
+
+// GetApprovalPolicy возвращает политику действия; gorm.ErrRecordNotFound — политики нет
+func (r *Repository) GetApprovalPolicy(action string) (*model.ApprovalPolicy, error) {
+	var policy model.ApprovalPolicy
+	err := r.tenant().Where("action = ?", action).First(&policy).Error
+	return &policy, err
+}
+
+// DeleteApprovalPolicy удаляет политику действия; false — политики не было
+func (r *Repository) DeleteApprovalPolicy(action string) (bool, error) {
+	result := r.tenant().Where("action = ?", action).Delete(&model.ApprovalPolicy{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Change Request Methods
+func (r *Repository) CreateChangeRequest(cr *model.ChangeRequest) error {
+	cr.TenantID = r.tenantID
+	return r.db.Create(cr).Error
+}
+
+func (r *Repository) GetChangeRequestByID(id int) (*model.ChangeRequest, error) {
+	var cr model.ChangeRequest
+	err := r.tenant().First(&cr, id).Error
+	return &cr, err
+}
+
+// GetChangeRequestForUpdate читает запрос с блокировкой строки до конца транзакции
+func (r *Repository) GetChangeRequestForUpdate(id int) (*model.ChangeRequest, error) {
+	var cr model.ChangeRequest
+	err := r.tenant().Clauses(clause.Locking{Strength: "UPDATE"}).First(&cr, id).Error
+	return &cr, err
+}
+
+// ListChangeRequests возвращает запросы арендатора, новые первыми; пустой status — в любом статусе
+func (r *Repository) ListChangeRequests(status string) ([]model.ChangeRequest, error) {
+	query := r.tenant()
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []model.ChangeRequest
+	err := query.Order("id DESC").Find(&requests).Error
+	return requests, err
+}
+
+// DecideChangeRequest фиксирует решение по запросу
+func (r *Repository) DecideChangeRequest(id int, status, decidedBy string, comment *string, at time.Time) error {
+	return r.tenant().Model(&model.ChangeRequest{}).Where("id = ?", id).Updates(map[string]any{
+		"status":     status,
+		"decided_by": decidedBy,
+		"comment":    comment,
+		"decided_at": at,
+	}).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// Attribute Definition Methods
+func (r *Repository) CreateAttributeDefinition(def *model.AttributeDefinition) error {
+	def.TenantID = r.tenantID
+	return r.db.Create(def).Error
+}
+
+func (r *Repository) GetAttributeDefinitionByID(id int) (*model.AttributeDefinition, error) {
+	var def model.AttributeDefinition
+	err := r.tenant().First(&def, id).Error
+	return &def, err
+}
+
+// ListAttributeDefinitions возвращает схемы атрибутов; пустая entity — для всех сущностей
+func (r *Repository) ListAttributeDefinitions(entity string) ([]model.AttributeDefinition, error) {
+	var defs []model.AttributeDefinition
+	query := r.tenant()
+	if entity != "" {
+		query = query.Where("entity = ?", entity)
+	}
+	err := query.Order("id ASC").Find(&defs).Error
+	return defs, err
+}
+
+func (r *Repository) CheckUniqueAttributeDefinition(entity, name string) (bool, error) {
+	var count int64
+	err := r.tenant().Model(&model.AttributeDefinition{}).Where("entity = ? AND name = ?", entity, name).Count(&count).Error
+	return count == 0, err
+}
+
+// DeleteAttributeDefinition удаляет схему и значения атрибута у всех сущностей организации
+func (r *Repository) DeleteAttributeDefinition(def *model.AttributeDefinition) error {
+	var table any = &model.Employee{}
+	if def.Entity == model.AttributeEntityDepartment {
+		table = &model.Department{}
+	}
+	err := r.tenant().Model(table).
+		Where("jsonb_exists(attributes, ?)", def.Name).
+		UpdateColumn("attributes", gorm.Expr("attributes - ?", def.Name)).Error
+	if err != nil {
+		return err
+	}
+	return r.tenant().Delete(&model.AttributeDefinition{}, def.ID).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// ListDepartments возвращает все подразделения арендатора
+func (r *Repository) ListDepartments() ([]model.Department, error) {
+	var depts []model.Department
+	err := r.tenant().Order("id ASC").Find(&depts).Error
+	return depts, err
+}
+
+// ListEmployees возвращает всех сотрудников арендатора
+func (r *Repository) ListEmployees() ([]model.Employee, error) {
+	var employees []model.Employee
+	err := r.tenant().Order("id ASC").Find(&employees).Error
+	return employees, err
+}
+
+// CountEmployeesByDepartment считает сотрудников в одном из статусов непосредственно в каждом подразделении;
+// подразделения без сотрудников в результат не попадают
+func (r *Repository) CountEmployeesByDepartment(statuses []string) (map[int]int, error) {
+	var rows []struct {
+		DepartmentID int
+		Count        int
+	}
+	err := r.tenant().Model(&model.Employee{}).
+		Select("department_id, COUNT(*) AS count").
+		Where("status IN ?", statuses).
+		Group("department_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int, len(rows))
+	for _, row := range rows {
+		counts[row.DepartmentID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+// Idempotency Key Methods
+
+// CreateIdempotencyKey резервирует ключ; false — ключ уже занят
+func (r *Repository) CreateIdempotencyKey(key *model.IdempotencyKey) (bool, error) {
+	key.TenantID = r.tenantID
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *Repository) GetIdempotencyKey(subject, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	err := r.tenant().Where("subject = ? AND key = ?", subject, key).First(&record).Error
+	return &record, err
+}
+
+// CompleteIdempotencyKey сохраняет ответ исходного запроса
+func (r *Repository) CompleteIdempotencyKey(id int, status int, body []byte, etag string) error {
+	return r.tenant().Model(&model.IdempotencyKey{}).Where("id = ?", id).Updates(map[string]any{
+		"response_status": status,
+		"response_body":   body,
+		"response_etag":   etag,
+	}).Error
+}
+
+func (r *Repository) DeleteIdempotencyKey(id int) error {
+	return r.tenant().Delete(&model.IdempotencyKey{}, id).Error
+}
+
+// DeleteStaleIdempotencyKey освобождает ключ, если он истёк или его резервирование брошено
+func (r *Repository) DeleteStaleIdempotencyKey(subject, key string, now time.Time, abandonedBefore time.Time) error {
+	return r.tenant().
+		Where("subject = ? AND key = ?", subject, key).
+		Where("expires_at < ? OR (response_status = 0 AND created_at < ?)", now, abandonedBefore).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+// DeleteExpiredIdempotencyKeys удаляет истёкшие ключи всех арендаторов
+func (r *Repository) DeleteExpiredIdempotencyKeys(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
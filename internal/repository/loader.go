@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Пакетная загрузка для GraphQL: один запрос на набор подразделений вместо запроса на каждое
+
+// GetDepartmentsByIDs возвращает подразделения по списку ID
+func (r *Repository) GetDepartmentsByIDs(ids []int) ([]model.Department, error) {
+	var depts []model.Department
+	if len(ids) == 0 {
+		return depts, nil
+	}
+	err := r.tenant().Where("id IN ?", ids).Order("id ASC").Find(&depts).Error
+	return depts, err
+}
+
+// GetEmployeesByIDs возвращает сотрудников по списку ID
+func (r *Repository) GetEmployeesByIDs(ids []int) ([]model.Employee, error) {
+	var employees []model.Employee
+	if len(ids) == 0 {
+		return employees, nil
+	}
+	err := r.tenant().Where("id IN ?", ids).Order("id ASC").Find(&employees).Error
+	return employees, err
+}
+
+// GetChildrenByParentIDs возвращает непосредственных потомков всех указанных подразделений в порядке сортировки
+func (r *Repository) GetChildrenByParentIDs(ids []int) ([]model.Department, error) {
+	var children []model.Department
+	if len(ids) == 0 {
+		return children, nil
+	}
+	err := r.tenant().Where("parent_id IN ?", ids).Order("sort_order ASC, id ASC").Find(&children).Error
+	return children, err
+}
+
+// GetRootDepartments возвращает подразделения верхнего уровня в порядке сортировки
+func (r *Repository) GetRootDepartments() ([]model.Department, error) {
+	var roots []model.Department
+	err := r.tenant().Where("parent_id IS NULL").Order("sort_order ASC, id ASC").Find(&roots).Error
+	return roots, err
+}
+
+// GetEmployeesByDeptIDs возвращает сотрудников всех указанных подразделений в одном из статусов;
+// пустой список статусов — в любом
+func (r *Repository) GetEmployeesByDeptIDs(ids []int, statuses []string) ([]model.Employee, error) {
+	var employees []model.Employee
+	if len(ids) == 0 {
+		return employees, nil
+	}
+	query := r.tenant().Where("department_id IN ?", ids)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("created_at ASC").Find(&employees).Error
+	return employees, err
+}
+
+// CountSubtreeEmployees считает сотрудников в одном из статусов в поддереве каждого из указанных подразделений
+func (r *Repository) CountSubtreeEmployees(ids []int, statuses []string) (map[int]int, error) {
+	counts := make(map[int]int, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		RootID int
+		Count  int
+	}
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id AS root_id, id FROM departments WHERE tenant_id = ? AND id IN ?
+			UNION ALL
+			SELECT subtree.root_id, d.id FROM departments d
+			JOIN subtree ON d.parent_id = subtree.id
+			WHERE d.tenant_id = ?
+		)
+		SELECT subtree.root_id, COUNT(e.id) AS count
+		FROM subtree
+		LEFT JOIN employees e ON e.department_id = subtree.id AND e.tenant_id = ? AND e.status IN ?
+		GROUP BY subtree.root_id`,
+		r.tenantID, ids, r.tenantID, r.tenantID, statuses).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.RootID] = row.Count
+	}
+	return counts, nil
+}
+
+// GetAncestors возвращает предков каждого из указанных подразделений, начиная с родителя
+func (r *Repository) GetAncestors(ids []int) (map[int][]model.Department, error) {
+	ancestors := make(map[int][]model.Department, len(ids))
+	if len(ids) == 0 {
+		return ancestors, nil
+	}
+
+	var rows []struct {
+		StartID int
+		model.Department
+	}
+	err := r.db.Raw(`
+		WITH RECURSIVE chain AS (
+			SELECT id AS start_id, parent_id AS ancestor_id, 1 AS depth
+			FROM departments WHERE tenant_id = ? AND id IN ? AND parent_id IS NOT NULL
+			UNION ALL
+			SELECT chain.start_id, d.parent_id, chain.depth + 1 FROM departments d
+			JOIN chain ON d.id = chain.ancestor_id
+			WHERE d.tenant_id = ? AND d.parent_id IS NOT NULL
+		)
+		SELECT chain.start_id, d.*
+		FROM chain JOIN departments d ON d.id = chain.ancestor_id
+		ORDER BY chain.start_id, chain.depth`,
+		r.tenantID, ids, r.tenantID).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		ancestors[row.StartID] = append(ancestors[row.StartID], row.Department)
+	}
+	return ancestors, nil
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/pressly/goose/v3"
+)
+
+const migrationsDir = "../../migrations"
+
+// Версия миграции, предшествующей каталогу должностей
+const versionBeforePositions = 20261018143000
+
+// TestMigrations_PositionsBackfill_Integration прогоняет миграции goose по базе с данными:
+// заполнение каталога должностей связывает сотрудников с должностями, не меняя их текст,
+// а откат и повторное применение проходят без ошибок
+func TestMigrations_PositionsBackfill_Integration(t *testing.T) {
+	pgContainer, db, ctx := startPostgres(t)
+	defer pgContainer.Terminate(ctx)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("ошибка получения sql.DB: %v", err)
+	}
+	if err := goose.UpTo(sqlDB, migrationsDir, versionBeforePositions); err != nil {
+		t.Fatalf("ошибка миграции: %v", err)
+	}
+
+	// Данные, существовавшие до появления каталога
+	var deptID int
+	if err := db.Raw("INSERT INTO departments (name) VALUES ('Engineering') RETURNING id").Scan(&deptID).Error; err != nil {
+		t.Fatalf("ошибка создания подразделения: %v", err)
+	}
+	original := map[string]string{"Ann": "Sr. Engineer", "Bob": "senior engineer", "Kim": "Senior Engineer", "Lee": "Designer"}
+	for name, position := range original {
+		err := db.Exec("INSERT INTO employees (department_id, full_name, position) VALUES (?, ?, ?)", deptID, name, position).Error
+		if err != nil {
+			t.Fatalf("ошибка создания сотрудника: %v", err)
+		}
+	}
+
+	if err := goose.Up(sqlDB, migrationsDir); err != nil {
+		t.Fatalf("ошибка миграции: %v", err)
+	}
+
+	type row struct {
+		FullName   string
+		Position   string
+		PositionID *int
+		Version    int
+	}
+	var rows []row
+	if err := db.Raw("SELECT full_name, position, position_id, version FROM employees").Scan(&rows).Error; err != nil {
+		t.Fatalf("ошибка чтения сотрудников: %v", err)
+	}
+	linked := map[string]int{}
+	for _, r := range rows {
+		if r.Position != original[r.FullName] {
+			t.Errorf("%s: должность %q не должна меняться, получено %q", r.FullName, original[r.FullName], r.Position)
+		}
+		if r.Version != 1 {
+			t.Errorf("%s: версия не должна меняться, получено %d", r.FullName, r.Version)
+		}
+		if r.PositionID == nil {
+			t.Errorf("%s: сотрудник должен быть связан с должностью", r.FullName)
+			continue
+		}
+		linked[r.FullName] = *r.PositionID
+	}
+	if linked["Ann"] != linked["Bob"] || linked["Bob"] != linked["Kim"] || linked["Lee"] == linked["Ann"] {
+		t.Errorf("написания одной должности должны ссылаться на одну запись каталога: %v", linked)
+	}
+	var positions int64
+	db.Raw("SELECT COUNT(*) FROM positions").Scan(&positions)
+	if positions != 2 {
+		t.Errorf("ожидалось 2 должности в каталоге, получено %d", positions)
+	}
+
+	// Откат возвращает схему без потери исходного текста, повторное применение проходит
+	if err := goose.DownTo(sqlDB, migrationsDir, versionBeforePositions); err != nil {
+		t.Fatalf("ошибка отката: %v", err)
+	}
+	var positionsAfterDown []string
+	db.Raw("SELECT position FROM employees ORDER BY full_name").Scan(&positionsAfterDown)
+	expected := []string{"Sr. Engineer", "senior engineer", "Senior Engineer", "Designer"}
+	if len(positionsAfterDown) != len(expected) {
+		t.Fatalf("ожидалось %d сотрудников после отката, получено %d", len(expected), len(positionsAfterDown))
+	}
+	for i, p := range positionsAfterDown {
+		if p != expected[i] {
+			t.Errorf("после отката ожидалась должность %q, получено %q", expected[i], p)
+		}
+	}
+	if err := goose.Up(sqlDB, migrationsDir); err != nil {
+		t.Fatalf("ошибка повторной миграции: %v", err)
+	}
+}
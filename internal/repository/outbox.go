@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// outboxLockKey ключ advisory-блокировки: одновременно публикует только один relay,
+// что сохраняет порядок событий
+const outboxLockKey = 0x6f7574626f78
+
+// Outbox Methods
+func (r *Repository) CreateOutboxEvent(event *model.OutboxEvent) error {
+	event.TenantID = r.tenantID
+	return r.db.Create(event).Error
+}
+
+// TryLockOutbox берёт транзакционную advisory-блокировку; вызывать внутри транзакции
+func (r *Repository) TryLockOutbox() (bool, error) {
+	var locked bool
+	err := r.db.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLockKey).Scan(&locked).Error
+	return locked, err
+}
+
+// GetUnpublishedOutboxEvents возвращает неопубликованные события всех арендаторов в порядке транзакций.
+// Идентификаторы выдаются при вставке, а не при фиксации, поэтому события берутся только
+// из транзакций старше самой старой незавершённой: позже в очереди перед ними ничего не появится.
+// Отложенные после исчерпания попыток события пропускаются.
+func (r *Repository) GetUnpublishedOutboxEvents(limit int) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := r.db.
+		Where("published_at IS NULL AND parked_at IS NULL").
+		Where("txid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint").
+		Order("txid ASC, id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// MarkOutboxEventPublished отмечает событие опубликованным и присваивает следующий номер seq.
+// Номера выдаются под блокировкой outbox, поэтому возрастают в порядке фиксации.
+func (r *Repository) MarkOutboxEventPublished(id int, publishedAt time.Time) error {
+	return r.db.Model(&model.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"published_at":    publishedAt,
+		"last_error":      "",
+		"next_attempt_at": nil,
+		"seq":             gorm.Expr("(SELECT COALESCE(MAX(seq), 0) + 1 FROM outbox)"),
+	}).Error
+}
+
+// RecordOutboxFailure сохраняет неудачную попытку: событие ждёт до nextAttemptAt
+// либо, если parkedAt задано, откладывается и больше не задерживает очередь
+func (r *Repository) RecordOutboxFailure(id int, attempts int, lastError string, nextAttemptAt, parkedAt *time.Time) error {
+	return r.db.Model(&model.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":        attempts,
+		"last_error":      lastError,
+		"next_attempt_at": nextAttemptAt,
+		"parked_at":       parkedAt,
+	}).Error
+}
+
+// ListPublishedOutboxEvents возвращает опубликованные события арендатора с seq больше after.
+// rootID ограничивает события поддеревом, eventTypes — типами.
+func (r *Repository) ListPublishedOutboxEvents(after int, rootID *int, eventTypes []string, limit int) ([]model.OutboxEvent, error) {
+	query := r.tenant().Where("seq > ?", after)
+	if rootID != nil {
+		query = query.Where("department_path @> ?::jsonb", fmt.Sprintf("[%d]", *rootID))
+	}
+	if len(eventTypes) > 0 {
+		query = query.Where("event_type IN ?", eventTypes)
+	}
+
+	var events []model.OutboxEvent
+	err := query.Order("seq ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// GetLatestOutboxSeq последний выданный номер публикации арендатора
+func (r *Repository) GetLatestOutboxSeq() (int, error) {
+	var seq int
+	err := r.tenant().Model(&model.OutboxEvent{}).Select("COALESCE(MAX(seq), 0)").Scan(&seq).Error
+	return seq, err
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// Position Methods
+func (r *Repository) CreatePosition(pos *model.Position) error {
+	pos.TenantID = r.tenantID
+	return r.db.Create(pos).Error
+}
+
+func (r *Repository) GetPositionByID(id int) (*model.Position, error) {
+	var pos model.Position
+	err := r.tenant().First(&pos, id).Error
+	return &pos, err
+}
+
+// GetPositionByKey возвращает должность с нормализованным названием key
+func (r *Repository) GetPositionByKey(key string) (*model.Position, error) {
+	var pos model.Position
+	err := r.tenant().Where("title_key = ?", key).First(&pos).Error
+	return &pos, err
+}
+
+// ListPositions возвращает каталог должностей; пустая jobFamily — все семейства
+func (r *Repository) ListPositions(jobFamily string) ([]model.Position, error) {
+	var positions []model.Position
+	query := r.tenant()
+	if jobFamily != "" {
+		query = query.Where("job_family = ?", jobFamily)
+	}
+	err := query.Order("job_family ASC NULLS LAST, grade ASC NULLS LAST, title ASC").Find(&positions).Error
+	return positions, err
+}
+
+func (r *Repository) CheckUniquePositionKey(key string, excludeID int) (bool, error) {
+	var count int64
+	err := r.tenant().Model(&model.Position{}).Where("title_key = ? AND id != ?", key, excludeID).Count(&count).Error
+	return count == 0, err
+}
+
+// UpdatePosition сохраняет должность и переносит новое название в записи сотрудников,
+// которые на неё ссылаются; версии этих сотрудников увеличиваются
+func (r *Repository) UpdatePosition(pos *model.Position) error {
+	now := time.Now()
+	err := r.tenant().Model(&model.Position{}).Where("id = ?", pos.ID).Updates(map[string]any{
+		"title":      pos.Title,
+		"title_key":  pos.TitleKey,
+		"job_family": pos.JobFamily,
+		"grade":      pos.Grade,
+		"updated_at": now,
+	}).Error
+	if err != nil {
+		return err
+	}
+	pos.UpdatedAt = now
+	return r.tenant().Model(&model.Employee{}).
+		Where("position_id = ? AND position != ?", pos.ID, pos.Title).
+		Updates(map[string]any{
+			"position":   pos.Title,
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		}).Error
+}
+
+// CountPositionEmployees считает сотрудников любого статуса, ссылающихся на должность
+func (r *Repository) CountPositionEmployees(id int) (int64, error) {
+	var count int64
+	err := r.tenant().Model(&model.Employee{}).Where("position_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *Repository) DeletePosition(id int) error {
+	return r.tenant().Delete(&model.Position{}, id).Error
+}
+
+// PositionReport считает сотрудников в одном из статусов по семействам и грейдам одним запросом
+// (GROUPING SETS). deptID ограничивает отчёт поддеревом подразделения, nil — вся организация.
+func (r *Repository) PositionReport(deptID *int, statuses []string) (*model.PositionReport, error) {
+	query := `
+		SELECT p.job_family, p.grade,
+			GROUPING(p.job_family) AS family_rolled_up, GROUPING(p.grade) AS grade_rolled_up,
+			COUNT(*) AS headcount
+		FROM employees e
+		LEFT JOIN positions p ON p.id = e.position_id
+		WHERE e.tenant_id = ? AND e.status IN ?`
+	args := []any{r.tenantID, statuses}
+	if deptID != nil {
+		query = `
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM departments WHERE tenant_id = ? AND id = ?
+			UNION ALL
+			SELECT d.id FROM departments d JOIN subtree ON d.parent_id = subtree.id
+			WHERE d.tenant_id = ?
+		)` + query + ` AND e.department_id IN (SELECT id FROM subtree)`
+		args = append([]any{r.tenantID, *deptID, r.tenantID}, args...)
+	}
+	query += `
+		GROUP BY GROUPING SETS ((p.job_family, p.grade), (p.job_family), (p.grade), ())
+		ORDER BY p.job_family ASC NULLS LAST, p.grade ASC NULLS LAST`
+
+	var rows []struct {
+		JobFamily      *string
+		Grade          *int
+		FamilyRolledUp int
+		GradeRolledUp  int
+		Headcount      int
+	}
+	if err := r.db.Raw(query, args...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	report := &model.PositionReport{
+		ByJobFamily:         []model.JobFamilyHeadcount{},
+		ByGrade:             []model.GradeHeadcount{},
+		ByJobFamilyAndGrade: []model.JobFamilyGradeHeadcount{},
+	}
+	for _, row := range rows {
+		switch {
+		case row.FamilyRolledUp == 1 && row.GradeRolledUp == 1:
+			report.Total = row.Headcount
+		case row.GradeRolledUp == 1:
+			report.ByJobFamily = append(report.ByJobFamily, model.JobFamilyHeadcount{JobFamily: row.JobFamily, Headcount: row.Headcount})
+		case row.FamilyRolledUp == 1:
+			report.ByGrade = append(report.ByGrade, model.GradeHeadcount{Grade: row.Grade, Headcount: row.Headcount})
+		default:
+			report.ByJobFamilyAndGrade = append(report.ByJobFamilyAndGrade, model.JobFamilyGradeHeadcount{
+				JobFamily: row.JobFamily, Grade: row.Grade, Headcount: row.Headcount,
+			})
+		}
+	}
+	return report, nil
+}
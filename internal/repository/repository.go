@@ -1,16 +1,25 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/SergeiKhy/org-structure-api/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrVersionConflict запись изменена другим запросом после чтения
+var ErrVersionConflict = errors.New("version conflict")
+
 type Repository struct {
-	db *gorm.DB
+	db       *gorm.DB
+	tenantID int
 }
 
+// NewRepository создаёт репозиторий, работающий с данными арендатора по умолчанию
 func NewRepository(db *gorm.DB) *Repository {
-	return &Repository{db: db}
+	return &Repository{db: db, tenantID: model.DefaultTenantID}
 }
 
 // DB возвращает базовый экземпляр gorm.DB для транзакций
@@ -20,22 +29,99 @@ func (r *Repository) DB() *gorm.DB {
 
 // WithTx создает новый экземпляр Repository с использованием транзакционной БД
 func (r *Repository) WithTx(tx *gorm.DB) *Repository {
-	return &Repository{db: tx}
+	return &Repository{db: tx, tenantID: r.tenantID}
+}
+
+// WithTenant создаёт экземпляр Repository, все запросы которого ограничены арендатором
+func (r *Repository) WithTenant(tenantID int) *Repository {
+	return &Repository{db: r.db, tenantID: tenantID}
+}
+
+// TenantID возвращает арендатора, которым ограничены запросы
+func (r *Repository) TenantID() int {
+	return r.tenantID
+}
+
+// tenant возвращает запрос, ограниченный текущим арендатором
+func (r *Repository) tenant() *gorm.DB {
+	return r.db.Where("tenant_id = ?", r.tenantID)
 }
 
 // Department Methods
+
+// CreateDepartment добавляет подразделение в конец списка потомков родителя
 func (r *Repository) CreateDepartment(dept *model.Department) error {
+	dept.TenantID = r.tenantID
+	next, err := r.NextSortOrder(dept.ParentID)
+	if err != nil {
+		return err
+	}
+	dept.SortOrder = next
 	return r.db.Create(dept).Error
 }
 
+// rootSortOrderLockKey ключ advisory-блокировки списка корневых подразделений арендатора
+// (вторая часть ключа — ID арендатора); у корня нет строки родителя, которую можно заблокировать
+const rootSortOrderLockKey = 0x736f7274
+
+// NextSortOrder возвращает позицию после последнего потомка родителя (nil — корень).
+// Вызывается в транзакции: строка родителя (для корня — advisory-блокировка арендатора) блокируется
+// до её конца, поэтому параллельные создания и переносы под тем же родителем получают разные позиции.
+func (r *Repository) NextSortOrder(parentID *int) (int, error) {
+	if err := r.LockChildrenOrder(parentID); err != nil {
+		return 0, err
+	}
+	query := r.tenant().Model(&model.Department{})
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	var next int
+	err := query.Select("COALESCE(MAX(sort_order), 0) + 1").Scan(&next).Error
+	return next, err
+}
+
 func (r *Repository) GetDepartmentByID(id int) (*model.Department, error) {
 	var dept model.Department
-	err := r.db.First(&dept, id).Error
+	err := r.tenant().First(&dept, id).Error
 	return &dept, err
 }
 
+// UpdateDepartment сохраняет подразделение, только если его версия не изменилась с момента чтения.
+// При успехе версия увеличивается, иначе возвращается ErrVersionConflict.
 func (r *Repository) UpdateDepartment(dept *model.Department) error {
-	return r.db.Save(dept).Error
+	now := time.Now()
+	result := r.tenant().Model(&model.Department{}).
+		Where("id = ? AND version = ?", dept.ID, dept.Version).
+		Updates(map[string]any{
+			"name":        dept.Name,
+			"parent_id":   dept.ParentID,
+			"code":        dept.Code,
+			"cost_center": dept.CostCenter,
+			"description": dept.Description,
+			"active":      dept.Active,
+			"attributes":  dept.Attributes,
+			"sort_order":  dept.SortOrder,
+			"version":     gorm.Expr("version + 1"),
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	dept.Version++
+	dept.UpdatedAt = now
+	return nil
+}
+
+// GetDepartmentForUpdate читает подразделение с блокировкой строки до конца транзакции
+func (r *Repository) GetDepartmentForUpdate(id int) (*model.Department, error) {
+	var dept model.Department
+	err := r.tenant().Clauses(clause.Locking{Strength: "UPDATE"}).First(&dept, id).Error
+	return &dept, err
 }
 
 func (r *Repository) DeleteDepartment(id int) error {
@@ -44,33 +130,33 @@ func (r *Repository) DeleteDepartment(id int) error {
 	if err != nil {
 		return err
 	}
-	
+
 	// Сначала удаляем сотрудников из этого подразделения
-	if err := r.db.Where("department_id = ?", id).Delete(&model.Employee{}).Error; err != nil {
+	if err := r.tenant().Where("department_id = ?", id).Delete(&model.Employee{}).Error; err != nil {
 		return err
 	}
-	
+
 	// Удаляем сотрудников из дочерних подразделений
 	if len(childrenIDs) > 0 {
-		if err := r.db.Where("department_id IN ?", childrenIDs).Delete(&model.Employee{}).Error; err != nil {
+		if err := r.tenant().Where("department_id IN ?", childrenIDs).Delete(&model.Employee{}).Error; err != nil {
 			return err
 		}
 	}
-	
+
 	// Удаляем дочерние подразделения
 	if len(childrenIDs) > 0 {
-		if err := r.db.Where("id IN ?", childrenIDs).Delete(&model.Department{}).Error; err != nil {
+		if err := r.tenant().Where("id IN ?", childrenIDs).Delete(&model.Department{}).Error; err != nil {
 			return err
 		}
 	}
-	
+
 	// Затем удаляем само подразделение
-	return r.db.Delete(&model.Department{}, id).Error
+	return r.tenant().Delete(&model.Department{}, id).Error
 }
 
 func (r *Repository) CheckUniqueName(parentID *int, name string, excludeID int) (bool, error) {
 	var count int64
-	query := r.db.Model(&model.Department{}).Where("name = ? AND id != ?", name, excludeID)
+	query := r.tenant().Model(&model.Department{}).Where("name = ? AND id != ?", name, excludeID)
 	if parentID == nil {
 		query = query.Where("parent_id IS NULL")
 	} else {
@@ -80,12 +166,26 @@ func (r *Repository) CheckUniqueName(parentID *int, name string, excludeID int)
 	return count == 0, err
 }
 
+// CheckUniqueCode проверяет, что код не занят другим подразделением организации
+func (r *Repository) CheckUniqueCode(code string, excludeID int) (bool, error) {
+	var count int64
+	err := r.tenant().Model(&model.Department{}).Where("code = ? AND id != ?", code, excludeID).Count(&count).Error
+	return count == 0, err
+}
+
+// GetDepartmentByCode возвращает подразделение по коду
+func (r *Repository) GetDepartmentByCode(code string) (*model.Department, error) {
+	var dept model.Department
+	err := r.tenant().Where("code = ?", code).First(&dept).Error
+	return &dept, err
+}
+
 func (r *Repository) GetParentChain(id int) ([]int, error) {
 	var parents []int
 	currentID := id
 	for {
 		var dept model.Department
-		if err := r.db.Select("parent_id").First(&dept, currentID).Error; err != nil {
+		if err := r.tenant().Select("parent_id").First(&dept, currentID).Error; err != nil {
 			if err == gorm.ErrRecordNotFound {
 				break
 			}
@@ -105,7 +205,7 @@ func (r *Repository) GetChildrenIDs(id int) ([]int, error) {
 	// Рекурсивный запрос для плоского списка детей
 	// Выбираем всех, у кого parent_id = id
 	var depts []model.Department
-	if err := r.db.Where("parent_id = ?", id).Find(&depts).Error; err != nil {
+	if err := r.tenant().Where("parent_id = ?", id).Find(&depts).Error; err != nil {
 		return nil, err
 	}
 	for _, d := range depts {
@@ -120,43 +220,143 @@ func (r *Repository) GetChildrenIDs(id int) ([]int, error) {
 }
 
 func (r *Repository) ReassignDepartments(oldParentID int, newParentID int) error {
-	return r.db.Model(&model.Department{}).Where("parent_id = ?", oldParentID).Update("parent_id", newParentID).Error
+	return r.tenant().Model(&model.Department{}).Where("parent_id = ?", oldParentID).Update("parent_id", newParentID).Error
 }
 
 func (r *Repository) ReassignEmployees(oldDeptID int, newDeptID int) error {
-	return r.db.Model(&model.Employee{}).Where("department_id = ?", oldDeptID).Update("department_id", newDeptID).Error
+	return r.tenant().Model(&model.Employee{}).Where("department_id = ?", oldDeptID).Update("department_id", newDeptID).Error
 }
 
 // Employee Methods
 func (r *Repository) CreateEmployee(emp *model.Employee) error {
+	emp.TenantID = r.tenantID
 	return r.db.Create(emp).Error
 }
 
+func (r *Repository) GetEmployeeByID(id int) (*model.Employee, error) {
+	var emp model.Employee
+	err := r.tenant().First(&emp, id).Error
+	return &emp, err
+}
+
+// UpdateEmployee сохраняет сотрудника, только если его версия не изменилась с момента чтения
+func (r *Repository) UpdateEmployee(emp *model.Employee) error {
+	now := time.Now()
+	result := r.tenant().Model(&model.Employee{}).
+		Where("id = ? AND version = ?", emp.ID, emp.Version).
+		Updates(map[string]any{
+			"full_name":          emp.FullName,
+			"position":           emp.Position,
+			"position_id":        emp.PositionID,
+			"hired_at":           emp.HiredAt,
+			"status":             emp.Status,
+			"terminated_at":      emp.TerminatedAt,
+			"termination_reason": emp.TerminationReason,
+			"department_id":      emp.DepartmentID,
+			"user_name":          emp.UserName,
+			"external_id":        emp.ExternalID,
+			"attributes":         emp.Attributes,
+			"version":            gorm.Expr("version + 1"),
+			"updated_at":         now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	emp.Version++
+	emp.UpdatedAt = now
+	return nil
+}
+
+// CheckUniqueUserName проверяет, что логин (без учёта регистра) не занят другим сотрудником
+func (r *Repository) CheckUniqueUserName(userName string, excludeID int) (bool, error) {
+	var count int64
+	err := r.tenant().Model(&model.Employee{}).
+		Where("LOWER(user_name) = LOWER(?) AND id != ?", userName, excludeID).
+		Count(&count).Error
+	return count == 0, err
+}
+
+// GetEmployeesByDeptIDAndStatus возвращает сотрудников подразделения в одном из статусов; пустой список — в любом
+func (r *Repository) GetEmployeesByDeptIDAndStatus(deptID int, statuses []string) ([]model.Employee, error) {
+	var employees []model.Employee
+	query := r.tenant().Where("department_id = ?", deptID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	err := query.Order("created_at ASC").Find(&employees).Error
+	return employees, err
+}
+
 func (r *Repository) GetEmployeesByDeptID(deptID int) ([]model.Employee, error) {
 	var employees []model.Employee
-	err := r.db.Where("department_id = ?", deptID).Order("created_at ASC").Find(&employees).Error
+	err := r.tenant().Where("department_id = ?", deptID).Order("created_at ASC").Find(&employees).Error
 	return employees, err
 }
 
 func (r *Repository) GetDepartmentWithChildren(id int, depth int) (*model.Department, error) {
 	var dept model.Department
-	if err := r.db.First(&dept, id).Error; err != nil {
+	if err := r.tenant().First(&dept, id).Error; err != nil {
 		return nil, err
 	}
 	return &dept, nil
 }
 
+// GetChildren возвращает непосредственных потомков подразделения в порядке сортировки
+func (r *Repository) GetChildren(id int) ([]model.Department, error) {
+	var children []model.Department
+	err := r.tenant().Where("parent_id = ?", id).Order("sort_order ASC, id ASC").Find(&children).Error
+	return children, err
+}
+
+// LockChildrenOrder блокирует список потомков родителя (nil — корень) до конца транзакции
+func (r *Repository) LockChildrenOrder(parentID *int) error {
+	if parentID == nil {
+		return r.db.Exec("SELECT pg_advisory_xact_lock(?::int, ?::int)", rootSortOrderLockKey, r.tenantID).Error
+	}
+	_, err := r.GetDepartmentForUpdate(*parentID)
+	return err
+}
+
+// GetChildrenForUpdate читает непосредственных потомков с блокировкой строк до конца транзакции
+func (r *Repository) GetChildrenForUpdate(id int) ([]model.Department, error) {
+	var children []model.Department
+	err := r.tenant().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("parent_id = ?", id).Order("id ASC").Find(&children).Error
+	return children, err
+}
+
+// SetSortOrder назначает подразделениям позиции по порядку ids (1, 2, ...);
+// версия увеличивается только у подразделений, чья позиция изменилась
+func (r *Repository) SetSortOrder(ids []int) error {
+	now := time.Now()
+	for i, id := range ids {
+		err := r.tenant().Model(&model.Department{}).Where("id = ? AND sort_order <> ?", id, i+1).
+			Updates(map[string]any{
+				"sort_order": i + 1,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteChildrenIDs удаляет подразделения по их ID в рамках транзакции
 func (r *Repository) DeleteChildrenIDs(tx *gorm.DB, ids []int) error {
 	if len(ids) == 0 {
 		return nil
 	}
-	
+
 	// Сначала удаляем сотрудников из удаляемых подразделений
-	if err := tx.Where("department_id IN ?", ids).Delete(&model.Employee{}).Error; err != nil {
+	if err := tx.Where("tenant_id = ? AND department_id IN ?", r.tenantID, ids).Delete(&model.Employee{}).Error; err != nil {
 		return err
 	}
-	
+
 	// Затем удаляем подразделения
-	return tx.Where("id IN ?", ids).Delete(&model.Department{}).Error
+	return tx.Where("tenant_id = ? AND id IN ?", r.tenantID, ids).Delete(&model.Department{}).Error
 }
@@ -19,6 +19,21 @@ import (
 func setupTestContainer(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB, context.Context) {
 	t.Helper()
 
+	pgContainer, db, ctx := startPostgres(t)
+
+	// Создаём таблицы
+	err := db.AutoMigrate(&model.Department{}, &model.Employee{})
+	if err != nil {
+		t.Fatalf("ошибка миграции: %v", err)
+	}
+
+	return pgContainer, db, ctx
+}
+
+// startPostgres запускает контейнер с пустой базой PostgreSQL
+func startPostgres(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB, context.Context) {
+	t.Helper()
+
 	ctx := context.Background()
 
 	// Запускаем PostgreSQL контейнер
@@ -48,12 +63,6 @@ func setupTestContainer(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB,
 		t.Fatalf("ошибка подключения к БД: %v", err)
 	}
 
-	// Создаём таблицы
-	err = db.AutoMigrate(&model.Department{}, &model.Employee{})
-	if err != nil {
-		t.Fatalf("ошибка миграции: %v", err)
-	}
-
 	return pgContainer, db, ctx
 }
 
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Role Binding Methods
+func (r *Repository) CreateRoleBinding(binding *model.RoleBinding) error {
+	binding.TenantID = r.tenantID
+	return r.db.Create(binding).Error
+}
+
+func (r *Repository) GetRoleBindingByID(id int) (*model.RoleBinding, error) {
+	var binding model.RoleBinding
+	err := r.tenant().First(&binding, id).Error
+	return &binding, err
+}
+
+// ListRoleBindings возвращает привязки с необязательными фильтрами по субъекту и подразделению
+func (r *Repository) ListRoleBindings(subject string, departmentID *int) ([]model.RoleBinding, error) {
+	var bindings []model.RoleBinding
+	query := r.tenant().Model(&model.RoleBinding{})
+	if subject != "" {
+		query = query.Where("subject = ?", subject)
+	}
+	if departmentID != nil {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	err := query.Order("id ASC").Find(&bindings).Error
+	return bindings, err
+}
+
+func (r *Repository) GetRoleBindingsBySubject(subject string) ([]model.RoleBinding, error) {
+	var bindings []model.RoleBinding
+	err := r.tenant().Where("subject = ?", subject).Find(&bindings).Error
+	return bindings, err
+}
+
+func (r *Repository) CheckUniqueRoleBinding(subject, role string, departmentID *int) (bool, error) {
+	var count int64
+	query := r.tenant().Model(&model.RoleBinding{}).Where("subject = ? AND role = ?", subject, role)
+	if departmentID == nil {
+		query = query.Where("department_id IS NULL")
+	} else {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	err := query.Count(&count).Error
+	return count == 0, err
+}
+
+func (r *Repository) DeleteRoleBinding(id int) error {
+	return r.tenant().Delete(&model.RoleBinding{}, id).Error
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+// Scenario Methods
+func (r *Repository) CreateScenario(sc *model.Scenario) error {
+	sc.TenantID = r.tenantID
+	return r.db.Create(sc).Error
+}
+
+func (r *Repository) GetScenarioByID(id int) (*model.Scenario, error) {
+	var sc model.Scenario
+	err := r.tenant().First(&sc, id).Error
+	return &sc, err
+}
+
+// ListScenarios возвращает сценарии арендатора; непустой createdBy оставляет только сценарии автора
+func (r *Repository) ListScenarios(createdBy string) ([]model.Scenario, error) {
+	query := r.tenant()
+	if createdBy != "" {
+		query = query.Where("created_by = ?", createdBy)
+	}
+	var scenarios []model.Scenario
+	err := query.Order("id ASC").Find(&scenarios).Error
+	return scenarios, err
+}
+
+// UpdateScenario сохраняет сценарий, если его версия не изменилась с момента чтения.
+// Обновление через структуру, чтобы JSONB-поля прошли через сериализатор.
+func (r *Repository) UpdateScenario(sc *model.Scenario) error {
+	updated := *sc
+	updated.Version = sc.Version + 1
+	updated.UpdatedAt = time.Now()
+	result := r.tenant().Model(&model.Scenario{}).
+		Where("id = ? AND version = ?", sc.ID, sc.Version).
+		Select("name", "description", "status", "operations", "department_versions", "employee_versions",
+			"applied_at", "version", "updated_at").
+		Updates(&updated)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	sc.Version = updated.Version
+	sc.UpdatedAt = updated.UpdatedAt
+	return nil
+}
+
+func (r *Repository) DeleteScenario(id int) error {
+	return r.tenant().Delete(&model.Scenario{}, id).Error
+}
+
+// GetScenarioForUpdate читает сценарий с блокировкой строки до конца транзакции
+func (r *Repository) GetScenarioForUpdate(id int) (*model.Scenario, error) {
+	var sc model.Scenario
+	err := r.tenant().Clauses(clause.Locking{Strength: "UPDATE"}).First(&sc, id).Error
+	return &sc, err
+}
+
+// LockDepartmentVersions блокирует подразделения до конца транзакции и возвращает их версии;
+// удалённых подразделений в результате нет
+func (r *Repository) LockDepartmentVersions(ids []int) (map[int]int, error) {
+	return r.lockVersions(&model.Department{}, ids)
+}
+
+// LockEmployeeVersions блокирует сотрудников до конца транзакции и возвращает их версии
+func (r *Repository) LockEmployeeVersions(ids []int) (map[int]int, error) {
+	return r.lockVersions(&model.Employee{}, ids)
+}
+
+func (r *Repository) lockVersions(table any, ids []int) (map[int]int, error) {
+	versions := make(map[int]int, len(ids))
+	if len(ids) == 0 {
+		return versions, nil
+	}
+	var rows []struct {
+		ID      int
+		Version int
+	}
+	// Строки блокируются в порядке ID, чтобы параллельные транзакции не взаимоблокировались
+	err := r.tenant().Model(table).Select("id, version").Where("id IN ?", ids).
+		Order("id ASC").Clauses(clause.Locking{Strength: "UPDATE"}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		versions[row.ID] = row.Version
+	}
+	return versions, nil
+}
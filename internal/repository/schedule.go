@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm/clause"
+)
+
+// Scheduled Change Methods
+func (r *Repository) CreateScheduledChange(change *model.ScheduledChange) error {
+	change.TenantID = r.tenantID
+	return r.db.Create(change).Error
+}
+
+func (r *Repository) GetScheduledChangeByID(id int) (*model.ScheduledChange, error) {
+	var change model.ScheduledChange
+	err := r.tenant().First(&change, id).Error
+	return &change, err
+}
+
+// ListScheduledChanges возвращает изменения арендатора по дате вступления в силу.
+// Пустой status — все статусы; deptID ограничивает изменениями, затрагивающими подразделение.
+func (r *Repository) ListScheduledChanges(status string, deptID *int) ([]model.ScheduledChange, error) {
+	query := r.tenant()
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if deptID != nil {
+		query = query.Where("department_id = ? OR parent_id = ?", *deptID, *deptID)
+	}
+
+	var changes []model.ScheduledChange
+	err := query.Order("effective_at ASC, id ASC").Find(&changes).Error
+	return changes, err
+}
+
+// GetScheduledChangeForUpdate читает изменение с блокировкой строки до конца транзакции
+func (r *Repository) GetScheduledChangeForUpdate(id int) (*model.ScheduledChange, error) {
+	var change model.ScheduledChange
+	err := r.tenant().Clauses(clause.Locking{Strength: "UPDATE"}).First(&change, id).Error
+	return &change, err
+}
+
+// ClaimDueScheduledChange блокирует самое раннее наступившее изменение любого арендатора.
+// Строки, занятые другим экземпляром планировщика, пропускаются; nil — изменений нет.
+func (r *Repository) ClaimDueScheduledChange(now time.Time) (*model.ScheduledChange, error) {
+	var changes []model.ScheduledChange
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND effective_at <= ?", model.ScheduledPending, now).
+		Order("effective_at ASC, id ASC").Limit(1).Find(&changes).Error
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	return &changes[0], nil
+}
+
+// FinishScheduledChange переводит изменение в итоговый статус
+func (r *Repository) FinishScheduledChange(id int, status string, errMsg *string, at time.Time) error {
+	return r.db.Model(&model.ScheduledChange{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       status,
+		"error":        errMsg,
+		"processed_at": at,
+	}).Error
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// SearchEmployees возвращает страницу сотрудников, удовлетворяющих условию where,
+// и общее число таких сотрудников. Пустое условие выбирает всех.
+func (r *Repository) SearchEmployees(where string, args []interface{}, offset, limit int) ([]model.Employee, int64, error) {
+	var employees []model.Employee
+	total, err := r.search(&model.Employee{}, &employees, where, args, offset, limit)
+	return employees, total, err
+}
+
+// SearchDepartments возвращает страницу подразделений, удовлетворяющих условию where,
+// и общее число таких подразделений
+func (r *Repository) SearchDepartments(where string, args []interface{}, offset, limit int) ([]model.Department, int64, error) {
+	var depts []model.Department
+	total, err := r.search(&model.Department{}, &depts, where, args, offset, limit)
+	return depts, total, err
+}
+
+func (r *Repository) search(table interface{}, dest interface{}, where string, args []interface{}, offset, limit int) (int64, error) {
+	query := func() *gorm.DB {
+		q := r.tenant().Model(table)
+		if where != "" {
+			q = q.Where(where, args...)
+		}
+		return q
+	}
+
+	var total int64
+	if err := query().Count(&total).Error; err != nil {
+		return 0, err
+	}
+	if limit == 0 || int64(offset) >= total {
+		return total, nil
+	}
+	return total, query().Order("id ASC").Offset(offset).Limit(limit).Find(dest).Error
+}
+
+// FindDepartments возвращает подразделения, удовлетворяющие фильтру, в порядке ID;
+// с фильтром по родителю или корню — в порядке сортировки потомков
+func (r *Repository) FindDepartments(filter model.DepartmentFilter) ([]model.Department, error) {
+	query := r.tenant()
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+	if filter.CostCenter != "" {
+		query = query.Where("cost_center = ?", filter.CostCenter)
+	}
+	if filter.Root {
+		query = query.Where("parent_id IS NULL")
+	} else if filter.ParentID != nil {
+		query = query.Where("parent_id = ?", *filter.ParentID)
+	}
+	if filter.Query != "" {
+		query = query.Where("name ILIKE ?", "%"+escapeLike(filter.Query)+"%")
+	}
+	if len(filter.Attributes) > 0 {
+		query = query.Where("attributes @> ?::jsonb", filter.Attributes)
+	}
+
+	// Потомки одного родителя возвращаются в порядке сортировки
+	order := "id ASC"
+	if filter.Root || filter.ParentID != nil {
+		order = "sort_order ASC, id ASC"
+	}
+
+	var depts []model.Department
+	err := query.Order(order).Offset(filter.Offset).Limit(filter.Limit).Find(&depts).Error
+	return depts, err
+}
+
+// FindEmployees возвращает сотрудников, удовлетворяющих фильтру, в порядке ID
+func (r *Repository) FindEmployees(filter model.EmployeeFilter) ([]model.Employee, error) {
+	query := r.tenant()
+	if filter.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filter.DepartmentID)
+	}
+	if filter.Query != "" {
+		query = query.Where("full_name ILIKE ?", "%"+escapeLike(filter.Query)+"%")
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if len(filter.Attributes) > 0 {
+		query = query.Where("attributes @> ?::jsonb", filter.Attributes)
+	}
+
+	var employees []model.Employee
+	err := query.Order("id ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&employees).Error
+	return employees, err
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
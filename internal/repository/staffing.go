@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HeadcountBudget Methods
+
+// UpsertBudget создаёт или заменяет бюджет подразделения на период
+func (r *Repository) UpsertBudget(budget *model.HeadcountBudget) error {
+	budget.TenantID = r.tenantID
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "department_id"}, {Name: "period"}},
+		DoUpdates: clause.AssignmentColumns([]string{"approved_headcount", "updated_at"}),
+	}).Create(budget).Error
+}
+
+// ListBudgets возвращает бюджеты подразделения по всем периодам
+func (r *Repository) ListBudgets(deptID int) ([]model.HeadcountBudget, error) {
+	var budgets []model.HeadcountBudget
+	err := r.tenant().Where("department_id = ?", deptID).Order("period ASC").Find(&budgets).Error
+	return budgets, err
+}
+
+// DeleteBudget удаляет бюджет на период; false — бюджета не было
+func (r *Repository) DeleteBudget(deptID int, period string) (bool, error) {
+	result := r.tenant().Where("department_id = ? AND period = ?", deptID, period).Delete(&model.HeadcountBudget{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Requisition Methods
+func (r *Repository) CreateRequisition(req *model.Requisition) error {
+	req.TenantID = r.tenantID
+	return r.db.Create(req).Error
+}
+
+func (r *Repository) GetRequisitionByID(id int) (*model.Requisition, error) {
+	var req model.Requisition
+	err := r.tenant().First(&req, id).Error
+	return &req, err
+}
+
+// ListRequisitions возвращает вакансии подразделения; пустой status — в любом статусе
+func (r *Repository) ListRequisitions(deptID int, status string) ([]model.Requisition, error) {
+	var reqs []model.Requisition
+	query := r.tenant().Where("department_id = ?", deptID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("target_start_date ASC, id ASC").Find(&reqs).Error
+	return reqs, err
+}
+
+// UpdateRequisition сохраняет вакансию, если её версия не изменилась с момента чтения
+func (r *Repository) UpdateRequisition(req *model.Requisition) error {
+	now := time.Now()
+	result := r.tenant().Model(&model.Requisition{}).
+		Where("id = ? AND version = ?", req.ID, req.Version).
+		Updates(map[string]any{
+			"position_id":       req.PositionID,
+			"target_start_date": req.TargetStartDate,
+			"status":            req.Status,
+			"note":              req.Note,
+			"version":           gorm.Expr("version + 1"),
+			"updated_at":        now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	req.Version++
+	req.UpdatedAt = now
+	return nil
+}
+
+// CountPositionRequisitions считает вакансии любого статуса, ссылающиеся на должность
+func (r *Repository) CountPositionRequisitions(id int) (int64, error) {
+	var count int64
+	err := r.tenant().Model(&model.Requisition{}).Where("position_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// Staffing возвращает бюджет на период, число сотрудников в одном из статусов и открытых вакансий
+// для каждого подразделения поддерева deptID одним запросом; корень идёт первым
+func (r *Repository) Staffing(deptID int, period string, statuses []string) ([]model.DepartmentStaffing, error) {
+	var rows []model.DepartmentStaffing
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, name, 0 AS depth FROM departments WHERE tenant_id = ? AND id = ?
+			UNION ALL
+			SELECT d.id, d.name, subtree.depth + 1 FROM departments d
+			JOIN subtree ON d.parent_id = subtree.id
+			WHERE d.tenant_id = ?
+		)
+		SELECT subtree.id AS department_id, subtree.name, b.approved_headcount AS budget,
+			(SELECT COUNT(*) FROM employees e
+				WHERE e.tenant_id = ? AND e.department_id = subtree.id AND e.status IN ?) AS actual,
+			(SELECT COUNT(*) FROM requisitions q
+				WHERE q.tenant_id = ? AND q.department_id = subtree.id AND q.status = ?) AS open
+		FROM subtree
+		LEFT JOIN headcount_budgets b ON b.tenant_id = ? AND b.department_id = subtree.id AND b.period = ?
+		ORDER BY subtree.depth, subtree.id`,
+		r.tenantID, deptID, r.tenantID,
+		r.tenantID, statuses,
+		r.tenantID, model.RequisitionOpen,
+		r.tenantID, period).Scan(&rows).Error
+	return rows, err
+}
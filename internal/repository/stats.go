@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// DepartmentStats считает показатели поддерева deptID одним запросом по сотрудникам в одном из статусов.
+// Найм и стаж отсчитываются от now; сотрудники с будущей датой приёма попадают в not_started.
+func (r *Repository) DepartmentStats(deptID int, statuses []string, now time.Time) (*model.DepartmentStats, error) {
+	var row struct {
+		DirectHeadcount       int
+		TotalHeadcount        int
+		DescendantDepartments int
+		MaxDepth              int
+		AverageTeamSize       float64
+		Hired30               int
+		Hired90               int
+		Hired365              int
+		TenureUnderOne        int
+		TenureOneToThree      int
+		TenureThreeToFive     int
+		TenureFivePlus        int
+		TenureUnknown         int
+		TenureNotStarted      int
+	}
+	today := now.UTC().Truncate(24 * time.Hour)
+	err := r.db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id, 0 AS depth FROM departments WHERE tenant_id = ? AND id = ?
+			UNION ALL
+			SELECT d.id, subtree.depth + 1 FROM departments d
+			JOIN subtree ON d.parent_id = subtree.id
+			WHERE d.tenant_id = ?
+		),
+		staff AS (
+			SELECT e.department_id, e.hired_at FROM employees e
+			WHERE e.tenant_id = ? AND e.status IN ? AND e.department_id IN (SELECT id FROM subtree)
+		),
+		tree AS (
+			SELECT COUNT(*) AS departments, MAX(depth) AS max_depth FROM subtree
+		)
+		SELECT
+			COUNT(staff.department_id) FILTER (WHERE staff.department_id = ?) AS direct_headcount,
+			COUNT(staff.department_id) AS total_headcount,
+			tree.departments - 1 AS descendant_departments,
+			tree.max_depth,
+			ROUND(COUNT(staff.department_id)::numeric / tree.departments, 2) AS average_team_size,
+			COUNT(*) FILTER (WHERE hired_at > ? AND hired_at <= ?) AS hired30,
+			COUNT(*) FILTER (WHERE hired_at > ? AND hired_at <= ?) AS hired90,
+			COUNT(*) FILTER (WHERE hired_at > ? AND hired_at <= ?) AS hired365,
+			COUNT(*) FILTER (WHERE hired_at > ? AND hired_at <= ?) AS tenure_under_one,
+			COUNT(*) FILTER (WHERE hired_at <= ? AND hired_at > ?) AS tenure_one_to_three,
+			COUNT(*) FILTER (WHERE hired_at <= ? AND hired_at > ?) AS tenure_three_to_five,
+			COUNT(*) FILTER (WHERE hired_at <= ?) AS tenure_five_plus,
+			COUNT(staff.department_id) FILTER (WHERE hired_at IS NULL) AS tenure_unknown,
+			COUNT(*) FILTER (WHERE hired_at > ?) AS tenure_not_started
+		FROM tree LEFT JOIN staff ON true
+		GROUP BY tree.departments, tree.max_depth`,
+		r.tenantID, deptID, r.tenantID,
+		r.tenantID, statuses,
+		deptID,
+		today.AddDate(0, 0, -30), today,
+		today.AddDate(0, 0, -90), today,
+		today.AddDate(0, 0, -365), today,
+		today.AddDate(-1, 0, 0), today,
+		today.AddDate(-1, 0, 0), today.AddDate(-3, 0, 0),
+		today.AddDate(-3, 0, 0), today.AddDate(-5, 0, 0),
+		today.AddDate(-5, 0, 0),
+		today).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.DepartmentStats{
+		DepartmentID:          deptID,
+		DirectHeadcount:       row.DirectHeadcount,
+		TotalHeadcount:        row.TotalHeadcount,
+		DescendantDepartments: row.DescendantDepartments,
+		MaxDepth:              row.MaxDepth,
+		AverageTeamSize:       row.AverageTeamSize,
+		Hires: model.HireCounts{
+			Last30Days:  row.Hired30,
+			Last90Days:  row.Hired90,
+			Last365Days: row.Hired365,
+		},
+		Tenure: []model.TenureBucket{
+			{Bucket: model.TenureUnderOneYear, Headcount: row.TenureUnderOne},
+			{Bucket: model.TenureOneToThreeYears, Headcount: row.TenureOneToThree},
+			{Bucket: model.TenureThreeToFiveYears, Headcount: row.TenureThreeToFive},
+			{Bucket: model.TenureFiveYearsPlus, Headcount: row.TenureFivePlus},
+			{Bucket: model.TenureUnknown, Headcount: row.TenureUnknown},
+			{Bucket: model.TenureNotStarted, Headcount: row.TenureNotStarted},
+		},
+	}, nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Tenant Methods
+// Арендаторы — глобальный справочник, запросы к нему не ограничиваются tenant_id
+func (r *Repository) CreateTenant(tenant *model.Tenant) error {
+	return r.db.Create(tenant).Error
+}
+
+func (r *Repository) GetTenantBySlug(slug string) (*model.Tenant, error) {
+	var tenant model.Tenant
+	err := r.db.Where("slug = ?", slug).First(&tenant).Error
+	return &tenant, err
+}
+
+func (r *Repository) ListTenants() ([]model.Tenant, error) {
+	var tenants []model.Tenant
+	err := r.db.Order("id ASC").Find(&tenants).Error
+	return tenants, err
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Webhook Subscription Methods
+func (r *Repository) CreateWebhookSubscription(sub *model.WebhookSubscription) error {
+	sub.TenantID = r.tenantID
+	return r.db.Create(sub).Error
+}
+
+func (r *Repository) GetWebhookSubscriptionByID(id int) (*model.WebhookSubscription, error) {
+	var sub model.WebhookSubscription
+	err := r.tenant().First(&sub, id).Error
+	return &sub, err
+}
+
+func (r *Repository) ListWebhookSubscriptions() ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.tenant().Order("id ASC").Find(&subs).Error
+	return subs, err
+}
+
+func (r *Repository) GetActiveWebhookSubscriptions() ([]model.WebhookSubscription, error) {
+	var subs []model.WebhookSubscription
+	err := r.tenant().Where("active = ?", true).Find(&subs).Error
+	return subs, err
+}
+
+func (r *Repository) DeleteWebhookSubscription(id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ? AND subscription_id = ?", r.tenantID, id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("tenant_id = ?", r.tenantID).Delete(&model.WebhookSubscription{}, id).Error
+	})
+}
+
+// Webhook Delivery Methods
+
+// CreateWebhookDeliveries создаёт доставки; уже существующие (та же подписка и событие) пропускаются
+func (r *Repository) CreateWebhookDeliveries(deliveries []model.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	for i := range deliveries {
+		deliveries[i].TenantID = r.tenantID
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+// ListWebhookDeliveries журнал доставок подписки, новые первыми
+func (r *Repository) ListWebhookDeliveries(subscriptionID int, status string, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	query := r.tenant().Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDueWebhookDeliveries выбирает доставки, срок которых наступил, среди всех арендаторов
+// и откладывает их на lease, чтобы параллельные диспетчеры не отправили их повторно
+func (r *Repository) ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.DeliveryPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]int, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+		}
+		return tx.Model(&model.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return deliveries, err
+	}
+
+	// Подписки нужны для URL и секрета
+	subIDs := make([]int, len(deliveries))
+	for i := range deliveries {
+		subIDs[i] = deliveries[i].SubscriptionID
+	}
+	var subs []model.WebhookSubscription
+	if err := r.db.Where("id IN ?", subIDs).Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]*model.WebhookSubscription, len(subs))
+	for i := range subs {
+		byID[subs[i].ID] = &subs[i]
+	}
+	for i := range deliveries {
+		deliveries[i].Subscription = byID[deliveries[i].SubscriptionID]
+	}
+	return deliveries, nil
+}
+
+// UpdateWebhookDelivery сохраняет результат попытки доставки (используется диспетчером)
+func (r *Repository) UpdateWebhookDelivery(d *model.WebhookDelivery) error {
+	return r.db.Model(&model.WebhookDelivery{}).Where("id = ?", d.ID).Updates(map[string]any{
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"next_attempt_at": d.NextAttemptAt,
+		"last_error":      d.LastError,
+		"response_status": d.ResponseStatus,
+		"delivered_at":    d.DeliveredAt,
+	}).Error
+}
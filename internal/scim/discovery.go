@@ -0,0 +1,208 @@
+package scim
+
+// Документы обнаружения возможностей (RFC 7643, разделы 5–7)
+
+type supported struct {
+	Supported bool `json:"supported"`
+}
+
+type bulk struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type filter struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type authenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary,omitempty"`
+}
+
+// ServiceProviderConfig возможности сервера
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	DocumentationURI      string                 `json:"documentationUri,omitempty"`
+	Patch                 supported              `json:"patch"`
+	Bulk                  bulk                   `json:"bulk"`
+	Filter                filter                 `json:"filter"`
+	ChangePassword        supported              `json:"changePassword"`
+	Sort                  supported              `json:"sort"`
+	ETag                  supported              `json:"etag"`
+	AuthenticationSchemes []authenticationScheme `json:"authenticationSchemes"`
+	Meta                  *Meta                  `json:"meta,omitempty"`
+}
+
+// NewServiceProviderConfig описывает возможности сервера; maxResults — наибольший count
+func NewServiceProviderConfig(maxResults int, baseURL string) ServiceProviderConfig {
+	return ServiceProviderConfig{
+		Schemas:        []string{SchemaServiceProviderConfig},
+		Patch:          supported{Supported: true},
+		Filter:         filter{Supported: true, MaxResults: maxResults},
+		ChangePassword: supported{},
+		Sort:           supported{},
+		ETag:           supported{},
+		AuthenticationSchemes: []authenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer Token",
+			Description: "API key or JWT in the Authorization header",
+			Primary:     true,
+		}},
+		Meta: &Meta{ResourceType: "ServiceProviderConfig", Location: baseURL + "/ServiceProviderConfig"},
+	}
+}
+
+type schemaExtension struct {
+	Schema   string `json:"schema"`
+	Required bool   `json:"required"`
+}
+
+// ResourceType описание типа ресурса
+type ResourceType struct {
+	Schemas          []string          `json:"schemas"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Endpoint         string            `json:"endpoint"`
+	Description      string            `json:"description"`
+	Schema           string            `json:"schema"`
+	SchemaExtensions []schemaExtension `json:"schemaExtensions,omitempty"`
+	Meta             *Meta             `json:"meta,omitempty"`
+}
+
+// NewResourceTypes типы ресурсов User и Group
+func NewResourceTypes(baseURL string) []ResourceType {
+	return []ResourceType{
+		{
+			Schemas:          []string{SchemaResourceType},
+			ID:               ResourceUser,
+			Name:             ResourceUser,
+			Endpoint:         "/Users",
+			Description:      "Employee",
+			Schema:           SchemaUser,
+			SchemaExtensions: []schemaExtension{{Schema: SchemaEmployee, Required: true}},
+			Meta:             &Meta{ResourceType: "ResourceType", Location: baseURL + "/ResourceTypes/" + ResourceUser},
+		},
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceGroup,
+			Name:        ResourceGroup,
+			Endpoint:    "/Groups",
+			Description: "Department",
+			Schema:      SchemaGroup,
+			Meta:        &Meta{ResourceType: "ResourceType", Location: baseURL + "/ResourceTypes/" + ResourceGroup},
+		},
+	}
+}
+
+// SchemaAttribute описание атрибута схемы
+type SchemaAttribute struct {
+	Name           string            `json:"name"`
+	Type           string            `json:"type"`
+	MultiValued    bool              `json:"multiValued"`
+	Description    string            `json:"description,omitempty"`
+	Required       bool              `json:"required"`
+	CaseExact      bool              `json:"caseExact"`
+	Mutability     string            `json:"mutability"`
+	Returned       string            `json:"returned"`
+	Uniqueness     string            `json:"uniqueness"`
+	ReferenceTypes []string          `json:"referenceTypes,omitempty"`
+	SubAttributes  []SchemaAttribute `json:"subAttributes,omitempty"`
+}
+
+// Schema описание схемы ресурса
+type Schema struct {
+	Schemas     []string          `json:"schemas"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Attributes  []SchemaAttribute `json:"attributes"`
+	Meta        *Meta             `json:"meta,omitempty"`
+}
+
+func attribute(name, typ, mutability string, required bool) SchemaAttribute {
+	return SchemaAttribute{Name: name, Type: typ, Required: required, Mutability: mutability, Returned: "default", Uniqueness: "none"}
+}
+
+// NewSchemas схемы User, Group и расширения Employee
+func NewSchemas(baseURL string) []Schema {
+	userName := attribute("userName", "string", "readWrite", true)
+	userName.Uniqueness = "server"
+	userName.Description = "Login; employee-<id> when not set"
+	externalID := attribute("externalId", "string", "readWrite", false)
+	externalID.CaseExact = true
+	groups := attribute("groups", "complex", "readOnly", false)
+	groups.MultiValued = true
+	groups.Description = "Direct department and its ancestors (indirect)"
+	groups.SubAttributes = []SchemaAttribute{
+		attribute("value", "string", "readOnly", false),
+		attribute("display", "string", "readOnly", false),
+		attribute("type", "string", "readOnly", false),
+	}
+	name := attribute("name", "complex", "readWrite", false)
+	name.SubAttributes = []SchemaAttribute{
+		attribute("formatted", "string", "readWrite", false),
+		attribute("givenName", "string", "writeOnly", false),
+		attribute("familyName", "string", "writeOnly", false),
+	}
+	active := attribute("active", "boolean", "readWrite", false)
+	active.Description = "True for active employees and employees on leave"
+
+	members := attribute("members", "complex", "readWrite", false)
+	members.MultiValued = true
+	members.Description = "Employees and child departments"
+	memberType := attribute("type", "string", "immutable", false)
+	memberType.Description = "User or Group"
+	members.SubAttributes = []SchemaAttribute{
+		attribute("value", "string", "immutable", true),
+		attribute("display", "string", "readOnly", false),
+		memberType,
+	}
+
+	departmentID := attribute("departmentId", "string", "readWrite", true)
+	departmentID.Description = "Department (Group) id"
+	hiredAt := attribute("hiredAt", "string", "readWrite", false)
+	hiredAt.Description = "Hire date, YYYY-MM-DD"
+
+	return []Schema{
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaUser,
+			Name:        ResourceUser,
+			Description: "Employee",
+			Attributes: []SchemaAttribute{
+				userName,
+				name,
+				attribute("displayName", "string", "readWrite", true),
+				attribute("title", "string", "readWrite", true),
+				active,
+				externalID,
+				groups,
+			},
+			Meta: &Meta{ResourceType: "Schema", Location: baseURL + "/Schemas/" + SchemaUser},
+		},
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaGroup,
+			Name:        ResourceGroup,
+			Description: "Department",
+			Attributes: []SchemaAttribute{
+				attribute("displayName", "string", "readWrite", true),
+				members,
+			},
+			Meta: &Meta{ResourceType: "Schema", Location: baseURL + "/Schemas/" + SchemaGroup},
+		},
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaEmployee,
+			Name:        "Employee",
+			Description: "Employee extension",
+			Attributes:  []SchemaAttribute{departmentID, hiredAt},
+			Meta:        &Meta{ResourceType: "Schema", Location: baseURL + "/Schemas/" + SchemaEmployee},
+		},
+	}
+}
@@ -0,0 +1,436 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidFilter некорректный или неподдерживаемый фильтр
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// AttrType тип атрибута, по которому фильтруется список
+type AttrType int
+
+const (
+	AttrString AttrType = iota
+	AttrInteger
+	AttrBoolean
+	AttrDateTime
+)
+
+// Attribute атрибут ресурса и выражение SQL, которому он соответствует
+type Attribute struct {
+	Column    string
+	Type      AttrType
+	CaseExact bool
+}
+
+// Attributes поддерживаемые в фильтре атрибуты; ключ — путь атрибута в нижнем регистре
+type Attributes map[string]Attribute
+
+// Атрибуты фильтра пользователей и групп
+var (
+	UserAttributes = Attributes{
+		"id":                {Column: "id", Type: AttrInteger},
+		"username":          {Column: "COALESCE(user_name, 'employee-' || id)", Type: AttrString},
+		"externalid":        {Column: "external_id", Type: AttrString, CaseExact: true},
+		"displayname":       {Column: "full_name", Type: AttrString},
+		"name.formatted":    {Column: "full_name", Type: AttrString},
+		"title":             {Column: "position", Type: AttrString},
+		"active":            {Column: "(status IN ('active', 'on_leave'))", Type: AttrBoolean},
+		"meta.created":      {Column: "created_at", Type: AttrDateTime},
+		"meta.lastmodified": {Column: "updated_at", Type: AttrDateTime},
+		strings.ToLower(SchemaEmployee) + ":departmentid": {Column: "department_id", Type: AttrInteger},
+	}
+	GroupAttributes = Attributes{
+		"id":                {Column: "id", Type: AttrInteger},
+		"displayname":       {Column: "name", Type: AttrString},
+		"meta.created":      {Column: "created_at", Type: AttrDateTime},
+		"meta.lastmodified": {Column: "updated_at", Type: AttrDateTime},
+	}
+)
+
+// Where разбирает фильтр (RFC 7644, раздел 3.4.2.2) и переводит его в условие SQL
+// с параметрами. Пустой фильтр даёт пустое условие.
+func Where(filter string, attrs Attributes) (string, []interface{}, error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", nil, nil
+	}
+	expr, err := parseFilter(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	return expr.sql(attrs)
+}
+
+type expr interface {
+	sql(attrs Attributes) (string, []interface{}, error)
+}
+
+type logical struct {
+	op          string
+	left, right expr
+}
+
+type negation struct {
+	inner expr
+}
+
+type comparison struct {
+	attr  string
+	op    string
+	value interface{}
+}
+
+func (l logical) sql(attrs Attributes) (string, []interface{}, error) {
+	left, leftArgs, err := l.left.sql(attrs)
+	if err != nil {
+		return "", nil, err
+	}
+	right, rightArgs, err := l.right.sql(attrs)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + left + " " + l.op + " " + right + ")", append(leftArgs, rightArgs...), nil
+}
+
+func (n negation) sql(attrs Attributes) (string, []interface{}, error) {
+	inner, args, err := n.inner.sql(attrs)
+	if err != nil {
+		return "", nil, err
+	}
+	return "NOT " + inner, args, nil
+}
+
+var sqlOperators = map[string]string{"eq": "=", "ne": "<>", "gt": ">", "ge": ">=", "lt": "<", "le": "<="}
+
+// sql переводит сравнение в условие, которое никогда не равно NULL,
+// чтобы not() и отсутствующие значения работали как в SCIM
+func (c comparison) sql(attrs Attributes) (string, []interface{}, error) {
+	attr, ok := attrs[attributeKey(c.attr)]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unsupported attribute %q", ErrInvalidFilter, c.attr)
+	}
+	col := attr.Column
+
+	if c.op == "pr" {
+		switch attr.Type {
+		case AttrString:
+			return "(" + col + " IS NOT NULL AND " + col + " <> '')", nil, nil
+		default:
+			return "(" + col + " IS NOT NULL)", nil, nil
+		}
+	}
+	if c.value == nil {
+		switch c.op {
+		case "eq":
+			return "(" + col + " IS NULL)", nil, nil
+		case "ne":
+			return "(" + col + " IS NOT NULL)", nil, nil
+		}
+		return "", nil, fmt.Errorf("%w: null is only comparable with eq and ne", ErrInvalidFilter)
+	}
+
+	switch attr.Type {
+	case AttrString:
+		s, ok := c.value.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: %s expects a string", ErrInvalidFilter, c.attr)
+		}
+		return stringComparison(col, c.op, s, attr.CaseExact)
+	case AttrInteger:
+		return integerComparison(col, c.op, c.value, c.attr)
+	case AttrBoolean:
+		b, ok := c.value.(bool)
+		if !ok || (c.op != "eq" && c.op != "ne") {
+			return "", nil, fmt.Errorf("%w: %s expects a boolean compared with eq or ne", ErrInvalidFilter, c.attr)
+		}
+		return "COALESCE(" + col + " " + sqlOperators[c.op] + " ?, FALSE)", []interface{}{b}, nil
+	case AttrDateTime:
+		s, ok := c.value.(string)
+		op, known := sqlOperators[c.op]
+		if !ok || !known {
+			return "", nil, fmt.Errorf("%w: %s expects a date-time compared with eq, ne, gt, ge, lt or le", ErrInvalidFilter, c.attr)
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s expects an RFC 3339 date-time", ErrInvalidFilter, c.attr)
+		}
+		return "COALESCE(" + col + " " + op + " ?, FALSE)", []interface{}{t}, nil
+	}
+	return "", nil, fmt.Errorf("%w: unsupported attribute %q", ErrInvalidFilter, c.attr)
+}
+
+func stringComparison(col, op, value string, caseExact bool) (string, []interface{}, error) {
+	like := "ILIKE"
+	if caseExact {
+		like = "LIKE"
+	}
+	switch op {
+	case "co":
+		return "COALESCE(" + col + " " + like + " ?, FALSE)", []interface{}{"%" + escapeLike(value) + "%"}, nil
+	case "sw":
+		return "COALESCE(" + col + " " + like + " ?, FALSE)", []interface{}{escapeLike(value) + "%"}, nil
+	case "ew":
+		return "COALESCE(" + col + " " + like + " ?, FALSE)", []interface{}{"%" + escapeLike(value)}, nil
+	}
+	if caseExact {
+		return "COALESCE(" + col + " " + sqlOperators[op] + " ?, FALSE)", []interface{}{value}, nil
+	}
+	return "COALESCE(LOWER(" + col + ") " + sqlOperators[op] + " LOWER(?), FALSE)", []interface{}{value}, nil
+}
+
+func integerComparison(col, op string, value interface{}, name string) (string, []interface{}, error) {
+	sqlOp, known := sqlOperators[op]
+	if !known {
+		return "", nil, fmt.Errorf("%w: %s does not support %s", ErrInvalidFilter, name, op)
+	}
+	var n int
+	switch v := value.(type) {
+	case json.Number:
+		i, err := strconv.Atoi(v.String())
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s expects an integer", ErrInvalidFilter, name)
+		}
+		n = i
+	case string:
+		// идентификаторы в SCIM — строки; нечисловой идентификатор не совпадает ни с одним ресурсом
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			switch op {
+			case "eq":
+				return "FALSE", nil, nil
+			case "ne":
+				return "TRUE", nil, nil
+			}
+			return "", nil, fmt.Errorf("%w: %s expects an integer", ErrInvalidFilter, name)
+		}
+		n = i
+	default:
+		return "", nil, fmt.Errorf("%w: %s expects an integer", ErrInvalidFilter, name)
+	}
+	return "COALESCE(" + col + " " + sqlOp + " ?, FALSE)", []interface{}{n}, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// attributeKey приводит путь атрибута к ключу Attributes: без префикса основной схемы, в нижнем регистре
+func attributeKey(path string) string {
+	key := strings.ToLower(path)
+	for _, schema := range []string{SchemaUser, SchemaGroup} {
+		prefix := strings.ToLower(schema) + ":"
+		if strings.HasPrefix(key, prefix) {
+			return strings.TrimPrefix(key, prefix)
+		}
+	}
+	return key
+}
+
+// Разбор фильтра
+
+type token struct {
+	text   string
+	quoted bool
+}
+
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{text: string(c)})
+			i++
+		case c == '"':
+			end := i + 1
+			for ; end < len(s) && s[end] != '"'; end++ {
+				if s[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(s) {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidFilter)
+			}
+			var text string
+			if err := json.Unmarshal([]byte(s[i:end+1]), &text); err != nil {
+				return nil, fmt.Errorf("%w: invalid string %s", ErrInvalidFilter, s[i:end+1])
+			}
+			tokens = append(tokens, token{text: text, quoted: true})
+			i = end + 1
+		case c == '[' || c == ']':
+			return nil, fmt.Errorf("%w: value filters are not supported", ErrInvalidFilter)
+		default:
+			end := strings.IndexFunc(s[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || r == '(' || r == ')' || r == '"' || r == '[' || r == ']'
+			})
+			if end < 0 {
+				end = len(s) - i
+			}
+			tokens = append(tokens, token{text: s[i : i+end]})
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func parseFilter(s string) (expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidFilter, p.tokens[p.pos].text)
+	}
+	return e, nil
+}
+
+// keyword проверяет, что следующий токен — ключевое слово kw, и пропускает его
+func (p *parser) keyword(kw string) bool {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted && strings.EqualFold(p.tokens[p.pos].text, kw) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) next() (token, error) {
+	if p.pos >= len(p.tokens) {
+		return token{}, fmt.Errorf("%w: unexpected end of filter", ErrInvalidFilter)
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	return t, nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "OR", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{op: "AND", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (expr, error) {
+	if p.keyword("not") {
+		if !p.keyword("(") {
+			return nil, fmt.Errorf("%w: expected ( after not", ErrInvalidFilter)
+		}
+		inner, err := p.parseGroup()
+		if err != nil {
+			return nil, err
+		}
+		return negation{inner: inner}, nil
+	}
+	if p.keyword("(") {
+		return p.parseGroup()
+	}
+	return p.parseComparison()
+}
+
+// parseGroup разбирает выражение в скобках после открывающей скобки
+func (p *parser) parseGroup() (expr, error) {
+	inner, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.keyword(")") {
+		return nil, fmt.Errorf("%w: expected )", ErrInvalidFilter)
+	}
+	return inner, nil
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	attr, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	if attr.quoted || attr.text == "(" || attr.text == ")" {
+		return nil, fmt.Errorf("%w: expected attribute, got %q", ErrInvalidFilter, attr.text)
+	}
+	opToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	op := strings.ToLower(opToken.text)
+	if opToken.quoted {
+		return nil, fmt.Errorf("%w: expected operator, got %q", ErrInvalidFilter, opToken.text)
+	}
+	if op == "pr" {
+		return comparison{attr: attr.text, op: op}, nil
+	}
+	switch op {
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, opToken.text)
+	}
+
+	valueToken, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	value, err := literal(valueToken)
+	if err != nil {
+		return nil, err
+	}
+	return comparison{attr: attr.text, op: op, value: value}, nil
+}
+
+// literal значение сравнения: строка, число, true, false или null
+func literal(t token) (interface{}, error) {
+	if t.quoted {
+		return t.text, nil
+	}
+	switch strings.ToLower(t.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if _, err := strconv.ParseFloat(t.text, 64); err == nil {
+		return json.Number(t.text), nil
+	}
+	return nil, fmt.Errorf("%w: invalid value %q", ErrInvalidFilter, t.text)
+}
@@ -0,0 +1,316 @@
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// RequestError ошибка в теле запроса с типом ошибки SCIM
+type RequestError struct {
+	Type   string
+	Detail string
+}
+
+func (e *RequestError) Error() string {
+	return e.Detail
+}
+
+func requestError(scimType, detail string) *RequestError {
+	return &RequestError{Type: scimType, Detail: detail}
+}
+
+// PatchRequest тело PATCH (RFC 7644, раздел 3.5.2)
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation операция PATCH: add, replace или remove
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// ID идентификатор, который клиенты передают строкой или числом
+type ID string
+
+func (id *ID) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err == nil {
+		*id = ID(n.String())
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*id = ID(s)
+	return nil
+}
+
+// Int числовое значение идентификатора
+func (id ID) Int() (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(string(id)))
+	return n, err == nil && n > 0
+}
+
+// UserPatch переводит операции PATCH пользователя в изменение сотрудника
+func UserPatch(req PatchRequest) (model.EmployeePatch, error) {
+	var patch model.EmployeePatch
+	if len(req.Operations) == 0 {
+		return patch, requestError(ErrorInvalidValue, "Operations required")
+	}
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			if op.Path == "" {
+				var values map[string]json.RawMessage
+				if err := json.Unmarshal(op.Value, &values); err != nil {
+					return patch, requestError(ErrorInvalidValue, "value must be an object when path is omitted")
+				}
+				for attr, value := range values {
+					if err := setUserAttribute(&patch, attr, value); err != nil {
+						return patch, err
+					}
+				}
+				continue
+			}
+			if err := setUserAttribute(&patch, op.Path, op.Value); err != nil {
+				return patch, err
+			}
+		case "remove":
+			if err := removeUserAttribute(&patch, op.Path); err != nil {
+				return patch, err
+			}
+		default:
+			return patch, requestError(ErrorInvalidSyntax, "unknown op "+strconv.Quote(op.Op))
+		}
+	}
+	return patch, nil
+}
+
+var employeeKey = strings.ToLower(SchemaEmployee)
+
+func setUserAttribute(patch *model.EmployeePatch, path string, value json.RawMessage) error {
+	switch key := attributeKey(path); key {
+	case "username":
+		return setString(&patch.UserName, path, value, true)
+	case "externalid":
+		return setString(&patch.ExternalID, path, value, true)
+	case "displayname", "name.formatted":
+		return setString(&patch.FullName, path, value, false)
+	case "name":
+		var name Name
+		if err := json.Unmarshal(value, &name); err != nil {
+			return requestError(ErrorInvalidValue, "name must be an object")
+		}
+		if formatted := name.FullName(); formatted != "" {
+			patch.FullName = model.Some(formatted)
+		}
+		return nil
+	case "name.givenname", "name.familyname":
+		return requestError(ErrorInvalidPath, path+" cannot be changed separately; use name.formatted or displayName")
+	case "title":
+		return setString(&patch.Position, path, value, false)
+	case "active":
+		return checkActive(value)
+	case employeeKey:
+		var ext map[string]json.RawMessage
+		if err := json.Unmarshal(value, &ext); err != nil {
+			return requestError(ErrorInvalidValue, path+" must be an object")
+		}
+		for attr, v := range ext {
+			if err := setUserAttribute(patch, SchemaEmployee+":"+attr, v); err != nil {
+				return err
+			}
+		}
+		return nil
+	case employeeKey + ":departmentid":
+		var id ID
+		if err := json.Unmarshal(value, &id); err != nil {
+			return requestError(ErrorInvalidValue, path+" must be a department id")
+		}
+		deptID, ok := id.Int()
+		if !ok {
+			return requestError(ErrorInvalidValue, path+" must be a department id")
+		}
+		patch.DepartmentID = model.Some(deptID)
+		return nil
+	case employeeKey + ":hiredat":
+		return setString(&patch.HiredAt, path, value, true)
+	case "id", "groups", "meta", "schemas":
+		return requestError(ErrorMutability, path+" is read-only")
+	default:
+		return requestError(ErrorInvalidPath, "unsupported attribute "+strconv.Quote(path))
+	}
+}
+
+func removeUserAttribute(patch *model.EmployeePatch, path string) error {
+	switch attributeKey(path) {
+	case "":
+		return requestError(ErrorNoTarget, "path required for remove")
+	case "username":
+		patch.UserName = model.Null[string]()
+	case "externalid":
+		patch.ExternalID = model.Null[string]()
+	case employeeKey + ":hiredat":
+		patch.HiredAt = model.Null[string]()
+	case "displayname", "name", "name.formatted", "title", employeeKey + ":departmentid", "active":
+		return requestError(ErrorMutability, path+" is required")
+	case "id", "groups", "meta", "schemas":
+		return requestError(ErrorMutability, path+" is read-only")
+	default:
+		return requestError(ErrorInvalidPath, "unsupported attribute "+strconv.Quote(path))
+	}
+	return nil
+}
+
+// setString присваивает строковое значение; null допустим только для необязательных атрибутов
+func setString(field *model.Optional[string], path string, value json.RawMessage, nullable bool) error {
+	if string(value) == "null" {
+		if !nullable {
+			return requestError(ErrorMutability, path+" is required")
+		}
+		*field = model.Null[string]()
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return requestError(ErrorInvalidValue, path+" must be a string")
+	}
+	*field = model.Some(s)
+	return nil
+}
+
+// checkActive принимает только active = true: увольнение сотрудников через SCIM не поддерживается.
+// Некоторые клиенты передают булевы значения строками.
+func checkActive(value json.RawMessage) error {
+	var active bool
+	if err := json.Unmarshal(value, &active); err != nil {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return requestError(ErrorInvalidValue, "active must be a boolean")
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return requestError(ErrorInvalidValue, "active must be a boolean")
+		}
+		active = b
+	}
+	if !active {
+		return requestError(ErrorMutability, "deactivating users is not supported")
+	}
+	return nil
+}
+
+// GroupPatch переводит операции PATCH группы в изменение подразделения и его состава
+func GroupPatch(req PatchRequest) (model.DepartmentPatch, []model.MembershipChange, error) {
+	var patch model.DepartmentPatch
+	var changes []model.MembershipChange
+	if len(req.Operations) == 0 {
+		return patch, nil, requestError(ErrorInvalidValue, "Operations required")
+	}
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "add", "replace":
+			values := map[string]json.RawMessage{op.Path: op.Value}
+			if op.Path == "" {
+				values = nil
+				if err := json.Unmarshal(op.Value, &values); err != nil {
+					return patch, nil, requestError(ErrorInvalidValue, "value must be an object when path is omitted")
+				}
+			}
+			for attr, value := range values {
+				switch attributeKey(attr) {
+				case "displayname":
+					if err := setString(&patch.Name, attr, value, false); err != nil {
+						return patch, nil, err
+					}
+				case "members":
+					if strings.EqualFold(op.Op, "replace") {
+						return patch, nil, requestError(ErrorMutability, "replacing members is not supported; use add and remove")
+					}
+					added, err := memberChanges(value, false)
+					if err != nil {
+						return patch, nil, err
+					}
+					changes = append(changes, added...)
+				case "id", "meta", "schemas":
+					return patch, nil, requestError(ErrorMutability, attr+" is read-only")
+				default:
+					return patch, nil, requestError(ErrorInvalidPath, "unsupported attribute "+strconv.Quote(attr))
+				}
+			}
+		case "remove":
+			removed, err := removeMembers(op)
+			if err != nil {
+				return patch, nil, err
+			}
+			changes = append(changes, removed...)
+		default:
+			return patch, nil, requestError(ErrorInvalidSyntax, "unknown op "+strconv.Quote(op.Op))
+		}
+	}
+	return patch, changes, nil
+}
+
+// memberPath путь вида members[value eq "42"]
+var memberPath = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]*)"\s*\]$`)
+
+func removeMembers(op PatchOperation) ([]model.MembershipChange, error) {
+	path := strings.TrimSpace(op.Path)
+	if m := memberPath.FindStringSubmatch(path); m != nil {
+		id, ok := ID(m[1]).Int()
+		if !ok {
+			return nil, requestError(ErrorNoTarget, "unknown member "+strconv.Quote(m[1]))
+		}
+		return []model.MembershipChange{{Remove: true, ID: id}}, nil
+	}
+	switch attributeKey(path) {
+	case "members":
+		if len(op.Value) == 0 || string(op.Value) == "null" {
+			return nil, requestError(ErrorMutability, "removing all members is not supported")
+		}
+		return memberChanges(op.Value, true)
+	case "":
+		return nil, requestError(ErrorNoTarget, "path required for remove")
+	case "displayname":
+		return nil, requestError(ErrorMutability, path+" is required")
+	default:
+		return nil, requestError(ErrorInvalidPath, "unsupported attribute "+strconv.Quote(path))
+	}
+}
+
+// memberChanges разбирает список участников. Участник без type считается пользователем
+// при добавлении и участником любого вида при удалении.
+func memberChanges(value json.RawMessage, remove bool) ([]model.MembershipChange, error) {
+	var members []struct {
+		Value ID     `json:"value"`
+		Ref   string `json:"$ref"`
+		Type  string `json:"type"`
+	}
+	if err := json.Unmarshal(value, &members); err != nil {
+		return nil, requestError(ErrorInvalidValue, "members must be an array of {\"value\", \"type\"}")
+	}
+	changes := make([]model.MembershipChange, 0, len(members))
+	for _, m := range members {
+		id, ok := m.Value.Int()
+		if !ok {
+			return nil, requestError(ErrorNoTarget, "unknown member "+strconv.Quote(string(m.Value)))
+		}
+		change := model.MembershipChange{Remove: remove, ID: id}
+		switch {
+		case strings.EqualFold(m.Type, ResourceGroup) || strings.Contains(m.Ref, "/Groups/"):
+			change.Kind = model.MemberDepartment
+		case strings.EqualFold(m.Type, ResourceUser) || strings.Contains(m.Ref, "/Users/") || !remove:
+			change.Kind = model.MemberEmployee
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -0,0 +1,102 @@
+package scim
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// EmployeeRequest переводит тело POST /Users в запрос создания сотрудника
+// и возвращает подразделение из расширения Employee
+func (u *User) EmployeeRequest() (int, model.CreateEmployeeRequest, error) {
+	var req model.CreateEmployeeRequest
+	deptID, fullName, err := u.required()
+	if err != nil {
+		return 0, req, err
+	}
+	req.FullName = fullName
+	req.Position = u.Title
+	req.UserName = optionalString(u.UserName)
+	req.ExternalID = u.ExternalID
+	if u.Employee.HiredAt != "" {
+		req.HiredAt = &u.Employee.HiredAt
+	}
+	return deptID, req, nil
+}
+
+// ReplacePatch переводит тело PUT /Users/{id} в изменение всех изменяемых атрибутов:
+// отсутствующие необязательные атрибуты очищаются
+func (u *User) ReplacePatch() (model.EmployeePatch, error) {
+	var patch model.EmployeePatch
+	deptID, fullName, err := u.required()
+	if err != nil {
+		return patch, err
+	}
+	patch.FullName = model.Some(fullName)
+	patch.Position = model.Some(u.Title)
+	patch.DepartmentID = model.Some(deptID)
+	patch.UserName = optional(optionalString(u.UserName))
+	patch.ExternalID = optional(u.ExternalID)
+	patch.HiredAt = model.Null[string]()
+	if u.Employee.HiredAt != "" {
+		patch.HiredAt = model.Some(u.Employee.HiredAt)
+	}
+	return patch, nil
+}
+
+// required проверяет обязательные атрибуты: имя, должность и подразделение
+func (u *User) required() (int, string, error) {
+	if u.Active != nil && !*u.Active {
+		return 0, "", requestError(ErrorMutability, "deactivating users is not supported")
+	}
+	fullName := strings.TrimSpace(u.DisplayName)
+	if fullName == "" && u.Name != nil {
+		fullName = u.Name.FullName()
+	}
+	if fullName == "" {
+		return 0, "", requestError(ErrorInvalidValue, "displayName or name required")
+	}
+	if strings.TrimSpace(u.Title) == "" {
+		return 0, "", requestError(ErrorInvalidValue, "title required")
+	}
+	if u.Employee == nil {
+		return 0, "", requestError(ErrorInvalidValue, SchemaEmployee+":departmentId required")
+	}
+	deptID, ok := u.Employee.DepartmentID.Int()
+	if !ok {
+		return 0, "", requestError(ErrorInvalidValue, SchemaEmployee+":departmentId required")
+	}
+	return deptID, fullName, nil
+}
+
+// MembershipChanges добавление участников из тела POST /Groups
+func (g *Group) MembershipChanges() ([]model.MembershipChange, error) {
+	changes := make([]model.MembershipChange, 0, len(g.Members))
+	for _, m := range g.Members {
+		id, ok := ID(m.Value).Int()
+		if !ok {
+			return nil, requestError(ErrorNoTarget, "unknown member "+strconv.Quote(m.Value))
+		}
+		kind := model.MemberEmployee
+		if strings.EqualFold(m.Type, ResourceGroup) || strings.Contains(m.Ref, "/Groups/") {
+			kind = model.MemberDepartment
+		}
+		changes = append(changes, model.MembershipChange{Kind: kind, ID: id})
+	}
+	return changes, nil
+}
+
+func optionalString(s string) *string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return &s
+}
+
+func optional(s *string) model.Optional[string] {
+	if s == nil {
+		return model.Null[string]()
+	}
+	return model.Some(*s)
+}
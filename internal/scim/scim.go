@@ -0,0 +1,239 @@
+// Package scim реализует представление данных в SCIM 2.0 (RFC 7643, RFC 7644):
+// сотрудники — ресурсы User, подразделения — ресурсы Group, дочерние подразделения —
+// участники родительской группы.
+package scim
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Идентификаторы схем
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaEmployee     = "urn:org-structure-api:params:scim:schemas:extension:2.0:Employee"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+)
+
+// MediaType тип содержимого ответов SCIM
+const MediaType = "application/scim+json"
+
+// Типы ресурсов
+const (
+	ResourceUser  = "User"
+	ResourceGroup = "Group"
+)
+
+// Типы ошибок (RFC 7644, раздел 3.12)
+const (
+	ErrorInvalidFilter = "invalidFilter"
+	ErrorInvalidSyntax = "invalidSyntax"
+	ErrorInvalidPath   = "invalidPath"
+	ErrorInvalidValue  = "invalidValue"
+	ErrorNoTarget      = "noTarget"
+	ErrorMutability    = "mutability"
+	ErrorUniqueness    = "uniqueness"
+)
+
+// UserNamePrefix логин сотрудника без собственного userName: employee-<id>
+const UserNamePrefix = "employee-"
+
+// Meta метаданные ресурса
+type Meta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+	Version      string     `json:"version,omitempty"`
+}
+
+// Name имя пользователя
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// GroupRef группа, в которую входит пользователь: direct — его подразделение,
+// indirect — вышестоящие подразделения
+type GroupRef struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// EmployeeExtension расширение User с подразделением сотрудника
+type EmployeeExtension struct {
+	DepartmentID ID     `json:"departmentId,omitempty"`
+	HiredAt      string `json:"hiredAt,omitempty"`
+}
+
+// User ресурс SCIM User
+type User struct {
+	Schemas     []string           `json:"schemas"`
+	ID          string             `json:"id,omitempty"`
+	ExternalID  *string            `json:"externalId,omitempty"`
+	UserName    string             `json:"userName"`
+	Name        *Name              `json:"name,omitempty"`
+	DisplayName string             `json:"displayName,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Active      *bool              `json:"active,omitempty"`
+	Groups      []GroupRef         `json:"groups,omitempty"`
+	Employee    *EmployeeExtension `json:"urn:org-structure-api:params:scim:schemas:extension:2.0:Employee,omitempty"`
+	Meta        *Meta              `json:"meta,omitempty"`
+}
+
+// Member участник группы: пользователь или дочерняя группа
+type Member struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+}
+
+// Group ресурс SCIM Group
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// ListResponse ответ на запрос списка ресурсов
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+// NewListResponse создаёт ответ со списком ресурсов
+func NewListResponse(resources interface{}, count int, total int64, startIndex int) ListResponse {
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: count,
+		Resources:    resources,
+	}
+}
+
+// Error ответ об ошибке
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+// NewError создаёт ответ об ошибке с HTTP-статусом status
+func NewError(status int, scimType, detail string) Error {
+	return Error{Schemas: []string{SchemaError}, Status: strconv.Itoa(status), ScimType: scimType, Detail: detail}
+}
+
+// UserName логин сотрудника; без собственного логина — employee-<id>
+func UserName(emp *model.Employee) string {
+	if emp.UserName != nil {
+		return *emp.UserName
+	}
+	return UserNamePrefix + strconv.Itoa(emp.ID)
+}
+
+// NewUser представляет сотрудника как User. ancestors — вышестоящие подразделения от корня,
+// baseURL — адрес /scim/v2 для ссылок.
+func NewUser(emp *model.Employee, dept *model.Department, ancestors []model.Department, baseURL string) User {
+	active := emp.Employed()
+	id := strconv.Itoa(emp.ID)
+	created, modified := emp.CreatedAt, emp.UpdatedAt
+	user := User{
+		Schemas:     []string{SchemaUser, SchemaEmployee},
+		ID:          id,
+		ExternalID:  emp.ExternalID,
+		UserName:    UserName(emp),
+		Name:        &Name{Formatted: emp.FullName},
+		DisplayName: emp.FullName,
+		Title:       emp.Position,
+		Active:      &active,
+		Employee:    &EmployeeExtension{DepartmentID: ID(strconv.Itoa(emp.DepartmentID))},
+		Meta: &Meta{
+			ResourceType: ResourceUser,
+			Created:      &created,
+			LastModified: &modified,
+			Location:     baseURL + "/Users/" + id,
+			Version:      version(emp.Version),
+		},
+	}
+	if emp.HiredAt != nil {
+		user.Employee.HiredAt = emp.HiredAt.Format("2006-01-02")
+	}
+	if dept != nil {
+		user.Groups = append(user.Groups, groupRef(dept, "direct", baseURL))
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		user.Groups = append(user.Groups, groupRef(&ancestors[i], "indirect", baseURL))
+	}
+	return user
+}
+
+func groupRef(d *model.Department, kind, baseURL string) GroupRef {
+	id := strconv.Itoa(d.ID)
+	return GroupRef{Value: id, Ref: baseURL + "/Groups/" + id, Display: d.Name, Type: kind}
+}
+
+// NewGroup представляет подразделение как Group. Участники — сотрудники подразделения
+// и его непосредственные потомки; withMembers == false не включает их в ответ.
+func NewGroup(dept *model.Department, employees []model.Employee, children []model.Department, withMembers bool, baseURL string) Group {
+	id := strconv.Itoa(dept.ID)
+	created, modified := dept.CreatedAt, dept.UpdatedAt
+	group := Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          id,
+		DisplayName: dept.Name,
+		Meta: &Meta{
+			ResourceType: ResourceGroup,
+			Created:      &created,
+			LastModified: &modified,
+			Location:     baseURL + "/Groups/" + id,
+			Version:      version(dept.Version),
+		},
+	}
+	if !withMembers {
+		return group
+	}
+	group.Members = []Member{}
+	for _, e := range employees {
+		memberID := strconv.Itoa(e.ID)
+		group.Members = append(group.Members, Member{Value: memberID, Ref: baseURL + "/Users/" + memberID, Display: e.FullName, Type: ResourceUser})
+	}
+	for _, c := range children {
+		memberID := strconv.Itoa(c.ID)
+		group.Members = append(group.Members, Member{Value: memberID, Ref: baseURL + "/Groups/" + memberID, Display: c.Name, Type: ResourceGroup})
+	}
+	return group
+}
+
+// version слабый ETag ресурса
+func version(v int) string {
+	return `W/"` + strconv.Itoa(v) + `"`
+}
+
+// FullName полное имя: formatted или givenName и familyName через пробел
+func (n Name) FullName() string {
+	if formatted := strings.TrimSpace(n.Formatted); formatted != "" {
+		return formatted
+	}
+	return strings.TrimSpace(strings.TrimSpace(n.GivenName) + " " + strings.TrimSpace(n.FamilyName))
+}
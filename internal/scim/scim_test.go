@@ -0,0 +1,344 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+const baseURL = "https://org.example.com/scim/v2"
+
+func readFixture(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+}
+
+// assertGolden сравнивает JSON-представление v с эталоном из testdata
+func assertGolden(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotValue, expected interface{}
+	readFixture(t, name, &expected)
+	json.Unmarshal(got, &gotValue)
+	if !reflect.DeepEqual(gotValue, expected) {
+		t.Errorf("%s: got %s", name, got)
+	}
+}
+
+func TestWhere(t *testing.T) {
+	employeeAttr := SchemaEmployee + ":departmentId"
+	tests := []struct {
+		filter   string
+		attrs    Attributes
+		expected string
+		args     []interface{}
+	}{
+		{`userName eq "Ivan.Petrov@example.com"`, UserAttributes,
+			"COALESCE(LOWER(COALESCE(user_name, 'employee-' || id)) = LOWER(?), FALSE)", []interface{}{"Ivan.Petrov@example.com"}},
+		{`USERNAME Eq "a"`, UserAttributes,
+			"COALESCE(LOWER(COALESCE(user_name, 'employee-' || id)) = LOWER(?), FALSE)", []interface{}{"a"}},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "a"`, UserAttributes,
+			"COALESCE(LOWER(COALESCE(user_name, 'employee-' || id)) = LOWER(?), FALSE)", []interface{}{"a"}},
+		{`externalId eq "00uA"`, UserAttributes, "COALESCE(external_id = ?, FALSE)", []interface{}{"00uA"}},
+		{`displayName co "50%_off"`, UserAttributes, "COALESCE(full_name ILIKE ?, FALSE)", []interface{}{`%50\%\_off%`}},
+		{`title sw "Dev" and not (displayName ew "ов")`, UserAttributes,
+			"(COALESCE(position ILIKE ?, FALSE) AND NOT COALESCE(full_name ILIKE ?, FALSE))", []interface{}{"Dev%", "%ов"}},
+		{`externalId pr or id eq "42"`, UserAttributes,
+			"((external_id IS NOT NULL AND external_id <> '') OR COALESCE(id = ?, FALSE))", []interface{}{42}},
+		{`id eq "abc"`, UserAttributes, "FALSE", nil},
+		{`id ne "abc"`, UserAttributes, "TRUE", nil},
+		{`active eq true`, UserAttributes, "COALESCE((status IN ('active', 'on_leave')) = ?, FALSE)", []interface{}{true}},
+		{`externalId eq null`, UserAttributes, "(external_id IS NULL)", nil},
+		{employeeAttr + ` eq 3`, UserAttributes, "COALESCE(department_id = ?, FALSE)", []interface{}{3}},
+		{`meta.lastModified gt "2026-01-01T00:00:00Z"`, UserAttributes,
+			"COALESCE(updated_at > ?, FALSE)", []interface{}{time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}},
+		{`(displayName eq "A" or displayName eq "B") and id lt 10`, GroupAttributes,
+			"((COALESCE(LOWER(name) = LOWER(?), FALSE) OR COALESCE(LOWER(name) = LOWER(?), FALSE)) AND COALESCE(id < ?, FALSE))", []interface{}{"A", "B", 10}},
+		{`displayName eq "say \"hi\""`, GroupAttributes, "COALESCE(LOWER(name) = LOWER(?), FALSE)", []interface{}{`say "hi"`}},
+		{"  ", UserAttributes, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			where, args, err := Where(tt.filter, tt.attrs)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if where != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, where)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("expected args %v, got %v", tt.args, args)
+			}
+		})
+	}
+}
+
+func TestWhere_Errors(t *testing.T) {
+	filters := []string{
+		`userName`,
+		`userName eq`,
+		`userName like "a"`,
+		`userName eq "a" and`,
+		`(userName eq "a"`,
+		`not userName eq "a"`,
+		`userName eq "unterminated`,
+		`emails[type eq "work"]`,
+		`password eq "x"`,
+		`userName eq 5`,
+		`id co "4"`,
+		`active gt true`,
+		`meta.created gt "yesterday"`,
+		`title gt null`,
+		`userName eq "a" extra`,
+	}
+	for _, filter := range filters {
+		if _, _, err := Where(filter, UserAttributes); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("%s: expected ErrInvalidFilter, got %v", filter, err)
+		}
+	}
+	if _, _, err := Where(`title eq "x"`, GroupAttributes); !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("expected ErrInvalidFilter for user attribute in group filter, got %v", err)
+	}
+}
+
+func TestUserPatch_Fixtures(t *testing.T) {
+	var azure PatchRequest
+	readFixture(t, "user_patch_azure.json", &azure)
+	patch, err := UserPatch(azure)
+	if err != nil {
+		t.Fatalf("azure: unexpected error: %v", err)
+	}
+	expected := model.EmployeePatch{
+		UserName:     model.Some("ivan.petrov@example.com"),
+		Position:     model.Some("Team Lead"),
+		ExternalID:   model.Some("8f1c2d3e-0000-4000-8000-000000000001"),
+		DepartmentID: model.Some(7),
+	}
+	if !reflect.DeepEqual(patch, expected) {
+		t.Errorf("azure: expected %+v, got %+v", expected, patch)
+	}
+
+	var okta PatchRequest
+	readFixture(t, "user_patch_okta.json", &okta)
+	patch, err = UserPatch(okta)
+	if err != nil {
+		t.Fatalf("okta: unexpected error: %v", err)
+	}
+	expected = model.EmployeePatch{
+		FullName:   model.Some("Иван Петров"),
+		HiredAt:    model.Some("2024-03-01"),
+		ExternalID: model.Null[string](),
+	}
+	if !reflect.DeepEqual(patch, expected) {
+		t.Errorf("okta: expected %+v, got %+v", expected, patch)
+	}
+}
+
+func TestUserPatch_Errors(t *testing.T) {
+	tests := []struct {
+		op       string
+		scimType string
+	}{
+		{`{"op":"replace","path":"active","value":false}`, ErrorMutability},
+		{`{"op":"replace","value":{"active":"False"}}`, ErrorMutability},
+		{`{"op":"replace","path":"groups","value":[]}`, ErrorMutability},
+		{`{"op":"remove","path":"title"}`, ErrorMutability},
+		{`{"op":"replace","path":"displayName","value":null}`, ErrorMutability},
+		{`{"op":"replace","path":"name.givenName","value":"Ivan"}`, ErrorInvalidPath},
+		{`{"op":"replace","path":"emails","value":[]}`, ErrorInvalidPath},
+		{`{"op":"replace","path":"title","value":5}`, ErrorInvalidValue},
+		{`{"op":"replace","value":"x"}`, ErrorInvalidValue},
+		{`{"op":"remove"}`, ErrorNoTarget},
+		{`{"op":"move","path":"title"}`, ErrorInvalidSyntax},
+	}
+	for _, tt := range tests {
+		var op PatchOperation
+		json.Unmarshal([]byte(tt.op), &op)
+		_, err := UserPatch(PatchRequest{Operations: []PatchOperation{op}})
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) || reqErr.Type != tt.scimType {
+			t.Errorf("%s: expected %s, got %v", tt.op, tt.scimType, err)
+		}
+	}
+}
+
+func TestGroupPatch_Fixtures(t *testing.T) {
+	var azure PatchRequest
+	readFixture(t, "group_patch_azure.json", &azure)
+	patch, changes, err := GroupPatch(azure)
+	if err != nil {
+		t.Fatalf("azure: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(patch, model.DepartmentPatch{Name: model.Some("Backend")}) {
+		t.Errorf("azure: unexpected patch %+v", patch)
+	}
+	expected := []model.MembershipChange{
+		{Kind: model.MemberEmployee, ID: 42},
+		{Kind: model.MemberEmployee, ID: 43},
+		{Remove: true, ID: 44},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("azure: expected %+v, got %+v", expected, changes)
+	}
+
+	var okta PatchRequest
+	readFixture(t, "group_patch_okta.json", &okta)
+	patch, changes, err = GroupPatch(okta)
+	if err != nil {
+		t.Fatalf("okta: unexpected error: %v", err)
+	}
+	if patch.Name.Set || patch.ParentID.Set {
+		t.Errorf("okta: unexpected patch %+v", patch)
+	}
+	expected = []model.MembershipChange{
+		{Kind: model.MemberDepartment, ID: 5},
+		{Kind: model.MemberEmployee, ID: 12},
+		{Remove: true, Kind: model.MemberDepartment, ID: 9},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("okta: expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestGroupPatch_Errors(t *testing.T) {
+	tests := []struct {
+		op       string
+		scimType string
+	}{
+		{`{"op":"replace","path":"members","value":[{"value":"1"}]}`, ErrorMutability},
+		{`{"op":"remove","path":"members"}`, ErrorMutability},
+		{`{"op":"remove","path":"displayName"}`, ErrorMutability},
+		{`{"op":"add","path":"members","value":[{"value":"abc"}]}`, ErrorNoTarget},
+		{`{"op":"add","path":"members","value":{"value":"1"}}`, ErrorInvalidValue},
+		{`{"op":"replace","path":"owner","value":"x"}`, ErrorInvalidPath},
+	}
+	for _, tt := range tests {
+		var op PatchOperation
+		json.Unmarshal([]byte(tt.op), &op)
+		_, _, err := GroupPatch(PatchRequest{Operations: []PatchOperation{op}})
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) || reqErr.Type != tt.scimType {
+			t.Errorf("%s: expected %s, got %v", tt.op, tt.scimType, err)
+		}
+	}
+}
+
+func TestUser_EmployeeRequest(t *testing.T) {
+	var user User
+	readFixture(t, "user_create.json", &user)
+	deptID, req, err := user.EmployeeRequest()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deptID != 3 {
+		t.Errorf("expected department 3, got %d", deptID)
+	}
+	if req.FullName != "Анна Смирнова" || req.Position != "Analyst" {
+		t.Errorf("unexpected names: %+v", req)
+	}
+	if req.UserName == nil || *req.UserName != "anna.smirnova@example.com" {
+		t.Errorf("unexpected user name: %v", req.UserName)
+	}
+	if req.ExternalID == nil || *req.ExternalID != "00u1abcd" {
+		t.Errorf("unexpected external id: %v", req.ExternalID)
+	}
+	if req.HiredAt == nil || *req.HiredAt != "2025-09-15" {
+		t.Errorf("unexpected hired_at: %v", req.HiredAt)
+	}
+
+	// PUT без необязательных атрибутов очищает их
+	user.ExternalID = nil
+	user.UserName = ""
+	user.Employee.HiredAt = ""
+	patch, err := user.ReplacePatch()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !patch.UserName.Null || !patch.ExternalID.Null || !patch.HiredAt.Null {
+		t.Errorf("expected optional attributes to be cleared: %+v", patch)
+	}
+	if patch.DepartmentID.Value != 3 || patch.FullName.Value != "Анна Смирнова" {
+		t.Errorf("unexpected patch %+v", patch)
+	}
+
+	user.Employee = nil
+	if _, _, err := user.EmployeeRequest(); err == nil {
+		t.Error("expected error without departmentId")
+	}
+}
+
+func TestNewUser_Golden(t *testing.T) {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	hired := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	externalID := "00u1abcd"
+	root := 1
+	emp := model.Employee{
+		ID: 42, DepartmentID: 3, FullName: "Иван Петров", Position: "Developer",
+		HiredAt: &hired, ExternalID: &externalID, Status: model.EmployeeActive, Version: 2, CreatedAt: created, UpdatedAt: created.Add(time.Hour),
+	}
+	dept := model.Department{ID: 3, Name: "Backend", ParentID: &root}
+	ancestors := []model.Department{{ID: 1, Name: "Company"}, {ID: 2, Name: "Engineering", ParentID: &root}}
+
+	assertGolden(t, "user.json", NewUser(&emp, &dept, ancestors, baseURL))
+
+	// Уволенный сотрудник и ещё не вышедший на работу представлены неактивными
+	for _, status := range []string{model.EmployeePending, model.EmployeeTerminated} {
+		emp.Status = status
+		if user := NewUser(&emp, &dept, ancestors, baseURL); *user.Active {
+			t.Errorf("expected inactive user for status %s", status)
+		}
+	}
+}
+
+func TestNewGroup_Golden(t *testing.T) {
+	created := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	parent := 2
+	dept := model.Department{ID: 3, Name: "Backend", ParentID: &parent, Version: 5, CreatedAt: created, UpdatedAt: created}
+	employees := []model.Employee{{ID: 42, FullName: "Иван Петров"}}
+	children := []model.Department{{ID: 7, Name: "Platform"}}
+
+	assertGolden(t, "group.json", NewGroup(&dept, employees, children, true, baseURL))
+
+	group := NewGroup(&dept, employees, children, false, baseURL)
+	if group.Members != nil {
+		t.Errorf("expected members to be omitted, got %+v", group.Members)
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	config := NewServiceProviderConfig(200, baseURL)
+	if !config.Patch.Supported || !config.Filter.Supported || config.Filter.MaxResults != 200 || config.Bulk.Supported {
+		t.Errorf("unexpected config %+v", config)
+	}
+
+	ids := map[string]bool{}
+	for _, s := range NewSchemas(baseURL) {
+		ids[s.ID] = true
+	}
+	for _, rt := range NewResourceTypes(baseURL) {
+		if !ids[rt.Schema] {
+			t.Errorf("resource type %s refers to unknown schema %s", rt.ID, rt.Schema)
+		}
+		for _, ext := range rt.SchemaExtensions {
+			if !ids[ext.Schema] {
+				t.Errorf("resource type %s refers to unknown extension %s", rt.ID, ext.Schema)
+			}
+		}
+	}
+}
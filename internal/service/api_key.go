@@ -0,0 +1,129 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+func (s *Service) ListAPIKeys() ([]model.APIKey, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAPIKeys()
+}
+
+func (s *Service) CreateAPIKey(req model.CreateAPIKeyRequest) (*model.APIKeyWithSecret, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	name := validateName(req.Name)
+	if name == "" || len(name) > 200 {
+		return nil, errors.New("invalid name")
+	}
+	if req.Scope != auth.ScopeReadOnly && req.Scope != auth.ScopeReadWrite {
+		return nil, errors.New("invalid scope")
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *req.ExpiresAt)
+		if err != nil {
+			return nil, errors.New("invalid date format")
+		}
+		if !t.After(time.Now()) {
+			return nil, errors.New("expires_at must be in the future")
+		}
+		expiresAt = &t
+	}
+
+	secret, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   auth.HashAPIKey(secret),
+		Scope:     req.Scope,
+		ExpiresAt: expiresAt,
+		CreatedBy: s.principal.Subject,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateAPIKey(key); err != nil {
+		return nil, err
+	}
+	return &model.APIKeyWithSecret{APIKey: *key, Key: secret}, nil
+}
+
+// RotateAPIKey выпускает новое значение ключа; старое значение сразу перестаёт работать
+func (s *Service) RotateAPIKey(id int) (*model.APIKeyWithSecret, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	key, err := s.repo.GetAPIKeyByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+
+	secret, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	key.Prefix = prefix
+	key.KeyHash = auth.HashAPIKey(secret)
+	key.LastUsedAt = nil
+
+	if err := s.repo.UpdateAPIKey(key); err != nil {
+		return nil, err
+	}
+	return &model.APIKeyWithSecret{APIKey: *key, Key: secret}, nil
+}
+
+func (s *Service) RevokeAPIKey(id int) error {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return err
+	}
+
+	key, err := s.repo.GetAPIKeyByID(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	return s.repo.UpdateAPIKey(key)
+}
+
+// ResolveAPIKey реализует auth.APIKeyResolver
+func (s *Service) ResolveAPIKey(secret string) (auth.Principal, error) {
+	key, err := s.repo.GetAPIKeyByHash(auth.HashAPIKey(secret))
+	if err != nil {
+		return auth.Principal{}, auth.ErrUnauthorized
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return auth.Principal{}, auth.ErrUnauthorized
+	}
+	// Ошибка обновления статистики не должна блокировать запрос
+	_ = s.repo.TouchAPIKey(key.ID, now)
+
+	return auth.Principal{
+		Subject:  fmt.Sprintf("apikey:%d", key.ID),
+		Scope:    key.Scope,
+		TenantID: key.TenantID,
+	}, nil
+}
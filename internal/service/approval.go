@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// ApprovalRequiredError возвращается вместо выполнения изменения, попавшего под политику
+// согласования; изменение сохранено как запрос Request. Внутри пакета, сценария или
+// запланированного изменения запрос откатывается вместе с транзакцией, и вся операция
+// завершается ошибкой approval required.
+type ApprovalRequiredError struct {
+	Request *model.ChangeRequest
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return ErrApprovalRequired.Error()
+}
+
+func (e *ApprovalRequiredError) Unwrap() error {
+	return ErrApprovalRequired
+}
+
+// ListApprovalPolicies возвращает политики согласования; требует роль admin на всю организацию
+func (s *Service) ListApprovalPolicies() ([]model.ApprovalPolicy, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListApprovalPolicies()
+}
+
+// SetApprovalPolicy создаёт или заменяет политику согласования действия
+func (s *Service) SetApprovalPolicy(action string, req model.SetApprovalPolicyRequest) (*model.ApprovalPolicy, error) {
+	if !model.ValidApprovalAction(action) {
+		return nil, errors.New("invalid action")
+	}
+	if req.MinEmployees < 0 {
+		return nil, errors.New("invalid min_employees")
+	}
+	if req.ApproverRole == "" {
+		req.ApproverRole = model.RoleAdmin
+	}
+	if req.ApproverRole != model.RoleEditor && req.ApproverRole != model.RoleAdmin {
+		return nil, errors.New("invalid approver_role")
+	}
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	policy := &model.ApprovalPolicy{
+		Action:       action,
+		MinEmployees: req.MinEmployees,
+		ApproverRole: req.ApproverRole,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := s.repo.UpsertApprovalPolicy(policy); err != nil {
+		return nil, err
+	}
+	return s.repo.GetApprovalPolicy(action)
+}
+
+func (s *Service) DeleteApprovalPolicy(action string) error {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteApprovalPolicy(action)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListChangeRequests возвращает запросы на изменение подразделений, на которые у субъекта
+// есть роль viewer (запросы по удалённым подразделениям — только с ролью на всю организацию);
+// пустой status — в любом статусе
+func (s *Service) ListChangeRequests(status string) ([]model.ChangeRequest, error) {
+	switch status {
+	case "", model.ChangeRequestPending, model.ChangeRequestApproved, model.ChangeRequestRejected:
+	default:
+		return nil, errors.New("invalid status")
+	}
+	if err := s.authorizeAny(model.RoleViewer); err != nil {
+		return nil, err
+	}
+	requests, err := s.repo.ListChangeRequests(status)
+	if err != nil {
+		return nil, err
+	}
+
+	deptIDs := make([]int, 0, len(requests))
+	for _, cr := range requests {
+		deptIDs = append(deptIDs, cr.DepartmentID)
+	}
+	canView, err := s.departmentAccess(deptIDs, model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]model.ChangeRequest, 0, len(requests))
+	for _, cr := range requests {
+		if canView(cr.DepartmentID) {
+			visible = append(visible, cr)
+		}
+	}
+	return visible, nil
+}
+
+// GetChangeRequest возвращает запрос; требует роль viewer на его подразделение
+func (s *Service) GetChangeRequest(id int) (*model.ChangeRequest, error) {
+	if err := s.authorizeAny(model.RoleViewer); err != nil {
+		return nil, err
+	}
+	cr, err := s.repo.GetChangeRequestByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(s.changeRequestScope(cr), model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// ApproveChangeRequest одобряет запрос и в той же транзакции выполняет изменение
+// методом сервиса от имени согласующего. Если изменение больше нельзя выполнить
+// (подразделение изменено или удалено), возвращается ошибка и запрос остаётся ожидающим.
+func (s *Service) ApproveChangeRequest(id int, comment string) (*model.ChangeRequest, error) {
+	return s.decideChangeRequest(id, model.ChangeRequestApproved, comment)
+}
+
+// RejectChangeRequest отклоняет запрос; автор может отклонить свой запрос без роли согласующего
+func (s *Service) RejectChangeRequest(id int, comment string) (*model.ChangeRequest, error) {
+	return s.decideChangeRequest(id, model.ChangeRequestRejected, comment)
+}
+
+func (s *Service) decideChangeRequest(id int, status, comment string) (*model.ChangeRequest, error) {
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > 2000 {
+		return nil, errors.New("invalid comment")
+	}
+
+	var cr *model.ChangeRequest
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		var err error
+		if cr, err = txRepo.GetChangeRequestForUpdate(id); err != nil {
+			return ErrNotFound
+		}
+		if err := s.authorizeDecision(cr, status); err != nil {
+			return err
+		}
+		if cr.Status != model.ChangeRequestPending {
+			return ErrChangeRequestDecided
+		}
+
+		if status == model.ChangeRequestApproved {
+			txSvc := *s
+			txSvc.repo = txRepo
+			txSvc.approved = true
+			if err := txSvc.executeChangeRequest(cr); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		var note *string
+		if comment != "" {
+			note = &comment
+		}
+		if err := txRepo.DecideChangeRequest(id, status, s.principal.Subject, note, now); err != nil {
+			return err
+		}
+		decidedBy := s.principal.Subject
+		cr.Status, cr.DecidedBy, cr.Comment, cr.DecidedAt = status, &decidedBy, note, &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// authorizeDecision проверяет право решения: роль ApproverRole на подразделение
+// (на всю организацию, если подразделения уже нет). Автор не может одобрить свой запрос,
+// но может его отклонить; системный субъект (аутентификация выключена) ограничений не имеет.
+func (s *Service) authorizeDecision(cr *model.ChangeRequest, status string) error {
+	isAuthor := cr.RequestedBy == s.principal.Subject && s.principal.Subject != auth.System.Subject
+	if isAuthor && status == model.ChangeRequestRejected {
+		return nil
+	}
+
+	if err := s.authorize(s.changeRequestScope(cr), cr.ApproverRole); err != nil {
+		return err
+	}
+	if isAuthor {
+		return ErrSelfApproval
+	}
+	return nil
+}
+
+// changeRequestScope возвращает подразделение запроса для проверки прав;
+// nil (уровень всей организации), если подразделения уже нет
+func (s *Service) changeRequestScope(cr *model.ChangeRequest) *int {
+	if _, err := s.repo.GetDepartmentByID(cr.DepartmentID); err != nil {
+		return nil
+	}
+	return &cr.DepartmentID
+}
+
+// executeChangeRequest выполняет сохранённое изменение с версией подразделения на момент запроса
+func (s *Service) executeChangeRequest(cr *model.ChangeRequest) error {
+	switch cr.Action {
+	case model.ApprovalDeleteDepartment:
+		return s.DeleteDepartment(cr.DepartmentID, cr.Mode, cr.ReassignToID, cr.DepartmentVersion)
+	case model.ApprovalMoveDepartment:
+		if cr.Patch == nil {
+			return errors.New("change request has no patch")
+		}
+		_, err := s.PatchDepartment(cr.DepartmentID, *cr.Patch, cr.DepartmentVersion)
+		return err
+	}
+	return errors.New("unknown action: " + cr.Action)
+}
+
+// requireApproval сохраняет изменение как запрос на согласование, если оно попадает
+// под политику действия cr.Action, и возвращает *ApprovalRequiredError; иначе nil.
+// Внутри одобренного запроса политики не проверяются.
+func (s *Service) requireApproval(cr model.ChangeRequest, dept *model.Department) error {
+	if s.approved {
+		return nil
+	}
+	policy, employees, err := s.matchApprovalPolicy(cr.Action, dept.ID)
+	if err != nil || policy == nil {
+		return err
+	}
+
+	cr.DepartmentID = dept.ID
+	cr.DepartmentName = dept.Name
+	cr.DepartmentVersion = dept.Version
+	cr.Employees = employees
+	cr.PolicyID = &policy.ID
+	cr.ApproverRole = policy.ApproverRole
+	cr.Status = model.ChangeRequestPending
+	cr.RequestedBy = s.principal.Subject
+	cr.CreatedAt = time.Now()
+	if err := s.repo.CreateChangeRequest(&cr); err != nil {
+		return err
+	}
+	return &ApprovalRequiredError{Request: &cr}
+}
+
+// withoutDeferral заменяет ошибку согласования операции, выполнявшейся в общей транзакции:
+// запрос на изменение откатан вместе с ней, поэтому ссылаться на него нельзя
+func withoutDeferral(err error) error {
+	if errors.Is(err, ErrApprovalRequired) {
+		return ErrApprovalNotDeferrable
+	}
+	return err
+}
+
+// matchApprovalPolicy возвращает политику действия, под которую попадает подразделение,
+// и численность его поддерева; nil — согласование не требуется
+func (s *Service) matchApprovalPolicy(action string, deptID int) (*model.ApprovalPolicy, int, error) {
+	policy, err := s.repo.GetApprovalPolicy(action)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	counts, err := s.repo.CountSubtreeEmployees([]int{deptID}, employeeStatuses(nil))
+	if err != nil {
+		return nil, 0, err
+	}
+	if counts[deptID] <= policy.MinEmployees {
+		return nil, counts[deptID], nil
+	}
+	return policy, counts[deptID], nil
+}
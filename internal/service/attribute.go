@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// attributeName формат имени атрибута: строчная латинская буква, затем до 63 строчных букв, цифр и "_"
+var attributeName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// ListAttributeDefinitions возвращает схемы атрибутов; пустая entity — для всех сущностей
+func (s *Service) ListAttributeDefinitions(entity string) ([]model.AttributeDefinition, error) {
+	if entity != "" && !model.ValidAttributeEntity(entity) {
+		return nil, errors.New("invalid entity")
+	}
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAttributeDefinitions(entity)
+}
+
+// CreateAttributeDefinition добавляет схему атрибута; требует роль администратора организации
+func (s *Service) CreateAttributeDefinition(req model.CreateAttributeDefinitionRequest) (*model.AttributeDefinition, error) {
+	if !model.ValidAttributeEntity(req.Entity) {
+		return nil, errors.New("invalid entity")
+	}
+	name := strings.TrimSpace(req.Name)
+	if !attributeName.MatchString(name) {
+		return nil, errors.New("invalid attribute name")
+	}
+	if !model.ValidAttributeType(req.Type) {
+		return nil, errors.New("invalid attribute type")
+	}
+	enumValues, err := validateEnumValues(req.Type, req.EnumValues)
+	if err != nil {
+		return nil, err
+	}
+	description := strings.TrimSpace(req.Description)
+	if utf8.RuneCountInString(description) > 2000 {
+		return nil, errors.New("invalid description")
+	}
+
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.CheckUniqueAttributeDefinition(req.Entity, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDuplicateAttribute
+	}
+
+	def := &model.AttributeDefinition{
+		Entity:      req.Entity,
+		Name:        name,
+		Type:        req.Type,
+		Required:    req.Required,
+		EnumValues:  enumValues,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.repo.CreateAttributeDefinition(def); err != nil {
+		return nil, err
+	}
+	return def, nil
+}
+
+// DeleteAttributeDefinition удаляет схему атрибута вместе с его значениями
+func (s *Service) DeleteAttributeDefinition(id int) error {
+	def, err := s.repo.GetAttributeDefinitionByID(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return err
+	}
+	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.repo.WithTx(tx).DeleteAttributeDefinition(def)
+	})
+}
+
+// validateEnumValues проверяет допустимые значения: непустой список без повторов только для типа enum
+func validateEnumValues(attrType string, values []string) ([]string, error) {
+	if attrType != model.AttributeTypeEnum {
+		if len(values) > 0 {
+			return nil, errors.New("enum_values allowed only for enum attributes")
+		}
+		return nil, nil
+	}
+	if len(values) == 0 {
+		return nil, errors.New("enum_values required for enum attributes")
+	}
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v == "" || utf8.RuneCountInString(v) > 200 || seen[v] {
+			return nil, errors.New("invalid enum_values")
+		}
+		seen[v] = true
+	}
+	return values, nil
+}
+
+// mergeAttributes объединяет текущие значения атрибутов сущности с частичным изменением
+// (null удаляет атрибут, null вместо объекта — все атрибуты) и проверяет результат по схемам
+func (s *Service) mergeAttributes(entity string, current model.Attributes, patch model.Optional[*model.Attributes]) (model.Attributes, error) {
+	merged := make(model.Attributes, len(current))
+	if !patch.Null {
+		for name, value := range current {
+			merged[name] = value
+		}
+		if patch.Value != nil {
+			for name, value := range *patch.Value {
+				if value == nil {
+					delete(merged, name)
+				} else {
+					merged[name] = value
+				}
+			}
+		}
+	}
+
+	defs, err := s.repo.ListAttributeDefinitions(entity)
+	if err != nil {
+		return nil, err
+	}
+	return merged, validateAttributes(defs, merged)
+}
+
+// validateAttributes проверяет значения по схемам: неизвестные атрибуты, типы и обязательность
+func validateAttributes(defs []model.AttributeDefinition, attrs model.Attributes) error {
+	byName := make(map[string]*model.AttributeDefinition, len(defs))
+	for i := range defs {
+		byName[defs[i].Name] = &defs[i]
+		if _, ok := attrs[defs[i].Name]; defs[i].Required && !ok {
+			return fmt.Errorf("%w: attribute %q is required", ErrInvalidAttributes, defs[i].Name)
+		}
+	}
+	for name, value := range attrs {
+		def, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("%w: unknown attribute %q", ErrInvalidAttributes, name)
+		}
+		if !validAttributeValue(def, value) {
+			return fmt.Errorf("%w: invalid value of attribute %q", ErrInvalidAttributes, name)
+		}
+	}
+	return nil
+}
+
+func validAttributeValue(def *model.AttributeDefinition, value any) bool {
+	switch def.Type {
+	case model.AttributeTypeNumber:
+		_, ok := value.(float64)
+		return ok
+	case model.AttributeTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return false
+	}
+	switch def.Type {
+	case model.AttributeTypeDate:
+		_, err := time.Parse("2006-01-02", str)
+		return err == nil
+	case model.AttributeTypeEnum:
+		for _, v := range def.EnumValues {
+			if v == str {
+				return true
+			}
+		}
+		return false
+	default:
+		return str != "" && utf8.RuneCountInString(str) <= 2000
+	}
+}
+
+// attributeFilter переводит строковые значения фильтра из параметров запроса в типы схем атрибутов
+func (s *Service) attributeFilter(entity string, filter model.Attributes) (model.Attributes, error) {
+	if len(filter) == 0 {
+		return nil, nil
+	}
+	defs, err := s.repo.ListAttributeDefinitions(entity)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*model.AttributeDefinition, len(defs))
+	for i := range defs {
+		byName[defs[i].Name] = &defs[i]
+	}
+
+	typed := make(model.Attributes, len(filter))
+	for name, raw := range filter {
+		def, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown attribute %q", ErrInvalidAttributes, name)
+		}
+		str, _ := raw.(string)
+		var value any = str
+		switch def.Type {
+		case model.AttributeTypeNumber:
+			value, err = strconv.ParseFloat(str, 64)
+		case model.AttributeTypeBoolean:
+			value, err = strconv.ParseBool(str)
+		}
+		if err != nil || !validAttributeValue(def, value) {
+			return nil, fmt.Errorf("%w: invalid value of attribute %q", ErrInvalidAttributes, name)
+		}
+		typed[name] = value
+	}
+	return typed, nil
+}
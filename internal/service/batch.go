@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// Максимальное число операций в пакете
+const maxBatchOperations = 200
+
+// ExecuteBatch выполняет операции по порядку в одной транзакции.
+// При ошибке все изменения откатываются; ответ содержит результат каждой операции,
+// а возвращаемая ошибка — причину отказа упавшей операции.
+func (s *Service) ExecuteBatch(req model.BatchRequest) (*model.BatchResponse, error) {
+	if len(req.Operations) == 0 {
+		return nil, errors.New("operations required")
+	}
+	if len(req.Operations) > maxBatchOperations {
+		return nil, fmt.Errorf("too many operations (max %d)", maxBatchOperations)
+	}
+
+	resp := &model.BatchResponse{Results: make([]model.BatchResult, len(req.Operations))}
+	for i, op := range req.Operations {
+		resp.Results[i] = model.BatchResult{Index: i, Op: op.Op, Ref: op.Ref, Status: model.BatchStatusSkipped}
+	}
+
+	failed := -1
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		// Методы сервиса внутри пакета открывают вложенные транзакции (точки сохранения)
+		txSvc := *s
+		txSvc.repo = s.repo.WithTx(tx)
+		refs := make(map[string]int)
+
+		for i, op := range req.Operations {
+			if err := txSvc.executeBatchOperation(op, refs, &resp.Results[i]); err != nil {
+				failed = i
+				return err
+			}
+			resp.Results[i].Status = model.BatchStatusOK
+		}
+		return nil
+	})
+
+	if err != nil {
+		err = withoutDeferral(err)
+		for i := range resp.Results {
+			switch {
+			case i < failed:
+				resp.Results[i].Status = model.BatchStatusRolledBack
+			case i == failed:
+				resp.Results[i].Status = model.BatchStatusFailed
+				resp.Results[i].Error = err.Error()
+			}
+		}
+		return resp, err
+	}
+
+	resp.Committed = true
+	return resp, nil
+}
+
+func (s *Service) executeBatchOperation(op model.BatchOperation, refs map[string]int, result *model.BatchResult) error {
+	if op.Ref != "" {
+		if op.Op != model.BatchCreateDepartment {
+			return errors.New("ref is only supported for create_department")
+		}
+		if _, ok := refs[op.Ref]; ok {
+			return errors.New("duplicate ref: " + op.Ref)
+		}
+	}
+
+	switch op.Op {
+	case model.BatchCreateDepartment:
+		parentID, err := resolveBatchRef(op.ParentID, refs)
+		if err != nil {
+			return err
+		}
+		dept, err := s.CreateDepartment(model.CreateDepartmentRequest{Name: op.Name, ParentID: parentID})
+		if err != nil {
+			return err
+		}
+		if op.Ref != "" {
+			refs[op.Ref] = dept.ID
+		}
+		result.Department = dept
+
+	case model.BatchUpdateDepartment:
+		id, err := requireBatchRef(op.ID, refs, "id")
+		if err != nil {
+			return err
+		}
+		parentID, err := resolveBatchRef(op.ParentID, refs)
+		if err != nil {
+			return err
+		}
+		dept, err := s.UpdateDepartment(id, model.UpdateDepartmentRequest{Name: op.Name, ParentID: parentID}, op.Version)
+		if err != nil {
+			return err
+		}
+		result.Department = dept
+
+	case model.BatchDeleteDepartment:
+		id, err := requireBatchRef(op.ID, refs, "id")
+		if err != nil {
+			return err
+		}
+		mode := op.Mode
+		if mode == "" {
+			mode = "cascade"
+		}
+		if mode != "cascade" && mode != "reassign" {
+			return errors.New("invalid mode")
+		}
+		reassignToID, err := resolveBatchRef(op.ReassignToDepartmentID, refs)
+		if err != nil {
+			return err
+		}
+		return s.DeleteDepartment(id, mode, reassignToID, op.Version)
+
+	case model.BatchCreateEmployee:
+		deptID, err := requireBatchRef(op.DepartmentID, refs, "department_id")
+		if err != nil {
+			return err
+		}
+		emp, err := s.CreateEmployee(deptID, model.CreateEmployeeRequest{
+			FullName: op.FullName,
+			Position: op.Position,
+			HiredAt:  op.HiredAt,
+		})
+		if err != nil {
+			return err
+		}
+		result.Employee = emp
+
+	default:
+		return errors.New("unknown op: " + op.Op)
+	}
+	return nil
+}
+
+// resolveBatchRef превращает ссылку в ID; nil остаётся nil
+func resolveBatchRef(ref *model.BatchRef, refs map[string]int) (*int, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	if ref.Ref == "" {
+		id := ref.ID
+		return &id, nil
+	}
+	id, ok := refs[ref.Ref]
+	if !ok {
+		return nil, errors.New("unknown ref: " + ref.Ref)
+	}
+	return &id, nil
+}
+
+func requireBatchRef(ref *model.BatchRef, refs map[string]int, field string) (int, error) {
+	id, err := resolveBatchRef(ref, refs)
+	if err != nil {
+		return 0, err
+	}
+	if id == nil {
+		return 0, errors.New(field + " required")
+	}
+	return *id, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ChangeEmployeeStatus переводит сотрудника подразделения deptID в новый статус.
+// При увольнении сохраняются дата (по умолчанию сегодня) и причина; возврат из отпуска их не затрагивает.
+func (s *Service) ChangeEmployeeStatus(deptID, id int, req model.ChangeEmployeeStatusRequest, expectedVersion int) (*model.Employee, error) {
+	emp, err := s.repo.GetEmployeeByID(id)
+	if err != nil || emp.DepartmentID != deptID {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if expectedVersion != 0 && emp.Version != expectedVersion {
+		return nil, ErrVersionMismatch
+	}
+
+	if err := applyEmployeeStatus(emp, req, time.Now()); err != nil {
+		return nil, err
+	}
+	oldStatus := emp.Status
+	emp.Status = req.Status
+
+	scope, err := departmentScope(s.repo, deptID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.UpdateEmployee(emp); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return ErrVersionMismatch
+			}
+			return err
+		}
+		return recordEvent(txRepo, model.EventEmployeeStatus, &deptID, scope, model.EmployeeStatusChangedData{
+			Employee:  emp,
+			OldStatus: oldStatus,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// applyEmployeeStatus проверяет переход и заполняет дату и причину увольнения; статус не меняет
+func applyEmployeeStatus(emp *model.Employee, req model.ChangeEmployeeStatusRequest, now time.Time) error {
+	if !model.ValidEmployeeStatus(req.Status) {
+		return errors.New("invalid status")
+	}
+	if !model.CanTransition(emp.Status, req.Status) {
+		return ErrInvalidTransition
+	}
+	if req.Status != model.EmployeeTerminated {
+		if req.EffectiveDate != nil || req.Reason != nil {
+			return errors.New("effective_date and reason apply only to termination")
+		}
+		return nil
+	}
+
+	terminatedAt := now.UTC().Truncate(24 * time.Hour)
+	if req.EffectiveDate != nil {
+		t, err := time.Parse("2006-01-02", *req.EffectiveDate)
+		if err != nil {
+			return errors.New("invalid date format")
+		}
+		terminatedAt = t
+	}
+	if emp.HiredAt != nil && terminatedAt.Before(*emp.HiredAt) {
+		return errors.New("termination date before hire date")
+	}
+	emp.TerminatedAt = &terminatedAt
+
+	emp.TerminationReason = nil
+	if req.Reason != nil {
+		reason := strings.TrimSpace(*req.Reason)
+		if utf8.RuneCountInString(reason) > 500 {
+			return errors.New("reason too long")
+		}
+		if reason != "" {
+			emp.TerminationReason = &reason
+		}
+	}
+	return nil
+}
+
+// employeeStatuses статусы сотрудников, учитываемые в дереве и численности: по умолчанию только работающие
+func employeeStatuses(statuses []string) []string {
+	if len(statuses) == 0 {
+		return []string{model.EmployeeActive}
+	}
+	return statuses
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"database/sql"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// ExportDirectory возвращает все подразделения и сотрудников организации для выгрузки
+// в каталог; требует роль на всю организацию. Данные читаются одним снимком.
+func (s *Service) ExportDirectory() ([]model.Department, []model.Employee, error) {
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, nil, err
+	}
+
+	var depts []model.Department
+	var employees []model.Employee
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		var err error
+		if depts, err = txRepo.ListDepartments(); err != nil {
+			return err
+		}
+		employees, err = txRepo.ListEmployees()
+		return err
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	return depts, employees, nil
+}
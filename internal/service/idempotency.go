@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// Срок хранения результатов и время, после которого незавершённое резервирование считается брошенным
+const (
+	idempotencyKeyTTL      = 24 * time.Hour
+	idempotencyLockTimeout = 5 * time.Minute
+)
+
+// ReserveIdempotencyKey резервирует ключ для запроса с отпечатком fingerprint.
+// Если ключ уже использован тем же запросом, возвращается запись с сохранённым ответом
+// (Completed() == true); новая резервация возвращается с Completed() == false.
+func (s *Service) ReserveIdempotencyKey(key, fingerprint string) (*model.IdempotencyKey, error) {
+	subject := s.principal.Subject
+	now := time.Now()
+
+	if err := s.repo.DeleteStaleIdempotencyKey(subject, key, now, now.Add(-idempotencyLockTimeout)); err != nil {
+		return nil, err
+	}
+
+	record := &model.IdempotencyKey{
+		Subject:     subject,
+		Key:         key,
+		Fingerprint: fingerprint,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(idempotencyKeyTTL),
+	}
+	reserved, err := s.repo.CreateIdempotencyKey(record)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return record, nil
+	}
+
+	existing, err := s.repo.GetIdempotencyKey(subject, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Ключ освобождён параллельным запросом между вставкой и чтением
+			return nil, ErrIdempotencyKeyInProgress
+		}
+		return nil, err
+	}
+	if existing.Fingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+// CompleteIdempotencyKey сохраняет ответ для повторов запроса
+func (s *Service) CompleteIdempotencyKey(record *model.IdempotencyKey, status int, body []byte, etag string) error {
+	return s.repo.CompleteIdempotencyKey(record.ID, status, body, etag)
+}
+
+// ReleaseIdempotencyKey снимает резервирование, чтобы запрос можно было повторить
+func (s *Service) ReleaseIdempotencyKey(record *model.IdempotencyKey) error {
+	return s.repo.DeleteIdempotencyKey(record.ID)
+}
+
+// PurgeExpiredIdempotencyKeys удаляет истёкшие ключи всех арендаторов
+func (s *Service) PurgeExpiredIdempotencyKeys() (int64, error) {
+	return s.repo.DeleteExpiredIdempotencyKeys(time.Now())
+}
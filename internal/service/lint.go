@@ -0,0 +1,36 @@
+package service
+
+import (
+	"database/sql"
+
+	"github.com/SergeiKhy/org-structure-api/internal/lint"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// LintOrg проверяет всё дерево подразделений правилами cfg; численность считается
+// по работающим сотрудникам. Требует роль viewer на всю организацию.
+func (s *Service) LintOrg(cfg lint.Config) (*lint.Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	var depts []model.Department
+	var headcounts map[int]int
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		var err error
+		if depts, err = txRepo.ListDepartments(); err != nil {
+			return err
+		}
+		headcounts, err = txRepo.CountEmployeesByDepartment(employeeStatuses(nil))
+		return err
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return lint.Run(depts, headcounts, cfg), nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Методы пакетной загрузки для GraphQL. Каждый принимает набор подразделений
+// и выполняет фиксированное число запросов независимо от его размера.
+
+// GetDepartment возвращает подразделение без вложенных сотрудников и потомков
+func (s *Service) GetDepartment(id int) (*model.Department, error) {
+	if err := s.authorize(&id, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	dept, err := s.repo.GetDepartmentByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return dept, nil
+}
+
+// ListRootDepartments возвращает подразделения верхнего уровня; требует роль на всю организацию
+func (s *Service) ListRootDepartments() ([]model.Department, error) {
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRootDepartments()
+}
+
+// LoadDepartments возвращает подразделения по ID
+func (s *Service) LoadDepartments(ids []int) (map[int]model.Department, error) {
+	if err := s.authorizeAll(ids, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	depts, err := s.repo.GetDepartmentsByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int]model.Department, len(depts))
+	for _, d := range depts {
+		result[d.ID] = d
+	}
+	return result, nil
+}
+
+// LoadChildren возвращает непосредственных потомков каждого из подразделений
+func (s *Service) LoadChildren(ids []int) (map[int][]model.Department, error) {
+	if err := s.authorizeAll(ids, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	children, err := s.repo.GetChildrenByParentIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int][]model.Department, len(ids))
+	for _, c := range children {
+		result[*c.ParentID] = append(result[*c.ParentID], c)
+	}
+	return result, nil
+}
+
+// LoadEmployees возвращает сотрудников каждого из подразделений в одном из статусов; по умолчанию — работающих
+func (s *Service) LoadEmployees(ids []int, statuses []string) (map[int][]model.Employee, error) {
+	if err := s.authorizeAll(ids, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	employees, err := s.repo.GetEmployeesByDeptIDs(ids, employeeStatuses(statuses))
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int][]model.Employee, len(ids))
+	for _, e := range employees {
+		result[e.DepartmentID] = append(result[e.DepartmentID], e)
+	}
+	return result, nil
+}
+
+// LoadHeadcounts возвращает численность поддерева каждого из подразделений с учётом сотрудников
+// в одном из статусов; по умолчанию — только работающих
+func (s *Service) LoadHeadcounts(ids []int, statuses []string) (map[int]int, error) {
+	if err := s.authorizeAll(ids, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.CountSubtreeEmployees(ids, employeeStatuses(statuses))
+}
+
+// LoadAncestors возвращает предков каждого из подразделений от корня к родителю.
+// Предки выше области роли субъекта не включаются.
+func (s *Service) LoadAncestors(ids []int) (map[int][]model.Department, error) {
+	all, scopes, err := s.roleScopes(model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	ancestors, err := s.repo.GetAncestors(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]model.Department, len(ids))
+	for _, id := range ids {
+		chain := ancestors[id]
+		if !all && !coveredBy(id, chain, scopes) {
+			return nil, ErrForbidden
+		}
+		// Предок виден, если роль выдана на него или на кого-то выше
+		visible := make([]model.Department, 0, len(chain))
+		covered := all
+		for i := len(chain) - 1; i >= 0; i-- {
+			covered = covered || scopes[chain[i].ID]
+			if covered {
+				visible = append(visible, chain[i])
+			}
+		}
+		result[id] = visible
+	}
+	return result, nil
+}
+
+// authorizeAll проверяет роль на каждое из подразделений; предки загружаются одним запросом
+func (s *Service) authorizeAll(ids []int, required string) error {
+	all, scopes, err := s.roleScopes(required)
+	if err != nil || all {
+		return err
+	}
+	if len(scopes) == 0 {
+		return ErrForbidden
+	}
+
+	// Проверка без запроса предков, если роль выдана на сами подразделения
+	if !slices.ContainsFunc(ids, func(id int) bool { return !scopes[id] }) {
+		return nil
+	}
+	ancestors, err := s.repo.GetAncestors(ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if !coveredBy(id, ancestors[id], scopes) {
+			return ErrForbidden
+		}
+	}
+	return nil
+}
+
+// departmentAccess возвращает проверку роли не ниже required на подразделение из ids;
+// предки загружаются одним запросом. Привязки ролей удаляются вместе с подразделением,
+// поэтому удалённое подразделение доступно только с ролью на всю организацию.
+func (s *Service) departmentAccess(ids []int, required string) (func(id int) bool, error) {
+	all, scopes, err := s.roleScopes(required)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return func(int) bool { return true }, nil
+	}
+	if len(scopes) == 0 {
+		return func(int) bool { return false }, nil
+	}
+	ancestors, err := s.repo.GetAncestors(ids)
+	if err != nil {
+		return nil, err
+	}
+	return func(id int) bool { return coveredBy(id, ancestors[id], scopes) }, nil
+}
+
+// coveredBy сообщает, покрывает ли одна из областей подразделение id с цепочкой предков chain
+func coveredBy(id int, chain []model.Department, scopes map[int]bool) bool {
+	if scopes[id] {
+		return true
+	}
+	for _, d := range chain {
+		if scopes[d.ID] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// Методы для SCIM: поиск по всей организации и изменение состава подразделения
+
+// SearchEmployees возвращает страницу сотрудников по условию SQL и их общее число;
+// требует роль на всю организацию
+func (s *Service) SearchEmployees(where string, args []interface{}, offset, limit int) ([]model.Employee, int64, error) {
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.SearchEmployees(where, args, offset, limit)
+}
+
+// SearchDepartments возвращает страницу подразделений по условию SQL и их общее число;
+// требует роль на всю организацию
+func (s *Service) SearchDepartments(where string, args []interface{}, offset, limit int) ([]model.Department, int64, error) {
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.SearchDepartments(where, args, offset, limit)
+}
+
+// FindEmployee возвращает сотрудника по ID без указания подразделения
+func (s *Service) FindEmployee(id int) (*model.Employee, error) {
+	emp, err := s.repo.GetEmployeeByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&emp.DepartmentID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// CreateDepartmentWithMembers создаёт подразделение и добавляет в него участников в одной транзакции
+func (s *Service) CreateDepartmentWithMembers(req model.CreateDepartmentRequest, changes []model.MembershipChange) (*model.Department, error) {
+	var dept *model.Department
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txSvc := *s
+		txSvc.repo = s.repo.WithTx(tx)
+		created, err := txSvc.CreateDepartment(req)
+		if err != nil {
+			return err
+		}
+		dept, err = txSvc.applyMembership(created, changes)
+		return err
+	})
+	if err != nil {
+		return nil, withoutDeferral(err)
+	}
+	return dept, nil
+}
+
+// UpdateDepartmentMembers применяет изменение подразделения и его состава атомарно:
+// при ошибке любого шага не сохраняется ничего. Перенос, требующий согласования,
+// отклоняется с ErrApprovalNotDeferrable.
+func (s *Service) UpdateDepartmentMembers(id int, patch model.DepartmentPatch, changes []model.MembershipChange, expectedVersion int) (*model.Department, error) {
+	var dept *model.Department
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txSvc := *s
+		txSvc.repo = s.repo.WithTx(tx)
+		current, err := txSvc.GetDepartment(id)
+		if err != nil {
+			return err
+		}
+		if expectedVersion != 0 && current.Version != expectedVersion {
+			return ErrVersionMismatch
+		}
+		if patch.Name.Set || patch.ParentID.Set {
+			if current, err = txSvc.PatchDepartment(id, patch, 0); err != nil {
+				return err
+			}
+		}
+		dept, err = txSvc.applyMembership(current, changes)
+		return err
+	})
+	if err != nil {
+		return nil, withoutDeferral(err)
+	}
+	return dept, nil
+}
+
+// applyMembership переводит сотрудников и переносит подразделения; повторное добавление
+// и удаление отсутствующего участника ничего не меняют
+func (s *Service) applyMembership(dept *model.Department, changes []model.MembershipChange) (*model.Department, error) {
+	for _, c := range changes {
+		if c.Remove {
+			if err := s.removeMember(dept.ID, c); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		switch c.Kind {
+		case model.MemberEmployee:
+			emp, err := s.repo.GetEmployeeByID(c.ID)
+			if err != nil {
+				return nil, ErrNotFound
+			}
+			if emp.DepartmentID != dept.ID {
+				if _, err := s.PatchEmployee(emp.DepartmentID, emp.ID, model.EmployeePatch{DepartmentID: model.Some(dept.ID)}, 0); err != nil {
+					return nil, err
+				}
+			}
+		case model.MemberDepartment:
+			child, err := s.repo.GetDepartmentByID(c.ID)
+			if err != nil {
+				return nil, ErrNotFound
+			}
+			if child.ParentID == nil || *child.ParentID != dept.ID {
+				if _, err := s.PatchDepartment(child.ID, model.DepartmentPatch{ParentID: model.Some(dept.ID)}, 0); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	// Версия и время изменения после всех шагов
+	return s.GetDepartment(dept.ID)
+}
+
+// removeMember переносит дочернее подразделение в корень. Сотрудника нельзя оставить
+// без подразделения — его переводят добавлением в другое.
+func (s *Service) removeMember(deptID int, c model.MembershipChange) error {
+	if c.Kind != model.MemberEmployee {
+		child, err := s.repo.GetDepartmentByID(c.ID)
+		if err == nil && child.ParentID != nil && *child.ParentID == deptID {
+			_, err := s.PatchDepartment(child.ID, model.DepartmentPatch{ParentID: model.Null[int]()}, 0)
+			return err
+		}
+	}
+	if c.Kind != model.MemberDepartment {
+		emp, err := s.repo.GetEmployeeByID(c.ID)
+		if err == nil && emp.DepartmentID == deptID {
+			return ErrEmployeeMembership
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Размер страницы списков подразделений и сотрудников по умолчанию и наибольший
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// departmentCode формат кода: латинская буква, затем 1–31 заглавных букв, цифр, "-" или "_"
+var departmentCode = regexp.MustCompile(`^[A-Z][A-Z0-9_-]{1,31}$`)
+
+// GetDepartmentByCode возвращает подразделение по коду без учёта регистра
+func (s *Service) GetDepartmentByCode(code string) (*model.Department, error) {
+	dept, err := s.repo.GetDepartmentByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&dept.ID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return dept, nil
+}
+
+// ListDepartments возвращает страницу подразделений по фильтру; требует роль на всю организацию
+func (s *Service) ListDepartments(filter model.DepartmentFilter) ([]model.Department, error) {
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	filter.Limit = min(filter.Limit, maxListLimit)
+	filter.Offset = max(filter.Offset, 0)
+	filter.CostCenter = strings.TrimSpace(filter.CostCenter)
+	filter.Query = strings.TrimSpace(filter.Query)
+	attrs, err := s.attributeFilter(model.AttributeEntityDepartment, filter.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	filter.Attributes = attrs
+	return s.repo.FindDepartments(filter)
+}
+
+// ListEmployees возвращает страницу сотрудников по фильтру. Поиск в подразделении требует
+// роли на него, поиск по всей организации — роли на всю организацию.
+func (s *Service) ListEmployees(filter model.EmployeeFilter) ([]model.Employee, error) {
+	if err := s.authorize(filter.DepartmentID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	filter.Limit = min(filter.Limit, maxListLimit)
+	filter.Offset = max(filter.Offset, 0)
+	filter.Query = strings.TrimSpace(filter.Query)
+	attrs, err := s.attributeFilter(model.AttributeEntityEmployee, filter.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	filter.Attributes = attrs
+	return s.repo.FindEmployees(filter)
+}
+
+// metadataPatch собирает частичное изменение метаданных из полей запроса application/json:
+// отсутствующее поле не меняет значения
+func metadataPatch(code, costCenter, description *string, active *bool) model.DepartmentPatch {
+	var patch model.DepartmentPatch
+	if code != nil {
+		patch.Code = model.Some(*code)
+	}
+	if costCenter != nil {
+		patch.CostCenter = model.Some(*costCenter)
+	}
+	if description != nil {
+		patch.Description = model.Some(*description)
+	}
+	if active != nil {
+		patch.Active = model.Some(*active)
+	}
+	return patch
+}
+
+// applyDepartmentMetadata переносит код, центр затрат, описание и признак активности из patch
+// в dept с проверкой. Пустая строка, как и null, очищает значение.
+func (s *Service) applyDepartmentMetadata(dept *model.Department, patch model.DepartmentPatch) error {
+	if patch.Code.Set {
+		dept.Code = nil
+		if !patch.Code.Null && strings.TrimSpace(patch.Code.Value) != "" {
+			code, err := s.checkCode(patch.Code.Value, dept.ID)
+			if err != nil {
+				return err
+			}
+			dept.Code = &code
+		}
+	}
+	if patch.CostCenter.Set {
+		dept.CostCenter = nil
+		if !patch.CostCenter.Null {
+			costCenter := strings.TrimSpace(patch.CostCenter.Value)
+			if utf8.RuneCountInString(costCenter) > 50 {
+				return errors.New("invalid cost center")
+			}
+			if costCenter != "" {
+				dept.CostCenter = &costCenter
+			}
+		}
+	}
+	if patch.Description.Set {
+		dept.Description = nil
+		if !patch.Description.Null {
+			description := strings.TrimSpace(patch.Description.Value)
+			if utf8.RuneCountInString(description) > 2000 {
+				return errors.New("invalid description")
+			}
+			if description != "" {
+				dept.Description = &description
+			}
+		}
+	}
+	if patch.Active.Set {
+		if patch.Active.Null {
+			return errors.New("active cannot be null")
+		}
+		dept.Active = patch.Active.Value
+	}
+	return nil
+}
+
+// checkCode приводит код к верхнему регистру и проверяет формат и уникальность в организации
+func (s *Service) checkCode(code string, excludeID int) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !departmentCode.MatchString(code) {
+		return "", errors.New("invalid code")
+	}
+	unique, err := s.repo.CheckUniqueCode(code, excludeID)
+	if err != nil {
+		return "", err
+	}
+	if !unique {
+		return "", ErrDuplicateCode
+	}
+	return code, nil
+}
+
+// sameMetadata сравнивает поля подразделения, изменение которых публикуется как department.updated
+func sameMetadata(a, b *model.Department) bool {
+	return a.Name == b.Name && sameString(a.Code, b.Code) && sameString(a.CostCenter, b.CostCenter) &&
+		sameString(a.Description, b.Description) && a.Active == b.Active && reflect.DeepEqual(a.Attributes, b.Attributes)
+}
+
+func sameString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+)
+
+// recordEvent записывает событие в outbox через переданный репозиторий.
+// Вызывается с транзакционным репозиторием, поэтому событие фиксируется
+// вместе с изменением и не теряется при сбое между коммитом и публикацией.
+// scope — подразделение и его предки, см. departmentScope.
+func recordEvent(repo *repository.Repository, eventType string, departmentID *int, scope []int, data any) error {
+	eventID, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	payload, err := json.Marshal(model.Event{ID: eventID, Type: eventType, OccurredAt: now, Data: data})
+	if err != nil {
+		return err
+	}
+
+	return repo.CreateOutboxEvent(&model.OutboxEvent{
+		EventID:        eventID,
+		EventType:      eventType,
+		DepartmentID:   departmentID,
+		DepartmentPath: scope,
+		Payload:        payload,
+		CreatedAt:      now,
+	})
+}
+
+// departmentScope возвращает подразделение вместе с цепочкой его предков
+func departmentScope(repo *repository.Repository, id int) ([]int, error) {
+	parents, err := repo.GetParentChain(id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]int{id}, parents...), nil
+}
+
+// mergeScopes объединяет области видимости без повторов (для перемещения — старая и новая)
+func mergeScopes(a, b []int) []int {
+	merged := append([]int{}, a...)
+	for _, id := range b {
+		if !slices.Contains(merged, id) {
+			merged = append(merged, id)
+		}
+	}
+	return merged
+}
+
+// Максимальное число событий ленты за один запрос
+const maxEventFeedLimit = 500
+
+// ListEvents возвращает опубликованные события после номера after.
+// rootID ограничивает ленту поддеревом и требует права просмотра на него.
+func (s *Service) ListEvents(after int, rootID *int, eventTypes []string, limit int) ([]model.OutboxEvent, error) {
+	for _, t := range eventTypes {
+		if t == model.EventAll || !model.ValidEventType(t) {
+			return nil, errors.New("invalid event type: " + t)
+		}
+	}
+	if err := s.authorize(rootID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > maxEventFeedLimit {
+		limit = maxEventFeedLimit
+	}
+	return s.repo.ListPublishedOutboxEvents(after, rootID, eventTypes, limit)
+}
+
+// LatestEventSeq номер последнего опубликованного события — точка старта для новых подписчиков
+func (s *Service) LatestEventSeq(rootID *int) (int, error) {
+	if err := s.authorize(rootID, model.RoleViewer); err != nil {
+		return 0, err
+	}
+	return s.repo.GetLatestOutboxSeq()
+}
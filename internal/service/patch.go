@@ -0,0 +1,337 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/jsonpatch"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ApplyDepartmentJSONPatch применяет JSON Patch (RFC 6902) к документу
+// {"name", "parent_id", "code", "cost_center", "description", "active", "attributes"}.
+// Удаление поля равносильно null: для parent_id — перенос в корень, для attributes — удаление всех атрибутов.
+func (s *Service) ApplyDepartmentJSONPatch(id int, ops []byte, expectedVersion int) (*model.Department, error) {
+	dept, err := s.repo.GetDepartmentByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&id, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	// Патч вычислен для прочитанной версии: параллельное изменение должно его отклонить
+	if expectedVersion == 0 {
+		expectedVersion = dept.Version
+	}
+
+	doc, err := json.Marshal(model.DepartmentDocument{
+		Name:        dept.Name,
+		ParentID:    dept.ParentID,
+		Code:        dept.Code,
+		CostCenter:  dept.CostCenter,
+		Description: dept.Description,
+		Active:      dept.Active,
+		Attributes:  documentAttributes(dept.Attributes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := jsonpatch.Apply(doc, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch model.DepartmentPatch
+	if err := decodeDocument(result, &patch); err != nil {
+		return nil, err
+	}
+	if !patch.Name.Set {
+		patch.Name = model.Null[string]()
+	}
+	if !patch.ParentID.Set {
+		patch.ParentID = model.Null[int]()
+	}
+	if !patch.Code.Set {
+		patch.Code = model.Null[string]()
+	}
+	if !patch.CostCenter.Set {
+		patch.CostCenter = model.Null[string]()
+	}
+	if !patch.Description.Set {
+		patch.Description = model.Null[string]()
+	}
+	if !patch.Active.Set {
+		patch.Active = model.Null[bool]()
+	}
+	replaceAttributes(dept.Attributes, &patch.Attributes)
+	return s.PatchDepartment(id, patch, expectedVersion)
+}
+
+// GetEmployee возвращает сотрудника подразделения deptID
+func (s *Service) GetEmployee(deptID, id int) (*model.Employee, error) {
+	if err := s.authorize(&deptID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	emp, err := s.repo.GetEmployeeByID(id)
+	if err != nil || emp.DepartmentID != deptID {
+		return nil, ErrNotFound
+	}
+	return emp, nil
+}
+
+// PatchEmployee применяет частичное изменение (RFC 7396) к сотруднику подразделения deptID.
+// department_id переводит сотрудника и требует прав в обоих подразделениях.
+func (s *Service) PatchEmployee(deptID, id int, patch model.EmployeePatch, expectedVersion int) (*model.Employee, error) {
+	emp, err := s.repo.GetEmployeeByID(id)
+	if err != nil || emp.DepartmentID != deptID {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if expectedVersion != 0 && emp.Version != expectedVersion {
+		return nil, ErrVersionMismatch
+	}
+
+	oldDeptID := emp.DepartmentID
+
+	if patch.FullName.Set {
+		emp.FullName = ""
+		if !patch.FullName.Null {
+			emp.FullName = validateName(patch.FullName.Value)
+		}
+	}
+	if patch.Position.Set {
+		emp.Position = ""
+		if !patch.Position.Null {
+			emp.Position = validateName(patch.Position.Value)
+		}
+	}
+	if patch.PositionID.Set && patch.PositionID.Null {
+		// Отвязка от каталога сохраняет название
+		emp.PositionID = nil
+	} else if patch.PositionID.Set {
+		if !patch.Position.Set {
+			emp.Position = ""
+		}
+		if err := s.linkPosition(emp, &patch.PositionID.Value); err != nil {
+			return nil, err
+		}
+	} else if patch.Position.Set {
+		if err := s.linkPosition(emp, nil); err != nil {
+			return nil, err
+		}
+	}
+	if emp.FullName == "" || emp.Position == "" || len(emp.FullName) > 200 || len(emp.Position) > 200 {
+		return nil, errors.New("invalid fields")
+	}
+
+	if patch.HiredAt.Set {
+		emp.HiredAt = nil
+		if !patch.HiredAt.Null {
+			t, err := time.Parse("2006-01-02", patch.HiredAt.Value)
+			if err != nil {
+				return nil, errors.New("invalid date format")
+			}
+			emp.HiredAt = &t
+		}
+	}
+
+	if patch.UserName.Set {
+		emp.UserName = nil
+		if !patch.UserName.Null {
+			userName, err := s.checkUserName(patch.UserName.Value, emp.ID)
+			if err != nil {
+				return nil, err
+			}
+			emp.UserName = &userName
+		}
+	}
+	if patch.ExternalID.Set {
+		emp.ExternalID = nil
+		if !patch.ExternalID.Null {
+			externalID, err := validateExternalID(patch.ExternalID.Value)
+			if err != nil {
+				return nil, err
+			}
+			emp.ExternalID = &externalID
+		}
+	}
+	if patch.Attributes.Set {
+		if emp.Attributes, err = s.mergeAttributes(model.AttributeEntityEmployee, emp.Attributes, patch.Attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	if patch.DepartmentID.Set && (patch.DepartmentID.Null || patch.DepartmentID.Value != oldDeptID) {
+		if patch.DepartmentID.Null {
+			return nil, errors.New("department_id cannot be null")
+		}
+		target := patch.DepartmentID.Value
+		if _, err := s.repo.GetDepartmentByID(target); err != nil {
+			return nil, ErrNotFound
+		}
+		if err := s.authorize(&target, model.RoleEditor); err != nil {
+			return nil, err
+		}
+		emp.DepartmentID = target
+	}
+
+	oldScope, err := departmentScope(s.repo, oldDeptID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.UpdateEmployee(emp); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return ErrVersionMismatch
+			}
+			return err
+		}
+		scope, err := departmentScope(txRepo, emp.DepartmentID)
+		if err != nil {
+			return err
+		}
+		return recordEvent(txRepo, model.EventEmployeeUpdated, &emp.DepartmentID, mergeScopes(oldScope, scope), model.EmployeeUpdatedData{
+			Employee:        emp,
+			OldDepartmentID: oldDeptID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return emp, nil
+}
+
+// ApplyEmployeeJSONPatch применяет JSON Patch (RFC 6902) к документу
+// {"full_name", "position", "position_id", "hired_at", "department_id", "user_name", "external_id", "attributes"}
+func (s *Service) ApplyEmployeeJSONPatch(deptID, id int, ops []byte, expectedVersion int) (*model.Employee, error) {
+	emp, err := s.repo.GetEmployeeByID(id)
+	if err != nil || emp.DepartmentID != deptID {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if expectedVersion == 0 {
+		expectedVersion = emp.Version
+	}
+
+	current := model.EmployeeDocument{
+		FullName:     emp.FullName,
+		Position:     emp.Position,
+		PositionID:   emp.PositionID,
+		DepartmentID: emp.DepartmentID,
+		UserName:     emp.UserName,
+		ExternalID:   emp.ExternalID,
+		Attributes:   documentAttributes(emp.Attributes),
+	}
+	if emp.HiredAt != nil {
+		hiredAt := emp.HiredAt.Format("2006-01-02")
+		current.HiredAt = &hiredAt
+	}
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	result, err := jsonpatch.Apply(doc, ops)
+	if err != nil {
+		return nil, err
+	}
+
+	var patch model.EmployeePatch
+	if err := decodeDocument(result, &patch); err != nil {
+		return nil, err
+	}
+	if !patch.FullName.Set {
+		patch.FullName = model.Null[string]()
+	}
+	if !patch.Position.Set {
+		patch.Position = model.Null[string]()
+	}
+	if !patch.PositionID.Set {
+		patch.PositionID = model.Null[int]()
+	}
+	if !patch.HiredAt.Set {
+		patch.HiredAt = model.Null[string]()
+	}
+	if !patch.DepartmentID.Set {
+		patch.DepartmentID = model.Null[int]()
+	}
+	if !patch.UserName.Set {
+		patch.UserName = model.Null[string]()
+	}
+	if !patch.ExternalID.Set {
+		patch.ExternalID = model.Null[string]()
+	}
+	replaceAttributes(emp.Attributes, &patch.Attributes)
+	return s.PatchEmployee(deptID, id, patch, expectedVersion)
+}
+
+// reservedUserName логины вида employee-<id> подставляются сотрудникам без собственного логина
+var reservedUserName = regexp.MustCompile(`^(?i)employee-[0-9]+$`)
+
+// checkUserName проверяет логин сотрудника: непустой, до 200 символов, не зарезервирован и не занят
+func (s *Service) checkUserName(userName string, excludeID int) (string, error) {
+	userName = strings.TrimSpace(userName)
+	if userName == "" || len(userName) > 200 || reservedUserName.MatchString(userName) {
+		return "", errors.New("invalid user name")
+	}
+	unique, err := s.repo.CheckUniqueUserName(userName, excludeID)
+	if err != nil {
+		return "", err
+	}
+	if !unique {
+		return "", ErrDuplicateUserName
+	}
+	return userName, nil
+}
+
+func validateExternalID(externalID string) (string, error) {
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" || len(externalID) > 200 {
+		return "", errors.New("invalid external id")
+	}
+	return externalID, nil
+}
+
+// documentAttributes атрибуты для документа JSON Patch: отсутствие значений — пустой объект,
+// чтобы к нему можно было добавлять атрибуты по пути /attributes/<name>
+func documentAttributes(attrs model.Attributes) model.Attributes {
+	if attrs == nil {
+		return model.Attributes{}
+	}
+	return attrs
+}
+
+// replaceAttributes превращает итоговые атрибуты документа в частичное изменение:
+// атрибуты, исчезнувшие из документа, удаляются, отсутствие объекта удаляет все атрибуты
+func replaceAttributes(current model.Attributes, patch *model.Optional[*model.Attributes]) {
+	if !patch.Set || patch.Value == nil {
+		*patch = model.Null[*model.Attributes]()
+		return
+	}
+	for name := range current {
+		if _, ok := (*patch.Value)[name]; !ok {
+			(*patch.Value)[name] = nil
+		}
+	}
+}
+
+// decodeDocument разбирает документ после JSON Patch; посторонние поля — ошибка
+func decodeDocument(doc []byte, patch any) error {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(patch); err != nil {
+		return errors.New("invalid document: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// ListPositions возвращает каталог должностей; доступен субъекту с ролью viewer на любое подразделение,
+// чтобы редакторы отдельных подразделений могли выбирать должности сотрудников
+func (s *Service) ListPositions(jobFamily string) ([]model.Position, error) {
+	if err := s.authorizeAny(model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListPositions(strings.TrimSpace(jobFamily))
+}
+
+func (s *Service) GetPosition(id int) (*model.Position, error) {
+	if err := s.authorizeAny(model.RoleViewer); err != nil {
+		return nil, err
+	}
+	pos, err := s.repo.GetPositionByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return pos, nil
+}
+
+// CreatePosition добавляет должность в каталог; требует роль администратора организации
+func (s *Service) CreatePosition(req model.CreatePositionRequest) (*model.Position, error) {
+	pos := &model.Position{}
+	if err := applyPosition(pos, &req.Title, req.JobFamily, req.Grade); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if err := s.checkPositionKey(pos.TitleKey, 0); err != nil {
+		return nil, err
+	}
+
+	pos.CreatedAt = time.Now()
+	pos.UpdatedAt = pos.CreatedAt
+	if err := s.repo.CreatePosition(pos); err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// UpdatePosition изменяет должность; новое название переносится в записи сотрудников
+func (s *Service) UpdatePosition(id int, req model.UpdatePositionRequest) (*model.Position, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	pos, err := s.repo.GetPositionByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := applyPosition(pos, req.Title, req.JobFamily, req.Grade); err != nil {
+		return nil, err
+	}
+	if err := s.checkPositionKey(pos.TitleKey, pos.ID); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		return s.repo.WithTx(tx).UpdatePosition(pos)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pos, nil
+}
+
+// DeletePosition удаляет должность, на которую не ссылаются ни сотрудники, ни вакансии
+func (s *Service) DeletePosition(id int) error {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetPositionByID(id); err != nil {
+		return ErrNotFound
+	}
+	count, err := s.repo.CountPositionEmployees(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPositionInUse
+	}
+	if count, err = s.repo.CountPositionRequisitions(id); err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrPositionInUse
+	}
+	return s.repo.DeletePosition(id)
+}
+
+// PositionReport численность сотрудников по семействам должностей и грейдам.
+// deptID ограничивает отчёт поддеревом (роль viewer на него), nil — вся организация;
+// пустой список статусов — только работающие сотрудники.
+func (s *Service) PositionReport(deptID *int, statuses []string) (*model.PositionReport, error) {
+	if deptID != nil {
+		if _, err := s.repo.GetDepartmentByID(*deptID); err != nil {
+			return nil, ErrNotFound
+		}
+	}
+	if err := s.authorize(deptID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.PositionReport(deptID, employeeStatuses(statuses))
+}
+
+// applyPosition проверяет и применяет поля должности; nil не меняет поле,
+// пустая строка job_family и grade = 0 сбрасывают значение
+func applyPosition(pos *model.Position, title, jobFamily *string, grade *int) error {
+	if title != nil {
+		t := validateName(*title)
+		key := model.PositionKey(t)
+		if t == "" || key == "" || utf8.RuneCountInString(t) > 200 {
+			return errors.New("invalid title")
+		}
+		pos.Title, pos.TitleKey = t, key
+	}
+	if jobFamily != nil {
+		family := strings.TrimSpace(*jobFamily)
+		if utf8.RuneCountInString(family) > 100 {
+			return errors.New("invalid job family")
+		}
+		pos.JobFamily = nil
+		if family != "" {
+			pos.JobFamily = &family
+		}
+	}
+	if grade != nil {
+		if *grade != 0 && (*grade < model.MinGrade || *grade > model.MaxGrade) {
+			return errors.New("invalid grade")
+		}
+		pos.Grade = nil
+		if *grade != 0 {
+			g := *grade
+			pos.Grade = &g
+		}
+	}
+	return nil
+}
+
+func (s *Service) checkPositionKey(key string, excludeID int) error {
+	ok, err := s.repo.CheckUniquePositionKey(key, excludeID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDuplicatePosition
+	}
+	return nil
+}
+
+// linkPosition связывает сотрудника с должностью каталога. positionID задаёт должность явно
+// (название сотрудника берётся из каталога), иначе должность ищется по нормализованному названию;
+// без совпадения сотрудник остаётся с названием вне каталога.
+func (s *Service) linkPosition(emp *model.Employee, positionID *int) error {
+	if positionID != nil {
+		pos, err := s.repo.GetPositionByID(*positionID)
+		if err != nil {
+			return errors.New("position not found")
+		}
+		if emp.Position != "" && model.PositionKey(emp.Position) != pos.TitleKey {
+			return errors.New("position does not match position_id")
+		}
+		emp.PositionID, emp.Position = &pos.ID, pos.Title
+		return nil
+	}
+
+	emp.PositionID = nil
+	key := model.PositionKey(emp.Position)
+	if key == "" {
+		return nil
+	}
+	pos, err := s.repo.GetPositionByKey(key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	emp.PositionID, emp.Position = &pos.ID, pos.Title
+	return nil
+}
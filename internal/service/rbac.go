@@ -0,0 +1,160 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// WithPrincipal возвращает копию сервиса, выполняющую операции от имени субъекта
+// в рамках его арендатора
+func (s *Service) WithPrincipal(p auth.Principal) *Service {
+	scoped := *s
+	scoped.principal = p
+	if p.TenantID != 0 {
+		scoped.repo = s.repo.WithTenant(p.TenantID)
+	}
+	return &scoped
+}
+
+// authorize проверяет, что у текущего субъекта есть роль не ниже required
+// на подразделение deptID (nil — уровень всей организации).
+// Роль, выданная на подразделение, распространяется на всё его поддерево.
+func (s *Service) authorize(deptID *int, required string) error {
+	all, scopes, err := s.roleScopes(required)
+	if err != nil {
+		return err
+	}
+	if all {
+		return nil
+	}
+	if deptID == nil || len(scopes) == 0 {
+		return ErrForbidden
+	}
+	if scopes[*deptID] {
+		return nil
+	}
+
+	// Роль на любого из предков покрывает deptID
+	parents, err := s.repo.GetParentChain(*deptID)
+	if err != nil {
+		return err
+	}
+	for _, pID := range parents {
+		if scopes[pID] {
+			return nil
+		}
+	}
+	return ErrForbidden
+}
+
+// authorizeAny проверяет, что у субъекта есть роль не ниже required хотя бы на одно подразделение
+func (s *Service) authorizeAny(required string) error {
+	all, scopes, err := s.roleScopes(required)
+	if err != nil {
+		return err
+	}
+	if !all && len(scopes) == 0 {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// roleScopes возвращает подразделения, на которые у субъекта есть роль не ниже required.
+// all == true означает роль на всю организацию.
+func (s *Service) roleScopes(required string) (bool, map[int]bool, error) {
+	if s.principal.Superuser {
+		return true, nil, nil
+	}
+	if s.principal.Subject == "" {
+		return false, nil, nil
+	}
+
+	// API-ключи действуют на всю организацию в пределах своей области
+	switch s.principal.Scope {
+	case auth.ScopeReadOnly:
+		return model.RoleSatisfies(model.RoleViewer, required), nil, nil
+	case auth.ScopeReadWrite:
+		return model.RoleSatisfies(model.RoleEditor, required), nil, nil
+	}
+
+	bindings, err := s.repo.GetRoleBindingsBySubject(s.principal.Subject)
+	if err != nil {
+		return false, nil, err
+	}
+
+	scopes := make(map[int]bool)
+	for _, b := range bindings {
+		if !model.RoleSatisfies(b.Role, required) {
+			continue
+		}
+		if b.DepartmentID == nil {
+			return true, nil, nil
+		}
+		scopes[*b.DepartmentID] = true
+	}
+	return false, scopes, nil
+}
+
+func (s *Service) ListRoleBindings(subject string, departmentID *int) ([]model.RoleBinding, error) {
+	if err := s.authorize(departmentID, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRoleBindings(strings.TrimSpace(subject), departmentID)
+}
+
+func (s *Service) CreateRoleBinding(req model.CreateRoleBindingRequest) (*model.RoleBinding, error) {
+	subject := validateName(req.Subject)
+	if subject == "" || len(subject) > 200 {
+		return nil, errors.New("invalid subject")
+	}
+	if !model.ValidRole(req.Role) {
+		return nil, errors.New("invalid role")
+	}
+
+	if req.DepartmentID != nil {
+		if *req.DepartmentID == 0 {
+			req.DepartmentID = nil
+		} else if _, err := s.repo.GetDepartmentByID(*req.DepartmentID); err != nil {
+			return nil, ErrNotFound
+		}
+	}
+
+	// Выдавать роли на поддерево может только его администратор
+	if err := s.authorize(req.DepartmentID, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.repo.CheckUniqueRoleBinding(subject, req.Role, req.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrDuplicateRoleBinding
+	}
+
+	binding := &model.RoleBinding{
+		Subject:      subject,
+		Role:         req.Role,
+		DepartmentID: req.DepartmentID,
+		CreatedAt:    time.Now(),
+	}
+	if err := s.repo.CreateRoleBinding(binding); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+func (s *Service) DeleteRoleBinding(id int) error {
+	binding, err := s.repo.GetRoleBindingByID(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	if err := s.authorize(binding.DepartmentID, model.RoleAdmin); err != nil {
+		return err
+	}
+	return s.repo.DeleteRoleBinding(id)
+}
@@ -0,0 +1,752 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"gorm.io/gorm"
+)
+
+// Максимальное число операций в сценарии
+const maxScenarioOperations = 500
+
+// ListScenarios возвращает сценарии реорганизации арендатора: все — субъекту с ролью viewer
+// на всю организацию, иначе только его собственные
+func (s *Service) ListScenarios() ([]model.Scenario, error) {
+	all, scopes, err := s.roleScopes(model.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		return s.repo.ListScenarios("")
+	}
+	if len(scopes) == 0 {
+		return nil, ErrForbidden
+	}
+	return s.repo.ListScenarios(s.principal.Subject)
+}
+
+func (s *Service) GetScenario(id int) (*model.Scenario, error) {
+	if err := s.authorizeAny(model.RoleViewer); err != nil {
+		return nil, err
+	}
+	sc, err := s.repo.GetScenarioByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorizeScenario(sc, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// CreateScenario создаёт пустой черновик; доступен субъекту с ролью editor на любое подразделение
+func (s *Service) CreateScenario(req model.CreateScenarioRequest) (*model.Scenario, error) {
+	name := validateName(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > 200 {
+		return nil, errors.New("invalid name")
+	}
+	if req.Description != nil && utf8.RuneCountInString(*req.Description) > 2000 {
+		return nil, errors.New("description too long")
+	}
+	if err := s.authorizeAny(model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sc := &model.Scenario{
+		Name:               name,
+		Description:        req.Description,
+		Status:             model.ScenarioDraft,
+		Operations:         []model.ScenarioOperation{},
+		DepartmentVersions: map[int]int{},
+		EmployeeVersions:   map[int]int{},
+		CreatedBy:          s.principal.Subject,
+		Version:            1,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := s.repo.CreateScenario(sc); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// DeleteScenario удаляет сценарий; доступно автору и субъекту с ролью editor на всю организацию
+func (s *Service) DeleteScenario(id int) error {
+	if err := s.authorizeAny(model.RoleEditor); err != nil {
+		return err
+	}
+	sc, err := s.repo.GetScenarioByID(id)
+	if err != nil {
+		return ErrNotFound
+	}
+	if err := s.authorizeScenario(sc, model.RoleEditor); err != nil {
+		return err
+	}
+	return s.repo.DeleteScenario(id)
+}
+
+// AddScenarioOperation добавляет операцию в конец черновика. Операция должна применяться
+// к прогнозируемой оргструктуре; версии затронутых записей запоминаются для поиска конфликтов.
+func (s *Service) AddScenarioOperation(id int, op model.ScenarioOperation, expectedVersion int) (*model.Scenario, error) {
+	sc, err := s.draftScenario(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if len(sc.Operations) >= maxScenarioOperations {
+		return nil, fmt.Errorf("too many operations (max %d)", maxScenarioOperations)
+	}
+
+	live, err := s.loadOrgState()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeScenarioOperation(live, op); err != nil {
+		return nil, err
+	}
+	projected := live.clone()
+	for _, prev := range sc.Operations {
+		// Операции, ставшие неприменимыми, пропускаются так же, как в прогнозе
+		projected.apply(prev)
+	}
+	if err := projected.apply(op); err != nil {
+		return nil, err
+	}
+
+	sc.Operations = append(sc.Operations, op)
+	recordScenarioVersions(sc, live, op)
+	if err := s.saveScenario(sc); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// RemoveScenarioOperation удаляет операцию черновика по индексу
+func (s *Service) RemoveScenarioOperation(id, index int, expectedVersion int) (*model.Scenario, error) {
+	sc, err := s.draftScenario(id, expectedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if index < 0 || index >= len(sc.Operations) {
+		return nil, ErrNotFound
+	}
+
+	sc.Operations = slices.Delete(sc.Operations, index, index+1)
+	// Версии записей, на которые больше не ссылается ни одна операция, не нужны для поиска конфликтов
+	departments, employees := scenarioEntities(sc.Operations)
+	maps.DeleteFunc(sc.DepartmentVersions, func(id, _ int) bool { return !slices.Contains(departments, id) })
+	maps.DeleteFunc(sc.EmployeeVersions, func(id, _ int) bool { return !slices.Contains(employees, id) })
+	if err := s.saveScenario(sc); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// GetScenarioTree возвращает прогнозируемую оргструктуру после применения сценария.
+// Неприменимые операции пропускаются и попадают в конфликты. Требует роль viewer на всю организацию.
+func (s *Service) GetScenarioTree(id int, includeEmployees bool) (*model.ScenarioTree, error) {
+	sc, live, err := s.scenarioWithLiveState(id)
+	if err != nil {
+		return nil, err
+	}
+	projected, conflicts := projectScenario(sc, live)
+	return &model.ScenarioTree{
+		Departments: projected.tree(includeEmployees),
+		Conflicts:   conflicts,
+	}, nil
+}
+
+// GetScenarioDiff сравнивает прогноз сценария с рабочими данными
+func (s *Service) GetScenarioDiff(id int) (*model.ScenarioDiff, error) {
+	sc, live, err := s.scenarioWithLiveState(id)
+	if err != nil {
+		return nil, err
+	}
+	projected, conflicts := projectScenario(sc, live)
+	return &model.ScenarioDiff{
+		Changes:   diffOrgState(live, projected),
+		Conflicts: conflicts,
+	}, nil
+}
+
+// ApplyScenario применяет все операции сценария в одной транзакции через методы сервиса,
+// поэтому действуют те же проверки прав и события outbox. Затронутые записи блокируются;
+// при конфликтах с рабочими данными ничего не меняется и возвращаются ErrScenarioConflict и список конфликтов.
+func (s *Service) ApplyScenario(id int, expectedVersion int) (*model.Scenario, []model.ScenarioConflict, error) {
+	var sc *model.Scenario
+	var conflicts []model.ScenarioConflict
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		// Методы сервиса внутри сценария открывают вложенные транзакции (точки сохранения)
+		txSvc := *s
+		txSvc.repo = s.repo.WithTx(tx)
+
+		var err error
+		if sc, err = txSvc.repo.GetScenarioForUpdate(id); err != nil {
+			return ErrNotFound
+		}
+		if err := txSvc.authorizeScenario(sc, model.RoleEditor); err != nil {
+			return err
+		}
+		if sc.Status != model.ScenarioDraft {
+			return ErrScenarioApplied
+		}
+		if expectedVersion != 0 && sc.Version != expectedVersion {
+			return ErrVersionMismatch
+		}
+		if len(sc.Operations) == 0 {
+			return errors.New("scenario has no operations")
+		}
+
+		// Блокировка затронутых записей исключает их изменение между проверкой и применением
+		if _, err := txSvc.repo.LockDepartmentVersions(slices.Collect(maps.Keys(sc.DepartmentVersions))); err != nil {
+			return err
+		}
+		if _, err := txSvc.repo.LockEmployeeVersions(slices.Collect(maps.Keys(sc.EmployeeVersions))); err != nil {
+			return err
+		}
+		live, err := txSvc.loadOrgState()
+		if err != nil {
+			return err
+		}
+		if _, conflicts = projectScenario(sc, live); len(conflicts) > 0 {
+			return ErrScenarioConflict
+		}
+
+		for i, op := range sc.Operations {
+			if err := txSvc.executeOperation(op); err != nil {
+				return fmt.Errorf("operation %d: %w", i, withoutDeferral(err))
+			}
+		}
+
+		now := time.Now()
+		sc.Status = model.ScenarioApplied
+		sc.AppliedAt = &now
+		if err := txSvc.repo.UpdateScenario(sc); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return ErrVersionMismatch
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if err != ErrScenarioConflict {
+			conflicts = nil
+		}
+		return nil, conflicts, err
+	}
+	return sc, nil, nil
+}
+
+// executeOperation выполняет операцию сценария или запланированного изменения через методы сервиса
+func (s *Service) executeOperation(op model.ScenarioOperation) error {
+	switch op.Op {
+	case model.ScenarioMoveDepartment:
+		patch := model.DepartmentPatch{ParentID: model.Null[int]()}
+		if op.ParentID != nil {
+			patch.ParentID = model.Some(*op.ParentID)
+		}
+		_, err := s.PatchDepartment(op.DepartmentID, patch, 0)
+		return err
+
+	case model.ScenarioRenameDepartment:
+		_, err := s.PatchDepartment(op.DepartmentID, model.DepartmentPatch{Name: model.Some(op.Name)}, 0)
+		return err
+
+	case model.ScenarioMergeDepartments:
+		children, err := s.repo.GetChildrenByParentIDs([]int{op.DepartmentID})
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if _, err := s.PatchDepartment(child.ID, model.DepartmentPatch{ParentID: model.Some(op.TargetID)}, 0); err != nil {
+				return err
+			}
+		}
+		return s.DeleteDepartment(op.DepartmentID, "reassign", &op.TargetID, 0)
+
+	case model.ScenarioTransferEmployee:
+		emp, err := s.repo.GetEmployeeByID(op.EmployeeID)
+		if err != nil {
+			return ErrNotFound
+		}
+		_, err = s.PatchEmployee(emp.DepartmentID, emp.ID, model.EmployeePatch{DepartmentID: model.Some(op.DepartmentID)}, 0)
+		return err
+	}
+	return errors.New("unknown op: " + op.Op)
+}
+
+// draftScenario читает черновик для изменения; доступно автору с ролью editor на любое
+// подразделение и субъекту с ролью editor на всю организацию
+func (s *Service) draftScenario(id int, expectedVersion int) (*model.Scenario, error) {
+	if err := s.authorizeAny(model.RoleEditor); err != nil {
+		return nil, err
+	}
+	sc, err := s.repo.GetScenarioByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorizeScenario(sc, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if sc.Status != model.ScenarioDraft {
+		return nil, ErrScenarioApplied
+	}
+	if expectedVersion != 0 && sc.Version != expectedVersion {
+		return nil, ErrVersionMismatch
+	}
+	return sc, nil
+}
+
+// authorizeScenario проверяет доступ к сценарию: автору достаточно роли required на любое
+// подразделение, остальным нужна роль required на всю организацию
+func (s *Service) authorizeScenario(sc *model.Scenario, required string) error {
+	if s.principal.Subject != "" && sc.CreatedBy == s.principal.Subject {
+		return s.authorizeAny(required)
+	}
+	return s.authorize(nil, required)
+}
+
+func (s *Service) saveScenario(sc *model.Scenario) error {
+	if err := s.repo.UpdateScenario(sc); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return ErrVersionMismatch
+		}
+		return err
+	}
+	return nil
+}
+
+// scenarioWithLiveState читает сценарий и рабочие данные для прогноза. Прогноз показывает
+// оргструктуру целиком, поэтому требует роль viewer на всю организацию даже от автора.
+func (s *Service) scenarioWithLiveState(id int) (*model.Scenario, *orgState, error) {
+	if err := s.authorize(nil, model.RoleViewer); err != nil {
+		return nil, nil, err
+	}
+	sc, err := s.repo.GetScenarioByID(id)
+	if err != nil {
+		return nil, nil, ErrNotFound
+	}
+	live, err := s.loadOrgState()
+	if err != nil {
+		return nil, nil, err
+	}
+	return sc, live, nil
+}
+
+// loadOrgState читает подразделения и сотрудников одним согласованным снимком
+func (s *Service) loadOrgState() (*orgState, error) {
+	var depts []model.Department
+	var employees []model.Employee
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		var err error
+		if depts, err = txRepo.ListDepartments(); err != nil {
+			return err
+		}
+		employees, err = txRepo.ListEmployees()
+		return err
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	return newOrgState(depts, employees), nil
+}
+
+// authorizeScenarioOperation требует роль editor на подразделения, которые затрагивает операция,
+// как при её непосредственном выполнении
+func (s *Service) authorizeScenarioOperation(live *orgState, op model.ScenarioOperation) error {
+	var targets []*int
+	switch op.Op {
+	case model.ScenarioMoveDepartment:
+		targets = []*int{&op.DepartmentID, op.ParentID}
+	case model.ScenarioRenameDepartment:
+		targets = []*int{&op.DepartmentID}
+	case model.ScenarioMergeDepartments:
+		targets = []*int{&op.DepartmentID, &op.TargetID}
+	case model.ScenarioTransferEmployee:
+		emp, ok := live.employees[op.EmployeeID]
+		if !ok {
+			return ErrNotFound
+		}
+		targets = []*int{&emp.DepartmentID, &op.DepartmentID}
+	default:
+		return errors.New("unknown op: " + op.Op)
+	}
+	for _, deptID := range targets {
+		if err := s.authorize(deptID, model.RoleEditor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordScenarioVersions запоминает версии записей, впервые затронутых операцией
+func recordScenarioVersions(sc *model.Scenario, live *orgState, op model.ScenarioOperation) {
+	departments, employees := scenarioEntities([]model.ScenarioOperation{op})
+	for _, id := range departments {
+		if _, ok := sc.DepartmentVersions[id]; !ok {
+			sc.DepartmentVersions[id] = live.depts[id].Version
+		}
+	}
+	for _, id := range employees {
+		if _, ok := sc.EmployeeVersions[id]; !ok {
+			sc.EmployeeVersions[id] = live.employees[id].Version
+		}
+	}
+}
+
+// scenarioEntities возвращает подразделения и сотрудников, которые изменяют операции
+func scenarioEntities(ops []model.ScenarioOperation) (departments, employees []int) {
+	for _, op := range ops {
+		switch op.Op {
+		case model.ScenarioMoveDepartment, model.ScenarioRenameDepartment, model.ScenarioMergeDepartments:
+			if !slices.Contains(departments, op.DepartmentID) {
+				departments = append(departments, op.DepartmentID)
+			}
+		case model.ScenarioTransferEmployee:
+			if !slices.Contains(employees, op.EmployeeID) {
+				employees = append(employees, op.EmployeeID)
+			}
+		}
+	}
+	return departments, employees
+}
+
+// versionConflicts сравнивает запомненные версии с текущими; отсутствующая запись удалена
+func versionConflicts(sc *model.Scenario, deptVersions, empVersions map[int]int) []model.ScenarioConflict {
+	var conflicts []model.ScenarioConflict
+	check := func(entity string, base, current map[int]int) {
+		ids := slices.Sorted(maps.Keys(base))
+		for _, id := range ids {
+			version, ok := current[id]
+			if ok && version == base[id] {
+				continue
+			}
+			conflict := model.ScenarioConflict{Entity: entity, ID: id, BaseVersion: base[id], Message: entity + " was deleted"}
+			if ok {
+				conflict.CurrentVersion = &version
+				conflict.Message = entity + " was modified"
+			}
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	check("department", sc.DepartmentVersions, deptVersions)
+	check("employee", sc.EmployeeVersions, empVersions)
+	return conflicts
+}
+
+// projectScenario применяет операции к копии рабочего состояния. Версии затронутых записей
+// сравниваются с запомненными; неприменимые операции пропускаются и возвращаются как конфликты.
+func projectScenario(sc *model.Scenario, live *orgState) (*orgState, []model.ScenarioConflict) {
+	conflicts := versionConflicts(sc, live.departmentVersions(), live.employeeVersions())
+	projected := live.clone()
+	for i, op := range sc.Operations {
+		if err := projected.apply(op); err != nil {
+			index := i
+			conflicts = append(conflicts, model.ScenarioConflict{Operation: &index, Message: err.Error()})
+		}
+	}
+	if conflicts == nil {
+		conflicts = []model.ScenarioConflict{}
+	}
+	return projected, conflicts
+}
+
+// orgState оргструктура в памяти, к которой применяются операции сценария
+type orgState struct {
+	depts     map[int]*model.Department
+	employees map[int]*model.Employee
+	// mergedInto — подразделения, удалённые слиянием, и куда они слиты
+	mergedInto map[int]int
+}
+
+func newOrgState(depts []model.Department, employees []model.Employee) *orgState {
+	st := &orgState{
+		depts:      make(map[int]*model.Department, len(depts)),
+		employees:  make(map[int]*model.Employee, len(employees)),
+		mergedInto: map[int]int{},
+	}
+	for i := range depts {
+		st.depts[depts[i].ID] = &depts[i]
+	}
+	for i := range employees {
+		st.employees[employees[i].ID] = &employees[i]
+	}
+	return st
+}
+
+func (st *orgState) clone() *orgState {
+	c := &orgState{
+		depts:      make(map[int]*model.Department, len(st.depts)),
+		employees:  make(map[int]*model.Employee, len(st.employees)),
+		mergedInto: maps.Clone(st.mergedInto),
+	}
+	for id, d := range st.depts {
+		copied := *d
+		c.depts[id] = &copied
+	}
+	for id, e := range st.employees {
+		copied := *e
+		c.employees[id] = &copied
+	}
+	return c
+}
+
+func (st *orgState) departmentVersions() map[int]int {
+	versions := make(map[int]int, len(st.depts))
+	for id, d := range st.depts {
+		versions[id] = d.Version
+	}
+	return versions
+}
+
+func (st *orgState) employeeVersions() map[int]int {
+	versions := make(map[int]int, len(st.employees))
+	for id, e := range st.employees {
+		versions[id] = e.Version
+	}
+	return versions
+}
+
+// children возвращает ID непосредственных потомков подразделения в порядке сортировки
+func (st *orgState) children(id int) []int {
+	var ids []int
+	for childID, d := range st.depts {
+		if d.ParentID != nil && *d.ParentID == id {
+			ids = append(ids, childID)
+		}
+	}
+	st.sortSiblings(ids)
+	return ids
+}
+
+// sortSiblings упорядочивает ID подразделений одного родителя по (sort_order, id)
+func (st *orgState) sortSiblings(ids []int) {
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := st.depts[ids[i]], st.depts[ids[j]]
+		if a.SortOrder != b.SortOrder {
+			return a.SortOrder < b.SortOrder
+		}
+		return a.ID < b.ID
+	})
+}
+
+// nextSortOrder возвращает позицию после последнего потомка родителя (nil — корень)
+func (st *orgState) nextSortOrder(parentID *int) int {
+	next := 1
+	for _, d := range st.depts {
+		if sameParent(d.ParentID, parentID) && d.SortOrder >= next {
+			next = d.SortOrder + 1
+		}
+	}
+	return next
+}
+
+// inSubtree сообщает, лежит ли id в поддереве root (включая сам root)
+func (st *orgState) inSubtree(id, root int) bool {
+	for d := st.depts[id]; d != nil; {
+		if d.ID == root {
+			return true
+		}
+		if d.ParentID == nil {
+			return false
+		}
+		d = st.depts[*d.ParentID]
+	}
+	return false
+}
+
+func (st *orgState) uniqueName(parentID *int, name string, excludeID int) bool {
+	for id, d := range st.depts {
+		if id != excludeID && d.Name == name && sameParent(d.ParentID, parentID) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply применяет операцию с теми же проверками, что и методы сервиса
+func (st *orgState) apply(op model.ScenarioOperation) error {
+	switch op.Op {
+	case model.ScenarioMoveDepartment:
+		dept, ok := st.depts[op.DepartmentID]
+		if !ok {
+			return ErrNotFound
+		}
+		if op.ParentID != nil {
+			if *op.ParentID == dept.ID {
+				return ErrSelfParent
+			}
+			if _, ok := st.depts[*op.ParentID]; !ok {
+				return ErrNotFound
+			}
+			if st.inSubtree(*op.ParentID, dept.ID) {
+				return ErrCycleDetected
+			}
+		}
+		if !st.uniqueName(op.ParentID, dept.Name, dept.ID) {
+			return ErrDuplicateName
+		}
+		if !sameParent(dept.ParentID, op.ParentID) {
+			dept.SortOrder = st.nextSortOrder(op.ParentID)
+		}
+		if op.ParentID != nil {
+			parentID := *op.ParentID
+			dept.ParentID = &parentID
+		} else {
+			dept.ParentID = nil
+		}
+
+	case model.ScenarioRenameDepartment:
+		dept, ok := st.depts[op.DepartmentID]
+		if !ok {
+			return ErrNotFound
+		}
+		name := validateName(op.Name)
+		if name == "" || len(name) > 200 {
+			return errors.New("invalid name")
+		}
+		if !st.uniqueName(dept.ParentID, name, dept.ID) {
+			return ErrDuplicateName
+		}
+		dept.Name = name
+
+	case model.ScenarioMergeDepartments:
+		if op.DepartmentID == op.TargetID {
+			return ErrSelfParent
+		}
+		if _, ok := st.depts[op.DepartmentID]; !ok {
+			return ErrNotFound
+		}
+		if _, ok := st.depts[op.TargetID]; !ok {
+			return ErrNotFound
+		}
+		if st.inSubtree(op.TargetID, op.DepartmentID) {
+			return ErrCycleDetected
+		}
+		children := st.children(op.DepartmentID)
+		for _, childID := range children {
+			if !st.uniqueName(&op.TargetID, st.depts[childID].Name, childID) {
+				return ErrDuplicateName
+			}
+		}
+		// Потомки переходят в конец списка потомков целевого подразделения, сохраняя порядок
+		for _, childID := range children {
+			targetID := op.TargetID
+			st.depts[childID].SortOrder = st.nextSortOrder(&targetID)
+			st.depts[childID].ParentID = &targetID
+		}
+		for _, emp := range st.employees {
+			if emp.DepartmentID == op.DepartmentID {
+				emp.DepartmentID = op.TargetID
+			}
+		}
+		delete(st.depts, op.DepartmentID)
+		for id, into := range st.mergedInto {
+			if into == op.DepartmentID {
+				st.mergedInto[id] = op.TargetID
+			}
+		}
+		st.mergedInto[op.DepartmentID] = op.TargetID
+
+	case model.ScenarioTransferEmployee:
+		emp, ok := st.employees[op.EmployeeID]
+		if !ok {
+			return ErrNotFound
+		}
+		if _, ok := st.depts[op.DepartmentID]; !ok {
+			return ErrNotFound
+		}
+		emp.DepartmentID = op.DepartmentID
+
+	default:
+		return errors.New("unknown op: " + op.Op)
+	}
+	return nil
+}
+
+// tree строит вложенное дерево подразделений; сотрудники — только работающие
+func (st *orgState) tree(includeEmployees bool) []model.Department {
+	employees := map[int][]model.Employee{}
+	if includeEmployees {
+		statuses := employeeStatuses(nil)
+		ids := slices.Sorted(maps.Keys(st.employees))
+		for _, id := range ids {
+			emp := st.employees[id]
+			if slices.Contains(statuses, emp.Status) {
+				employees[emp.DepartmentID] = append(employees[emp.DepartmentID], *emp)
+			}
+		}
+	}
+
+	var build func(id int) model.Department
+	build = func(id int) model.Department {
+		dept := *st.depts[id]
+		dept.Employees = employees[id]
+		dept.Children = nil
+		for _, childID := range st.children(id) {
+			dept.Children = append(dept.Children, build(childID))
+		}
+		return dept
+	}
+
+	var rootIDs []int
+	for id, d := range st.depts {
+		if d.ParentID == nil {
+			rootIDs = append(rootIDs, id)
+		}
+	}
+	st.sortSiblings(rootIDs)
+	roots := []model.Department{}
+	for _, id := range rootIDs {
+		roots = append(roots, build(id))
+	}
+	return roots
+}
+
+// diffOrgState перечисляет изменения прогноза относительно рабочего состояния
+func diffOrgState(live, projected *orgState) []model.ScenarioChange {
+	changes := []model.ScenarioChange{}
+	for _, id := range slices.Sorted(maps.Keys(live.depts)) {
+		before := live.depts[id]
+		if into, ok := projected.mergedInto[id]; ok {
+			changes = append(changes, model.ScenarioChange{
+				Type: model.ChangeDepartmentMerged, DepartmentID: id, Name: before.Name, From: nil, To: into,
+			})
+			continue
+		}
+		after, ok := projected.depts[id]
+		if !ok {
+			continue
+		}
+		if !sameParent(before.ParentID, after.ParentID) {
+			changes = append(changes, model.ScenarioChange{
+				Type: model.ChangeDepartmentMoved, DepartmentID: id, Name: before.Name, From: before.ParentID, To: after.ParentID,
+			})
+		}
+		if before.Name != after.Name {
+			changes = append(changes, model.ScenarioChange{
+				Type: model.ChangeDepartmentRenamed, DepartmentID: id, Name: before.Name, From: before.Name, To: after.Name,
+			})
+		}
+	}
+	for _, id := range slices.Sorted(maps.Keys(live.employees)) {
+		before, after := live.employees[id], projected.employees[id]
+		if before.DepartmentID != after.DepartmentID {
+			changes = append(changes, model.ScenarioChange{
+				Type: model.ChangeEmployeeTransferred, EmployeeID: id, Name: before.FullName, From: before.DepartmentID, To: after.DepartmentID,
+			})
+		}
+	}
+	return changes
+}
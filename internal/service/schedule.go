@@ -0,0 +1,303 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+// ScheduleDepartmentUpdate планирует изменение по запросу application/json (см. UpdateDepartment)
+func (s *Service) ScheduleDepartmentUpdate(id int, req model.UpdateDepartmentRequest, effectiveAt time.Time) ([]model.ScheduledChange, error) {
+	return s.ScheduleDepartmentPatch(id, updateRequestPatch(req), effectiveAt)
+}
+
+// ScheduleDepartmentPatch планирует перенос и переименование подразделения на дату effectiveAt.
+// Допускаются только name и parent_id; каждое изменение сохраняется отдельной записью
+// (сначала перенос, затем переименование). Права и политика согласования переноса проверяются
+// при планировании, циклы и уникальность имени — при применении.
+func (s *Service) ScheduleDepartmentPatch(id int, patch model.DepartmentPatch, effectiveAt time.Time) ([]model.ScheduledChange, error) {
+	if patch.Code.Set || patch.CostCenter.Set || patch.Description.Set || patch.Active.Set || patch.Attributes.Set ||
+		(!patch.Name.Set && !patch.ParentID.Set) {
+		return nil, ErrNotSchedulable
+	}
+	dept, err := s.repo.GetDepartmentByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&id, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	var changes []model.ScheduledChange
+	if patch.ParentID.Set {
+		var parentID *int
+		if !patch.ParentID.Null {
+			parentID = &patch.ParentID.Value
+			if *parentID == id {
+				return nil, ErrSelfParent
+			}
+			if _, err := s.repo.GetDepartmentByID(*parentID); err != nil {
+				return nil, ErrNotFound
+			}
+		}
+		if !sameParent(dept.ParentID, parentID) {
+			if err := s.authorize(parentID, model.RoleEditor); err != nil {
+				return nil, err
+			}
+			// Запрос на согласование, созданный при применении, откатился бы вместе с изменением,
+			// поэтому перенос под политикой согласования не планируется
+			policy, _, err := s.matchApprovalPolicy(model.ApprovalMoveDepartment, id)
+			if err != nil {
+				return nil, err
+			}
+			if policy != nil {
+				return nil, ErrScheduleApproval
+			}
+		}
+		changes = append(changes, model.ScheduledChange{Op: model.ScenarioMoveDepartment, DepartmentID: id, ParentID: parentID})
+	}
+	if patch.Name.Set {
+		name := ""
+		if !patch.Name.Null {
+			name = validateName(patch.Name.Value)
+		}
+		if name == "" || len(name) > 200 {
+			return nil, errors.New("invalid name")
+		}
+		changes = append(changes, model.ScheduledChange{Op: model.ScenarioRenameDepartment, DepartmentID: id, Name: name})
+	}
+	return s.createScheduledChanges(changes, effectiveAt)
+}
+
+// ScheduleEmployeeTransfer планирует перевод сотрудника подразделения deptID на дату effectiveAt.
+// Допускается только department_id; права нужны в обоих подразделениях.
+func (s *Service) ScheduleEmployeeTransfer(deptID, id int, patch model.EmployeePatch, effectiveAt time.Time) ([]model.ScheduledChange, error) {
+	if patch.FullName.Set || patch.Position.Set || patch.PositionID.Set || patch.HiredAt.Set || patch.UserName.Set ||
+		patch.ExternalID.Set || patch.Attributes.Set || !patch.DepartmentID.Set {
+		return nil, ErrNotSchedulable
+	}
+	if patch.DepartmentID.Null {
+		return nil, ErrEmployeeMembership
+	}
+	emp, err := s.repo.GetEmployeeByID(id)
+	if err != nil || emp.DepartmentID != deptID {
+		return nil, ErrNotFound
+	}
+	target := patch.DepartmentID.Value
+	if _, err := s.repo.GetDepartmentByID(target); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorizeAll([]int{deptID, target}, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	return s.createScheduledChanges([]model.ScheduledChange{
+		{Op: model.ScenarioTransferEmployee, DepartmentID: target, EmployeeID: &id},
+	}, effectiveAt)
+}
+
+func (s *Service) createScheduledChanges(changes []model.ScheduledChange, effectiveAt time.Time) ([]model.ScheduledChange, error) {
+	if !effectiveAt.After(time.Now()) {
+		return nil, errors.New("effective_at must be in the future")
+	}
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		for i := range changes {
+			changes[i].EffectiveAt = effectiveAt
+			changes[i].Status = model.ScheduledPending
+			changes[i].CreatedBy = s.principal.Subject
+			if err := txRepo.CreateScheduledChange(&changes[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ListScheduledChanges возвращает запланированные изменения, доступные субъекту с ролью viewer
+// (см. accessibleScheduledChanges); пустой status — все статусы. Фильтр deptID требует роль
+// viewer на подразделение.
+func (s *Service) ListScheduledChanges(status string, deptID *int) ([]model.ScheduledChange, error) {
+	if status != "" && !model.ValidScheduledStatus(status) {
+		return nil, errors.New("invalid status")
+	}
+	if err := s.authorizeAny(model.RoleViewer); err != nil {
+		return nil, err
+	}
+	if deptID != nil {
+		if err := s.authorize(deptID, model.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := s.repo.ListScheduledChanges(status, deptID)
+	if err != nil {
+		return nil, err
+	}
+	return s.accessibleScheduledChanges(changes, model.RoleViewer)
+}
+
+func (s *Service) GetScheduledChange(id int) (*model.ScheduledChange, error) {
+	if err := s.authorizeAny(model.RoleViewer); err != nil {
+		return nil, err
+	}
+	change, err := s.repo.GetScheduledChangeByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorizeScheduledChange(change, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// CancelScheduledChange отменяет ожидающее изменение; требует роль editor на его подразделения
+func (s *Service) CancelScheduledChange(id int) (*model.ScheduledChange, error) {
+	var change *model.ScheduledChange
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txSvc := *s
+		txSvc.repo = s.repo.WithTx(tx)
+		txRepo := txSvc.repo
+		var err error
+		if change, err = txRepo.GetScheduledChangeForUpdate(id); err != nil {
+			return ErrNotFound
+		}
+		if err := txSvc.authorizeScheduledChange(change, model.RoleEditor); err != nil {
+			return err
+		}
+		if change.Status != model.ScheduledPending {
+			return ErrChangeNotPending
+		}
+
+		now := time.Now()
+		if err := txRepo.FinishScheduledChange(id, model.ScheduledCancelled, nil, now); err != nil {
+			return err
+		}
+		change.Status = model.ScheduledCancelled
+		change.ProcessedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return change, nil
+}
+
+// authorizeScheduledChange проверяет роль required на подразделения изменения
+func (s *Service) authorizeScheduledChange(change *model.ScheduledChange, required string) error {
+	accessible, err := s.accessibleScheduledChanges([]model.ScheduledChange{*change}, required)
+	if err != nil {
+		return err
+	}
+	if len(accessible) == 0 {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// accessibleScheduledChanges оставляет изменения, на все подразделения которых у субъекта есть
+// роль required: подразделение изменения и, для перевода, текущее подразделение сотрудника
+func (s *Service) accessibleScheduledChanges(changes []model.ScheduledChange, required string) ([]model.ScheduledChange, error) {
+	var employeeIDs []int
+	for _, c := range changes {
+		if c.EmployeeID != nil {
+			employeeIDs = append(employeeIDs, *c.EmployeeID)
+		}
+	}
+	employees, err := s.repo.GetEmployeesByIDs(employeeIDs)
+	if err != nil {
+		return nil, err
+	}
+	source := make(map[int]int, len(employees))
+	for _, e := range employees {
+		source[e.ID] = e.DepartmentID
+	}
+
+	deptIDs := make([]int, 0, len(changes)+len(source))
+	for _, c := range changes {
+		deptIDs = append(deptIDs, c.DepartmentID)
+	}
+	for _, deptID := range source {
+		deptIDs = append(deptIDs, deptID)
+	}
+	canAccess, err := s.departmentAccess(deptIDs, required)
+	if err != nil {
+		return nil, err
+	}
+
+	accessible := make([]model.ScheduledChange, 0, len(changes))
+	for _, c := range changes {
+		if !canAccess(c.DepartmentID) {
+			continue
+		}
+		if c.EmployeeID != nil {
+			if deptID, ok := source[*c.EmployeeID]; ok && !canAccess(deptID) {
+				continue
+			}
+		}
+		accessible = append(accessible, c)
+	}
+	return accessible, nil
+}
+
+// scheduledOutcome статус применённого изменения и текст ошибки для записи в колонку size:2000
+func scheduledOutcome(err error) (string, *string) {
+	if err == nil {
+		return model.ScheduledApplied, nil
+	}
+	msg := model.TruncateRunes(err.Error(), 2000)
+	return model.ScheduledFailed, &msg
+}
+
+// ApplyDueScheduledChanges применяет наступившие изменения всех арендаторов по порядку
+// дат вступления в силу и возвращает число применённых и неудавшихся.
+// Каждое изменение выполняется в своей транзакции от имени системного субъекта арендатора;
+// если операцию больше нельзя выполнить (подразделение удалено, цикл, совпадение имён),
+// её изменения откатываются, а запись получает статус failed с текстом ошибки.
+func (s *Service) ApplyDueScheduledChanges(now time.Time) (int, int, error) {
+	applied, failed := 0, 0
+	for {
+		var change *model.ScheduledChange
+		err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+			txRepo := s.repo.WithTx(tx)
+			var err error
+			if change, err = txRepo.ClaimDueScheduledChange(now); err != nil || change == nil {
+				return err
+			}
+
+			principal := auth.System
+			principal.TenantID = change.TenantID
+			txSvc := s.WithPrincipal(principal)
+			// Операция выполняется в точке сохранения, чтобы при ошибке отметка failed осталась
+			err = tx.Transaction(func(sp *gorm.DB) error {
+				spSvc := *txSvc
+				spSvc.repo = txSvc.repo.WithTx(sp)
+				return spSvc.executeOperation(change.Operation())
+			})
+
+			status, errMsg := scheduledOutcome(withoutDeferral(err))
+			if err := txRepo.FinishScheduledChange(change.ID, status, errMsg, time.Now()); err != nil {
+				return err
+			}
+			change.Status = status
+			return nil
+		})
+		if err != nil {
+			return applied, failed, err
+		}
+		if change == nil {
+			return applied, failed, nil
+		}
+		if change.Status == model.ScheduledApplied {
+			applied++
+		} else {
+			failed++
+		}
+	}
+}
@@ -2,9 +2,11 @@ package service
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
 	"github.com/SergeiKhy/org-structure-api/internal/model"
 	"github.com/SergeiKhy/org-structure-api/internal/repository"
 	"gorm.io/gorm"
@@ -15,14 +17,51 @@ var (
 	ErrCycleDetected = errors.New("cycle detected")
 	ErrDuplicateName = errors.New("duplicate name within parent")
 	ErrSelfParent    = errors.New("cannot be parent of itself")
+	ErrForbidden     = errors.New("forbidden")
+
+	ErrDuplicateRoleBinding = errors.New("role binding already exists")
+	ErrAPIKeyRevoked        = errors.New("api key revoked")
+	ErrDuplicateTenant      = errors.New("tenant already exists")
+	ErrVersionMismatch      = errors.New("version mismatch")
+	ErrDuplicateUserName    = errors.New("duplicate user name")
+	ErrEmployeeMembership   = errors.New("employee must belong to a department")
+	ErrDuplicateCode        = errors.New("duplicate department code")
+	ErrDuplicateAttribute   = errors.New("attribute already defined")
+	ErrInvalidAttributes    = errors.New("invalid attributes")
+	ErrInvalidTransition    = errors.New("invalid status transition")
+	ErrDuplicatePosition    = errors.New("position already exists")
+	ErrPositionInUse        = errors.New("position is assigned to employees or requisitions")
+	ErrScenarioApplied      = errors.New("scenario already applied")
+	ErrScenarioConflict     = errors.New("scenario conflicts with live data")
+	ErrNotSchedulable       = errors.New("only moves, renames and transfers can be scheduled")
+	ErrChangeNotPending     = errors.New("scheduled change is not pending")
+	ErrScheduleApproval     = errors.New("move requires approval and cannot be scheduled")
+	ErrApprovalRequired     = errors.New("approval required")
+	ErrChangeRequestDecided = errors.New("change request already decided")
+	ErrSelfApproval         = errors.New("change request cannot be approved by its author")
+	ErrOrderMismatch        = errors.New("order must list every child exactly once")
+
+	// ErrApprovalNotDeferrable согласование требуется внутри общей транзакции (пакет, сценарий,
+	// SCIM, планировщик): запрос на изменение откатывается вместе с ней и не сохраняется
+	ErrApprovalNotDeferrable = fmt.Errorf("%w: the change cannot wait for approval inside this operation, submit it as a separate department update", ErrApprovalRequired)
+
+	ErrIdempotencyKeyReused     = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
 )
 
 type Service struct {
-	repo *repository.Repository
+	repo      *repository.Repository
+	principal auth.Principal
+	// approved отключает политики согласования при выполнении одобренного запроса
+	approved bool
+	// allowPrivateWebhooks разрешает подписки на адреса внутренней сети
+	allowPrivateWebhooks bool
 }
 
+// NewService создаёт сервис, работающий от имени системного субъекта.
+// Для запросов пользователей используйте WithPrincipal.
 func NewService(repo *repository.Repository) *Service {
-	return &Service{repo: repo}
+	return &Service{repo: repo, principal: auth.System}
 }
 
 // Валидация имени
@@ -36,6 +75,23 @@ func (s *Service) CreateDepartment(req model.CreateDepartmentRequest) (*model.De
 		return nil, errors.New("invalid name")
 	}
 
+	// Обработка кейса, когда фронт может прислать 0 вместо nil
+	if req.ParentID != nil && *req.ParentID == 0 {
+		req.ParentID = nil
+	}
+
+	// Права проверяются до чтения данных, чтобы ответы 404 и 409 не раскрывали чужое поддерево
+	if err := s.authorize(req.ParentID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	// Проверка существования родителя
+	if req.ParentID != nil {
+		if _, err := s.repo.GetDepartmentByID(*req.ParentID); err != nil {
+			return nil, ErrNotFound
+		}
+	}
+
 	// Проверка уникальности
 	ok, err := s.repo.CheckUniqueName(req.ParentID, name, 0)
 	if err != nil {
@@ -45,64 +101,108 @@ func (s *Service) CreateDepartment(req model.CreateDepartmentRequest) (*model.De
 		return nil, ErrDuplicateName
 	}
 
-	// Проверка на цикл, если parent_id указан
-	if req.ParentID != nil {
-		if *req.ParentID == 0 {
-			// Обработка кейса, когда фронт может прислать 0 вместо nil
-			req.ParentID = nil
-		} else {
-			// Проверка существования родителя
-			_, err = s.repo.GetDepartmentByID(*req.ParentID)
-			if err != nil {
-				return nil, ErrNotFound
-			}
-		}
-	}
-
 	dept := &model.Department{
 		Name:      name,
 		ParentID:  req.ParentID,
+		Active:    true,
+		Version:   1,
 		CreatedAt: time.Now(),
 	}
+	if err := s.applyDepartmentMetadata(dept, metadataPatch(req.Code, req.CostCenter, req.Description, req.Active)); err != nil {
+		return nil, err
+	}
+	if dept.Attributes, err = s.mergeAttributes(model.AttributeEntityDepartment, nil, model.Some(&req.Attributes)); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.CreateDepartment(dept); err != nil {
+	// Подразделение и событие записываются в одной транзакции
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.CreateDepartment(dept); err != nil {
+			return err
+		}
+		scope, err := departmentScope(txRepo, dept.ID)
+		if err != nil {
+			return err
+		}
+		return recordEvent(txRepo, model.EventDepartmentCreated, &dept.ID, scope, dept)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return dept, nil
 }
 
-func (s *Service) UpdateDepartment(id int, req model.UpdateDepartmentRequest) (*model.Department, error) {
+// UpdateDepartment изменяет подразделение по запросу application/json:
+// пустое имя и отсутствующие поля не меняют значения, parent_id = 0 переносит в корень,
+// пустые code, cost_center и description очищают их, attributes объединяется с текущими значениями.
+// expectedVersion — версия, с которой работал клиент (If-Match); 0 отключает проверку.
+func (s *Service) UpdateDepartment(id int, req model.UpdateDepartmentRequest, expectedVersion int) (*model.Department, error) {
+	return s.PatchDepartment(id, updateRequestPatch(req), expectedVersion)
+}
+
+// updateRequestPatch переводит запрос application/json в частичное изменение
+func updateRequestPatch(req model.UpdateDepartmentRequest) model.DepartmentPatch {
+	patch := metadataPatch(req.Code, req.CostCenter, req.Description, req.Active)
+	if req.Attributes != nil {
+		patch.Attributes = model.Some(&req.Attributes)
+	}
+	if req.Name != "" {
+		patch.Name = model.Some(req.Name)
+	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			patch.ParentID = model.Null[int]()
+		} else {
+			patch.ParentID = model.Some(*req.ParentID)
+		}
+	}
+	return patch
+}
+
+// PatchDepartment применяет частичное изменение (RFC 7396): меняются только заданные поля,
+// null в parent_id переносит подразделение в корень.
+func (s *Service) PatchDepartment(id int, patch model.DepartmentPatch, expectedVersion int) (*model.Department, error) {
 	dept, err := s.repo.GetDepartmentByID(id)
 	if err != nil {
 		return nil, ErrNotFound
 	}
 
-	if req.Name != "" {
-		name := validateName(req.Name)
-		if len(name) > 200 {
-			return nil, errors.New("invalid name")
-		}
-		// Проверка уникальности нового имени
-		ok, err := s.repo.CheckUniqueName(req.ParentID, name, id)
-		if err != nil {
-			return nil, err
+	if err := s.authorize(&id, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if expectedVersion != 0 && dept.Version != expectedVersion {
+		return nil, ErrVersionMismatch
+	}
+
+	old := *dept
+	oldName, oldParentID := dept.Name, dept.ParentID
+	oldScope, err := departmentScope(s.repo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if patch.Name.Set {
+		name := ""
+		if !patch.Name.Null {
+			name = validateName(patch.Name.Value)
 		}
-		if !ok {
-			return nil, ErrDuplicateName
+		if name == "" || len(name) > 200 {
+			return nil, errors.New("invalid name")
 		}
 		dept.Name = name
 	}
 
-	if req.ParentID != nil {
-		if *req.ParentID == 0 {
-			req.ParentID = nil
-		} else {
+	if patch.ParentID.Set {
+		var parentID *int
+		if !patch.ParentID.Null {
+			parentID = &patch.ParentID.Value
 			// Нельзя сделать родителем самого себя
-			if *req.ParentID == id {
+			if *parentID == id {
 				return nil, ErrSelfParent
 			}
 			// Проверка на цикл (новый родитель не должен быть потомком текущего)
-			parents, err := s.repo.GetParentChain(*req.ParentID)
+			parents, err := s.repo.GetParentChain(*parentID)
 			if err != nil {
 				return nil, err
 			}
@@ -112,35 +212,213 @@ func (s *Service) UpdateDepartment(id int, req model.UpdateDepartmentRequest) (*
 				}
 			}
 			// Проверка сущестования родителя
-			_, err = s.repo.GetDepartmentByID(*req.ParentID)
+			_, err = s.repo.GetDepartmentByID(*parentID)
 			if err != nil {
 				return nil, ErrNotFound
 			}
 		}
-		dept.ParentID = req.ParentID
+		// Перемещение требует прав и на новое место в иерархии
+		if !sameParent(oldParentID, parentID) {
+			if err := s.authorize(parentID, model.RoleEditor); err != nil {
+				return nil, err
+			}
+		}
+		dept.ParentID = parentID
 	}
-	if err := s.repo.UpdateDepartment(dept); err != nil {
+
+	if err := s.applyDepartmentMetadata(dept, patch); err != nil {
+		return nil, err
+	}
+	if patch.Attributes.Set {
+		if dept.Attributes, err = s.mergeAttributes(model.AttributeEntityDepartment, dept.Attributes, patch.Attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	// Уникальность имени проверяется в итоговом родителе
+	if dept.Name != oldName || !sameParent(oldParentID, dept.ParentID) {
+		ok, err := s.repo.CheckUniqueName(dept.ParentID, dept.Name, id)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrDuplicateName
+		}
+	}
+
+	// Перенос крупного подразделения может требовать согласования
+	if !sameParent(oldParentID, dept.ParentID) {
+		err := s.requireApproval(model.ChangeRequest{Action: model.ApprovalMoveDepartment, Patch: &patch}, &old)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		// Перенесённое подразделение встаёт в конец списка потомков нового родителя
+		if !sameParent(oldParentID, dept.ParentID) {
+			if dept.SortOrder, err = txRepo.NextSortOrder(dept.ParentID); err != nil {
+				return err
+			}
+		}
+		// Версия проверяется повторно при записи: между чтением и записью мог успеть другой запрос
+		if err := txRepo.UpdateDepartment(dept); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return ErrVersionMismatch
+			}
+			return err
+		}
+		scope, err := departmentScope(txRepo, id)
+		if err != nil {
+			return err
+		}
+
+		if !sameParent(oldParentID, dept.ParentID) {
+			// Перемещение видно наблюдателям и старого, и нового поддерева
+			err := recordEvent(txRepo, model.EventDepartmentMoved, &id, mergeScopes(oldScope, scope), model.DepartmentMovedData{
+				Department:  dept,
+				OldParentID: oldParentID,
+				NewParentID: dept.ParentID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if !sameMetadata(&old, dept) {
+			return recordEvent(txRepo, model.EventDepartmentUpdated, &id, scope, dept)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 	return dept, nil
 }
 
-func (s *Service) DeleteDepartment(id int, mode string, reassignToID *int) error {
+// ReorderChildren задаёт порядок непосредственных потомков подразделения: order — полный список
+// их ID без повторов. Родитель и потомки блокируются на время записи, поэтому, если список разошёлся
+// с текущим составом (потомка добавили, перенесли или удалили), возвращается ErrOrderMismatch
+// и порядок не меняется. Возвращает потомков в новом порядке.
+func (s *Service) ReorderChildren(id int, order []int) ([]model.Department, error) {
+	if _, err := s.repo.GetDepartmentByID(id); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&id, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
+	var children []model.Department
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		// Блокировка родителя исключает добавление потомков, пока сверяется состав
+		if err := txRepo.LockChildrenOrder(&id); err != nil {
+			return ErrNotFound
+		}
+		current, err := txRepo.GetChildrenForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if len(order) != len(current) {
+			return ErrOrderMismatch
+		}
+		seen := make(map[int]bool, len(order))
+		for _, childID := range order {
+			seen[childID] = true
+		}
+		for _, child := range current {
+			if !seen[child.ID] {
+				return ErrOrderMismatch
+			}
+		}
+
+		if err := txRepo.SetSortOrder(order); err != nil {
+			return err
+		}
+		if children, err = txRepo.GetChildren(id); err != nil {
+			return err
+		}
+		scope, err := departmentScope(txRepo, id)
+		if err != nil {
+			return err
+		}
+		return recordEvent(txRepo, model.EventChildrenReordered, &id, scope, model.ChildrenReorderedData{
+			ParentID: id,
+			Order:    order,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// DeleteDepartment удаляет подразделение.
+// expectedVersion — версия, с которой работал клиент (If-Match); 0 отключает проверку.
+func (s *Service) DeleteDepartment(id int, mode string, reassignToID *int, expectedVersion int) error {
 	// Проверка на существование
-	_, err := s.repo.GetDepartmentByID(id)
+	dept, err := s.repo.GetDepartmentByID(id)
 	if err != nil {
 		return ErrNotFound
 	}
 
+	if err := s.authorize(&id, model.RoleEditor); err != nil {
+		return err
+	}
+	if mode == "reassign" && reassignToID != nil {
+		if err := s.authorize(reassignToID, model.RoleEditor); err != nil {
+			return err
+		}
+	}
+
+	// Проверки до согласования: запрос на изменение не должен создаваться для устаревшего
+	// или некорректного удаления. Версия повторно сверяется под блокировкой в транзакции.
+	if expectedVersion != 0 && dept.Version != expectedVersion {
+		return ErrVersionMismatch
+	}
+	if mode == "reassign" {
+		if reassignToID == nil {
+			return errors.New("reassign_to_department_id is required")
+		}
+		if _, err := s.repo.GetDepartmentByID(*reassignToID); err != nil {
+			return ErrNotFound
+		}
+	}
+
+	// Удаление крупного подразделения может требовать согласования
+	err = s.requireApproval(model.ChangeRequest{Action: model.ApprovalDeleteDepartment, Mode: mode, ReassignToID: reassignToID}, dept)
+	if err != nil {
+		return err
+	}
+
 	return s.repo.DB().Transaction(func(tx *gorm.DB) error {
 		// Создание репозитория с транзакционной БД
 		txRepo := s.repo.WithTx(tx)
 
+		// Блокировка строки исключает изменение подразделения между проверкой версии и удалением
+		current, err := txRepo.GetDepartmentForUpdate(id)
+		if err != nil {
+			return ErrNotFound
+		}
+		if expectedVersion != 0 && current.Version != expectedVersion {
+			return ErrVersionMismatch
+		}
+
+		// Область события вычисляется до удаления, пока цепочка предков существует
+		scope, err := departmentScope(txRepo, id)
+		if err != nil {
+			return err
+		}
+
 		if mode == "reassign" {
-			if reassignToID == nil {
-				return errors.New("reassign_to_department_id is required")
-			}
-			// Проверка существования целевого департамента
+			// Целевой департамент мог быть удалён после проверки
 			_, err := txRepo.GetDepartmentByID(*reassignToID)
 			if err != nil {
 				return ErrNotFound
@@ -162,7 +440,15 @@ func (s *Service) DeleteDepartment(id int, mode string, reassignToID *int) error
 		}
 
 		// удаляем департамент
-		return txRepo.DeleteDepartment(id)
+		if err := txRepo.DeleteDepartment(id); err != nil {
+			return err
+		}
+
+		return recordEvent(txRepo, model.EventDepartmentDeleted, &id, scope, model.DepartmentDeletedData{
+			ID:                     id,
+			Mode:                   mode,
+			ReassignToDepartmentID: reassignToID,
+		})
 	})
 }
 
@@ -173,8 +459,16 @@ func (s *Service) CreateEmployee(deptID int, req model.CreateEmployeeRequest) (*
 		return nil, ErrNotFound
 	}
 
+	if err := s.authorize(&deptID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+
 	fullName := validateName(req.FullName)
-	position := validateName(req.Position)
+	linked := &model.Employee{Position: validateName(req.Position)}
+	if err := s.linkPosition(linked, req.PositionID); err != nil {
+		return nil, err
+	}
+	position := linked.Position
 
 	if fullName == "" || position == "" || len(fullName) > 200 || len(position) > 200 {
 		return nil, errors.New("invalid fields")
@@ -189,22 +483,67 @@ func (s *Service) CreateEmployee(deptID int, req model.CreateEmployeeRequest) (*
 		hiredAt = &t
 	}
 
+	status := model.EmployeeActive
+	if req.Status != nil {
+		status = *req.Status
+		if status != model.EmployeePending && status != model.EmployeeActive {
+			return nil, errors.New("invalid status")
+		}
+	}
+
 	emp := &model.Employee{
 		DepartmentID: deptID,
 		FullName:     fullName,
 		Position:     position,
+		PositionID:   linked.PositionID,
 		HiredAt:      hiredAt,
+		Status:       status,
+		Version:      1,
 		CreatedAt:    time.Now(),
 	}
+	if req.UserName != nil {
+		userName, err := s.checkUserName(*req.UserName, 0)
+		if err != nil {
+			return nil, err
+		}
+		emp.UserName = &userName
+	}
+	if req.ExternalID != nil {
+		externalID, err := validateExternalID(*req.ExternalID)
+		if err != nil {
+			return nil, err
+		}
+		emp.ExternalID = &externalID
+	}
+	if emp.Attributes, err = s.mergeAttributes(model.AttributeEntityEmployee, nil, model.Some(&req.Attributes)); err != nil {
+		return nil, err
+	}
 
-	if err := s.repo.CreateEmployee(emp); err != nil {
+	err = s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.CreateEmployee(emp); err != nil {
+			return err
+		}
+		scope, err := departmentScope(txRepo, deptID)
+		if err != nil {
+			return err
+		}
+		return recordEvent(txRepo, model.EventEmployeeCreated, &deptID, scope, emp)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return emp, nil
 }
 
-// Рекурсивное построение дерева
+// GetDepartmentTree возвращает поддерево подразделения с работающими сотрудниками
 func (s *Service) GetDepartmentTree(id int, depth int, includeEmployees bool) (*model.Department, error) {
+	return s.GetDepartmentTreeByStatus(id, depth, includeEmployees, nil)
+}
+
+// GetDepartmentTreeByStatus возвращает поддерево подразделения с сотрудниками в одном из статусов;
+// пустой список — только работающие
+func (s *Service) GetDepartmentTreeByStatus(id int, depth int, includeEmployees bool, statuses []string) (*model.Department, error) {
 	if depth < 1 {
 		depth = 1
 	}
@@ -212,13 +551,22 @@ func (s *Service) GetDepartmentTree(id int, depth int, includeEmployees bool) (*
 		depth = 5
 	}
 
+	if err := s.authorize(&id, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.buildTree(id, depth, includeEmployees, employeeStatuses(statuses))
+}
+
+// Рекурсивное построение дерева
+func (s *Service) buildTree(id int, depth int, includeEmployees bool, statuses []string) (*model.Department, error) {
 	dept, err := s.repo.GetDepartmentWithChildren(id, depth)
 	if err != nil {
 		return nil, ErrNotFound
 	}
 
 	if includeEmployees {
-		emps, err := s.repo.GetEmployeesByDeptID(id)
+		emps, err := s.repo.GetEmployeesByDeptIDAndStatus(id, statuses)
 		if err != nil {
 			return nil, err
 		}
@@ -227,13 +575,13 @@ func (s *Service) GetDepartmentTree(id int, depth int, includeEmployees bool) (*
 
 	if depth > 1 {
 		// Загружаем детей
-		var children []model.Department
-		if err := s.repo.DB().Where("parent_id = ?", id).Find(&children).Error; err != nil {
+		children, err := s.repo.GetChildren(id)
+		if err != nil {
 			return nil, err
 		}
 
 		for i := range children {
-			childTree, err := s.GetDepartmentTree(children[i].ID, depth-1, includeEmployees)
+			childTree, err := s.buildTree(children[i].ID, depth-1, includeEmployees, statuses)
 			if err != nil {
 				continue
 			}
@@ -4,11 +4,20 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/jsonpatch"
+	"github.com/SergeiKhy/org-structure-api/internal/lint"
 	"github.com/SergeiKhy/org-structure-api/internal/model"
 	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"github.com/SergeiKhy/org-structure-api/internal/scim"
 	"github.com/testcontainers/testcontainers-go"
 	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -46,11 +55,19 @@ func setupTestContainer(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB,
 		t.Fatalf("ошибка подключения к БД: %v", err)
 	}
 
-	err = db.AutoMigrate(&model.Department{}, &model.Employee{})
+	err = db.AutoMigrate(&model.Department{}, &model.Employee{}, &model.RoleBinding{}, &model.APIKey{}, &model.Tenant{},
+		&model.WebhookSubscription{}, &model.WebhookDelivery{}, &model.OutboxEvent{},
+		&model.IdempotencyKey{}, &model.AttributeDefinition{}, &model.Position{}, &model.HeadcountBudget{}, &model.Requisition{}, &model.Scenario{}, &model.ScheduledChange{}, &model.ApprovalPolicy{}, &model.ChangeRequest{})
 	if err != nil {
 		t.Fatalf("ошибка миграции: %v", err)
 	}
 
+	// Арендатор по умолчанию создаётся миграцией, здесь — вручную
+	if err := db.Create(&model.Tenant{ID: model.DefaultTenantID, Slug: model.DefaultTenantSlug, Name: "Default"}).Error; err != nil {
+		t.Fatalf("ошибка создания арендатора по умолчанию: %v", err)
+	}
+	db.Exec("SELECT setval(pg_get_serial_sequence('tenants', 'id'), (SELECT MAX(id) FROM tenants))")
+
 	return pgContainer, db, ctx
 }
 
@@ -117,7 +134,7 @@ func TestService_UpdateDepartment_Integration(t *testing.T) {
 
 	// Обновляем
 	updateReq := model.UpdateDepartmentRequest{Name: "Updated"}
-	updated, err := svc.UpdateDepartment(dept.ID, updateReq)
+	updated, err := svc.UpdateDepartment(dept.ID, updateReq, 0)
 	if err != nil {
 		t.Fatalf("ошибка обновления: %v", err)
 	}
@@ -140,7 +157,7 @@ func TestService_UpdateDepartment_SelfParent_Integration(t *testing.T) {
 
 	// Пытаемся сделать родителем самого себя
 	updateReq := model.UpdateDepartmentRequest{ParentID: &dept.ID}
-	_, err := svc.UpdateDepartment(dept.ID, updateReq)
+	_, err := svc.UpdateDepartment(dept.ID, updateReq, 0)
 	if err != ErrSelfParent {
 		t.Errorf("ожидалась ошибка ErrSelfParent, получено %v", err)
 	}
@@ -166,7 +183,7 @@ func TestService_DeleteDepartment_Cascade_Integration(t *testing.T) {
 	svc.CreateEmployee(dept.ID, empReq)
 
 	// Удаляем
-	err := svc.DeleteDepartment(dept.ID, "cascade", nil)
+	err := svc.DeleteDepartment(dept.ID, "cascade", nil, 0)
 	if err != nil {
 		t.Fatalf("ошибка удаления: %v", err)
 	}
@@ -199,7 +216,7 @@ func TestService_DeleteDepartment_Reassign_Integration(t *testing.T) {
 
 	// Удаляем с переназначением
 	reassignTo := newDept.ID
-	err := svc.DeleteDepartment(oldDept.ID, "reassign", &reassignTo)
+	err := svc.DeleteDepartment(oldDept.ID, "reassign", &reassignTo, 0)
 	if err != nil {
 		t.Fatalf("ошибка удаления: %v", err)
 	}
@@ -323,7 +340,7 @@ func TestService_CycleDetection_Integration(t *testing.T) {
 
 	// Пытаемся сделать Parent ребёнком Child (цикл!)
 	updateReq := model.UpdateDepartmentRequest{ParentID: &child.ID}
-	_, err := svc.UpdateDepartment(parent.ID, updateReq)
+	_, err := svc.UpdateDepartment(parent.ID, updateReq, 0)
 	if err != ErrCycleDetected {
 		t.Errorf("ожидалась ошибка ErrCycleDetected, получено %v", err)
 	}
@@ -380,6 +397,548 @@ func TestService_NameTrimming_Integration(t *testing.T) {
 	}
 }
 
+// TestService_RBAC_SubtreeScope_Integration тестирует ограничение прав поддеревом
+func TestService_RBAC_SubtreeScope_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	// Company -> Engineering, Company -> Sales
+	company, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	engineering, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering", ParentID: &company.ID})
+	sales, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales", ParentID: &company.ID})
+
+	_, err := svc.CreateRoleBinding(model.CreateRoleBindingRequest{
+		Subject:      "manager",
+		Role:         model.RoleEditor,
+		DepartmentID: &engineering.ID,
+	})
+	if err != nil {
+		t.Fatalf("ошибка создания привязки: %v", err)
+	}
+
+	manager := svc.WithPrincipal(auth.Principal{Subject: "manager"})
+
+	// Внутри Engineering редактирование разрешено
+	backend, err := manager.CreateDepartment(model.CreateDepartmentRequest{Name: "Backend", ParentID: &engineering.ID})
+	if err != nil {
+		t.Fatalf("ожидалось успешное создание, получено %v", err)
+	}
+	if _, err := manager.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer"}); err != nil {
+		t.Errorf("ожидалось успешное создание сотрудника, получено %v", err)
+	}
+
+	// Вне поддерева — запрещено
+	if _, err := manager.CreateDepartment(model.CreateDepartmentRequest{Name: "Field", ParentID: &sales.ID}); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+	// Вне поддерева существование имён и родителей не раскрывается
+	if _, err := manager.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering", ParentID: &company.ID}); err != ErrForbidden {
+		t.Errorf("совпадающее имя: ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+	missing := 9999
+	if _, err := manager.CreateDepartment(model.CreateDepartmentRequest{Name: "Ghost", ParentID: &missing}); err != ErrForbidden {
+		t.Errorf("несуществующий родитель: ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+
+	// Перенос в чужое поддерево — запрещён
+	if _, err := manager.UpdateDepartment(backend.ID, model.UpdateDepartmentRequest{ParentID: &sales.ID}, 0); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+
+	// Создание корневых подразделений — запрещено
+	if _, err := manager.CreateDepartment(model.CreateDepartmentRequest{Name: "Root"}); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+
+	// Редактор не может управлять ролями
+	if _, err := manager.CreateRoleBinding(model.CreateRoleBindingRequest{Subject: "intern", Role: model.RoleViewer, DepartmentID: &engineering.ID}); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+}
+
+// TestService_RBAC_Viewer_Integration тестирует роль viewer
+func TestService_RBAC_Viewer_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	dept, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering"})
+	svc.CreateRoleBinding(model.CreateRoleBindingRequest{Subject: "auditor", Role: model.RoleViewer})
+
+	auditor := svc.WithPrincipal(auth.Principal{Subject: "auditor"})
+
+	if _, err := auditor.GetDepartmentTree(dept.ID, 1, true); err != nil {
+		t.Errorf("ожидалось успешное чтение, получено %v", err)
+	}
+	if err := auditor.DeleteDepartment(dept.ID, "cascade", nil, 0); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+
+	// Без привязок нет доступа даже на чтение
+	stranger := svc.WithPrincipal(auth.Principal{Subject: "stranger"})
+	if _, err := stranger.GetDepartmentTree(dept.ID, 1, true); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+}
+
+// TestService_RoleBinding_Duplicate_Integration тестирует защиту от дублирования привязок
+func TestService_RoleBinding_Duplicate_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	req := model.CreateRoleBindingRequest{Subject: "alice", Role: model.RoleAdmin}
+	if _, err := svc.CreateRoleBinding(req); err != nil {
+		t.Fatalf("ошибка создания привязки: %v", err)
+	}
+	if _, err := svc.CreateRoleBinding(req); err != ErrDuplicateRoleBinding {
+		t.Errorf("ожидалась ошибка ErrDuplicateRoleBinding, получено %v", err)
+	}
+}
+
+// TestService_APIKey_Lifecycle_Integration тестирует создание, ротацию и отзыв ключа
+func TestService_APIKey_Lifecycle_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	created, err := svc.CreateAPIKey(model.CreateAPIKeyRequest{Name: "payroll", Scope: auth.ScopeReadOnly})
+	if err != nil {
+		t.Fatalf("ошибка создания ключа: %v", err)
+	}
+
+	p, err := svc.ResolveAPIKey(created.Key)
+	if err != nil {
+		t.Fatalf("ошибка проверки ключа: %v", err)
+	}
+	if p.Scope != auth.ScopeReadOnly {
+		t.Errorf("ожидалась область %q, получено %q", auth.ScopeReadOnly, p.Scope)
+	}
+
+	// Ключ только для чтения не может изменять данные
+	if _, err := svc.WithPrincipal(p).CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering"}); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+
+	// После ротации старый ключ недействителен
+	rotated, err := svc.RotateAPIKey(created.ID)
+	if err != nil {
+		t.Fatalf("ошибка ротации: %v", err)
+	}
+	if _, err := svc.ResolveAPIKey(created.Key); err != auth.ErrUnauthorized {
+		t.Errorf("ожидалась ошибка ErrUnauthorized для старого ключа, получено %v", err)
+	}
+	if _, err := svc.ResolveAPIKey(rotated.Key); err != nil {
+		t.Errorf("новый ключ должен работать, получено %v", err)
+	}
+
+	// После отзыва ключ недействителен
+	if err := svc.RevokeAPIKey(created.ID); err != nil {
+		t.Fatalf("ошибка отзыва: %v", err)
+	}
+	if _, err := svc.ResolveAPIKey(rotated.Key); err != auth.ErrUnauthorized {
+		t.Errorf("ожидалась ошибка ErrUnauthorized после отзыва, получено %v", err)
+	}
+	if _, err := svc.RotateAPIKey(created.ID); err != ErrAPIKeyRevoked {
+		t.Errorf("ожидалась ошибка ErrAPIKeyRevoked, получено %v", err)
+	}
+}
+
+// TestService_TenantIsolation_Integration тестирует изоляцию данных арендаторов
+func TestService_TenantIsolation_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	acme, err := svc.CreateTenant(model.CreateTenantRequest{Slug: "acme", Name: "Acme", AdminSubject: "alice"})
+	if err != nil {
+		t.Fatalf("ошибка создания арендатора: %v", err)
+	}
+	globex, _ := svc.CreateTenant(model.CreateTenantRequest{Slug: "globex", Name: "Globex"})
+
+	acmeSvc := svc.WithPrincipal(auth.Principal{Subject: "alice", TenantID: acme.ID})
+	globexSvc := svc.WithPrincipal(auth.Principal{Subject: "system", Superuser: true, TenantID: globex.ID})
+
+	// Администратор нового арендатора может создавать подразделения
+	acmeDept, err := acmeSvc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering"})
+	if err != nil {
+		t.Fatalf("ошибка создания подразделения: %v", err)
+	}
+
+	// Одинаковые имена допустимы у разных арендаторов
+	if _, err := globexSvc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering"}); err != nil {
+		t.Errorf("ожидалось успешное создание у другого арендатора, получено %v", err)
+	}
+
+	// Подразделение другого арендатора не видно
+	if _, err := globexSvc.GetDepartmentTree(acmeDept.ID, 1, true); err != ErrNotFound {
+		t.Errorf("ожидалась ошибка ErrNotFound, получено %v", err)
+	}
+	if _, err := globexSvc.CreateEmployee(acmeDept.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer"}); err != ErrNotFound {
+		t.Errorf("ожидалась ошибка ErrNotFound, получено %v", err)
+	}
+
+	// Привязки ролей тоже изолированы
+	aliceInGlobex := svc.WithPrincipal(auth.Principal{Subject: "alice", TenantID: globex.ID})
+	if _, err := aliceInGlobex.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales"}); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+
+	if _, err := svc.CreateTenant(model.CreateTenantRequest{Slug: "acme", Name: "Acme 2"}); err != ErrDuplicateTenant {
+		t.Errorf("ожидалась ошибка ErrDuplicateTenant, получено %v", err)
+	}
+}
+
+// TestService_Outbox_Integration проверяет, что события пишутся только вместе с изменением
+func TestService_Outbox_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	a, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A"})
+	b, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "B"})
+	svc.UpdateDepartment(b.ID, model.UpdateDepartmentRequest{ParentID: &a.ID}, 0)
+
+	// Неудачное изменение не оставляет события
+	if _, err := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A"}); err != ErrDuplicateName {
+		t.Fatalf("ожидалась ошибка ErrDuplicateName, получено %v", err)
+	}
+	missing := 9999
+	if err := svc.DeleteDepartment(a.ID, "reassign", &missing, 0); err == nil {
+		t.Fatal("ожидалась ошибка удаления")
+	}
+
+	events, err := repo.GetUnpublishedOutboxEvents(100)
+	if err != nil {
+		t.Fatalf("ошибка чтения outbox: %v", err)
+	}
+	var types []string
+	for _, e := range events {
+		types = append(types, e.EventType)
+	}
+	expected := []string{model.EventDepartmentCreated, model.EventDepartmentCreated, model.EventDepartmentMoved}
+	if strings.Join(types, ",") != strings.Join(expected, ",") {
+		t.Errorf("ожидались события %v, получено %v", expected, types)
+	}
+	if events[2].DepartmentID == nil || *events[2].DepartmentID != b.ID {
+		t.Errorf("событие должно ссылаться на подразделение %d", b.ID)
+	}
+}
+
+// TestService_ListEvents_Integration проверяет ленту событий: порядок, фильтры и возобновление
+func TestService_ListEvents_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	// Публикация вручную, как это делает relay
+	publishAll := func() {
+		events, _ := repo.GetUnpublishedOutboxEvents(100)
+		for _, e := range events {
+			if err := repo.MarkOutboxEventPublished(e.ID, time.Now()); err != nil {
+				t.Fatalf("ошибка публикации: %v", err)
+			}
+		}
+	}
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Root"})
+	child, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Child", ParentID: &root.ID})
+	other, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Other"})
+	svc.CreateEmployee(child.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer"})
+
+	// Неопубликованные события в ленту не попадают
+	if events, _ := svc.ListEvents(0, nil, nil, 0); len(events) != 0 {
+		t.Fatalf("ожидалась пустая лента, получено %d", len(events))
+	}
+	publishAll()
+
+	all, err := svc.ListEvents(0, nil, nil, 0)
+	if err != nil {
+		t.Fatalf("ошибка чтения ленты: %v", err)
+	}
+	if len(all) != 4 || *all[0].Seq != 1 || *all[3].Seq != 4 {
+		t.Fatalf("ожидалось 4 события с seq 1..4, получено %d", len(all))
+	}
+
+	// Поддерево Root: создание Root, Child и сотрудника
+	subtree, _ := svc.ListEvents(0, &root.ID, nil, 0)
+	if len(subtree) != 3 {
+		t.Errorf("ожидалось 3 события поддерева, получено %d", len(subtree))
+	}
+
+	// Перемещение Other в Root видно из поддерева Root
+	svc.UpdateDepartment(other.ID, model.UpdateDepartmentRequest{ParentID: &child.ID}, 0)
+	publishAll()
+
+	moved, _ := svc.ListEvents(*all[3].Seq, &root.ID, []string{model.EventDepartmentMoved}, 0)
+	if len(moved) != 1 || *moved[0].DepartmentID != other.ID {
+		t.Errorf("ожидалось событие перемещения, получено %d", len(moved))
+	}
+
+	latest, _ := svc.LatestEventSeq(nil)
+	if latest != 5 {
+		t.Errorf("ожидался последний seq 5, получено %d", latest)
+	}
+}
+
+// TestService_OptimisticConcurrency_Integration проверяет отказ при устаревшей версии
+func TestService_OptimisticConcurrency_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	dept, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A"})
+	if dept.Version != 1 {
+		t.Fatalf("ожидалась версия 1, получено %d", dept.Version)
+	}
+
+	// Первый редактор успевает сохранить изменения
+	updated, err := svc.UpdateDepartment(dept.ID, model.UpdateDepartmentRequest{Name: "B"}, 1)
+	if err != nil {
+		t.Fatalf("ошибка обновления: %v", err)
+	}
+	if updated.Version != 2 {
+		t.Errorf("ожидалась версия 2, получено %d", updated.Version)
+	}
+
+	// Второй редактор работал с версией 1
+	if _, err := svc.UpdateDepartment(dept.ID, model.UpdateDepartmentRequest{Name: "C"}, 1); err != ErrVersionMismatch {
+		t.Errorf("ожидалась ошибка ErrVersionMismatch, получено %v", err)
+	}
+	if err := svc.DeleteDepartment(dept.ID, "cascade", nil, 1); err != ErrVersionMismatch {
+		t.Errorf("ожидалась ошибка ErrVersionMismatch, получено %v", err)
+	}
+
+	// Запись по устаревшей версии отклоняется и на уровне репозитория
+	stale := *dept
+	stale.Name = "D"
+	if err := repo.UpdateDepartment(&stale); err != repository.ErrVersionConflict {
+		t.Errorf("ожидалась ошибка ErrVersionConflict, получено %v", err)
+	}
+
+	current, _ := repo.GetDepartmentByID(dept.ID)
+	if current.Name != "B" || current.Version != 2 {
+		t.Errorf("ожидалось B версии 2, получено %s версии %d", current.Name, current.Version)
+	}
+
+	if err := svc.DeleteDepartment(dept.ID, "cascade", nil, 2); err != nil {
+		t.Errorf("ошибка удаления актуальной версии: %v", err)
+	}
+}
+
+// TestService_IdempotencyKey_Integration проверяет резервирование, повтор и повторное использование ключа
+func TestService_IdempotencyKey_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	alice := NewService(repo).WithPrincipal(auth.Principal{Subject: "alice", Superuser: true})
+	bob := NewService(repo).WithPrincipal(auth.Principal{Subject: "bob", Superuser: true})
+
+	record, err := alice.ReserveIdempotencyKey("import-1", "fp-1")
+	if err != nil || record.Completed() {
+		t.Fatalf("ожидалось новое резервирование, получено %+v, %v", record, err)
+	}
+
+	// Пока исходный запрос выполняется, повтор получает отказ
+	if _, err := alice.ReserveIdempotencyKey("import-1", "fp-1"); err != ErrIdempotencyKeyInProgress {
+		t.Errorf("ожидалась ошибка ErrIdempotencyKeyInProgress, получено %v", err)
+	}
+
+	if err := alice.CompleteIdempotencyKey(record, 201, []byte(`{"id":1}`), `"v1-abc"`); err != nil {
+		t.Fatalf("ошибка сохранения ответа: %v", err)
+	}
+
+	replay, err := alice.ReserveIdempotencyKey("import-1", "fp-1")
+	if err != nil || !replay.Completed() || replay.ResponseStatus != 201 || string(replay.ResponseBody) != `{"id":1}` {
+		t.Errorf("ожидался сохранённый ответ, получено %+v, %v", replay, err)
+	}
+
+	if _, err := alice.ReserveIdempotencyKey("import-1", "fp-2"); err != ErrIdempotencyKeyReused {
+		t.Errorf("ожидалась ошибка ErrIdempotencyKeyReused, получено %v", err)
+	}
+
+	// Ключи разных субъектов не пересекаются
+	other, err := bob.ReserveIdempotencyKey("import-1", "fp-2")
+	if err != nil || other.Completed() {
+		t.Errorf("ожидалось новое резервирование для другого субъекта, получено %v", err)
+	}
+
+	// Снятое резервирование позволяет повторить запрос
+	bob.ReleaseIdempotencyKey(other)
+	if again, err := bob.ReserveIdempotencyKey("import-1", "fp-2"); err != nil || again.Completed() {
+		t.Errorf("ожидалось повторное резервирование, получено %v", err)
+	}
+
+	// Истёкший ключ освобождается
+	db.Model(&model.IdempotencyKey{}).Where("subject = ?", "alice").Update("expires_at", time.Now().Add(-time.Minute))
+	if fresh, err := alice.ReserveIdempotencyKey("import-1", "fp-2"); err != nil || fresh.Completed() {
+		t.Errorf("ожидалось резервирование истёкшего ключа, получено %v", err)
+	}
+}
+
+// TestService_ExecuteBatch_Integration проверяет ссылки между операциями и атомарность пакета
+func TestService_ExecuteBatch_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	old, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Old"})
+	svc.CreateEmployee(old.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer"})
+
+	resp, err := svc.ExecuteBatch(model.BatchRequest{Operations: []model.BatchOperation{
+		{Op: model.BatchCreateDepartment, Ref: "eng", Name: "Engineering"},
+		{Op: model.BatchCreateDepartment, Ref: "be", Name: "Backend", ParentID: &model.BatchRef{Ref: "eng"}},
+		{Op: model.BatchCreateEmployee, DepartmentID: &model.BatchRef{Ref: "be"}, FullName: "Jane Doe", Position: "Lead"},
+		{Op: model.BatchDeleteDepartment, ID: &model.BatchRef{ID: old.ID}, Mode: "reassign", ReassignToDepartmentID: &model.BatchRef{Ref: "be"}},
+	}})
+	if err != nil {
+		t.Fatalf("ошибка пакета: %v", err)
+	}
+	if !resp.Committed || resp.Results[1].Department.ParentID == nil || *resp.Results[1].Department.ParentID != resp.Results[0].Department.ID {
+		t.Fatalf("неожиданный результат: %+v", resp)
+	}
+	eng, be := resp.Results[0].Department.ID, resp.Results[1].Department.ID
+	if emps, _ := repo.GetEmployeesByDeptID(be); len(emps) != 2 {
+		t.Errorf("ожидалось 2 сотрудника в Backend, получено %d", len(emps))
+	}
+
+	// Ошибка в последней операции откатывает весь пакет
+	var before int64
+	db.Model(&model.Department{}).Count(&before)
+	resp, err = svc.ExecuteBatch(model.BatchRequest{Operations: []model.BatchOperation{
+		{Op: model.BatchCreateDepartment, Ref: "qa", Name: "QA"},
+		{Op: model.BatchUpdateDepartment, ID: &model.BatchRef{ID: be}, ParentID: &model.BatchRef{Ref: "qa"}},
+		{Op: model.BatchCreateEmployee, DepartmentID: &model.BatchRef{ID: 9999}, FullName: "X", Position: "Y"},
+		{Op: model.BatchCreateDepartment, Name: "Never"},
+	}})
+	if err != ErrNotFound {
+		t.Fatalf("ожидалась ошибка ErrNotFound, получено %v", err)
+	}
+	statuses := []string{resp.Results[0].Status, resp.Results[1].Status, resp.Results[2].Status, resp.Results[3].Status}
+	expected := []string{model.BatchStatusRolledBack, model.BatchStatusRolledBack, model.BatchStatusFailed, model.BatchStatusSkipped}
+	if strings.Join(statuses, ",") != strings.Join(expected, ",") || resp.Committed {
+		t.Errorf("ожидались статусы %v, получено %v", expected, statuses)
+	}
+
+	var after int64
+	db.Model(&model.Department{}).Count(&after)
+	if after != before {
+		t.Errorf("пакет должен быть откатан: было %d подразделений, стало %d", before, after)
+	}
+	if dept, _ := repo.GetDepartmentByID(be); dept.ParentID == nil || *dept.ParentID != eng {
+		t.Error("перемещение Backend должно быть откатано")
+	}
+}
+
+// TestService_PatchDepartment_Integration проверяет merge patch и JSON Patch подразделения
+func TestService_PatchDepartment_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Root"})
+	child, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Child", ParentID: &root.ID})
+
+	// null в parent_id переносит подразделение в корень, имя не меняется
+	moved, err := svc.PatchDepartment(child.ID, model.DepartmentPatch{ParentID: model.Null[int]()}, child.Version)
+	if err != nil {
+		t.Fatalf("ошибка merge patch: %v", err)
+	}
+	if moved.ParentID != nil || moved.Name != "Child" {
+		t.Errorf("ожидался корневой Child, получено %+v", moved)
+	}
+
+	// Неудачная проверка test не меняет подразделение
+	ops := []byte(`[{"op":"test","path":"/name","value":"Other"},{"op":"replace","path":"/name","value":"X"}]`)
+	if _, err := svc.ApplyDepartmentJSONPatch(child.ID, ops, 0); !errors.Is(err, jsonpatch.ErrTestFailed) {
+		t.Errorf("ожидалась ошибка ErrTestFailed, получено %v", err)
+	}
+
+	ops = []byte(fmt.Sprintf(`[{"op":"test","path":"/name","value":"Child"},{"op":"add","path":"/parent_id","value":%d}]`, root.ID))
+	patched, err := svc.ApplyDepartmentJSONPatch(child.ID, ops, 0)
+	if err != nil {
+		t.Fatalf("ошибка JSON Patch: %v", err)
+	}
+	if patched.ParentID == nil || *patched.ParentID != root.ID || patched.Version != 3 {
+		t.Errorf("ожидался Child под Root версии 3, получено %+v", patched)
+	}
+
+	// Удаление name оставляет подразделение без имени
+	if _, err := svc.ApplyDepartmentJSONPatch(child.ID, []byte(`[{"op":"remove","path":"/name"}]`), 0); err == nil {
+		t.Error("ожидалась ошибка при удалении имени")
+	}
+}
+
+// TestService_PatchEmployee_Integration проверяет изменение и перевод сотрудника
+func TestService_PatchEmployee_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	a, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A"})
+	b, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "B"})
+	hiredAt := "2024-01-15"
+	emp, _ := svc.CreateEmployee(a.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer", HiredAt: &hiredAt})
+
+	if _, err := svc.GetEmployee(b.ID, emp.ID); err != ErrNotFound {
+		t.Errorf("ожидалась ошибка ErrNotFound для чужого подразделения, получено %v", err)
+	}
+
+	updated, err := svc.PatchEmployee(a.ID, emp.ID, model.EmployeePatch{
+		Position: model.Some("Lead"),
+		HiredAt:  model.Null[string](),
+	}, emp.Version)
+	if err != nil {
+		t.Fatalf("ошибка изменения сотрудника: %v", err)
+	}
+	if updated.Position != "Lead" || updated.HiredAt != nil || updated.FullName != "John Doe" {
+		t.Errorf("неожиданный сотрудник: %+v", updated)
+	}
+
+	if _, err := svc.PatchEmployee(a.ID, emp.ID, model.EmployeePatch{Position: model.Some("CTO")}, emp.Version); err != ErrVersionMismatch {
+		t.Errorf("ожидалась ошибка ErrVersionMismatch, получено %v", err)
+	}
+
+	ops := []byte(fmt.Sprintf(`[{"op":"replace","path":"/department_id","value":%d}]`, b.ID))
+	transferred, err := svc.ApplyEmployeeJSONPatch(a.ID, emp.ID, ops, 0)
+	if err != nil {
+		t.Fatalf("ошибка перевода сотрудника: %v", err)
+	}
+	if transferred.DepartmentID != b.ID {
+		t.Errorf("ожидался перевод в B, получено %d", transferred.DepartmentID)
+	}
+
+	var event model.OutboxEvent
+	db.Where("event_type = ?", model.EventEmployeeUpdated).Order("id DESC").First(&event)
+	if !slices.Contains(event.DepartmentPath, a.ID) || !slices.Contains(event.DepartmentPath, b.ID) {
+		t.Errorf("событие перевода должно попадать в оба подразделения, получено %v", event.DepartmentPath)
+	}
+}
+
 // BenchmarkService_CreateDepartment_Integration бенчмарк
 func BenchmarkService_CreateDepartment_Integration(b *testing.B) {
 	pgContainer, db, ctx := setupTestContainer(b)
@@ -399,3 +958,1109 @@ func BenchmarkService_CreateDepartment_Integration(b *testing.B) {
 func strPtr(s string) *string {
 	return &s
 }
+
+// TestService_SCIM_Integration тестирует поиск по фильтру SCIM и атомарное изменение состава подразделения
+func TestService_SCIM_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	svc := NewService(repository.NewRepository(db))
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	backend, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Backend", ParentID: &root.ID})
+	platform, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Platform"})
+
+	userName := "Ivan.Petrov@example.com"
+	ivan, err := svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Иван Петров", Position: "Developer", UserName: &userName})
+	if err != nil {
+		t.Fatalf("ошибка создания сотрудника: %v", err)
+	}
+	anna, _ := svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Анна Смирнова", Position: "Analyst"})
+
+	duplicate := "ivan.petrov@EXAMPLE.com"
+	if _, err := svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Другой Иван", Position: "QA", UserName: &duplicate}); err != ErrDuplicateUserName {
+		t.Errorf("ожидалась ErrDuplicateUserName, получено %v", err)
+	}
+	reserved := "employee-999"
+	if _, err := svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Другой Иван", Position: "QA", UserName: &reserved}); err == nil {
+		t.Error("ожидалась ошибка для зарезервированного логина")
+	}
+
+	// Фильтры по логину без учёта регистра и по логину по умолчанию
+	for filter, expected := range map[string]int{
+		`userName eq "ivan.petrov@example.com"`:                            ivan.ID,
+		fmt.Sprintf(`userName eq "employee-%d"`, anna.ID):                  anna.ID,
+		`displayName sw "анна" and not (userName co "example")`:            anna.ID,
+		fmt.Sprintf(`%s:departmentId eq %d`, scim.SchemaEmployee, root.ID): ivan.ID,
+	} {
+		where, args, err := scim.Where(filter, scim.UserAttributes)
+		if err != nil {
+			t.Fatalf("%s: %v", filter, err)
+		}
+		found, total, err := svc.SearchEmployees(where, args, 0, 10)
+		if err != nil {
+			t.Fatalf("%s: %v", filter, err)
+		}
+		if total != 1 || len(found) != 1 || found[0].ID != expected {
+			t.Errorf("%s: ожидался сотрудник %d, получено %d (%+v)", filter, expected, total, found)
+		}
+	}
+	_, total, err := svc.SearchEmployees("", nil, 0, 0)
+	if err != nil || total != 2 {
+		t.Errorf("ожидалось 2 сотрудника без фильтра, получено %d (%v)", total, err)
+	}
+
+	// Добавление сотрудника переводит его, добавление подразделения делает его дочерним
+	dept, err := svc.UpdateDepartmentMembers(backend.ID, model.DepartmentPatch{Name: model.Some("Backend Team")}, []model.MembershipChange{
+		{Kind: model.MemberEmployee, ID: ivan.ID},
+		{Kind: model.MemberDepartment, ID: platform.ID},
+	}, 0)
+	if err != nil {
+		t.Fatalf("ошибка изменения состава: %v", err)
+	}
+	if dept.Name != "Backend Team" {
+		t.Errorf("ожидалось имя 'Backend Team', получено %q", dept.Name)
+	}
+	moved, _ := svc.FindEmployee(ivan.ID)
+	if moved.DepartmentID != backend.ID {
+		t.Errorf("ожидался перевод в %d, получено %d", backend.ID, moved.DepartmentID)
+	}
+	child, _ := svc.GetDepartment(platform.ID)
+	if child.ParentID == nil || *child.ParentID != backend.ID {
+		t.Errorf("ожидался родитель %d, получено %v", backend.ID, child.ParentID)
+	}
+
+	// Удаление сотрудника из подразделения запрещено, и вся операция откатывается
+	_, err = svc.UpdateDepartmentMembers(backend.ID, model.DepartmentPatch{Name: model.Some("Renamed")}, []model.MembershipChange{
+		{Remove: true, Kind: model.MemberDepartment, ID: platform.ID},
+		{Remove: true, ID: anna.ID},
+	}, 0)
+	if err != ErrEmployeeMembership {
+		t.Fatalf("ожидалась ErrEmployeeMembership, получено %v", err)
+	}
+	after, _ := svc.GetDepartment(backend.ID)
+	child, _ = svc.GetDepartment(platform.ID)
+	if after.Name != "Backend Team" || child.ParentID == nil {
+		t.Errorf("ожидался откат: имя %q, родитель %v", after.Name, child.ParentID)
+	}
+
+	// Удаление дочернего подразделения переносит его в корень
+	if _, err := svc.UpdateDepartmentMembers(backend.ID, model.DepartmentPatch{}, []model.MembershipChange{{Remove: true, ID: platform.ID}}, 0); err != nil {
+		t.Fatalf("ошибка удаления участника: %v", err)
+	}
+	child, _ = svc.GetDepartment(platform.ID)
+	if child.ParentID != nil {
+		t.Errorf("ожидался перенос в корень, получено %v", child.ParentID)
+	}
+}
+
+// TestService_ExportDirectory_Integration тестирует выгрузку всей организации и проверку роли
+func TestService_ExportDirectory_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	svc := NewService(repository.NewRepository(db))
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Acme"})
+	child, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales, EMEA", ParentID: &root.ID})
+	svc.CreateEmployee(child.ID, model.CreateEmployeeRequest{FullName: "Анна Смирнова", Position: "Manager"})
+
+	depts, employees, err := svc.ExportDirectory()
+	if err != nil {
+		t.Fatalf("ошибка выгрузки: %v", err)
+	}
+	if len(depts) != 2 || len(employees) != 1 {
+		t.Errorf("ожидалось 2 подразделения и 1 сотрудник, получено %d и %d", len(depts), len(employees))
+	}
+
+	// Роль на поддерево недостаточна для выгрузки всей организации
+	if _, err := svc.CreateRoleBinding(model.CreateRoleBindingRequest{Subject: "viewer", Role: model.RoleViewer, DepartmentID: &child.ID}); err != nil {
+		t.Fatalf("ошибка выдачи роли: %v", err)
+	}
+	scoped := svc.WithPrincipal(auth.Principal{Subject: "viewer"})
+	if _, _, err := scoped.ExportDirectory(); err != ErrForbidden {
+		t.Errorf("ожидалась ErrForbidden, получено %v", err)
+	}
+}
+
+// TestService_DepartmentMetadata_Integration проверяет код, центр затрат, признак активности и фильтры списка
+func TestService_DepartmentMetadata_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	code, costCenter := " sales ", "CC-100"
+	sales, err := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales", Code: &code, CostCenter: &costCenter})
+	if err != nil {
+		t.Fatalf("ошибка создания: %v", err)
+	}
+	if sales.Code == nil || *sales.Code != "SALES" || !sales.Active {
+		t.Errorf("ожидался активный SALES, получено %+v", sales)
+	}
+
+	// Код уникален в организации, а не только среди соседей
+	dup := "Sales"
+	if _, err := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "EU", ParentID: &sales.ID, Code: &dup}); err != ErrDuplicateCode {
+		t.Errorf("ожидалась ошибка ErrDuplicateCode, получено %v", err)
+	}
+
+	inactive := false
+	archive, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Archive", ParentID: &sales.ID, Active: &inactive})
+
+	found, err := svc.GetDepartmentByCode("sales")
+	if err != nil || found.ID != sales.ID {
+		t.Fatalf("ожидался поиск SALES по коду, получено %+v, %v", found, err)
+	}
+
+	active := true
+	list, err := svc.ListDepartments(model.DepartmentFilter{Active: &active, CostCenter: "CC-100"})
+	if err != nil || len(list) != 1 || list[0].ID != sales.ID {
+		t.Errorf("ожидался только Sales, получено %+v, %v", list, err)
+	}
+	list, _ = svc.ListDepartments(model.DepartmentFilter{ParentID: &sales.ID})
+	if len(list) != 1 || list[0].ID != archive.ID {
+		t.Errorf("ожидался только Archive, получено %+v", list)
+	}
+
+	// Изменение одних метаданных публикует department.updated и очищает код
+	patched, err := svc.PatchDepartment(sales.ID, model.DepartmentPatch{Code: model.Null[string](), Active: model.Some(false)}, 0)
+	if err != nil {
+		t.Fatalf("ошибка merge patch: %v", err)
+	}
+	if patched.Code != nil || patched.Active {
+		t.Errorf("ожидался неактивный отдел без кода, получено %+v", patched)
+	}
+	events, _ := svc.ListEvents(0, nil, []string{model.EventDepartmentUpdated}, 10)
+	if len(events) != 1 {
+		t.Errorf("ожидалось одно событие department.updated, получено %d", len(events))
+	}
+	if _, err := svc.GetDepartmentByCode("SALES"); err != ErrNotFound {
+		t.Errorf("ожидалась ошибка ErrNotFound, получено %v", err)
+	}
+}
+
+// TestService_CustomAttributes_Integration проверяет схемы атрибутов, проверку значений и поиск по ним
+func TestService_CustomAttributes_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	_, err := svc.CreateAttributeDefinition(model.CreateAttributeDefinitionRequest{
+		Entity: model.AttributeEntityEmployee, Name: "office", Type: model.AttributeTypeString, Required: true,
+	})
+	if err != nil {
+		t.Fatalf("ошибка создания схемы: %v", err)
+	}
+	floor, _ := svc.CreateAttributeDefinition(model.CreateAttributeDefinitionRequest{
+		Entity: model.AttributeEntityEmployee, Name: "floor", Type: model.AttributeTypeNumber,
+	})
+	if _, err := svc.CreateAttributeDefinition(model.CreateAttributeDefinitionRequest{
+		Entity: model.AttributeEntityEmployee, Name: "office", Type: model.AttributeTypeString,
+	}); err != ErrDuplicateAttribute {
+		t.Errorf("ожидалась ошибка ErrDuplicateAttribute, получено %v", err)
+	}
+
+	dept, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering"})
+
+	// Обязательный атрибут проверяется при создании
+	if _, err := svc.CreateEmployee(dept.ID, model.CreateEmployeeRequest{FullName: "Ann", Position: "Dev"}); !errors.Is(err, ErrInvalidAttributes) {
+		t.Errorf("ожидалась ошибка ErrInvalidAttributes, получено %v", err)
+	}
+	ann, err := svc.CreateEmployee(dept.ID, model.CreateEmployeeRequest{
+		FullName: "Ann", Position: "Dev", Attributes: model.Attributes{"office": "Berlin", "floor": float64(3)},
+	})
+	if err != nil {
+		t.Fatalf("ошибка создания сотрудника: %v", err)
+	}
+	svc.CreateEmployee(dept.ID, model.CreateEmployeeRequest{FullName: "Bob", Position: "Dev", Attributes: model.Attributes{"office": "Paris"}})
+
+	found, err := svc.ListEmployees(model.EmployeeFilter{Attributes: model.Attributes{"office": "Berlin", "floor": "3"}})
+	if err != nil || len(found) != 1 || found[0].ID != ann.ID {
+		t.Errorf("ожидалась только Ann, получено %+v, %v", found, err)
+	}
+	if _, err := svc.ListEmployees(model.EmployeeFilter{Attributes: model.Attributes{"floor": "third"}}); !errors.Is(err, ErrInvalidAttributes) {
+		t.Errorf("ожидалась ошибка ErrInvalidAttributes, получено %v", err)
+	}
+
+	// null в merge patch удаляет атрибут, обязательный атрибут удалить нельзя
+	patched, err := svc.PatchEmployee(dept.ID, ann.ID, model.EmployeePatch{Attributes: model.Some(&model.Attributes{"floor": nil})}, 0)
+	if err != nil {
+		t.Fatalf("ошибка merge patch: %v", err)
+	}
+	if _, ok := patched.Attributes["floor"]; ok || patched.Attributes["office"] != "Berlin" {
+		t.Errorf("ожидался только office, получено %v", patched.Attributes)
+	}
+	if _, err := svc.ApplyEmployeeJSONPatch(dept.ID, ann.ID, []byte(`[{"op":"remove","path":"/attributes/office"}]`), 0); !errors.Is(err, ErrInvalidAttributes) {
+		t.Errorf("ожидалась ошибка ErrInvalidAttributes, получено %v", err)
+	}
+
+	// Удаление схемы удаляет значения атрибута
+	svc.PatchEmployee(dept.ID, ann.ID, model.EmployeePatch{Attributes: model.Some(&model.Attributes{"floor": float64(4)})}, 0)
+	if err := svc.DeleteAttributeDefinition(floor.ID); err != nil {
+		t.Fatalf("ошибка удаления схемы: %v", err)
+	}
+	emp, _ := svc.GetEmployee(dept.ID, ann.ID)
+	if _, ok := emp.Attributes["floor"]; ok {
+		t.Errorf("ожидалось удаление floor, получено %v", emp.Attributes)
+	}
+}
+
+func TestService_EmployeeStatus_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	dept, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering"})
+	pending := model.EmployeePending
+	ann, err := svc.CreateEmployee(dept.ID, model.CreateEmployeeRequest{FullName: "Ann", Position: "Dev", Status: &pending})
+	if err != nil || ann.Status != model.EmployeePending {
+		t.Fatalf("ожидался сотрудник в статусе pending: %+v, %v", ann, err)
+	}
+	bob, _ := svc.CreateEmployee(dept.ID, model.CreateEmployeeRequest{FullName: "Bob", Position: "Dev"})
+	if bob.Status != model.EmployeeActive {
+		t.Errorf("ожидался статус active по умолчанию, получено %s", bob.Status)
+	}
+	terminated := model.EmployeeTerminated
+	if _, err := svc.CreateEmployee(dept.ID, model.CreateEmployeeRequest{FullName: "Eve", Position: "Dev", Status: &terminated}); err == nil {
+		t.Error("ожидалась ошибка для создания уволенного сотрудника")
+	}
+
+	// В дереве и численности по умолчанию только работающие сотрудники
+	tree, _ := svc.GetDepartmentTree(dept.ID, 1, true)
+	if len(tree.Employees) != 1 || tree.Employees[0].ID != bob.ID {
+		t.Errorf("ожидался только Bob, получено %+v", tree.Employees)
+	}
+	tree, _ = svc.GetDepartmentTreeByStatus(dept.ID, 1, true, model.EmployeeStatuses)
+	if len(tree.Employees) != 2 {
+		t.Errorf("ожидались оба сотрудника, получено %d", len(tree.Employees))
+	}
+
+	ann, err = svc.ChangeEmployeeStatus(dept.ID, ann.ID, model.ChangeEmployeeStatusRequest{Status: model.EmployeeActive}, ann.Version)
+	if err != nil {
+		t.Fatalf("ошибка выхода на работу: %v", err)
+	}
+	if _, err := svc.ChangeEmployeeStatus(dept.ID, ann.ID, model.ChangeEmployeeStatusRequest{Status: model.EmployeeOnLeave}, ann.Version-1); err != ErrVersionMismatch {
+		t.Errorf("ожидалась ошибка ErrVersionMismatch, получено %v", err)
+	}
+	if _, err := svc.ChangeEmployeeStatus(dept.ID, ann.ID, model.ChangeEmployeeStatusRequest{Status: model.EmployeePending}, 0); err != ErrInvalidTransition {
+		t.Errorf("ожидалась ошибка ErrInvalidTransition, получено %v", err)
+	}
+	svc.ChangeEmployeeStatus(dept.ID, ann.ID, model.ChangeEmployeeStatusRequest{Status: model.EmployeeOnLeave}, 0)
+
+	reason := "relocation"
+	date := "2026-10-01"
+	bob, err = svc.ChangeEmployeeStatus(dept.ID, bob.ID, model.ChangeEmployeeStatusRequest{Status: model.EmployeeTerminated, EffectiveDate: &date, Reason: &reason}, 0)
+	if err != nil {
+		t.Fatalf("ошибка увольнения: %v", err)
+	}
+	stored, _ := svc.GetEmployee(dept.ID, bob.ID)
+	if stored.Status != model.EmployeeTerminated || stored.TerminatedAt == nil || stored.TerminatedAt.Format("2006-01-02") != date ||
+		stored.TerminationReason == nil || *stored.TerminationReason != reason {
+		t.Errorf("неверные данные увольнения: %+v", stored)
+	}
+	if _, err := svc.ChangeEmployeeStatus(dept.ID, bob.ID, model.ChangeEmployeeStatusRequest{Status: model.EmployeeActive}, 0); err != ErrInvalidTransition {
+		t.Errorf("ожидалась ошибка ErrInvalidTransition, получено %v", err)
+	}
+
+	counts, _ := svc.LoadHeadcounts([]int{dept.ID}, nil)
+	if counts[dept.ID] != 0 {
+		t.Errorf("ожидалась нулевая численность работающих, получено %d", counts[dept.ID])
+	}
+	counts, _ = svc.LoadHeadcounts([]int{dept.ID}, []string{model.EmployeeActive, model.EmployeeOnLeave})
+	if counts[dept.ID] != 1 {
+		t.Errorf("ожидался один сотрудник в штате, получено %d", counts[dept.ID])
+	}
+	found, _ := svc.ListEmployees(model.EmployeeFilter{Statuses: []string{model.EmployeeTerminated}})
+	if len(found) != 1 || found[0].ID != bob.ID {
+		t.Errorf("ожидался только Bob, получено %+v", found)
+	}
+
+	var events int64
+	db.Model(&model.OutboxEvent{}).Where("event_type = ?", model.EventEmployeeStatus).Count(&events)
+	if events != 3 {
+		t.Errorf("ожидалось 3 события смены статуса, получено %d", events)
+	}
+}
+
+func TestService_Positions_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	family, grade := "Engineering", 5
+	senior, err := svc.CreatePosition(model.CreatePositionRequest{Title: "Senior Developer", JobFamily: &family, Grade: &grade})
+	if err != nil {
+		t.Fatalf("ошибка создания должности: %v", err)
+	}
+	if _, err := svc.CreatePosition(model.CreatePositionRequest{Title: "sr. developer"}); err != ErrDuplicatePosition {
+		t.Errorf("ожидалась ошибка ErrDuplicatePosition, получено %v", err)
+	}
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	backend, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Backend", ParentID: &root.ID})
+
+	// Свободное название связывается с каталогом по нормализованному ключу
+	ann, err := svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Ann", Position: "Sr developer"})
+	if err != nil {
+		t.Fatalf("ошибка создания сотрудника: %v", err)
+	}
+	if ann.PositionID == nil || *ann.PositionID != senior.ID || ann.Position != "Senior Developer" {
+		t.Errorf("ожидалась должность из каталога, получено %+v", ann)
+	}
+	bob, _ := svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Bob", PositionID: &senior.ID})
+	if bob.Position != "Senior Developer" {
+		t.Errorf("ожидалось название из каталога, получено %q", bob.Position)
+	}
+	eve, _ := svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Eve", Position: "CEO"})
+	if eve.PositionID != nil {
+		t.Errorf("ожидался сотрудник без должности из каталога, получено %v", *eve.PositionID)
+	}
+	if _, err := svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Joe", Position: "CEO", PositionID: &senior.ID}); err == nil {
+		t.Error("ожидалась ошибка несовпадения названия и position_id")
+	}
+	svc.ChangeEmployeeStatus(backend.ID, bob.ID, model.ChangeEmployeeStatusRequest{Status: model.EmployeeTerminated}, 0)
+
+	// Переименование переносится в записи сотрудников
+	title := "Senior Software Engineer"
+	if _, err := svc.UpdatePosition(senior.ID, model.UpdatePositionRequest{Title: &title}); err != nil {
+		t.Fatalf("ошибка изменения должности: %v", err)
+	}
+	stored, _ := svc.GetEmployee(backend.ID, ann.ID)
+	if stored.Position != title || stored.Version != ann.Version+1 {
+		t.Errorf("ожидалось новое название и версия, получено %q, v%d", stored.Position, stored.Version)
+	}
+
+	if err := svc.DeletePosition(senior.ID); err != ErrPositionInUse {
+		t.Errorf("ожидалась ошибка ErrPositionInUse, получено %v", err)
+	}
+
+	report, err := svc.PositionReport(nil, nil)
+	if err != nil {
+		t.Fatalf("ошибка отчёта: %v", err)
+	}
+	if report.Total != 2 || len(report.ByJobFamilyAndGrade) != 2 || len(report.ByJobFamily) != 2 || len(report.ByGrade) != 2 {
+		t.Errorf("неверный отчёт: %+v", report)
+	}
+	if row := report.ByJobFamily[0]; row.JobFamily == nil || *row.JobFamily != family || row.Headcount != 1 {
+		t.Errorf("неверная строка семейства: %+v", row)
+	}
+	report, _ = svc.PositionReport(&backend.ID, model.EmployeeStatuses)
+	if report.Total != 2 || len(report.ByGrade) != 1 || *report.ByGrade[0].Grade != grade || report.ByGrade[0].Headcount != 2 {
+		t.Errorf("неверный отчёт по поддереву: %+v", report)
+	}
+
+	// Отвязка сохраняет название, после чего должность можно удалить
+	for _, id := range []int{ann.ID, bob.ID} {
+		if _, err := svc.PatchEmployee(backend.ID, id, model.EmployeePatch{PositionID: model.Null[int]()}, 0); err != nil {
+			t.Fatalf("ошибка отвязки: %v", err)
+		}
+	}
+	if err := svc.DeletePosition(senior.ID); err != nil {
+		t.Errorf("ошибка удаления должности: %v", err)
+	}
+}
+
+func TestService_Staffing_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	dev, err := svc.CreatePosition(model.CreatePositionRequest{Title: "Developer"})
+	if err != nil {
+		t.Fatalf("ошибка создания должности: %v", err)
+	}
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	backend, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Backend", ParentID: &root.ID})
+	svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Eve"})
+	svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Ann"})
+	pending := model.EmployeePending
+	svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Bob", Status: &pending})
+
+	five, ten := 5, 10
+	if _, err := svc.SetBudget(root.ID, "2026-Q3", model.SetBudgetRequest{ApprovedHeadcount: &five}); err != nil {
+		t.Fatalf("ошибка установки бюджета: %v", err)
+	}
+	// Повторная установка заменяет бюджет на период
+	if _, err := svc.SetBudget(backend.ID, "2026-Q3", model.SetBudgetRequest{ApprovedHeadcount: &five}); err != nil {
+		t.Fatalf("ошибка установки бюджета: %v", err)
+	}
+	if _, err := svc.SetBudget(backend.ID, "2026-Q3", model.SetBudgetRequest{ApprovedHeadcount: &ten}); err != nil {
+		t.Fatalf("ошибка замены бюджета: %v", err)
+	}
+	svc.SetBudget(backend.ID, "2026-Q4", model.SetBudgetRequest{ApprovedHeadcount: &five})
+	if budgets, _ := svc.ListBudgets(backend.ID); len(budgets) != 2 || budgets[0].ApprovedHeadcount != ten {
+		t.Errorf("неверные бюджеты: %+v", budgets)
+	}
+	if _, err := svc.SetBudget(root.ID, "Q3-2026", model.SetBudgetRequest{ApprovedHeadcount: &five}); err == nil {
+		t.Error("ожидалась ошибка формата периода")
+	}
+
+	first, err := svc.CreateRequisition(backend.ID, model.CreateRequisitionRequest{PositionID: dev.ID, TargetStartDate: "2026-09-01"})
+	if err != nil {
+		t.Fatalf("ошибка создания вакансии: %v", err)
+	}
+	second, _ := svc.CreateRequisition(backend.ID, model.CreateRequisitionRequest{PositionID: dev.ID, TargetStartDate: "2026-10-01"})
+	if _, err := svc.CreateRequisition(backend.ID, model.CreateRequisitionRequest{PositionID: dev.ID + 100, TargetStartDate: "2026-10-01"}); err == nil {
+		t.Error("ожидалась ошибка несуществующей должности")
+	}
+	filled := model.RequisitionFilled
+	if _, err := svc.UpdateRequisition(backend.ID, second.ID, model.UpdateRequisitionRequest{Status: &filled}, second.Version); err != nil {
+		t.Fatalf("ошибка закрытия вакансии: %v", err)
+	}
+	if _, err := svc.UpdateRequisition(backend.ID, second.ID, model.UpdateRequisitionRequest{Status: &filled}, second.Version); err != ErrVersionMismatch {
+		t.Errorf("ожидалась ошибка ErrVersionMismatch, получено %v", err)
+	}
+	if open, _ := svc.ListRequisitions(backend.ID, model.RequisitionOpen); len(open) != 1 || open[0].ID != first.ID {
+		t.Errorf("неверный список открытых вакансий: %+v", open)
+	}
+
+	staffing, err := svc.GetStaffing(root.ID, "2026-Q3", nil)
+	if err != nil {
+		t.Fatalf("ошибка расчёта укомплектованности: %v", err)
+	}
+	if staffing.Budget != 15 || staffing.Actual != 2 || staffing.Open != 1 || staffing.Available != 12 || len(staffing.Departments) != 2 {
+		t.Errorf("неверная укомплектованность: %+v", staffing)
+	}
+	if row := staffing.Departments[1]; row.DepartmentID != backend.ID || row.Budget == nil || *row.Budget != ten || row.Actual != 1 || row.Open != 1 {
+		t.Errorf("неверная строка подразделения: %+v", row)
+	}
+	staffing, _ = svc.GetStaffing(backend.ID, "2027", model.EmployeeStatuses)
+	if staffing.Budget != 0 || staffing.Actual != 2 || staffing.Available != -3 || staffing.Departments[0].Budget != nil {
+		t.Errorf("неверная укомплектованность без бюджета: %+v", staffing)
+	}
+
+	// Должность с вакансиями удалить нельзя
+	if err := svc.DeletePosition(dev.ID); err != ErrPositionInUse {
+		t.Errorf("ожидалась ошибка ErrPositionInUse, получено %v", err)
+	}
+	if err := svc.DeleteBudget(backend.ID, "2026-Q4"); err != nil {
+		t.Errorf("ошибка удаления бюджета: %v", err)
+	}
+	if err := svc.DeleteBudget(backend.ID, "2026-Q4"); err != ErrNotFound {
+		t.Errorf("ожидалась ошибка ErrNotFound, получено %v", err)
+	}
+}
+
+func TestService_DepartmentStats_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering"})
+	backend, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Backend", ParentID: &root.ID})
+	platform, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Platform", ParentID: &backend.ID})
+	svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Frontend", ParentID: &root.ID})
+
+	daysAgo := func(days int) *string {
+		date := time.Now().UTC().AddDate(0, 0, -days).Format("2006-01-02")
+		return &date
+	}
+	svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Eve", HiredAt: daysAgo(10)})
+	svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Ann", HiredAt: daysAgo(60)})
+	svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Bob", HiredAt: daysAgo(2 * 365)})
+	svc.CreateEmployee(platform.ID, model.CreateEmployeeRequest{FullName: "Joe", HiredAt: daysAgo(6 * 365)})
+	svc.CreateEmployee(platform.ID, model.CreateEmployeeRequest{FullName: "Kim"})
+	pending := model.EmployeePending
+	svc.CreateEmployee(platform.ID, model.CreateEmployeeRequest{FullName: "Lee", Status: &pending, HiredAt: daysAgo(-30)})
+
+	stats, err := svc.GetDepartmentStats(root.ID, nil)
+	if err != nil {
+		t.Fatalf("ошибка расчёта показателей: %v", err)
+	}
+	if stats.DirectHeadcount != 1 || stats.TotalHeadcount != 5 || stats.DescendantDepartments != 3 || stats.MaxDepth != 2 || stats.AverageTeamSize != 1.25 {
+		t.Errorf("неверные показатели: %+v", stats)
+	}
+	if stats.Hires != (model.HireCounts{Last30Days: 1, Last90Days: 2, Last365Days: 2}) {
+		t.Errorf("неверный найм: %+v", stats.Hires)
+	}
+	want := []model.TenureBucket{
+		{Bucket: model.TenureUnderOneYear, Headcount: 2},
+		{Bucket: model.TenureOneToThreeYears, Headcount: 1},
+		{Bucket: model.TenureThreeToFiveYears, Headcount: 0},
+		{Bucket: model.TenureFiveYearsPlus, Headcount: 1},
+		{Bucket: model.TenureUnknown, Headcount: 1},
+		{Bucket: model.TenureNotStarted, Headcount: 0},
+	}
+	if !slices.Equal(stats.Tenure, want) {
+		t.Errorf("неверное распределение стажа: %+v", stats.Tenure)
+	}
+
+	// Будущая дата приёма не попадает ни в найм, ни в стаж до года
+	stats, err = svc.GetDepartmentStats(root.ID, []string{model.EmployeeActive, model.EmployeePending})
+	if err != nil {
+		t.Fatalf("ошибка расчёта показателей: %v", err)
+	}
+	if stats.TotalHeadcount != 6 || stats.Hires != (model.HireCounts{Last30Days: 1, Last90Days: 2, Last365Days: 2}) {
+		t.Errorf("неверные показатели с ожидающими выхода: %+v", stats)
+	}
+	if stats.Tenure[0].Headcount != 2 || stats.Tenure[5] != (model.TenureBucket{Bucket: model.TenureNotStarted, Headcount: 1}) {
+		t.Errorf("будущий сотрудник должен попасть в not_started: %+v", stats.Tenure)
+	}
+
+	// Лист без сотрудников в выбранных статусах
+	stats, err = svc.GetDepartmentStats(platform.ID, []string{model.EmployeeTerminated})
+	if err != nil {
+		t.Fatalf("ошибка расчёта показателей: %v", err)
+	}
+	if stats.TotalHeadcount != 0 || stats.DescendantDepartments != 0 || stats.MaxDepth != 0 || stats.AverageTeamSize != 0 || stats.Tenure[4].Headcount != 0 {
+		t.Errorf("неверные показатели пустого поддерева: %+v", stats)
+	}
+	if _, err := svc.GetDepartmentStats(platform.ID+100, nil); err != ErrNotFound {
+		t.Errorf("ожидалась ошибка ErrNotFound, получено %v", err)
+	}
+}
+
+func TestService_LintOrg_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	sales, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales", ParentID: &root.ID})
+	legal, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Legal", ParentID: &root.ID})
+	svc.CreateEmployee(root.ID, model.CreateEmployeeRequest{FullName: "Eve"})
+	for _, name := range []string{"Ann", "Bob", "Joe"} {
+		svc.CreateEmployee(sales.ID, model.CreateEmployeeRequest{FullName: name})
+	}
+	// Сотрудник, ещё не вышедший на работу, не делает подразделение непустым
+	pending := model.EmployeePending
+	svc.CreateEmployee(legal.ID, model.CreateEmployeeRequest{FullName: "Kim", Status: &pending})
+
+	report, err := svc.LintOrg(lint.Config{MaxDepth: 6, MaxTeamSize: 2})
+	if err != nil {
+		t.Fatalf("ошибка проверки: %v", err)
+	}
+	if len(report.Findings) != 2 {
+		t.Fatalf("ожидалось 2 находки, получено %+v", report.Findings)
+	}
+	if f := report.Findings[0]; f.Rule != lint.RuleEmptyDepartment || f.DepartmentID != legal.ID || strings.Join(f.Path, "/") != "Company/Legal" {
+		t.Errorf("неверная находка: %+v", f)
+	}
+	if f := report.Findings[1]; f.Rule != lint.RuleTeamSize || f.DepartmentID != sales.ID {
+		t.Errorf("неверная находка: %+v", f)
+	}
+	if _, err := svc.LintOrg(lint.Config{}); err == nil {
+		t.Error("ожидалась ошибка некорректной конфигурации")
+	}
+}
+
+func TestService_Scenarios_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	eng, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering", ParentID: &root.ID})
+	backend, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Backend", ParentID: &eng.ID})
+	platform, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Platform", ParentID: &root.ID})
+	ann, _ := svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: "Ann"})
+	svc.CreateEmployee(eng.ID, model.CreateEmployeeRequest{FullName: "Bob"})
+
+	sc, err := svc.CreateScenario(model.CreateScenarioRequest{Name: "Q3 reorg"})
+	if err != nil {
+		t.Fatalf("ошибка создания сценария: %v", err)
+	}
+	ops := []model.ScenarioOperation{
+		{Op: model.ScenarioMoveDepartment, DepartmentID: backend.ID, ParentID: &platform.ID},
+		{Op: model.ScenarioMergeDepartments, DepartmentID: eng.ID, TargetID: platform.ID},
+		{Op: model.ScenarioRenameDepartment, DepartmentID: platform.ID, Name: "Technology"},
+		{Op: model.ScenarioTransferEmployee, EmployeeID: ann.ID, DepartmentID: platform.ID},
+	}
+	for _, op := range ops {
+		if sc, err = svc.AddScenarioOperation(sc.ID, op, sc.Version); err != nil {
+			t.Fatalf("ошибка добавления операции %s: %v", op.Op, err)
+		}
+	}
+	// Операция, неприменимая к прогнозу, отклоняется сразу
+	if _, err := svc.AddScenarioOperation(sc.ID, model.ScenarioOperation{Op: model.ScenarioRenameDepartment, DepartmentID: eng.ID, Name: "X"}, sc.Version); err == nil {
+		t.Error("ожидалась ошибка для слитого подразделения")
+	}
+
+	// Рабочие данные не меняются до применения
+	live, _ := svc.GetDepartment(eng.ID)
+	if live == nil || live.Name != "Engineering" {
+		t.Fatalf("рабочие данные изменены до применения: %+v", live)
+	}
+
+	tree, err := svc.GetScenarioTree(sc.ID, true)
+	if err != nil {
+		t.Fatalf("ошибка прогноза: %v", err)
+	}
+	if len(tree.Departments) != 1 || len(tree.Departments[0].Children) != 1 || tree.Departments[0].Children[0].Name != "Technology" {
+		t.Fatalf("неверное прогнозируемое дерево: %+v", tree.Departments)
+	}
+	diff, err := svc.GetScenarioDiff(sc.ID)
+	if err != nil || len(diff.Changes) != 5 || len(diff.Conflicts) != 0 {
+		t.Fatalf("неверный diff: %+v, %v", diff, err)
+	}
+
+	// Изменение рабочих данных после составления сценария даёт конфликт
+	moved, _ := svc.UpdateDepartment(backend.ID, model.UpdateDepartmentRequest{Name: "Services"}, backend.Version)
+	if _, conflicts, err := svc.ApplyScenario(sc.ID, sc.Version); err != ErrScenarioConflict || len(conflicts) != 1 || conflicts[0].ID != backend.ID {
+		t.Fatalf("ожидался конфликт по Backend, получено %+v, %v", conflicts, err)
+	}
+
+	// После пересоздания операции сценарий применяется целиком
+	if sc, err = svc.RemoveScenarioOperation(sc.ID, 0, sc.Version); err != nil {
+		t.Fatalf("ошибка удаления операции: %v", err)
+	}
+	if sc, err = svc.AddScenarioOperation(sc.ID, model.ScenarioOperation{Op: model.ScenarioMoveDepartment, DepartmentID: moved.ID, ParentID: &root.ID}, sc.Version); err != nil {
+		t.Fatalf("ошибка добавления операции: %v", err)
+	}
+	applied, conflicts, err := svc.ApplyScenario(sc.ID, sc.Version)
+	if err != nil {
+		t.Fatalf("ошибка применения: %v, %+v", err, conflicts)
+	}
+	if applied.Status != model.ScenarioApplied || applied.AppliedAt == nil {
+		t.Errorf("сценарий не отмечен применённым: %+v", applied)
+	}
+	if _, err := svc.GetDepartment(eng.ID); err == nil {
+		t.Error("слитое подразделение должно быть удалено")
+	}
+	if d, _ := svc.GetDepartment(platform.ID); d == nil || d.Name != "Technology" {
+		t.Errorf("подразделение не переименовано: %+v", d)
+	}
+	if d, _ := svc.GetDepartment(moved.ID); d == nil || d.ParentID == nil || *d.ParentID != root.ID {
+		t.Errorf("подразделение не перенесено: %+v", d)
+	}
+	if e, _ := repo.GetEmployeeByID(ann.ID); e == nil || e.DepartmentID != platform.ID {
+		t.Errorf("сотрудник не переведён: %+v", e)
+	}
+	if _, _, err := svc.ApplyScenario(sc.ID, applied.Version); err != ErrScenarioApplied {
+		t.Errorf("ожидалась ошибка повторного применения, получено %v", err)
+	}
+}
+
+func TestService_ScenarioAccess_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	eng, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering", ParentID: &root.ID})
+	sales, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales", ParentID: &root.ID})
+	for _, binding := range []model.CreateRoleBindingRequest{
+		{Subject: "alice", Role: model.RoleEditor, DepartmentID: &eng.ID},
+		{Subject: "bob", Role: model.RoleEditor, DepartmentID: &sales.ID},
+		{Subject: "planner", Role: model.RoleEditor},
+	} {
+		if _, err := svc.CreateRoleBinding(binding); err != nil {
+			t.Fatalf("ошибка создания привязки: %v", err)
+		}
+	}
+	alice := svc.WithPrincipal(auth.Principal{Subject: "alice"})
+	bob := svc.WithPrincipal(auth.Principal{Subject: "bob"})
+	planner := svc.WithPrincipal(auth.Principal{Subject: "planner"})
+
+	own, err := alice.CreateScenario(model.CreateScenarioRequest{Name: "Engineering split"})
+	if err != nil {
+		t.Fatalf("ошибка создания сценария: %v", err)
+	}
+	other, _ := bob.CreateScenario(model.CreateScenarioRequest{Name: "Sales regions"})
+
+	// Без роли на всю организацию видны только свои сценарии
+	if list, err := alice.ListScenarios(); err != nil || len(list) != 1 || list[0].ID != own.ID {
+		t.Errorf("ожидался только свой сценарий, получено %+v, %v", list, err)
+	}
+	if _, err := alice.GetScenario(other.ID); err != ErrForbidden {
+		t.Errorf("чужой сценарий: ожидалась ErrForbidden, получено %v", err)
+	}
+	op := model.ScenarioOperation{Op: model.ScenarioRenameDepartment, DepartmentID: eng.ID, Name: "R&D"}
+	if _, err := alice.AddScenarioOperation(other.ID, op, 0); err != ErrForbidden {
+		t.Errorf("изменение чужого сценария: ожидалась ErrForbidden, получено %v", err)
+	}
+	if err := alice.DeleteScenario(other.ID); err != ErrForbidden {
+		t.Errorf("удаление чужого сценария: ожидалась ErrForbidden, получено %v", err)
+	}
+	if _, err := alice.AddScenarioOperation(own.ID, op, 0); err != nil {
+		t.Errorf("ошибка изменения своего сценария: %v", err)
+	}
+	// Прогноз показывает всю оргструктуру и требует роли на всю организацию даже от автора
+	if _, err := alice.GetScenarioTree(own.ID, false); err != ErrForbidden {
+		t.Errorf("прогноз: ожидалась ErrForbidden, получено %v", err)
+	}
+
+	if list, err := planner.ListScenarios(); err != nil || len(list) != 2 {
+		t.Errorf("ожидались все сценарии, получено %+v, %v", list, err)
+	}
+	if err := planner.DeleteScenario(other.ID); err != nil {
+		t.Errorf("ошибка удаления сценария редактором организации: %v", err)
+	}
+	if err := alice.DeleteScenario(own.ID); err != nil {
+		t.Errorf("ошибка удаления своего сценария: %v", err)
+	}
+}
+
+func TestService_ScheduledChanges_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	eng, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering", ParentID: &root.ID})
+	sales, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales", ParentID: &root.ID})
+	legal, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Legal", ParentID: &root.ID})
+	ann, _ := svc.CreateEmployee(eng.ID, model.CreateEmployeeRequest{FullName: "Ann"})
+
+	at := time.Now().Add(24 * time.Hour)
+	changes, err := svc.ScheduleDepartmentPatch(sales.ID, model.DepartmentPatch{ParentID: model.Some(eng.ID), Name: model.Some("Presales")}, at)
+	if err != nil || len(changes) != 2 || changes[0].Op != model.ScenarioMoveDepartment || changes[1].Op != model.ScenarioRenameDepartment {
+		t.Fatalf("неверное планирование: %+v, %v", changes, err)
+	}
+	if _, err := svc.ScheduleEmployeeTransfer(eng.ID, ann.ID, model.EmployeePatch{DepartmentID: model.Some(sales.ID)}, at); err != nil {
+		t.Fatalf("ошибка планирования перевода: %v", err)
+	}
+	legalRename, _ := svc.ScheduleDepartmentPatch(legal.ID, model.DepartmentPatch{Name: model.Some("Compliance")}, at)
+	if _, err := svc.ScheduleDepartmentPatch(eng.ID, model.DepartmentPatch{Code: model.Some("ENG")}, at); err != ErrNotSchedulable {
+		t.Errorf("ожидалась ErrNotSchedulable, получено %v", err)
+	}
+	if _, err := svc.ScheduleDepartmentPatch(eng.ID, model.DepartmentPatch{Name: model.Some("R&D")}, time.Now().Add(-time.Hour)); err == nil {
+		t.Error("ожидалась ошибка для прошедшей даты")
+	}
+
+	// До даты вступления в силу ничего не меняется
+	if applied, failed, err := svc.ApplyDueScheduledChanges(time.Now()); err != nil || applied != 0 || failed != 0 {
+		t.Fatalf("изменения применены раньше срока: %d, %d, %v", applied, failed, err)
+	}
+	pending, _ := svc.ListScheduledChanges(model.ScheduledPending, nil)
+	if len(pending) != 4 {
+		t.Fatalf("ожидалось 4 ожидающих изменения, получено %d", len(pending))
+	}
+	if filtered, _ := svc.ListScheduledChanges("", &eng.ID); len(filtered) != 1 {
+		t.Errorf("ожидалось 1 изменение с переносом в Engineering, получено %+v", filtered)
+	}
+
+	// Субъекту видны только изменения его подразделений; перевод — при доступе к обоим подразделениям
+	for _, binding := range []model.CreateRoleBindingRequest{
+		{Subject: "sales-viewer", Role: model.RoleViewer, DepartmentID: &sales.ID},
+		{Subject: "legal-editor", Role: model.RoleEditor, DepartmentID: &legal.ID},
+	} {
+		if _, err := svc.CreateRoleBinding(binding); err != nil {
+			t.Fatalf("ошибка создания привязки: %v", err)
+		}
+	}
+	salesViewer := svc.WithPrincipal(auth.Principal{Subject: "sales-viewer"})
+	legalEditor := svc.WithPrincipal(auth.Principal{Subject: "legal-editor"})
+	if visible, err := salesViewer.ListScheduledChanges(model.ScheduledPending, nil); err != nil || len(visible) != 2 ||
+		visible[0].DepartmentID != sales.ID || visible[1].DepartmentID != sales.ID {
+		t.Errorf("ожидались 2 изменения Sales без перевода из Engineering, получено %+v, %v", visible, err)
+	}
+	if _, err := salesViewer.ListScheduledChanges("", &eng.ID); err != ErrForbidden {
+		t.Errorf("фильтр по чужому подразделению: ожидалась ErrForbidden, получено %v", err)
+	}
+	if _, err := legalEditor.GetScheduledChange(changes[0].ID); err != ErrForbidden {
+		t.Errorf("чужое изменение: ожидалась ErrForbidden, получено %v", err)
+	}
+	if _, err := legalEditor.CancelScheduledChange(changes[0].ID); err != ErrForbidden {
+		t.Errorf("отмена чужого изменения: ожидалась ErrForbidden, получено %v", err)
+	}
+	if change, err := legalEditor.GetScheduledChange(legalRename[0].ID); err != nil || change.ID != legalRename[0].ID {
+		t.Errorf("ошибка чтения своего изменения: %+v, %v", change, err)
+	}
+
+	// Отменённое изменение не применяется и не отменяется повторно
+	if _, err := svc.CancelScheduledChange(legalRename[0].ID); err != nil {
+		t.Fatalf("ошибка отмены: %v", err)
+	}
+	if _, err := svc.CancelScheduledChange(legalRename[0].ID); err != ErrChangeNotPending {
+		t.Errorf("ожидалась ErrChangeNotPending, получено %v", err)
+	}
+
+	// Подразделение назначения удалено до даты вступления в силу: перевод завершается ошибкой
+	other, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Other", ParentID: &root.ID})
+	bob, _ := svc.CreateEmployee(eng.ID, model.CreateEmployeeRequest{FullName: "Bob"})
+	svc.ScheduleEmployeeTransfer(eng.ID, bob.ID, model.EmployeePatch{DepartmentID: model.Some(other.ID)}, at)
+	svc.DeleteDepartment(other.ID, "cascade", nil, 0)
+
+	applied, failed, err := svc.ApplyDueScheduledChanges(at.Add(time.Minute))
+	if err != nil || applied != 3 || failed != 1 {
+		t.Fatalf("ожидалось 3 применённых и 1 ошибка, получено %d, %d, %v", applied, failed, err)
+	}
+	if d, _ := svc.GetDepartment(sales.ID); d == nil || d.Name != "Presales" || d.ParentID == nil || *d.ParentID != eng.ID {
+		t.Errorf("подразделение не перенесено и не переименовано: %+v", d)
+	}
+	if d, _ := svc.GetDepartment(legal.ID); d == nil || d.Name != "Legal" {
+		t.Errorf("отменённое изменение применено: %+v", d)
+	}
+	if e, _ := repo.GetEmployeeByID(ann.ID); e == nil || e.DepartmentID != sales.ID {
+		t.Errorf("сотрудник не переведён: %+v", e)
+	}
+	if e, _ := repo.GetEmployeeByID(bob.ID); e == nil || e.DepartmentID != eng.ID {
+		t.Errorf("неудавшийся перевод изменил данные: %+v", e)
+	}
+	failedChanges, _ := svc.ListScheduledChanges(model.ScheduledFailed, nil)
+	if len(failedChanges) != 1 || failedChanges[0].Error == nil || failedChanges[0].ProcessedAt == nil {
+		t.Errorf("неверная запись неудавшегося изменения: %+v", failedChanges)
+	}
+
+	// Перенос под политикой согласования не планируется, переименование — планируется
+	if _, err := svc.SetApprovalPolicy(model.ApprovalMoveDepartment, model.SetApprovalPolicyRequest{}); err != nil {
+		t.Fatalf("ошибка создания политики: %v", err)
+	}
+	if _, err := svc.ScheduleDepartmentPatch(eng.ID, model.DepartmentPatch{ParentID: model.Some(legal.ID)}, at); err != ErrScheduleApproval {
+		t.Errorf("ожидалась ErrScheduleApproval, получено %v", err)
+	}
+	if _, err := svc.ScheduleDepartmentPatch(eng.ID, model.DepartmentPatch{Name: model.Some("R&D")}, at); err != nil {
+		t.Errorf("ошибка планирования переименования: %v", err)
+	}
+}
+
+func TestService_ApprovalWorkflow_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	eng, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering", ParentID: &root.ID})
+	backend, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Backend", ParentID: &eng.ID})
+	sales, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales", ParentID: &root.ID})
+	legal, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Legal", ParentID: &root.ID})
+	for _, name := range []string{"Ann", "Bob"} {
+		svc.CreateEmployee(backend.ID, model.CreateEmployeeRequest{FullName: name})
+	}
+	svc.CreateEmployee(legal.ID, model.CreateEmployeeRequest{FullName: "Kim"})
+
+	if _, err := svc.SetApprovalPolicy(model.ApprovalDeleteDepartment, model.SetApprovalPolicyRequest{MinEmployees: 1}); err != nil {
+		t.Fatalf("ошибка создания политики: %v", err)
+	}
+	policy, err := svc.SetApprovalPolicy(model.ApprovalMoveDepartment, model.SetApprovalPolicyRequest{MinEmployees: 1, ApproverRole: model.RoleEditor})
+	if err != nil || policy.ApproverRole != model.RoleEditor {
+		t.Fatalf("ошибка создания политики: %+v, %v", policy, err)
+	}
+	for _, binding := range []model.CreateRoleBindingRequest{
+		{Subject: "manager", Role: model.RoleEditor, DepartmentID: &root.ID},
+		{Subject: "lead", Role: model.RoleEditor, DepartmentID: &root.ID},
+		{Subject: "director", Role: model.RoleAdmin, DepartmentID: &root.ID},
+	} {
+		if _, err := svc.CreateRoleBinding(binding); err != nil {
+			t.Fatalf("ошибка создания привязки: %v", err)
+		}
+	}
+	manager := svc.WithPrincipal(auth.Principal{Subject: "manager"})
+	lead := svc.WithPrincipal(auth.Principal{Subject: "lead"})
+	director := svc.WithPrincipal(auth.Principal{Subject: "director"})
+
+	// Под порогом политика не срабатывает
+	if err := manager.DeleteDepartment(legal.ID, "reassign", &sales.ID, 0); err != nil {
+		t.Fatalf("ожидалось удаление без согласования, получено %v", err)
+	}
+
+	// Перенос Engineering (2 сотрудника в поддереве) откладывается до согласования
+	_, err = manager.PatchDepartment(eng.ID, model.DepartmentPatch{ParentID: model.Some(sales.ID), Name: model.Some("R&D")}, eng.Version)
+	var approvalErr *ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("ожидалась ApprovalRequiredError, получено %v", err)
+	}
+	move := approvalErr.Request
+	if move.Action != model.ApprovalMoveDepartment || move.Employees != 2 || move.RequestedBy != "manager" || move.Patch == nil {
+		t.Errorf("неверный запрос на изменение: %+v", move)
+	}
+	if d, _ := svc.GetDepartment(eng.ID); d == nil || d.Name != "Engineering" {
+		t.Fatalf("изменение выполнено до согласования: %+v", d)
+	}
+
+	// Автор не может одобрить свой запрос; одобряет другой редактор
+	if _, err := manager.ApproveChangeRequest(move.ID, ""); err != ErrSelfApproval {
+		t.Errorf("ожидалась ErrSelfApproval, получено %v", err)
+	}
+	approved, err := lead.ApproveChangeRequest(move.ID, "согласовано")
+	if err != nil || approved.Status != model.ChangeRequestApproved || *approved.DecidedBy != "lead" {
+		t.Fatalf("ошибка согласования: %+v, %v", approved, err)
+	}
+	if d, _ := svc.GetDepartment(eng.ID); d == nil || d.Name != "R&D" || *d.ParentID != sales.ID {
+		t.Errorf("изменение не выполнено после согласования: %+v", d)
+	}
+	if _, err := lead.RejectChangeRequest(move.ID, ""); err != ErrChangeRequestDecided {
+		t.Errorf("ожидалась ErrChangeRequestDecided, получено %v", err)
+	}
+
+	// Удаление согласует только admin; отклонённый запрос ничего не меняет
+	err = manager.DeleteDepartment(backend.ID, "cascade", nil, 0)
+	if !errors.As(err, &approvalErr) {
+		t.Fatalf("ожидалась ApprovalRequiredError, получено %v", err)
+	}
+	deletion := approvalErr.Request
+	if _, err := lead.ApproveChangeRequest(deletion.ID, ""); err != ErrForbidden {
+		t.Errorf("ожидалась ErrForbidden для редактора, получено %v", err)
+	}
+	if _, err := director.RejectChangeRequest(deletion.ID, "слишком рано"); err != nil {
+		t.Fatalf("ошибка отклонения: %v", err)
+	}
+	if _, err := svc.GetDepartment(backend.ID); err != nil {
+		t.Errorf("отклонённое удаление выполнено: %v", err)
+	}
+
+	// Запросы видны только субъектам с ролью на их подразделения
+	support, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Support", ParentID: &root.ID})
+	for _, binding := range []model.CreateRoleBindingRequest{
+		{Subject: "sales-viewer", Role: model.RoleViewer, DepartmentID: &sales.ID},
+		{Subject: "support-viewer", Role: model.RoleViewer, DepartmentID: &support.ID},
+	} {
+		if _, err := svc.CreateRoleBinding(binding); err != nil {
+			t.Fatalf("ошибка создания привязки: %v", err)
+		}
+	}
+	salesViewer := svc.WithPrincipal(auth.Principal{Subject: "sales-viewer"})
+	supportViewer := svc.WithPrincipal(auth.Principal{Subject: "support-viewer"})
+	if visible, err := salesViewer.ListChangeRequests(""); err != nil || len(visible) != 2 {
+		t.Errorf("ожидались 2 запроса поддерева Sales, получено %+v, %v", visible, err)
+	}
+	if visible, err := supportViewer.ListChangeRequests(""); err != nil || len(visible) != 0 {
+		t.Errorf("запросы чужих подразделений не должны быть видны, получено %+v, %v", visible, err)
+	}
+	if _, err := supportViewer.GetChangeRequest(move.ID); err != ErrForbidden {
+		t.Errorf("чужой запрос: ожидалась ErrForbidden, получено %v", err)
+	}
+	if cr, err := salesViewer.GetChangeRequest(move.ID); err != nil || cr.ID != move.ID {
+		t.Errorf("ошибка чтения запроса: %+v, %v", cr, err)
+	}
+
+	// Перенос через изменение состава (SCIM) выполняется в общей транзакции: он отклоняется,
+	// и запрос на изменение не остаётся
+	_, err = manager.UpdateDepartmentMembers(root.ID, model.DepartmentPatch{}, []model.MembershipChange{{Kind: model.MemberDepartment, ID: eng.ID}}, 0)
+	if err != ErrApprovalNotDeferrable {
+		t.Errorf("ожидалась ErrApprovalNotDeferrable, получено %v", err)
+	}
+	if pending, _ := svc.ListChangeRequests(model.ChangeRequestPending); len(pending) != 0 {
+		t.Errorf("откатанный запрос на изменение сохранился: %+v", pending)
+	}
+	if d, _ := svc.GetDepartment(eng.ID); d == nil || *d.ParentID != sales.ID {
+		t.Errorf("перенос выполнен без согласования: %+v", d)
+	}
+
+	// Повторный запрос одобряет admin
+	manager.DeleteDepartment(backend.ID, "cascade", nil, 0)
+	pending, _ := svc.ListChangeRequests(model.ChangeRequestPending)
+	if len(pending) != 1 {
+		t.Fatalf("ожидался 1 ожидающий запрос, получено %d", len(pending))
+	}
+	if _, err := director.ApproveChangeRequest(pending[0].ID, ""); err != nil {
+		t.Fatalf("ошибка согласования удаления: %v", err)
+	}
+	if _, err := svc.GetDepartment(backend.ID); err == nil {
+		t.Error("подразделение должно быть удалено после согласования")
+	}
+}
+
+func TestService_ReorderChildren_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+
+	repo := repository.NewRepository(db)
+	svc := NewService(repo)
+
+	root, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Company"})
+	eng, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Engineering", ParentID: &root.ID})
+	sales, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Sales", ParentID: &root.ID})
+	legal, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Legal", ParentID: &root.ID})
+	other, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Other"})
+	support, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "Support", ParentID: &other.ID})
+
+	childIDs := func() []int {
+		tree, err := svc.GetDepartmentTree(root.ID, 2, false)
+		if err != nil {
+			t.Fatalf("ошибка получения дерева: %v", err)
+		}
+		var ids []int
+		for _, c := range tree.Children {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	// Новые подразделения добавляются в конец
+	if got := childIDs(); !slices.Equal(got, []int{eng.ID, sales.ID, legal.ID}) {
+		t.Fatalf("ожидался порядок создания, получено %v", got)
+	}
+
+	children, err := svc.ReorderChildren(root.ID, []int{legal.ID, eng.ID, sales.ID})
+	if err != nil {
+		t.Fatalf("ошибка изменения порядка: %v", err)
+	}
+	if len(children) != 3 || children[0].ID != legal.ID || children[0].SortOrder != 1 {
+		t.Errorf("ожидались потомки в новом порядке, получено %+v", children)
+	}
+	if got := childIDs(); !slices.Equal(got, []int{legal.ID, eng.ID, sales.ID}) {
+		t.Errorf("дерево должно учитывать порядок, получено %v", got)
+	}
+	listed, err := svc.ListDepartments(model.DepartmentFilter{ParentID: &root.ID, Limit: 10})
+	if err != nil || len(listed) != 3 || listed[0].ID != legal.ID {
+		t.Errorf("список потомков должен учитывать порядок, получено %+v, %v", listed, err)
+	}
+
+	// Неполный список, лишний или повторяющийся ID — порядок не меняется
+	for _, order := range [][]int{{eng.ID, sales.ID}, {legal.ID, eng.ID, support.ID}, {legal.ID, legal.ID, eng.ID}} {
+		if _, err := svc.ReorderChildren(root.ID, order); err != ErrOrderMismatch {
+			t.Errorf("%v: ожидалась ошибка ErrOrderMismatch, получено %v", order, err)
+		}
+	}
+	if got := childIDs(); !slices.Equal(got, []int{legal.ID, eng.ID, sales.ID}) {
+		t.Errorf("порядок не должен меняться при ошибке, получено %v", got)
+	}
+
+	// Перенесённое подразделение встаёт в конец списка нового родителя
+	if _, err := svc.PatchDepartment(support.ID, model.DepartmentPatch{ParentID: model.Some(root.ID)}, 0); err != nil {
+		t.Fatalf("ошибка переноса: %v", err)
+	}
+	if got := childIDs(); !slices.Equal(got, []int{legal.ID, eng.ID, sales.ID, support.ID}) {
+		t.Errorf("перенесённое подразделение должно быть последним, получено %v", got)
+	}
+
+	if _, err := svc.WithPrincipal(auth.Principal{Subject: "stranger"}).ReorderChildren(root.ID, []int{}); err != ErrForbidden {
+		t.Errorf("ожидалась ошибка ErrForbidden, получено %v", err)
+	}
+	if _, err := svc.ReorderChildren(9999, []int{}); err != ErrNotFound {
+		t.Errorf("ожидалась ошибка ErrNotFound, получено %v", err)
+	}
+
+	// Параллельные создания под одним родителем и в корне получают разные позиции
+	for _, parentID := range []*int{&other.ID, nil} {
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := svc.CreateDepartment(model.CreateDepartmentRequest{Name: fmt.Sprintf("Team %d", i), ParentID: parentID}); err != nil {
+					t.Errorf("ошибка создания подразделения: %v", err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		var positions []int
+		query := db.Model(&model.Department{})
+		if parentID == nil {
+			query = query.Where("parent_id IS NULL")
+		} else {
+			query = query.Where("parent_id = ?", *parentID)
+		}
+		query.Order("sort_order").Pluck("sort_order", &positions)
+		for i := 1; i < len(positions); i++ {
+			if positions[i] == positions[i-1] {
+				t.Errorf("повторяющиеся позиции потомков: %v", positions)
+				break
+			}
+		}
+	}
+}
@@ -1,8 +1,14 @@
 package service
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
 	"github.com/SergeiKhy/org-structure-api/internal/model"
 )
 
@@ -70,4 +76,564 @@ func TestService_Errors(t *testing.T) {
 	_ = ErrCycleDetected
 	_ = ErrDuplicateName
 	_ = ErrSelfParent
+	_ = ErrForbidden
+	_ = ErrDuplicateRoleBinding
+	_ = ErrAPIKeyRevoked
+	_ = ErrDuplicateTenant
+	_ = ErrVersionMismatch
+	_ = ErrDuplicateCode
+	_ = ErrIdempotencyKeyReused
+	_ = ErrIdempotencyKeyInProgress
+}
+
+// TestService_Authorize_Superuser проверяет, что суперпользователь не требует привязок ролей
+func TestService_Authorize_Superuser(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.System)
+	if err := svc.authorize(nil, model.RoleAdmin); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
+
+// TestService_Authorize_Anonymous проверяет отказ анонимному субъекту
+func TestService_Authorize_Anonymous(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.Principal{})
+	if err := svc.authorize(nil, model.RoleViewer); err != ErrForbidden {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+// TestService_Authorize_APIKeyScope проверяет ограничения областей API-ключей
+func TestService_Authorize_APIKeyScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		scope    string
+		required string
+		expected error
+	}{
+		{"read-only can read", auth.ScopeReadOnly, model.RoleViewer, nil},
+		{"read-only cannot write", auth.ScopeReadOnly, model.RoleEditor, ErrForbidden},
+		{"read-write can write", auth.ScopeReadWrite, model.RoleEditor, nil},
+		{"read-write cannot administer", auth.ScopeReadWrite, model.RoleAdmin, ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := (&Service{}).WithPrincipal(auth.Principal{Subject: "apikey:1", Scope: tt.scope})
+			if err := svc.authorize(nil, tt.required); err != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+// TestService_Tenants_RequireSuperuser проверяет, что арендаторами управляет только суперпользователь
+func TestService_Tenants_RequireSuperuser(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.Principal{Subject: "alice"})
+
+	if _, err := svc.ListTenants(); err != ErrForbidden {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := svc.CreateTenant(model.CreateTenantRequest{Slug: "acme", Name: "Acme"}); err != ErrForbidden {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+// TestService_ListEvents_InvalidType проверяет валидацию типов событий ленты
+func TestService_ListEvents_InvalidType(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.System)
+
+	for _, eventType := range []string{"unknown", model.EventAll} {
+		if _, err := svc.ListEvents(0, nil, []string{eventType}, 0); err == nil {
+			t.Errorf("expected error for event type %q", eventType)
+		}
+	}
+}
+
+// TestService_CreateWebhookSubscription_PrivateNetwork проверяет запрет подписок на адреса внутренней сети
+func TestService_CreateWebhookSubscription_PrivateNetwork(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.System)
+
+	for _, target := range []string{
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://10.0.0.5/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[::1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		_, err := svc.CreateWebhookSubscription(model.CreateWebhookSubscriptionRequest{
+			URL:        target,
+			EventTypes: []string{model.EventAll},
+		})
+		if err == nil || !strings.Contains(err.Error(), "private network") {
+			t.Errorf("%s: expected private network error, got %v", target, err)
+		}
+	}
+}
+
+// TestScheduledOutcome проверяет, что длинный текст ошибки обрезается без разрыва символов
+func TestScheduledOutcome(t *testing.T) {
+	if status, msg := scheduledOutcome(nil); status != model.ScheduledApplied || msg != nil {
+		t.Errorf("expected applied without error, got %q %v", status, msg)
+	}
+
+	text := "department " + strings.Repeat("ошибка", 400)
+	if utf8.ValidString(text[:2000]) {
+		t.Fatal("byte 2000 should fall inside a multibyte character")
+	}
+	status, msg := scheduledOutcome(errors.New(text))
+	if status != model.ScheduledFailed || msg == nil {
+		t.Fatalf("expected failed with error text, got %q %v", status, msg)
+	}
+	if len(*msg) > 2000 || len(*msg) < 1996 || !utf8.ValidString(*msg) {
+		t.Errorf("expected valid UTF-8 up to 2000 bytes, got %d bytes (valid=%v)", len(*msg), utf8.ValidString(*msg))
+	}
+}
+
+// TestMergeScopes проверяет объединение областей события без повторов
+func TestMergeScopes(t *testing.T) {
+	merged := mergeScopes([]int{5, 2, 1}, []int{5, 3, 1})
+	if len(merged) != 4 || merged[0] != 5 || merged[3] != 3 {
+		t.Errorf("expected [5 2 1 3], got %v", merged)
+	}
+}
+
+// TestService_ExecuteBatch_Validation проверяет ограничения на размер пакета
+func TestService_ExecuteBatch_Validation(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.System)
+
+	if _, err := svc.ExecuteBatch(model.BatchRequest{}); err == nil {
+		t.Error("expected error for empty batch")
+	}
+
+	ops := make([]model.BatchOperation, maxBatchOperations+1)
+	if resp, err := svc.ExecuteBatch(model.BatchRequest{Operations: ops}); err == nil || resp != nil {
+		t.Error("expected error without results for oversized batch")
+	}
+}
+
+// TestResolveBatchRef проверяет разрешение ссылок на созданные в пакете подразделения
+func TestResolveBatchRef(t *testing.T) {
+	refs := map[string]int{"eng": 42}
+
+	if id, err := resolveBatchRef(nil, refs); id != nil || err != nil {
+		t.Errorf("expected nil, got %v, %v", id, err)
+	}
+	if id, err := resolveBatchRef(&model.BatchRef{ID: 7}, refs); err != nil || *id != 7 {
+		t.Errorf("expected 7, got %v, %v", id, err)
+	}
+	if id, err := resolveBatchRef(&model.BatchRef{Ref: "eng"}, refs); err != nil || *id != 42 {
+		t.Errorf("expected 42, got %v, %v", id, err)
+	}
+	if _, err := resolveBatchRef(&model.BatchRef{Ref: "sales"}, refs); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+	if _, err := requireBatchRef(nil, refs, "id"); err == nil {
+		t.Error("expected error for missing required ref")
+	}
+}
+
+// TestService_DepartmentMetadata_Validation проверяет отказ для некорректных метаданных до обращения к БД
+func TestService_DepartmentMetadata_Validation(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.System)
+	tests := []struct {
+		name  string
+		patch model.DepartmentPatch
+	}{
+		{"code too short", model.DepartmentPatch{Code: model.Some("A")}},
+		{"code starts with digit", model.DepartmentPatch{Code: model.Some("1SALES")}},
+		{"code with space", model.DepartmentPatch{Code: model.Some("SALES EU")}},
+		{"long cost center", model.DepartmentPatch{CostCenter: model.Some(strings.Repeat("x", 51))}},
+		{"long description", model.DepartmentPatch{Description: model.Some(strings.Repeat("я", 2001))}},
+		{"null active", model.DepartmentPatch{Active: model.Null[bool]()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.applyDepartmentMetadata(&model.Department{}, tt.patch); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	dept := model.Department{Code: new(string), Active: true}
+	patch := metadataPatch(nil, new(string), nil, new(bool))
+	if err := svc.applyDepartmentMetadata(&dept, patch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dept.Code == nil || dept.CostCenter != nil || dept.Active {
+		t.Errorf("expected code kept, cost center cleared and inactive, got %+v", dept)
+	}
+}
+
+// TestService_ListDepartments_Anonymous проверяет, что список подразделений требует роли
+func TestService_ListDepartments_Anonymous(t *testing.T) {
+	svc := (&Service{}).WithPrincipal(auth.Principal{})
+	if _, err := svc.ListDepartments(model.DepartmentFilter{}); err != ErrForbidden {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+}
+
+// TestValidateAttributes проверяет значения атрибутов по схемам
+func TestValidateAttributes(t *testing.T) {
+	defs := []model.AttributeDefinition{
+		{Name: "slack", Type: model.AttributeTypeString},
+		{Name: "floor", Type: model.AttributeTypeNumber},
+		{Name: "remote", Type: model.AttributeTypeBoolean},
+		{Name: "badge_expires", Type: model.AttributeTypeDate},
+		{Name: "clearance", Type: model.AttributeTypeEnum, Required: true, EnumValues: []string{"low", "high"}},
+	}
+	tests := []struct {
+		name  string
+		attrs model.Attributes
+		valid bool
+	}{
+		{"all types", model.Attributes{"slack": "@ann", "floor": float64(3), "remote": true, "badge_expires": "2027-01-31", "clearance": "high"}, true},
+		{"required missing", model.Attributes{"slack": "@ann"}, false},
+		{"unknown attribute", model.Attributes{"clearance": "low", "office": "Berlin"}, false},
+		{"number as string", model.Attributes{"clearance": "low", "floor": "3"}, false},
+		{"invalid date", model.Attributes{"clearance": "low", "badge_expires": "31.01.2027"}, false},
+		{"value outside enum", model.Attributes{"clearance": "top"}, false},
+		{"empty string", model.Attributes{"clearance": "low", "slack": ""}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttributes(defs, tt.attrs)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidAttributes) {
+				t.Errorf("expected ErrInvalidAttributes, got %v", err)
+			}
+		})
+	}
+}
+
+// TestValidateEnumValues проверяет, что список значений задаётся только для enum
+func TestValidateEnumValues(t *testing.T) {
+	if _, err := validateEnumValues(model.AttributeTypeString, []string{"a"}); err == nil {
+		t.Error("expected error for enum_values of string attribute")
+	}
+	for _, values := range [][]string{nil, {"a", "a"}, {""}} {
+		if _, err := validateEnumValues(model.AttributeTypeEnum, values); err == nil {
+			t.Errorf("%v: expected error", values)
+		}
+	}
+	if _, err := validateEnumValues(model.AttributeTypeEnum, []string{"low", "high"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestReplaceAttributes проверяет перевод документа JSON Patch в частичное изменение атрибутов
+func TestReplaceAttributes(t *testing.T) {
+	current := model.Attributes{"office": "Berlin", "slack": "@ann"}
+	doc := model.Attributes{"office": "Paris"}
+	patch := model.Some(&doc)
+	replaceAttributes(current, &patch)
+	if value, ok := doc["slack"]; !ok || value != nil || doc["office"] != "Paris" {
+		t.Errorf("expected slack removed and office replaced, got %v", doc)
+	}
+
+	var missing model.Optional[*model.Attributes]
+	replaceAttributes(current, &missing)
+	if !missing.Null {
+		t.Errorf("expected all attributes removed, got %+v", missing)
+	}
+}
+
+// TestApplyEmployeeStatus проверяет переходы и заполнение данных об увольнении
+func TestApplyEmployeeStatus(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC)
+	hired := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	str := func(s string) *string { return &s }
+
+	emp := &model.Employee{Status: model.EmployeeActive, HiredAt: &hired}
+	if err := applyEmployeeStatus(emp, model.ChangeEmployeeStatusRequest{Status: model.EmployeeTerminated, Reason: str("  relocation ")}, now); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if emp.TerminatedAt == nil || !emp.TerminatedAt.Equal(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected termination today, got %v", emp.TerminatedAt)
+	}
+	if emp.TerminationReason == nil || *emp.TerminationReason != "relocation" {
+		t.Errorf("unexpected reason %v", emp.TerminationReason)
+	}
+
+	tests := []struct {
+		name   string
+		status string
+		req    model.ChangeEmployeeStatusRequest
+	}{
+		{"unknown status", model.EmployeeActive, model.ChangeEmployeeStatusRequest{Status: "fired"}},
+		{"reason without termination", model.EmployeeActive, model.ChangeEmployeeStatusRequest{Status: model.EmployeeOnLeave, Reason: str("x")}},
+		{"invalid date", model.EmployeeActive, model.ChangeEmployeeStatusRequest{Status: model.EmployeeTerminated, EffectiveDate: str("18.10.2026")}},
+		{"before hire", model.EmployeeActive, model.ChangeEmployeeStatusRequest{Status: model.EmployeeTerminated, EffectiveDate: str("2024-01-01")}},
+		{"long reason", model.EmployeeActive, model.ChangeEmployeeStatusRequest{Status: model.EmployeeTerminated, Reason: str(strings.Repeat("я", 501))}},
+	}
+	for _, tt := range tests {
+		emp := &model.Employee{Status: tt.status, HiredAt: &hired}
+		if err := applyEmployeeStatus(emp, tt.req, now); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+
+	emp = &model.Employee{Status: model.EmployeeTerminated}
+	if err := applyEmployeeStatus(emp, model.ChangeEmployeeStatusRequest{Status: model.EmployeeActive}, now); err != ErrInvalidTransition {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+// TestApplyPosition проверяет поля должности: пустые значения сбрасывают семейство и грейд
+func TestApplyPosition(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+
+	pos := &model.Position{}
+	if err := applyPosition(pos, str(" Sr. Developer "), str(" Engineering "), num(5)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pos.Title != "Sr. Developer" || pos.TitleKey != "senior developer" || *pos.JobFamily != "Engineering" || *pos.Grade != 5 {
+		t.Errorf("unexpected position %+v", pos)
+	}
+	if err := applyPosition(pos, nil, str(""), num(0)); err != nil || pos.JobFamily != nil || pos.Grade != nil || pos.Title != "Sr. Developer" {
+		t.Errorf("expected cleared family and grade, got %+v (%v)", pos, err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		title     *string
+		jobFamily *string
+		grade     *int
+	}{
+		{"empty title", str(" "), nil, nil},
+		{"punctuation title", str("--"), nil, nil},
+		{"long title", str(strings.Repeat("a", 201)), nil, nil},
+		{"long job family", nil, str(strings.Repeat("a", 101)), nil},
+		{"grade too high", nil, nil, num(model.MaxGrade + 1)},
+		{"negative grade", nil, nil, num(-1)},
+	} {
+		if err := applyPosition(&model.Position{}, tt.title, tt.jobFamily, tt.grade); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestApplyRequisition(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+
+	reqn := &model.Requisition{Status: model.RequisitionOpen}
+	err := applyRequisition(reqn, model.UpdateRequisitionRequest{PositionID: num(3), TargetStartDate: str("2026-09-01"), Note: str(" backend ")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reqn.PositionID != 3 || reqn.TargetStartDate.Format("2006-01-02") != "2026-09-01" || *reqn.Note != "backend" {
+		t.Errorf("unexpected requisition %+v", reqn)
+	}
+	if err := applyRequisition(reqn, model.UpdateRequisitionRequest{Status: str(model.RequisitionFilled), Note: str("")}); err != nil || reqn.Status != model.RequisitionFilled || reqn.Note != nil {
+		t.Errorf("expected filled requisition without note, got %+v (%v)", reqn, err)
+	}
+
+	// Закрытая вакансия не переоткрывается и не меняет должность
+	if err := applyRequisition(reqn, model.UpdateRequisitionRequest{Status: str(model.RequisitionOpen)}); err != ErrInvalidTransition {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+	if err := applyRequisition(reqn, model.UpdateRequisitionRequest{PositionID: num(4)}); err != ErrInvalidTransition {
+		t.Errorf("expected ErrInvalidTransition, got %v", err)
+	}
+	if err := applyRequisition(reqn, model.UpdateRequisitionRequest{Note: str("hired")}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		req  model.UpdateRequisitionRequest
+	}{
+		{"invalid position", model.UpdateRequisitionRequest{PositionID: num(0)}},
+		{"invalid date", model.UpdateRequisitionRequest{TargetStartDate: str("01.09.2026")}},
+		{"invalid status", model.UpdateRequisitionRequest{Status: str("closed")}},
+		{"long note", model.UpdateRequisitionRequest{Note: str(strings.Repeat("a", 2001))}},
+	} {
+		if err := applyRequisition(&model.Requisition{Status: model.RequisitionOpen}, tt.req); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestSummarizeStaffing(t *testing.T) {
+	num := func(n int) *int { return &n }
+	staffing := summarizeStaffing(1, "2026-Q3", []model.DepartmentStaffing{
+		{DepartmentID: 1, Budget: num(10), Actual: 4, Open: 1},
+		{DepartmentID: 2, Actual: 3, Open: 2},
+		{DepartmentID: 3, Budget: num(2), Actual: 1},
+	})
+	if staffing.Budget != 12 || staffing.Actual != 8 || staffing.Open != 3 || staffing.Available != 1 || len(staffing.Departments) != 3 {
+		t.Errorf("unexpected staffing %+v", staffing)
+	}
+	if empty := summarizeStaffing(1, "2026", nil); empty.Departments == nil || empty.Available != 0 {
+		t.Errorf("unexpected empty staffing %+v", empty)
+	}
+}
+
+// testOrgState: Company(1) -> Engineering(2) -> Backend(3), Company -> Platform(4) -> Core(5); Ann(10) в Backend, Bob(11) в Platform
+func testOrgState() *orgState {
+	ptr := func(n int) *int { return &n }
+	return newOrgState([]model.Department{
+		{ID: 1, Name: "Company", Version: 1},
+		{ID: 2, Name: "Engineering", ParentID: ptr(1), Version: 1},
+		{ID: 3, Name: "Backend", ParentID: ptr(2), Version: 1},
+		{ID: 4, Name: "Platform", ParentID: ptr(1), Version: 1},
+		{ID: 5, Name: "Core", ParentID: ptr(4), Version: 1},
+	}, []model.Employee{
+		{ID: 10, FullName: "Ann", DepartmentID: 3, Status: model.EmployeeActive, Version: 1},
+		{ID: 11, FullName: "Bob", DepartmentID: 4, Status: model.EmployeeActive, Version: 1},
+	})
+}
+
+func TestOrgState_Apply(t *testing.T) {
+	ptr := func(n int) *int { return &n }
+	tests := []struct {
+		name string
+		op   model.ScenarioOperation
+		err  error
+	}{
+		{"move to itself", model.ScenarioOperation{Op: model.ScenarioMoveDepartment, DepartmentID: 2, ParentID: ptr(2)}, ErrSelfParent},
+		{"move into own subtree", model.ScenarioOperation{Op: model.ScenarioMoveDepartment, DepartmentID: 2, ParentID: ptr(3)}, ErrCycleDetected},
+		{"move missing", model.ScenarioOperation{Op: model.ScenarioMoveDepartment, DepartmentID: 9}, ErrNotFound},
+		{"rename to sibling name", model.ScenarioOperation{Op: model.ScenarioRenameDepartment, DepartmentID: 4, Name: "Engineering"}, ErrDuplicateName},
+		{"merge into itself", model.ScenarioOperation{Op: model.ScenarioMergeDepartments, DepartmentID: 4, TargetID: 4}, ErrSelfParent},
+		{"merge into own child", model.ScenarioOperation{Op: model.ScenarioMergeDepartments, DepartmentID: 4, TargetID: 5}, ErrCycleDetected},
+		{"transfer missing employee", model.ScenarioOperation{Op: model.ScenarioTransferEmployee, EmployeeID: 99, DepartmentID: 3}, ErrNotFound},
+		{"unknown op", model.ScenarioOperation{Op: "split_department"}, nil},
+	}
+	for _, tt := range tests {
+		if err := testOrgState().apply(tt.op); err == nil || (tt.err != nil && err != tt.err) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+
+	st := testOrgState()
+	ops := []model.ScenarioOperation{
+		{Op: model.ScenarioRenameDepartment, DepartmentID: 4, Name: " Infrastructure "},
+		{Op: model.ScenarioMergeDepartments, DepartmentID: 2, TargetID: 4},
+		{Op: model.ScenarioTransferEmployee, EmployeeID: 11, DepartmentID: 5},
+		{Op: model.ScenarioMoveDepartment, DepartmentID: 5},
+	}
+	for i, op := range ops {
+		if err := st.apply(op); err != nil {
+			t.Fatalf("operation %d: unexpected error %v", i, err)
+		}
+	}
+	if _, ok := st.depts[2]; ok || st.mergedInto[2] != 4 {
+		t.Error("expected Engineering merged into Platform")
+	}
+	if st.depts[4].Name != "Infrastructure" || *st.depts[3].ParentID != 4 || st.depts[5].ParentID != nil {
+		t.Errorf("unexpected departments: %+v %+v %+v", st.depts[3], st.depts[4], st.depts[5])
+	}
+	if st.employees[10].DepartmentID != 3 || st.employees[11].DepartmentID != 5 {
+		t.Errorf("unexpected employees: %+v %+v", st.employees[10], st.employees[11])
+	}
+
+	tree := st.tree(true)
+	if len(tree) != 2 || tree[0].ID != 1 || tree[1].ID != 5 || len(tree[1].Employees) != 1 || tree[0].Children[0].Children[0].ID != 3 {
+		t.Errorf("unexpected tree: %+v", tree)
+	}
+}
+
+func TestOrgState_SiblingOrder(t *testing.T) {
+	ptr := func(n int) *int { return &n }
+	st := newOrgState([]model.Department{
+		{ID: 1, Name: "Company", SortOrder: 1},
+		{ID: 2, Name: "Sales", ParentID: ptr(1), SortOrder: 2},
+		{ID: 3, Name: "Finance", ParentID: ptr(1), SortOrder: 1},
+		{ID: 4, Name: "Legal", ParentID: ptr(5), SortOrder: 1},
+		{ID: 5, Name: "Office", SortOrder: 2},
+		{ID: 6, Name: "Support", ParentID: ptr(5), SortOrder: 2},
+	}, nil)
+
+	if got := st.children(1); !slices.Equal(got, []int{3, 2}) {
+		t.Errorf("expected children [3 2], got %v", got)
+	}
+
+	// Перенесённое подразделение встаёт в конец списка нового родителя
+	if err := st.apply(model.ScenarioOperation{Op: model.ScenarioMoveDepartment, DepartmentID: 6, ParentID: ptr(1)}); err != nil {
+		t.Fatalf("move: %v", err)
+	}
+	if got := st.children(1); !slices.Equal(got, []int{3, 2, 6}) {
+		t.Errorf("after move expected [3 2 6], got %v", got)
+	}
+
+	// Слитые потомки добавляются в конец в прежнем порядке
+	if err := st.apply(model.ScenarioOperation{Op: model.ScenarioMergeDepartments, DepartmentID: 1, TargetID: 5}); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if got := st.children(5); !slices.Equal(got, []int{4, 3, 2, 6}) {
+		t.Errorf("after merge expected [4 3 2 6], got %v", got)
+	}
+}
+
+func TestDiffOrgState(t *testing.T) {
+	live := testOrgState()
+	projected := live.clone()
+	for _, op := range []model.ScenarioOperation{
+		{Op: model.ScenarioMergeDepartments, DepartmentID: 5, TargetID: 4},
+		{Op: model.ScenarioMoveDepartment, DepartmentID: 3, ParentID: &[]int{4}[0]},
+		{Op: model.ScenarioRenameDepartment, DepartmentID: 3, Name: "Services"},
+		{Op: model.ScenarioTransferEmployee, EmployeeID: 10, DepartmentID: 2},
+	} {
+		if err := projected.apply(op); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	// Рабочее состояние не меняется при применении операций к копии
+	if live.depts[3].Name != "Backend" || len(live.depts) != 5 {
+		t.Fatal("live state modified")
+	}
+
+	changes := diffOrgState(live, projected)
+	var types []string
+	for _, c := range changes {
+		types = append(types, c.Type)
+	}
+	want := []string{model.ChangeDepartmentMoved, model.ChangeDepartmentRenamed, model.ChangeDepartmentMerged, model.ChangeEmployeeTransferred}
+	if !slices.Equal(types, want) {
+		t.Errorf("expected %v, got %v", want, types)
+	}
+	if c := changes[1]; c.From != "Backend" || c.To != "Services" {
+		t.Errorf("unexpected rename: %+v", c)
+	}
+	if c := changes[2]; c.DepartmentID != 5 || c.To != 4 {
+		t.Errorf("unexpected merge: %+v", c)
+	}
+}
+
+func TestProjectScenario_Conflicts(t *testing.T) {
+	live := testOrgState()
+	sc := &model.Scenario{
+		Operations: []model.ScenarioOperation{
+			{Op: model.ScenarioRenameDepartment, DepartmentID: 3, Name: "Services"},
+			{Op: model.ScenarioTransferEmployee, EmployeeID: 10, DepartmentID: 9},
+		},
+		DepartmentVersions: map[int]int{3: 1, 8: 2},
+		EmployeeVersions:   map[int]int{10: 1},
+	}
+	live.depts[3].Version = 2
+
+	projected, conflicts := projectScenario(sc, live)
+	if len(conflicts) != 3 {
+		t.Fatalf("expected 3 conflicts, got %+v", conflicts)
+	}
+	if c := conflicts[0]; c.Entity != "department" || c.ID != 3 || c.CurrentVersion == nil || *c.CurrentVersion != 2 {
+		t.Errorf("unexpected modified conflict: %+v", c)
+	}
+	if c := conflicts[1]; c.ID != 8 || c.CurrentVersion != nil {
+		t.Errorf("unexpected deleted conflict: %+v", c)
+	}
+	if c := conflicts[2]; c.Operation == nil || *c.Operation != 1 {
+		t.Errorf("unexpected operation conflict: %+v", c)
+	}
+	// Применимые операции попадают в прогноз несмотря на конфликты
+	if projected.depts[3].Name != "Services" {
+		t.Errorf("expected renamed department in projection")
+	}
 }
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+)
+
+// Бюджеты и вакансии хранятся на подразделении и удаляются вместе с ним;
+// при удалении в режиме reassign они не переносятся в новое подразделение.
+
+// ListBudgets возвращает бюджеты численности подразделения по всем периодам
+func (s *Service) ListBudgets(deptID int) ([]model.HeadcountBudget, error) {
+	if _, err := s.repo.GetDepartmentByID(deptID); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListBudgets(deptID)
+}
+
+// SetBudget утверждает численность подразделения на период; требует роль admin на подразделение
+func (s *Service) SetBudget(deptID int, period string, req model.SetBudgetRequest) (*model.HeadcountBudget, error) {
+	if !model.ValidPeriod(period) {
+		return nil, errors.New("invalid period")
+	}
+	if req.ApprovedHeadcount == nil || *req.ApprovedHeadcount < 0 {
+		return nil, errors.New("invalid approved_headcount")
+	}
+	if _, err := s.repo.GetDepartmentByID(deptID); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	budget := &model.HeadcountBudget{
+		DepartmentID:      deptID,
+		Period:            period,
+		ApprovedHeadcount: *req.ApprovedHeadcount,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.repo.UpsertBudget(budget); err != nil {
+		return nil, err
+	}
+	return budget, nil
+}
+
+func (s *Service) DeleteBudget(deptID int, period string) error {
+	if _, err := s.repo.GetDepartmentByID(deptID); err != nil {
+		return ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleAdmin); err != nil {
+		return err
+	}
+	deleted, err := s.repo.DeleteBudget(deptID, period)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListRequisitions возвращает вакансии подразделения; пустой status — в любом статусе
+func (s *Service) ListRequisitions(deptID int, status string) ([]model.Requisition, error) {
+	if status != "" && !model.ValidRequisitionStatus(status) {
+		return nil, errors.New("invalid status")
+	}
+	if _, err := s.repo.GetDepartmentByID(deptID); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRequisitions(deptID, status)
+}
+
+// CreateRequisition открывает вакансию в подразделении на должность из каталога
+func (s *Service) CreateRequisition(deptID int, req model.CreateRequisitionRequest) (*model.Requisition, error) {
+	reqn := &model.Requisition{DepartmentID: deptID, Status: model.RequisitionOpen}
+	if err := applyRequisition(reqn, model.UpdateRequisitionRequest{
+		PositionID:      &req.PositionID,
+		TargetStartDate: &req.TargetStartDate,
+		Note:            req.Note,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetDepartmentByID(deptID); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetPositionByID(reqn.PositionID); err != nil {
+		return nil, errors.New("position not found")
+	}
+
+	reqn.Version = 1
+	reqn.CreatedAt = time.Now()
+	reqn.UpdatedAt = reqn.CreatedAt
+	if err := s.repo.CreateRequisition(reqn); err != nil {
+		return nil, err
+	}
+	return reqn, nil
+}
+
+// UpdateRequisition изменяет вакансию подразделения deptID; закрытая вакансия (filled, cancelled)
+// допускает только изменение заметки
+func (s *Service) UpdateRequisition(deptID, id int, req model.UpdateRequisitionRequest, expectedVersion int) (*model.Requisition, error) {
+	reqn, err := s.repo.GetRequisitionByID(id)
+	if err != nil || reqn.DepartmentID != deptID {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleEditor); err != nil {
+		return nil, err
+	}
+	if expectedVersion != 0 && reqn.Version != expectedVersion {
+		return nil, ErrVersionMismatch
+	}
+
+	oldPositionID := reqn.PositionID
+	if err := applyRequisition(reqn, req); err != nil {
+		return nil, err
+	}
+	if reqn.PositionID != oldPositionID {
+		if _, err := s.repo.GetPositionByID(reqn.PositionID); err != nil {
+			return nil, errors.New("position not found")
+		}
+	}
+
+	if err := s.repo.UpdateRequisition(reqn); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, ErrVersionMismatch
+		}
+		return nil, err
+	}
+	return reqn, nil
+}
+
+// GetStaffing сравнивает бюджет на период с фактической численностью и открытыми вакансиями
+// по поддереву подразделения; пустой список статусов — только работающие сотрудники
+func (s *Service) GetStaffing(deptID int, period string, statuses []string) (*model.Staffing, error) {
+	if !model.ValidPeriod(period) {
+		return nil, errors.New("invalid period")
+	}
+	if _, err := s.repo.GetDepartmentByID(deptID); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.Staffing(deptID, period, employeeStatuses(statuses))
+	if err != nil {
+		return nil, err
+	}
+	return summarizeStaffing(deptID, period, rows), nil
+}
+
+// summarizeStaffing суммирует показатели подразделений поддерева
+func summarizeStaffing(deptID int, period string, rows []model.DepartmentStaffing) *model.Staffing {
+	staffing := &model.Staffing{DepartmentID: deptID, Period: period, Departments: rows}
+	if staffing.Departments == nil {
+		staffing.Departments = []model.DepartmentStaffing{}
+	}
+	for _, row := range rows {
+		if row.Budget != nil {
+			staffing.Budget += *row.Budget
+		}
+		staffing.Actual += row.Actual
+		staffing.Open += row.Open
+	}
+	staffing.Available = staffing.Budget - staffing.Actual - staffing.Open
+	return staffing
+}
+
+// applyRequisition проверяет и применяет поля вакансии; nil не меняет поле, пустая заметка сбрасывает её
+func applyRequisition(reqn *model.Requisition, req model.UpdateRequisitionRequest) error {
+	if reqn.Status != model.RequisitionOpen && (req.PositionID != nil || req.TargetStartDate != nil) {
+		return ErrInvalidTransition
+	}
+	if req.PositionID != nil {
+		if *req.PositionID <= 0 {
+			return errors.New("invalid position_id")
+		}
+		reqn.PositionID = *req.PositionID
+	}
+	if req.TargetStartDate != nil {
+		t, err := time.Parse("2006-01-02", *req.TargetStartDate)
+		if err != nil {
+			return errors.New("invalid date format")
+		}
+		reqn.TargetStartDate = t
+	}
+	if req.Note != nil {
+		note := strings.TrimSpace(*req.Note)
+		if utf8.RuneCountInString(note) > 2000 {
+			return errors.New("note too long")
+		}
+		if note == "" {
+			reqn.Note = nil
+		} else {
+			reqn.Note = &note
+		}
+	}
+	if req.Status != nil && *req.Status != reqn.Status {
+		if !model.ValidRequisitionStatus(*req.Status) {
+			return errors.New("invalid status")
+		}
+		if reqn.Status != model.RequisitionOpen {
+			return ErrInvalidTransition
+		}
+		reqn.Status = *req.Status
+	}
+	return nil
+}
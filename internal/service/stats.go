@@ -0,0 +1,19 @@
+package service
+
+import (
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// GetDepartmentStats возвращает численность, размеры и стаж по поддереву подразделения;
+// пустой список статусов — только работающие сотрудники
+func (s *Service) GetDepartmentStats(deptID int, statuses []string) (*model.DepartmentStats, error) {
+	if _, err := s.repo.GetDepartmentByID(deptID); err != nil {
+		return nil, ErrNotFound
+	}
+	if err := s.authorize(&deptID, model.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.DepartmentStats(deptID, employeeStatuses(statuses), time.Now())
+}
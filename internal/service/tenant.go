@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/auth"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"gorm.io/gorm"
+)
+
+var tenantSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Управление арендаторами доступно только суперпользователям платформы
+func (s *Service) ListTenants() ([]model.Tenant, error) {
+	if !s.principal.Superuser {
+		return nil, ErrForbidden
+	}
+	return s.repo.ListTenants()
+}
+
+func (s *Service) CreateTenant(req model.CreateTenantRequest) (*model.Tenant, error) {
+	if !s.principal.Superuser {
+		return nil, ErrForbidden
+	}
+
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !tenantSlugPattern.MatchString(slug) {
+		return nil, errors.New("invalid slug")
+	}
+	name := validateName(req.Name)
+	if name == "" || len(name) > 200 {
+		return nil, errors.New("invalid name")
+	}
+	adminSubject := strings.TrimSpace(req.AdminSubject)
+	if len(adminSubject) > 200 {
+		return nil, errors.New("invalid admin_subject")
+	}
+
+	if _, err := s.repo.GetTenantBySlug(slug); err == nil {
+		return nil, ErrDuplicateTenant
+	}
+
+	tenant := &model.Tenant{
+		Slug:      slug,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+
+	err := s.repo.DB().Transaction(func(tx *gorm.DB) error {
+		txRepo := s.repo.WithTx(tx)
+		if err := txRepo.CreateTenant(tenant); err != nil {
+			return err
+		}
+		if adminSubject == "" {
+			return nil
+		}
+		// Первый администратор арендатора
+		return txRepo.WithTenant(tenant.ID).CreateRoleBinding(&model.RoleBinding{
+			Subject:   adminSubject,
+			Role:      model.RoleAdmin,
+			CreatedAt: time.Now(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// ResolveTenant реализует auth.TenantResolver
+func (s *Service) ResolveTenant(slug string) (int, error) {
+	tenant, err := s.repo.GetTenantBySlug(slug)
+	if err != nil {
+		return 0, auth.ErrUnknownTenant
+	}
+	return tenant.ID, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+// Максимальное число записей журнала доставок в ответе
+const maxDeliveryLogLimit = 200
+
+// Время на разрешение имени хоста при создании подписки
+const webhookResolveTimeout = 5 * time.Second
+
+// WithPrivateWebhooks разрешает подписки на адреса внутренней сети (для локальной разработки)
+func (s *Service) WithPrivateWebhooks(allow bool) *Service {
+	s.allowPrivateWebhooks = allow
+	return s
+}
+
+func (s *Service) ListWebhookSubscriptions() ([]model.WebhookSubscription, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	return s.repo.ListWebhookSubscriptions()
+}
+
+func (s *Service) GetWebhookSubscription(id int) (*model.WebhookSubscription, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	sub, err := s.repo.GetWebhookSubscriptionByID(id)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return sub, nil
+}
+
+func (s *Service) CreateWebhookSubscription(req model.CreateWebhookSubscriptionRequest) (*model.WebhookSubscriptionWithSecret, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	target := strings.TrimSpace(req.URL)
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(target) > 2000 {
+		return nil, errors.New("invalid url")
+	}
+	if !s.allowPrivateWebhooks {
+		if err := checkWebhookHost(u.Hostname()); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.EventTypes) == 0 {
+		return nil, errors.New("event_types required")
+	}
+	for _, t := range req.EventTypes {
+		if !model.ValidEventType(t) {
+			return nil, errors.New("invalid event type: " + t)
+		}
+	}
+
+	secret := strings.TrimSpace(req.Secret)
+	if secret == "" {
+		if secret, err = randomHex(32); err != nil {
+			return nil, err
+		}
+	}
+	if len(secret) < 16 || len(secret) > 200 {
+		return nil, errors.New("secret must be 16-200 characters")
+	}
+
+	sub := &model.WebhookSubscription{
+		URL:        target,
+		EventTypes: req.EventTypes,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.CreateWebhookSubscription(sub); err != nil {
+		return nil, err
+	}
+	return &model.WebhookSubscriptionWithSecret{WebhookSubscription: *sub, Secret: secret}, nil
+}
+
+func (s *Service) DeleteWebhookSubscription(id int) error {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetWebhookSubscriptionByID(id); err != nil {
+		return ErrNotFound
+	}
+	return s.repo.DeleteWebhookSubscription(id)
+}
+
+// ListWebhookDeliveries журнал доставок подписки с необязательным фильтром по статусу
+func (s *Service) ListWebhookDeliveries(subscriptionID int, status string, limit int) ([]model.WebhookDelivery, error) {
+	if err := s.authorize(nil, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetWebhookSubscriptionByID(subscriptionID); err != nil {
+		return nil, ErrNotFound
+	}
+	if status != "" && status != model.DeliveryPending && status != model.DeliveryDelivered && status != model.DeliveryDead {
+		return nil, errors.New("invalid status")
+	}
+	if limit < 1 || limit > maxDeliveryLogLimit {
+		limit = maxDeliveryLogLimit
+	}
+	return s.repo.ListWebhookDeliveries(subscriptionID, status, limit)
+}
+
+// checkWebhookHost отклоняет хосты, указывающие во внутреннюю сеть.
+// Диспетчер повторяет проверку при каждом соединении, поэтому здесь она только
+// даёт понятную ошибку при создании подписки.
+func checkWebhookHost(host string) error {
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = append(addrs, addr)
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookResolveTimeout)
+		defer cancel()
+		if addrs, err = net.DefaultResolver.LookupNetIP(ctx, "ip", host); err != nil || len(addrs) == 0 {
+			return errors.New("webhook host cannot be resolved")
+		}
+	}
+	for _, addr := range addrs {
+		if !model.PublicAddress(addr) {
+			return errors.New("webhook url must not point to a private network")
+		}
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/logger"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+)
+
+// Заголовки запроса доставки
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-ID"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign вычисляет подпись HMAC-SHA256 над "<timestamp>.<body>".
+// Временная метка в подписи защищает получателя от повторного воспроизведения.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify проверяет подпись на стороне получателя
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Config параметры диспетчера
+type Config struct {
+	PollInterval time.Duration
+	Timeout      time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// AllowPrivateNetworks разрешает доставку на адреса внутренней сети (для локальной разработки)
+	AllowPrivateNetworks bool
+}
+
+// DefaultConfig значения по умолчанию: 8 попыток с интервалами от 10 секунд до часа
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: 2 * time.Second,
+		Timeout:      10 * time.Second,
+		BatchSize:    20,
+		MaxAttempts:  8,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   time.Hour,
+	}
+}
+
+// Backoff задержка перед следующей попыткой после attempt неудачных попыток
+func (c Config) Backoff(attempt int) time.Duration {
+	d := c.BaseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= c.MaxBackoff {
+			return c.MaxBackoff
+		}
+	}
+	return d
+}
+
+// Dispatcher фоновая доставка вебхуков с повторами и dead-letter
+type Dispatcher struct {
+	repo   *repository.Repository
+	client *http.Client
+	cfg    Config
+}
+
+func NewDispatcher(repo *repository.Repository, cfg Config) *Dispatcher {
+	client := &http.Client{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateNetworks {
+		// Адрес проверяется после разрешения имени, непосредственно перед соединением,
+		// поэтому смена DNS-записи после создания подписки не открывает внутреннюю сеть
+		dialer := &net.Dialer{Timeout: cfg.Timeout, Control: denyPrivateAddress}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+		client.Transport = transport
+	}
+	return &Dispatcher{
+		repo:   repo,
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// denyPrivateAddress запрещает соединение с адресами внутренней сети
+func denyPrivateAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !model.PublicAddress(addrPort.Addr()) {
+		return fmt.Errorf("webhook address %s is not allowed", addrPort.Addr())
+	}
+	return nil
+}
+
+// Run обрабатывает очередь до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchDue(ctx); err != nil {
+			logger.Error("ошибка доставки вебхуков", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue выполняет один проход по очереди и возвращает число обработанных доставок
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	// Аренда с запасом на таймаут всех запросов пачки
+	lease := d.cfg.Timeout*time.Duration(d.cfg.BatchSize) + time.Minute
+	deliveries, err := d.repo.ClaimDueWebhookDeliveries(time.Now(), lease, d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		d.attempt(ctx, delivery)
+		if err := d.repo.UpdateWebhookDelivery(delivery); err != nil {
+			return i, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt отправляет доставку и обновляет её состояние
+func (d *Dispatcher) attempt(ctx context.Context, delivery *model.WebhookDelivery) {
+	delivery.Attempts++
+
+	var status int
+	var err error
+	if delivery.Subscription == nil || !delivery.Subscription.Active {
+		err = fmt.Errorf("subscription is inactive")
+	} else {
+		status, err = d.send(ctx, delivery.Subscription, delivery)
+	}
+	delivery.ResponseStatus = status
+
+	now := time.Now()
+	if err == nil {
+		delivery.Status = model.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = model.TruncateRunes(err.Error(), 2000)
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		delivery.Status = model.DeliveryDead
+		logger.Warn("вебхук перемещён в dead-letter",
+			slog.Int("delivery_id", delivery.ID),
+			slog.String("error", delivery.LastError))
+		return
+	}
+	delivery.NextAttemptAt = now.Add(d.cfg.Backoff(delivery.Attempts))
+}
+
+// send выполняет HTTP-запрос к подписчику; успехом считается любой ответ 2xx
+func (d *Dispatcher) send(ctx context.Context, sub *model.WebhookSubscription, delivery *model.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "org-structure-api-webhooks")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sink приёмник outbox: ставит событие в очередь доставки подходящим подпискам арендатора.
+// Повторная публикация того же события не создаёт дублей доставок.
+type Sink struct {
+	repo *repository.Repository
+}
+
+func NewSink(repo *repository.Repository) *Sink {
+	return &Sink{repo: repo}
+}
+
+func (s *Sink) Name() string {
+	return "webhook"
+}
+
+func (s *Sink) Publish(_ context.Context, event *model.OutboxEvent) error {
+	repo := s.repo.WithTenant(event.TenantID)
+	subs, err := repo.GetActiveWebhookSubscriptions()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []model.WebhookDelivery
+	for _, sub := range subs {
+		if !sub.Matches(event.EventType) {
+			continue
+		}
+		deliveries = append(deliveries, model.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.EventID,
+			EventType:      event.EventType,
+			Payload:        event.Payload,
+			Status:         model.DeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+	return repo.CreateWebhookDeliveries(deliveries)
+}
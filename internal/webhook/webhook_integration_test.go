@@ -0,0 +1,209 @@
+//go:build integration
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/logger"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+	"github.com/SergeiKhy/org-structure-api/internal/outbox"
+	"github.com/SergeiKhy/org-structure-api/internal/repository"
+	"github.com/SergeiKhy/org-structure-api/internal/service"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// setupTestContainer создаёт контейнер с PostgreSQL для тестов
+func setupTestContainer(t testing.TB) (*tcpostgres.PostgresContainer, *gorm.DB, context.Context) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	pgContainer, err := tcpostgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:15-alpine"),
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("test"),
+		tcpostgres.WithPassword("test"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(time.Minute)),
+	)
+	if err != nil {
+		t.Fatalf("ошибка запуска контейнера: %v", err)
+	}
+
+	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("ошибка получения connection string: %v", err)
+	}
+
+	db, err := gorm.Open(postgres.Open(connStr), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("ошибка подключения к БД: %v", err)
+	}
+
+	err = db.AutoMigrate(&model.Department{}, &model.Employee{}, &model.RoleBinding{},
+		&model.WebhookSubscription{}, &model.WebhookDelivery{}, &model.OutboxEvent{}, &model.AttributeDefinition{}, &model.Position{}, &model.HeadcountBudget{}, &model.Requisition{}, &model.Scenario{}, &model.ScheduledChange{}, &model.ApprovalPolicy{}, &model.ChangeRequest{})
+	if err != nil {
+		t.Fatalf("ошибка миграции: %v", err)
+	}
+
+	return pgContainer, db, ctx
+}
+
+// testConfig конфигурация без задержек между попытками
+func testConfig() Config {
+	cfg := localConfig()
+	cfg.BaseBackoff = 0
+	cfg.MaxBackoff = 0
+	cfg.MaxAttempts = 3
+	return cfg
+}
+
+// TestDispatcher_DeliversServiceEvents_Integration проверяет путь от изменения в сервисе до получателя
+func TestDispatcher_DeliversServiceEvents_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+	logger.Init("error")
+
+	var mu sync.Mutex
+	var received []model.Event
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if !Verify("0123456789abcdef", ts, body, r.Header.Get(HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event model.Event
+		json.Unmarshal(body, &event)
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	repo := repository.NewRepository(db)
+	svc := service.NewService(repo).WithPrivateWebhooks(true)
+
+	_, err := svc.CreateWebhookSubscription(model.CreateWebhookSubscriptionRequest{
+		URL:        receiver.URL,
+		EventTypes: []string{model.EventDepartmentMoved, model.EventEmployeeCreated},
+		Secret:     "0123456789abcdef",
+	})
+	if err != nil {
+		t.Fatalf("ошибка создания подписки: %v", err)
+	}
+
+	a, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A"})
+	b, _ := svc.CreateDepartment(model.CreateDepartmentRequest{Name: "B"})
+	svc.UpdateDepartment(b.ID, model.UpdateDepartmentRequest{ParentID: &a.ID}, 0)
+	svc.CreateEmployee(b.ID, model.CreateEmployeeRequest{FullName: "John Doe", Position: "Developer"})
+
+	// Доставки появляются только после публикации outbox
+	relay := outbox.NewRelay(repo, outbox.DefaultConfig(), NewSink(repo))
+	if _, err := relay.RelayPending(ctx); err != nil {
+		t.Fatalf("ошибка публикации outbox: %v", err)
+	}
+
+	d := NewDispatcher(repo, testConfig())
+	n, err := d.DispatchDue(ctx)
+	if err != nil {
+		t.Fatalf("ошибка доставки: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("ожидалось 2 доставки (moved и employee.created), получено %d", n)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[0].Type != model.EventDepartmentMoved || received[1].Type != model.EventEmployeeCreated {
+		t.Errorf("неожиданные события: %+v", received)
+	}
+}
+
+// TestDispatcher_DeadLetter_Integration проверяет перевод в dead-letter после исчерпания попыток
+func TestDispatcher_DeadLetter_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+	logger.Init("error")
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := repository.NewRepository(db)
+	svc := service.NewService(repo).WithPrivateWebhooks(true)
+
+	sub, _ := svc.CreateWebhookSubscription(model.CreateWebhookSubscriptionRequest{
+		URL:        receiver.URL,
+		EventTypes: []string{model.EventAll},
+	})
+	svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A"})
+	outbox.NewRelay(repo, outbox.DefaultConfig(), NewSink(repo)).RelayPending(ctx)
+
+	d := NewDispatcher(repo, testConfig())
+	for i := 0; i < testConfig().MaxAttempts; i++ {
+		// Сбрасываем аренду, чтобы доставка снова стала доступной
+		db.Model(&model.WebhookDelivery{}).Where("status = ?", model.DeliveryPending).Update("next_attempt_at", time.Now().Add(-time.Second))
+		if _, err := d.DispatchDue(ctx); err != nil {
+			t.Fatalf("ошибка доставки: %v", err)
+		}
+	}
+
+	dead, err := svc.ListWebhookDeliveries(sub.ID, model.DeliveryDead, 0)
+	if err != nil {
+		t.Fatalf("ошибка получения журнала: %v", err)
+	}
+	if len(dead) != 1 || dead[0].Attempts != testConfig().MaxAttempts {
+		t.Errorf("ожидалась 1 доставка в dead-letter, получено %+v", dead)
+	}
+}
+
+// TestSink_Idempotent_Integration проверяет, что повторная публикация события не дублирует доставки
+func TestSink_Idempotent_Integration(t *testing.T) {
+	pgContainer, db, ctx := setupTestContainer(t)
+	defer pgContainer.Terminate(ctx)
+	logger.Init("error")
+
+	repo := repository.NewRepository(db)
+	svc := service.NewService(repo).WithPrivateWebhooks(true)
+
+	sub, _ := svc.CreateWebhookSubscription(model.CreateWebhookSubscriptionRequest{
+		URL:        "http://example.com/hook",
+		EventTypes: []string{model.EventAll},
+	})
+	svc.CreateDepartment(model.CreateDepartmentRequest{Name: "A"})
+
+	events, err := repo.GetUnpublishedOutboxEvents(10)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("ожидалось 1 событие в outbox, получено %d (%v)", len(events), err)
+	}
+
+	// Имитация сбоя relay после публикации, но до отметки события
+	sink := NewSink(repo)
+	for i := 0; i < 2; i++ {
+		if err := sink.Publish(ctx, &events[0]); err != nil {
+			t.Fatalf("ошибка публикации: %v", err)
+		}
+	}
+
+	deliveries, _ := svc.ListWebhookDeliveries(sub.ID, "", 0)
+	if len(deliveries) != 1 || deliveries[0].EventID != events[0].EventID {
+		t.Errorf("ожидалась 1 доставка, получено %d", len(deliveries))
+	}
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SergeiKhy/org-structure-api/internal/logger"
+	"github.com/SergeiKhy/org-structure-api/internal/model"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"department.created"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	if !Verify("secret", 1700000000, body, signature) {
+		t.Error("signature should verify")
+	}
+	if Verify("other", 1700000000, body, signature) {
+		t.Error("signature with another secret should not verify")
+	}
+	if Verify("secret", 1700000001, body, signature) {
+		t.Error("signature with another timestamp should not verify")
+	}
+	if Verify("secret", 1700000000, []byte(`{}`), signature) {
+		t.Error("signature of another body should not verify")
+	}
+}
+
+func TestConfig_Backoff(t *testing.T) {
+	cfg := Config{BaseBackoff: 10 * time.Second, MaxBackoff: time.Minute}
+
+	tests := []struct {
+		attempt  int
+		expected time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, time.Minute},
+		{10, time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := cfg.Backoff(tt.attempt); got != tt.expected {
+			t.Errorf("Backoff(%d) = %v, expected %v", tt.attempt, got, tt.expected)
+		}
+	}
+}
+
+// localConfig конфигурация для приёмников httptest, слушающих на loopback
+func localConfig() Config {
+	cfg := DefaultConfig()
+	cfg.AllowPrivateNetworks = true
+	return cfg
+}
+
+func newTestDelivery(url string) *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		ID:        1,
+		EventID:   "evt-1",
+		EventType: model.EventDepartmentCreated,
+		Payload:   model.JSON(`{"id":"evt-1"}`),
+		Status:    model.DeliveryPending,
+		Subscription: &model.WebhookSubscription{
+			URL:    url,
+			Secret: "receiver-secret",
+			Active: true,
+		},
+	}
+}
+
+// TestAttempt_Delivered проверяет доставку на локальный приёмник и подпись запроса
+func TestAttempt_Delivered(t *testing.T) {
+	var verified bool
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		verified = Verify("receiver-secret", ts, body, r.Header.Get(HeaderSignature)) &&
+			r.Header.Get(HeaderEvent) == model.EventDepartmentCreated
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	d := NewDispatcher(nil, localConfig())
+	delivery := newTestDelivery(receiver.URL)
+	d.attempt(context.Background(), delivery)
+
+	if delivery.Status != model.DeliveryDelivered {
+		t.Fatalf("expected status %q, got %q (%s)", model.DeliveryDelivered, delivery.Status, delivery.LastError)
+	}
+	if !verified {
+		t.Error("receiver could not verify the signature")
+	}
+	if delivery.DeliveredAt == nil || delivery.Attempts != 1 || delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("unexpected delivery state %+v", delivery)
+	}
+}
+
+// TestAttempt_RetryAndDeadLetter проверяет повтор с задержкой и перевод в dead-letter
+func TestAttempt_RetryAndDeadLetter(t *testing.T) {
+	logger.Init("error")
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	cfg := localConfig()
+	cfg.MaxAttempts = 2
+	d := NewDispatcher(nil, cfg)
+	delivery := newTestDelivery(receiver.URL)
+
+	before := time.Now()
+	d.attempt(context.Background(), delivery)
+	if delivery.Status != model.DeliveryPending {
+		t.Fatalf("expected status %q after first failure, got %q", model.DeliveryPending, delivery.Status)
+	}
+	if delivery.NextAttemptAt.Before(before.Add(cfg.BaseBackoff)) {
+		t.Errorf("next attempt should be delayed by backoff, got %v", delivery.NextAttemptAt)
+	}
+	if delivery.ResponseStatus != http.StatusServiceUnavailable || delivery.LastError == "" {
+		t.Errorf("failure should be recorded, got %+v", delivery)
+	}
+
+	d.attempt(context.Background(), delivery)
+	if delivery.Status != model.DeliveryDead {
+		t.Errorf("expected status %q after max attempts, got %q", model.DeliveryDead, delivery.Status)
+	}
+}
+
+// TestAttempt_InactiveSubscription проверяет, что отключённой подписке ничего не отправляется
+func TestAttempt_InactiveSubscription(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	d := NewDispatcher(nil, localConfig())
+	delivery := newTestDelivery(receiver.URL)
+	delivery.Subscription.Active = false
+	d.attempt(context.Background(), delivery)
+
+	if called {
+		t.Error("inactive subscription should not receive requests")
+	}
+	if delivery.Status == model.DeliveryDelivered {
+		t.Error("delivery should not be marked delivered")
+	}
+}
+
+// TestAttempt_PrivateAddressDenied проверяет, что по умолчанию соединение с внутренней сетью
+// запрещается на этапе подключения, даже если адрес получен после разрешения имени
+func TestAttempt_PrivateAddressDenied(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	d := NewDispatcher(nil, DefaultConfig())
+	for _, url := range []string{receiver.URL, strings.Replace(receiver.URL, "127.0.0.1", "localhost", 1)} {
+		delivery := newTestDelivery(url)
+		d.attempt(context.Background(), delivery)
+
+		if delivery.Status == model.DeliveryDelivered {
+			t.Errorf("%s: delivery to a private address should fail", url)
+		}
+		if !strings.Contains(delivery.LastError, "is not allowed") {
+			t.Errorf("%s: unexpected error %q", url, delivery.LastError)
+		}
+	}
+	if called {
+		t.Error("private receiver should not receive requests")
+	}
+}
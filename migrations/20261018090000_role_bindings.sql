@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS role_bindings (
+    id SERIAL PRIMARY KEY,
+    subject VARCHAR(200) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('viewer', 'editor', 'admin')),
+    department_id INTEGER REFERENCES departments(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Index for faster lookups of a subject's bindings
+CREATE INDEX IF NOT EXISTS idx_role_bindings_subject ON role_bindings(subject);
+
+-- Index for cleanup and listing by department
+CREATE INDEX IF NOT EXISTS idx_role_bindings_department_id ON role_bindings(department_id);
+
+-- Unique constraint: one binding per subject, role and scope
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_bindings_unique ON role_bindings(subject, role, COALESCE(department_id, -1));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_role_bindings_unique;
+DROP INDEX IF EXISTS idx_role_bindings_department_id;
+DROP INDEX IF EXISTS idx_role_bindings_subject;
+DROP TABLE IF EXISTS role_bindings;
+
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS tenants (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(63) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tenants_slug ON tenants(slug);
+
+-- Existing data belongs to the default tenant
+INSERT INTO tenants (id, slug, name) VALUES (1, 'default', 'Default') ON CONFLICT DO NOTHING;
+SELECT setval(pg_get_serial_sequence('tenants', 'id'), (SELECT MAX(id) FROM tenants));
+
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE role_bindings ADD COLUMN IF NOT EXISTS tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id);
+
+CREATE INDEX IF NOT EXISTS idx_departments_tenant_id ON departments(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_employees_tenant_id ON employees(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_role_bindings_tenant_id ON role_bindings(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_tenant_id ON api_keys(tenant_id);
+
+-- Unique constraints are now per tenant
+DROP INDEX IF EXISTS idx_departments_name_parent;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_departments_name_parent ON departments(tenant_id, name, COALESCE(parent_id, -1));
+
+DROP INDEX IF EXISTS idx_role_bindings_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_bindings_unique ON role_bindings(tenant_id, subject, role, COALESCE(department_id, -1));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_role_bindings_unique;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_role_bindings_unique ON role_bindings(subject, role, COALESCE(department_id, -1));
+
+DROP INDEX IF EXISTS idx_departments_name_parent;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_departments_name_parent ON departments(name, COALESCE(parent_id, -1));
+
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+DROP INDEX IF EXISTS idx_role_bindings_tenant_id;
+DROP INDEX IF EXISTS idx_employees_tenant_id;
+DROP INDEX IF EXISTS idx_departments_tenant_id;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE role_bindings DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE employees DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE departments DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_tenants_slug;
+DROP TABLE IF EXISTS tenants;
+
+-- +goose StatementEnd
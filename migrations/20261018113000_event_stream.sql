@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Department and its ancestors at the time of the event, used for subtree filtering
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS department_path JSONB NOT NULL DEFAULT '[]';
+
+-- Publication order assigned by the relay; serves as the SSE event id
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS seq BIGINT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_seq ON outbox(seq);
+CREATE INDEX IF NOT EXISTS idx_outbox_department_path ON outbox USING GIN (department_path);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_outbox_department_path;
+DROP INDEX IF EXISTS idx_outbox_seq;
+ALTER TABLE outbox DROP COLUMN IF EXISTS seq;
+ALTER TABLE outbox DROP COLUMN IF EXISTS department_path;
+
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Row version for optimistic concurrency: incremented on every update
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW();
+
+UPDATE departments SET updated_at = created_at WHERE created_at IS NOT NULL;
+UPDATE employees SET updated_at = created_at WHERE created_at IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE employees DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE employees DROP COLUMN IF EXISTS version;
+ALTER TABLE departments DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE departments DROP COLUMN IF EXISTS version;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Login and identifier assigned by an external identity provider (SCIM userName/externalId)
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS user_name VARCHAR(200);
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS external_id VARCHAR(200);
+
+-- userName is case-insensitive and unique within a tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_user_name ON employees (tenant_id, LOWER(user_name)) WHERE user_name IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_employees_external_id ON employees (tenant_id, external_id) WHERE external_id IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_employees_external_id;
+DROP INDEX IF EXISTS idx_employees_user_name;
+ALTER TABLE employees DROP COLUMN IF EXISTS external_id;
+ALTER TABLE employees DROP COLUMN IF EXISTS user_name;
+
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS code VARCHAR(32);
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS cost_center VARCHAR(50);
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS description VARCHAR(2000);
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Codes are stored upper-case and are unique across the whole organization (tenant)
+-- PostgreSQL has no ADD CONSTRAINT IF NOT EXISTS; check the catalog so the migration can be re-run
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint
+        WHERE conname = 'chk_departments_code' AND conrelid = 'departments'::regclass
+    ) THEN
+        ALTER TABLE departments ADD CONSTRAINT chk_departments_code CHECK (code ~ '^[A-Z][A-Z0-9_-]{1,31}$');
+    END IF;
+END
+$$;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_departments_code ON departments (tenant_id, code) WHERE code IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_departments_cost_center ON departments (tenant_id, cost_center) WHERE cost_center IS NOT NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_departments_cost_center;
+DROP INDEX IF EXISTS idx_departments_code;
+ALTER TABLE departments DROP CONSTRAINT IF EXISTS chk_departments_code;
+ALTER TABLE departments DROP COLUMN IF EXISTS active;
+ALTER TABLE departments DROP COLUMN IF EXISTS description;
+ALTER TABLE departments DROP COLUMN IF EXISTS cost_center;
+ALTER TABLE departments DROP COLUMN IF EXISTS code;
+
+-- +goose StatementEnd
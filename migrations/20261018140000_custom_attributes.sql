@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS attribute_definitions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    entity VARCHAR(20) NOT NULL CHECK (entity IN ('employee', 'department')),
+    name VARCHAR(64) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('string', 'number', 'boolean', 'date', 'enum')),
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    enum_values JSONB,
+    description VARCHAR(2000),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One definition per attribute name and entity within a tenant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attribute_definitions_name ON attribute_definitions(tenant_id, entity, name);
+
+-- Attribute values are validated against the definitions by the application
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+-- Containment (@>) lookups for filtering by attribute values
+CREATE INDEX IF NOT EXISTS idx_departments_attributes ON departments USING GIN (attributes jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_employees_attributes ON employees USING GIN (attributes jsonb_path_ops);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_employees_attributes;
+DROP INDEX IF EXISTS idx_departments_attributes;
+ALTER TABLE employees DROP COLUMN IF EXISTS attributes;
+ALTER TABLE departments DROP COLUMN IF EXISTS attributes;
+DROP INDEX IF EXISTS idx_attribute_definitions_name;
+DROP TABLE IF EXISTS attribute_definitions;
+
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Existing employees are considered active
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active'
+    CHECK (status IN ('pending', 'active', 'on_leave', 'terminated'));
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS terminated_at DATE;
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS termination_reason VARCHAR(500);
+
+-- Headcount and tree queries filter by status
+CREATE INDEX IF NOT EXISTS idx_employees_department_status ON employees(department_id, status);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_employees_department_status;
+ALTER TABLE employees DROP COLUMN IF EXISTS termination_reason;
+ALTER TABLE employees DROP COLUMN IF EXISTS terminated_at;
+ALTER TABLE employees DROP COLUMN IF EXISTS status;
+
+-- +goose StatementEnd
@@ -0,0 +1,65 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS positions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    title VARCHAR(200) NOT NULL,
+    title_key TEXT NOT NULL,
+    job_family VARCHAR(100),
+    grade INTEGER CHECK (grade BETWEEN 1 AND 20),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Titles differing only in case, punctuation or common abbreviations are the same position
+CREATE UNIQUE INDEX IF NOT EXISTS idx_positions_title_key ON positions(tenant_id, title_key);
+CREATE INDEX IF NOT EXISTS idx_positions_job_family ON positions(tenant_id, job_family);
+
+ALTER TABLE employees ADD COLUMN IF NOT EXISTS position_id INTEGER REFERENCES positions(id);
+CREATE INDEX IF NOT EXISTS idx_employees_position_id ON employees(position_id);
+
+-- Backfill: normalize free-text positions the same way as model.PositionKey
+-- (lower case, non-alphanumeric runs as separators, sr/jr/mgr expanded)
+CREATE TEMPORARY TABLE employee_position_keys AS
+SELECT id, tenant_id, trim(position) AS title,
+       trim(regexp_replace(regexp_replace(regexp_replace(regexp_replace(
+           lower(position), '[^[:alnum:]]+', ' ', 'g'),
+           '\msr\M', 'senior', 'g'),
+           '\mjr\M', 'junior', 'g'),
+           '\mmgr\M', 'manager', 'g')) AS title_key
+FROM employees;
+
+-- One catalog entry per tenant and key, titled with the most common spelling
+INSERT INTO positions (tenant_id, title, title_key)
+SELECT DISTINCT ON (tenant_id, title_key) tenant_id, title, title_key
+FROM (
+    SELECT tenant_id, title, title_key, COUNT(*) AS uses
+    FROM employee_position_keys
+    WHERE title_key <> ''
+    GROUP BY tenant_id, title, title_key
+) spellings
+ORDER BY tenant_id, title_key, uses DESC, title
+ON CONFLICT (tenant_id, title_key) DO NOTHING;
+
+-- Only the link is set: the original free-text position is kept, so the Down migration loses nothing
+UPDATE employees e
+SET position_id = p.id
+FROM employee_position_keys k
+JOIN positions p ON p.tenant_id = k.tenant_id AND p.title_key = k.title_key
+WHERE e.id = k.id;
+
+DROP TABLE employee_position_keys;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_employees_position_id;
+ALTER TABLE employees DROP COLUMN IF EXISTS position_id;
+DROP INDEX IF EXISTS idx_positions_job_family;
+DROP INDEX IF EXISTS idx_positions_title_key;
+DROP TABLE IF EXISTS positions;
+
+-- +goose StatementEnd
@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS headcount_budgets (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    department_id INTEGER NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    -- Year (2026), quarter (2026-Q3) or month (2026-07)
+    period VARCHAR(7) NOT NULL,
+    approved_headcount INTEGER NOT NULL CHECK (approved_headcount >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One budget per department and period
+CREATE UNIQUE INDEX IF NOT EXISTS idx_headcount_budgets_period ON headcount_budgets(tenant_id, department_id, period);
+
+CREATE TABLE IF NOT EXISTS requisitions (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    department_id INTEGER NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    position_id INTEGER NOT NULL REFERENCES positions(id),
+    target_start_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'filled', 'cancelled')),
+    note VARCHAR(2000),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Staffing counts open requisitions per department
+CREATE INDEX IF NOT EXISTS idx_requisitions_department_status ON requisitions(department_id, status);
+CREATE INDEX IF NOT EXISTS idx_requisitions_position_id ON requisitions(position_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_requisitions_position_id;
+DROP INDEX IF EXISTS idx_requisitions_department_status;
+DROP TABLE IF EXISTS requisitions;
+DROP INDEX IF EXISTS idx_headcount_budgets_period;
+DROP TABLE IF EXISTS headcount_budgets;
+
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS scenarios (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    name VARCHAR(200) NOT NULL,
+    description VARCHAR(2000),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'applied')),
+    -- Proposed operations in order; not applied to live data until the scenario is applied
+    operations JSONB NOT NULL DEFAULT '[]',
+    -- Versions of the touched rows when the operations were added, used for conflict detection
+    department_versions JSONB NOT NULL DEFAULT '{}',
+    employee_versions JSONB NOT NULL DEFAULT '{}',
+    created_by VARCHAR(200),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    applied_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scenarios_tenant_id ON scenarios(tenant_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_scenarios_tenant_id;
+DROP TABLE IF EXISTS scenarios;
+
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS scheduled_changes (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    op VARCHAR(50) NOT NULL CHECK (op IN ('move_department', 'rename_department', 'transfer_employee')),
+    -- Department being moved or renamed; target department of an employee transfer.
+    -- No foreign keys: a change whose rows are gone fails when it becomes due.
+    department_id INTEGER NOT NULL,
+    parent_id INTEGER,
+    name VARCHAR(200),
+    employee_id INTEGER,
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'cancelled', 'failed')),
+    error VARCHAR(2000),
+    created_by VARCHAR(200),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_changes_tenant_id ON scheduled_changes(tenant_id);
+
+-- The scheduler scans pending changes by effective date across all tenants
+CREATE INDEX IF NOT EXISTS idx_scheduled_changes_due ON scheduled_changes(effective_at) WHERE status = 'pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_scheduled_changes_due;
+DROP INDEX IF EXISTS idx_scheduled_changes_tenant_id;
+DROP TABLE IF EXISTS scheduled_changes;
+
+-- +goose StatementEnd
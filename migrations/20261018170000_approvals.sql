@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE IF NOT EXISTS approval_policies (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    action VARCHAR(50) NOT NULL CHECK (action IN ('delete_department', 'move_department')),
+    -- Approval is required when the subtree has more active employees than this
+    min_employees INTEGER NOT NULL CHECK (min_employees >= 0),
+    approver_role VARCHAR(20) NOT NULL CHECK (approver_role IN ('editor', 'admin')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_policies_action ON approval_policies(tenant_id, action);
+
+CREATE TABLE IF NOT EXISTS change_requests (
+    id SERIAL PRIMARY KEY,
+    tenant_id INTEGER NOT NULL DEFAULT 1 REFERENCES tenants(id),
+    action VARCHAR(50) NOT NULL,
+    -- No foreign key: the request outlives the department once it is executed
+    department_id INTEGER NOT NULL,
+    department_name VARCHAR(200) NOT NULL,
+    department_version INTEGER NOT NULL,
+    employees INTEGER NOT NULL,
+    policy_id INTEGER REFERENCES approval_policies(id) ON DELETE SET NULL,
+    -- Copied from the policy so later policy edits do not affect pending requests
+    approver_role VARCHAR(20) NOT NULL,
+    -- Arguments of the deferred call: delete mode and target, or the department patch
+    mode VARCHAR(20),
+    reassign_to_id INTEGER,
+    patch JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    requested_by VARCHAR(200),
+    decided_by VARCHAR(200),
+    comment VARCHAR(2000),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_requests_tenant_id ON change_requests(tenant_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_change_requests_tenant_id;
+DROP TABLE IF EXISTS change_requests;
+DROP INDEX IF EXISTS idx_approval_policies_action;
+DROP TABLE IF EXISTS approval_policies;
+
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Position of a department among its siblings; children are listed by (sort_order, id)
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- Existing siblings keep their previous (insertion) order
+UPDATE departments d
+SET sort_order = o.pos
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY tenant_id, parent_id ORDER BY id) AS pos
+    FROM departments
+) o
+WHERE d.id = o.id;
+
+CREATE INDEX IF NOT EXISTS idx_departments_sort_order ON departments(tenant_id, parent_id, sort_order);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_departments_sort_order;
+ALTER TABLE departments DROP COLUMN IF EXISTS sort_order;
+
+-- +goose StatementEnd
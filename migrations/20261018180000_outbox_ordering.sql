@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Transaction that wrote the event. The relay publishes an event only after every
+-- transaction with a smaller id has finished, so a late commit never lands behind
+-- events that are already published.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS txid BIGINT NOT NULL DEFAULT (pg_current_xact_id()::text::bigint);
+
+-- Retry backoff and parking of events that keep failing
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS parked_at TIMESTAMP WITH TIME ZONE;
+
+-- Index for the relay picking up unpublished events in commit order
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(txid, id) WHERE published_at IS NULL AND parked_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(id) WHERE published_at IS NULL;
+ALTER TABLE outbox DROP COLUMN IF EXISTS parked_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS txid;
+
+-- +goose StatementEnd